@@ -3,6 +3,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"embed"
 	"flag"
@@ -19,21 +20,16 @@ import (
 	"time"
 
 	"FolderSynchronizer/internal/api"
+	"FolderSynchronizer/internal/buildinfo"
 	cfg "FolderSynchronizer/internal/config"
 	"FolderSynchronizer/internal/core"
 	"FolderSynchronizer/internal/logging"
 	"FolderSynchronizer/internal/scheduler"
 	"FolderSynchronizer/internal/tray"
 
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-// ===== BUILD INFORMATION =====
-
-// version is set during build time and represents the current application version
-var version = "0.2.0"
-
 // ===== EMBEDDED ASSETS =====
 
 //go:embed assets/*
@@ -55,11 +51,24 @@ const (
 	// ICO file header validation
 	ICOHeaderSize = 4
 
+	// PNG file signature validation
+	PNGSignature = "\x89PNG\r\n\x1a\n"
+
 	// Browser opening delays
 	CommandRetryDelay = 50 * time.Millisecond
 
+	// Delay between startup syncs of scheduled pairs, avoiding a disk storm at boot
+	StartupSyncStagger = 5 * time.Second
+
 	// Application metadata
 	AppName = "FolderSynchronizer"
+
+	// Default log output: file only, rotated at 10 MB with 5 backups kept for 30 days
+	DefaultLogFileName   = "syncronizer.log"
+	DefaultLogLevel      = "info"
+	DefaultLogMaxSizeMB  = 10
+	DefaultLogMaxBackups = 5
+	DefaultLogMaxAgeDays = 30
 )
 
 // ===== COMMAND LINE CONFIGURATION =====
@@ -69,6 +78,23 @@ type config struct {
 	Listen     string // HTTP server listen address
 	ConfigPath string // Path to configuration file
 	NoTray     bool   // Whether to disable system tray
+	APIOnly    bool   // Whether to serve only the REST API without the web UI
+	Profile    string // Auto-start profile overriding the config's activeProfile
+	Version    bool   // Whether to print version information and exit
+
+	// HTTPS, overriding the config's TLS settings
+	TLSCert       string // Certificate file
+	TLSKey        string // Private key file
+	TLSSelfSigned bool   // Whether to generate a self-signed certificate
+
+	// Log output
+	LogLevel      string // Minimum log level (trace, debug, info, warn, error)
+	LogConsole    bool   // Whether to also write logs to stdout
+	LogPretty     bool   // Whether console logs are human-readable instead of JSON
+	LogFile       string // Log file name in the logs directory
+	LogMaxSizeMB  int    // Size at which the log file is rotated
+	LogMaxBackups int    // Rotated log files kept
+	LogMaxAgeDays int    // Age after which rotated log files are removed
 }
 
 // ===== MAIN APPLICATION ENTRY POINT =====
@@ -88,6 +114,10 @@ func main() {
 
 	// Parse command line arguments
 	appConfig := parseCommandLineArgs()
+	if appConfig.Version {
+		fmt.Println(AppName, buildinfo.Get())
+		return
+	}
 
 	// Initialize application paths and directories
 	paths, err := initializePaths(appConfig.ConfigPath)
@@ -96,7 +126,7 @@ func main() {
 	}
 
 	// Set up logging system
-	logger, err := initializeLogging(paths.LogsDir)
+	logger, err := initializeLogging(paths.LogsDir, appConfig)
 	if err != nil {
 		exitWithError("logging setup", err)
 	}
@@ -108,6 +138,22 @@ func main() {
 		exitWithError("load config", err)
 	}
 
+	// Command line flag enables API-only mode regardless of config
+	appConf.Overrides.APIOnly = appConfig.APIOnly
+
+	// Command line profile takes precedence over the configured one
+	appConf.Overrides.ActiveProfile = appConfig.Profile
+
+	// Command line TLS settings take precedence over the configured ones
+	if appConfig.TLSCert != "" || appConfig.TLSKey != "" {
+		if appConfig.TLSCert == "" || appConfig.TLSKey == "" {
+			exitWithError("tls setup", fmt.Errorf("-tls-cert and -tls-key must be used together"))
+		}
+		appConf.Overrides.TLSCertFile = appConfig.TLSCert
+		appConf.Overrides.TLSKeyFile = appConfig.TLSKey
+	}
+	appConf.Overrides.TLSSelfSigned = appConfig.TLSSelfSigned
+
 	// Log startup diagnostics
 	logStartupDiagnostics(appConfig.Listen, paths.ConfigFile, appConf)
 
@@ -120,8 +166,12 @@ func main() {
 	// Load tray icon from embedded assets
 	loadTrayIcon()
 
+	// Remove temporary copy files left behind by an interrupted previous run
+	core.SweepStaleTempFiles(appConf.Pairs)
+
 	// Auto-start enabled sync pairs
 	autoStartEnabledPairs(server, appConf)
+	server.MarkReady()
 
 	// Run application in appropriate mode (tray or headless)
 	runApplication(appConfig, httpServer, server)
@@ -136,9 +186,35 @@ func parseCommandLineArgs() config {
 	flag.StringVar(&appConfig.Listen, "listen", DefaultListenAddress,
 		"HTTP listen address, e.g. 127.0.0.1:8080")
 	flag.StringVar(&appConfig.ConfigPath, "config", "",
-		"Path to config.json (optional)")
+		"Path to config file, .json or .yaml/.yml (optional)")
 	flag.BoolVar(&appConfig.NoTray, "no-tray", false,
 		"Disable system tray icon")
+	flag.BoolVar(&appConfig.APIOnly, "api-only", false,
+		"Serve only the REST API (no embedded web UI)")
+	flag.StringVar(&appConfig.Profile, "profile", "",
+		"Auto-start only enabled pairs tagged with this profile")
+	flag.StringVar(&appConfig.TLSCert, "tls-cert", "",
+		"Serve HTTPS with this PEM certificate (requires -tls-key)")
+	flag.StringVar(&appConfig.TLSKey, "tls-key", "",
+		"PEM private key for -tls-cert")
+	flag.BoolVar(&appConfig.TLSSelfSigned, "tls-selfsigned", false,
+		"Serve HTTPS with a generated self-signed certificate when none is configured")
+	flag.BoolVar(&appConfig.Version, "version", false,
+		"Print version information and exit")
+	flag.StringVar(&appConfig.LogLevel, "log-level", DefaultLogLevel,
+		"Minimum log level: trace, debug, info, warn or error")
+	flag.BoolVar(&appConfig.LogConsole, "log-console", false,
+		"Also write logs to stdout (JSON unless -log-pretty)")
+	flag.BoolVar(&appConfig.LogPretty, "log-pretty", false,
+		"Human-readable console logs instead of JSON")
+	flag.StringVar(&appConfig.LogFile, "log-file", DefaultLogFileName,
+		"Log file name in the logs directory")
+	flag.IntVar(&appConfig.LogMaxSizeMB, "log-max-size", DefaultLogMaxSizeMB,
+		"Log file size in MB at which it is rotated")
+	flag.IntVar(&appConfig.LogMaxBackups, "log-max-backups", DefaultLogMaxBackups,
+		"Number of rotated log files kept")
+	flag.IntVar(&appConfig.LogMaxAgeDays, "log-max-age", DefaultLogMaxAgeDays,
+		"Days after which rotated log files are removed")
 
 	flag.Parse()
 	return appConfig
@@ -158,8 +234,19 @@ func initializePaths(configPath string) (cfg.Paths, error) {
 	return paths, nil
 }
 
-// initializeLogging sets up the logging system
-func initializeLogging(logsDir string) (interface{}, error) {
+// initializeLogging sets up the logging system from the command line log options
+func initializeLogging(logsDir string, appConfig config) (interface{}, error) {
+	level, err := logging.ParseLogLevel(appConfig.LogLevel)
+	if err != nil || appConfig.LogLevel == "" {
+		return nil, fmt.Errorf("invalid log level %q", appConfig.LogLevel)
+	}
+	if appConfig.LogFile == "" || filepath.Base(appConfig.LogFile) != appConfig.LogFile {
+		return nil, fmt.Errorf("invalid log file name %q", appConfig.LogFile)
+	}
+	if appConfig.LogMaxSizeMB < 0 || appConfig.LogMaxBackups < 0 || appConfig.LogMaxAgeDays < 0 {
+		return nil, fmt.Errorf("log rotation limits cannot be negative")
+	}
+
 	// Определяем путь к логам относительно исполняемого файла
 	execPath, err := os.Executable()
 	if err != nil {
@@ -170,17 +257,17 @@ func initializeLogging(logsDir string) (interface{}, error) {
 	execDir := filepath.Dir(execPath)
 	logsDir = filepath.Join(execDir, "logs")
 
-	// Создаем конфигурацию только для файлового логирования
+	// File logging always; console output (e.g. for journald or Docker) on request
 	config := &logging.Config{
 		LogsDir:    logsDir,
-		FileName:   "syncronizer.log",
-		MaxSizeMB:  10,
-		MaxBackups: 5,
-		MaxAgeDays: 30,
+		FileName:   appConfig.LogFile,
+		MaxSizeMB:  appConfig.LogMaxSizeMB,
+		MaxBackups: appConfig.LogMaxBackups,
+		MaxAgeDays: appConfig.LogMaxAgeDays,
 		Compress:   true,
-		Level:      zerolog.InfoLevel,
-		ConsoleOut: false, // Отключаем вывод в консоль
-		PrettyLog:  false,
+		Level:      level,
+		ConsoleOut: appConfig.LogConsole,
+		PrettyLog:  appConfig.LogPretty,
 	}
 
 	logger, err := logging.SetupWithConfig(config)
@@ -205,13 +292,18 @@ func loadConfiguration(configFile string, listen *string) (*cfg.Config, error) {
 // logStartupDiagnostics logs comprehensive startup information
 func logStartupDiagnostics(listenAddr, configFile string, conf *cfg.Config) {
 	log.Info().
-		Str("version", version).
+		Str("version", buildinfo.Version).
+		Str("commit", buildinfo.Get().Commit).
 		Str("listen", listenAddr).
 		Str("config", configFile).
 		Int("pairs", len(conf.Pairs)).
+		Bool("api_only", conf.ServeAPIOnly()).
+		Bool("tls", conf.TLSEnabled()).
+		Str("profile", conf.AutoStartProfile()).
 		Str("goos", runtime.GOOS).
 		Str("goarch", runtime.GOARCH).
 		Bool("tray_windows", tray.WindowsBuild).
+		Bool("tray_supported", tray.IsSupported()).
 		Bool("scheduler_enabled", true).
 		Msg("starting " + AppName + " with scheduler")
 }
@@ -223,7 +315,11 @@ func initializeServer(paths cfg.Paths, conf *cfg.Config, listenAddr string) (*ap
 		return nil, nil, err
 	}
 
-	httpServer := server.StartHTTP(listenAddr)
+	httpServer, err := server.StartHTTP(listenAddr)
+	if err != nil {
+		server.Close()
+		return nil, nil, err
+	}
 	return server, httpServer, nil
 }
 
@@ -264,8 +360,8 @@ func logIconDiagnostics(usedAsset string) {
 	// Log warnings for problematic icons
 	if len(trayIcon) == 0 {
 		log.Warn().Msg("tray icon asset missing or empty; tray will show default placeholder")
-	} else if !isValidICOIcon(trayIcon) {
-		log.Warn().Msg("tray icon asset is not a valid .ico; icon will be skipped")
+	} else if !isValidICOIcon(trayIcon) && !isValidPNGIcon(trayIcon) {
+		log.Warn().Msg("tray icon asset is not a valid .ico or .png; icon will be skipped")
 	}
 }
 
@@ -279,15 +375,32 @@ func isValidICOIcon(iconData []byte) bool {
 		iconData[2] == 0x01 && iconData[3] == 0x00
 }
 
+// isValidPNGIcon checks if the icon data has a PNG signature (used by the macOS menu bar)
+func isValidPNGIcon(iconData []byte) bool {
+	return bytes.HasPrefix(iconData, []byte(PNGSignature))
+}
+
 // ===== SYNC PAIR MANAGEMENT =====
 
-// autoStartEnabledPairs automatically starts all enabled sync pairs
+// autoStartEnabledPairs automatically starts all enabled sync pairs, then
+// triggers the startup syncs of scheduled pairs (see runsOnStartup)
 func autoStartEnabledPairs(server *api.Server, conf *cfg.Config) {
+	var startupSyncs []string
+
 	for _, pair := range conf.Pairs {
 		if !pair.Enabled {
 			continue
 		}
 
+		// Skip pairs outside the active profile
+		if !cfg.MatchesProfile(pair, conf.AutoStartProfile()) {
+			log.Info().
+				Str("pair", pair.ID).
+				Str("profile", conf.AutoStartProfile()).
+				Msg("pair not in active profile, not auto-started")
+			continue
+		}
+
 		// Set default schedule for legacy configurations
 		if pair.Schedule.Type == "" {
 			pair.Schedule = scheduler.NewWatcherSchedule()
@@ -303,6 +416,42 @@ func autoStartEnabledPairs(server *api.Server, conf *cfg.Config) {
 				Str("pair", pair.ID).
 				Str("schedule", string(pair.Schedule.Type)).
 				Msg("auto-started sync pair")
+
+			if runsOnStartup(pair, conf) {
+				startupSyncs = append(startupSyncs, pair.ID)
+			}
+		}
+	}
+
+	if len(startupSyncs) > 0 {
+		go runStartupSyncs(server, startupSyncs)
+	}
+}
+
+// runsOnStartup reports whether a started pair gets an immediate sync in addition
+// to its schedule. Watcher and poll pairs are excluded since they scan when they start;
+// SyncOnStartup covers interval, cron, once and custom schedules, while RunOnStartup
+// also applies to pairs with a disabled (manual) schedule.
+func runsOnStartup(pair *cfg.Pair, conf *cfg.Config) bool {
+	if pair.Paused || pair.Schedule.Type == scheduler.ScheduleTypeWatcher || pair.Schedule.Type == scheduler.ScheduleTypePoll {
+		return false
+	}
+	if pair.RunOnStartup {
+		return true
+	}
+	return conf.SyncOnStartup && pair.Schedule.Type != scheduler.ScheduleTypeDisabled
+}
+
+// runStartupSyncs triggers one sync per pair, StartupSyncStagger apart
+func runStartupSyncs(server *api.Server, pairIDs []string) {
+	for i, id := range pairIDs {
+		if i > 0 {
+			time.Sleep(StartupSyncStagger)
+		}
+
+		log.Info().Str("pair", id).Msg("startup sync")
+		if err := server.PairManager.SyncPairNow(id); err != nil {
+			log.Error().Str("pair", id).Err(err).Msg("startup sync failed")
 		}
 	}
 }
@@ -311,10 +460,17 @@ func autoStartEnabledPairs(server *api.Server, conf *cfg.Config) {
 
 // runApplication runs the application in either tray mode or headless mode
 func runApplication(appConfig config, httpServer *http.Server, server *api.Server) {
+	// Reload configuration on SIGHUP (Unix only)
+	watchReloadSignals(server)
+
 	if appConfig.NoTray {
 		runHeadlessMode(httpServer, server)
+	} else if !tray.IsSupported() {
+		log.Warn().Str("goos", runtime.GOOS).Msg("system tray not available, running headless")
+		runHeadlessMode(httpServer, server)
 	} else {
-		runTrayMode(appConfig.Listen, httpServer, server)
+		// The bound address may differ from the configured one (listenPortFallback)
+		runTrayMode(httpServer.Addr, httpServer, server)
 	}
 }
 
@@ -334,6 +490,26 @@ func runHeadlessMode(httpServer *http.Server, server *api.Server) {
 	gracefulShutdown(httpServer, server)
 }
 
+// watchReloadSignals reloads the configuration and reconciles pairs whenever
+// a reload signal is received. Does nothing on platforms without one.
+func watchReloadSignals(server *api.Server) {
+	if len(reloadSignals) == 0 {
+		return
+	}
+
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, reloadSignals...)
+
+	go func() {
+		for sig := range reloadChan {
+			log.Info().Str("signal", sig.String()).Msg("reload signal received")
+			if _, err := server.ReloadConfig(); err != nil {
+				log.Error().Err(err).Msg("configuration reload failed, keeping current configuration")
+			}
+		}
+	}()
+}
+
 // runTrayMode runs the application with system tray integration
 func runTrayMode(listenAddr string, httpServer *http.Server, server *api.Server) {
 	log.Info().Msg("running with system tray integration")
@@ -349,7 +525,7 @@ func runTrayMode(listenAddr string, httpServer *http.Server, server *api.Server)
 func createTrayCallbacks(listenAddr string, httpServer *http.Server, server *api.Server) tray.Callbacks {
 	return tray.Callbacks{
 		OnOpenUI: func() {
-			url := buildLocalURL(listenAddr)
+			url := buildLocalURL(listenAddr, server.Cfg.TLSEnabled())
 			if err := openBrowser(url); err != nil {
 				log.Error().
 					Err(err).
@@ -401,6 +577,9 @@ func createTrayCallbacks(listenAddr string, httpServer *http.Server, server *api
 func gracefulShutdown(httpServer *http.Server, server *api.Server) {
 	log.Info().Msg("initiating graceful shutdown")
 
+	// Let running syncs finish; the API stays up meanwhile to report progress
+	server.Drain()
+
 	// Shutdown HTTP server
 	server.ShutdownHTTP(httpServer)
 
@@ -412,8 +591,13 @@ func gracefulShutdown(httpServer *http.Server, server *api.Server) {
 
 // ===== URL AND BROWSER UTILITIES =====
 
-// buildLocalURL constructs a local URL from the listen address
-func buildLocalURL(listenAddr string) string {
+// buildLocalURL constructs a local URL from the listen address, https when the server uses TLS
+func buildLocalURL(listenAddr string, secure bool) string {
+	scheme := "http"
+	if secure {
+		scheme = "https"
+	}
+
 	host, port, err := net.SplitHostPort(listenAddr)
 	if err != nil {
 		// Try lenient parsing for complex addresses like ::1:8080
@@ -421,14 +605,14 @@ func buildLocalURL(listenAddr string) string {
 			host = listenAddr[:idx]
 			port = listenAddr[idx+1:]
 		} else {
-			return "http://127.0.0.1/"
+			return scheme + "://127.0.0.1/"
 		}
 	}
 
 	// Normalize host for local access
 	localHost := normalizeHostForLocal(host)
 
-	return fmt.Sprintf("http://%s:%s/", localHost, port)
+	return fmt.Sprintf("%s://%s:%s/", scheme, localHost, port)
 }
 
 // normalizeHostForLocal converts bind addresses to localhost for browser opening
@@ -490,11 +674,8 @@ func openBrowserWindows(url string) error {
 
 // performSyncAll executes synchronization for all enabled pairs
 func performSyncAll(server *api.Server) {
-	// Get current pairs safely
-	server.CfgMu.Lock()
-	pairs := make([]*cfg.Pair, len(server.Cfg.Pairs))
-	copy(pairs, server.Cfg.Pairs)
-	server.CfgMu.Unlock()
+	// Enabled, unpaused pairs, as for the API's sync all
+	pairs := server.SyncAllPairs()
 
 	var totalFiles int
 	var totalBytes int64
@@ -503,13 +684,9 @@ func performSyncAll(server *api.Server) {
 	log.Info().Int("total_pairs", len(pairs)).Msg("starting sync all operation")
 
 	for _, pair := range pairs {
-		if !pair.Enabled {
-			continue
-		}
-
 		log.Debug().Str("pair", pair.ID).Msg("syncing pair")
 
-		copier := &core.Copier{}
+		copier := server.PairManager.NewCopier()
 		files, bytes, err := copier.CompareAndSync(context.Background(), pair)
 
 		if err != nil {
@@ -541,6 +718,7 @@ func performSyncAll(server *api.Server) {
 
 // exitWithError logs an error message and exits the application
 func exitWithError(operation string, err error) {
+	log.Error().Err(err).Msg(operation + " failed")
 	fmt.Printf("%s: %v\n", operation, err)
 	os.Exit(1)
 }
@@ -0,0 +1,12 @@
+//go:build !windows
+
+// Package main provides Unix signal handling for the FolderSynchronizer application.
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignals are the signals that trigger a configuration reload
+var reloadSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,75 @@
+//go:build !windows
+
+package main
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"FolderSynchronizer/internal/api"
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// testPair returns an enabled pair on an hourly interval over fresh directories
+func testPair(t *testing.T, id string) *cfg.Pair {
+	t.Helper()
+	return &cfg.Pair{
+		ID:       id,
+		Source:   t.TempDir(),
+		Target:   t.TempDir(),
+		Enabled:  true,
+		Schedule: scheduler.NewCronSchedule("0 0 0 * * *"),
+	}
+}
+
+func TestSIGHUPReloadsConfig(t *testing.T) {
+	paths, err := cfg.ResolvePaths(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, removed := testPair(t, "kept"), testPair(t, "removed")
+	conf := &cfg.Config{Pairs: []*cfg.Pair{kept, removed}}
+	if err := cfg.Save(paths.ConfigFile, conf); err != nil {
+		t.Fatal(err)
+	}
+	conf, err = cfg.Load(paths.ConfigFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, err := api.NewServer(paths, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	for _, pair := range conf.Pairs {
+		if err := server.PairManager.StartPair(pair); err != nil {
+			t.Fatal(err)
+		}
+	}
+	watchReloadSignals(server)
+
+	// Edit the file on disk: drop one pair and add another
+	added := testPair(t, "added")
+	if err := cfg.Save(paths.ConfigFile, &cfg.Config{Pairs: []*cfg.Pair{kept, added}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !server.PairManager.IsPairRunning("added") || server.PairManager.IsPairRunning("removed") {
+		if time.Now().After(deadline) {
+			t.Fatalf("pairs not reconciled after SIGHUP: added running %v, removed running %v",
+				server.PairManager.IsPairRunning("added"), server.PairManager.IsPairRunning("removed"))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !server.PairManager.IsPairRunning("kept") {
+		t.Error("unchanged pair stopped by the reload")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+// Package main provides Windows signal handling for the FolderSynchronizer application.
+package main
+
+import "os"
+
+// reloadSignals is empty on Windows, which has no SIGHUP equivalent
+var reloadSignals []os.Signal
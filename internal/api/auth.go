@@ -0,0 +1,91 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements optional token and HTTP basic authentication for the API.
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== AUTHENTICATION CONSTANTS =====
+
+const (
+	// APIKeyHeader is an alternative to "Authorization: Bearer <token>"
+	APIKeyHeader = "X-API-Key"
+
+	// AuthRealm is announced to browsers for basic authentication
+	AuthRealm = "FolderSynchronizer"
+)
+
+// ===== AUTHENTICATION MIDDLEWARE =====
+
+// authEnabled reports whether any credential is configured.
+func (s *Server) authEnabled() bool {
+	return s.Cfg.AuthToken != "" || s.Cfg.BasicAuthUser != ""
+}
+
+// requiresAuth reports whether a request is protected. The REST API, metrics and
+// the verbose health report are protected; the /healthz and /readyz probes and
+// the static UI are not.
+func requiresAuth(r *http.Request) bool {
+	path := r.URL.Path
+	return strings.HasPrefix(path, "/api/") || path == "/metrics" || isVerboseHealth(r)
+}
+
+// requireAuth wraps a handler and rejects requests to protected paths with
+// 401 when credentials are configured but missing or wrong.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authEnabled() || !requiresAuth(r) || s.isAuthorized(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		log.Warn().
+			Str("path", r.URL.Path).
+			Str("remote", r.RemoteAddr).
+			Msg("unauthorized API request")
+
+		if s.Cfg.BasicAuthUser != "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="`+AuthRealm+`", charset="UTF-8"`)
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
+
+// isAuthorized checks the request against the configured token and basic credentials.
+func (s *Server) isAuthorized(r *http.Request) bool {
+	if token := s.Cfg.AuthToken; token != "" {
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && secureEqual(bearer, token) {
+			return true
+		}
+		if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" && secureEqual(apiKey, token) {
+			return true
+		}
+	}
+
+	if s.Cfg.BasicAuthUser != "" {
+		if user, pass, ok := r.BasicAuth(); ok {
+			// Evaluate both comparisons to avoid leaking which one failed
+			userOK := secureEqual(user, s.Cfg.BasicAuthUser)
+			passOK := secureEqual(pass, s.Cfg.BasicAuthPass)
+			if userOK && passOK {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// secureEqual compares two secrets in constant time. Both values are hashed
+// first so the comparison doesn't leak their lengths.
+func secureEqual(given, expected string) bool {
+	givenHash := sha256.Sum256([]byte(given))
+	expectedHash := sha256.Sum256([]byte(expected))
+	return subtle.ConstantTimeCompare(givenHash[:], expectedHash[:]) == 1
+}
@@ -0,0 +1,121 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements the activity feed: recent events as JSON and a live SSE tail.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"FolderSynchronizer/internal/core"
+)
+
+// ===== EVENT FEED CONSTANTS =====
+
+// EventStreamKeepAlive is how often an idle event stream sends a comment line,
+// keeping proxies from closing the connection
+const EventStreamKeepAlive = 30 * time.Second
+
+// ===== EVENT FEED ENDPOINTS =====
+
+// handleEvents returns the recorded events, oldest first. ?since=<RFC3339 time>
+// limits the result to newer events.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, err := parseSince(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, core.Events.Since(since))
+}
+
+// handleEventStream tails the event feed as server-sent events. Events newer
+// than ?since are sent first, then new events as they happen.
+func (s *Server) handleEventStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	since, err := parseSince(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Subscribe before reading the backlog so no event falls in between
+	events, unsubscribe := core.Events.Subscribe()
+	defer unsubscribe()
+
+	noWriteTimeout(w)
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	var lastSeq uint64
+	for _, event := range core.Events.Since(since) {
+		if writeServerSentEvent(w, event) != nil {
+			return
+		}
+		lastSeq = event.Seq
+	}
+	if controller.Flush() != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(EventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if event.Seq <= lastSeq {
+				continue // Already sent with the backlog
+			}
+			if writeServerSentEvent(w, event) != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+		if controller.Flush() != nil {
+			return
+		}
+	}
+}
+
+// parseSince reads the optional RFC3339 since query parameter (zero when absent)
+func parseSince(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("since")
+	if value == "" {
+		return time.Time{}, nil
+	}
+	since, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q (want RFC3339)", value)
+	}
+	return since, nil
+}
+
+// writeServerSentEvent writes one event in SSE format, using its sequence number as ID
+func writeServerSentEvent(w http.ResponseWriter, event core.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
@@ -0,0 +1,89 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements the health endpoint with per-component status.
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"FolderSynchronizer/internal/core"
+)
+
+// ===== HEALTH STRUCTURES =====
+
+// HealthReport describes the state of the server's subsystems for /healthz?verbose=1
+type HealthReport struct {
+	Status           string  `json:"status"`           // "ok", or "unavailable" when a critical subsystem is down
+	SchedulerRunning bool    `json:"schedulerRunning"` // Whether the task scheduler is running
+	ActiveWatchers   int     `json:"activeWatchers"`   // File watchers currently running
+	EnabledPairs     int     `json:"enabledPairs"`     // Pairs enabled in the configuration
+	PairsInError     int     `json:"pairsInError"`     // Pairs whose latest run, initial sync or source check failed
+	SyncsRunning     int     `json:"syncsRunning"`     // Sync runs holding a slot of the maxConcurrentSyncs limit
+	SyncsQueued      int     `json:"syncsQueued"`      // Sync runs waiting for a slot
+	UptimeSeconds    float64 `json:"uptimeSeconds"`    // Time since the server was created
+}
+
+// ===== HEALTH ENDPOINT =====
+
+// handleHealth answers the liveness probe with a plain "ok", or with
+// ?verbose=1 a JSON HealthReport. The verbose form returns 503 while the
+// scheduler is down, so monitoring can tell a stuck process from a working one.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !isVerboseHealth(r) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+		return
+	}
+
+	report := s.healthReport()
+	if !report.SchedulerRunning {
+		report.Status = "unavailable"
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	writeJSON(w, report)
+}
+
+// isVerboseHealth reports whether a request asks /healthz for the HealthReport.
+// Unlike the plain probe it requires credentials and an allowed network.
+func isVerboseHealth(r *http.Request) bool {
+	verbose, _ := strconv.ParseBool(r.URL.Query().Get("verbose"))
+	return verbose && r.URL.Path == "/healthz"
+}
+
+// healthReport collects the current component status
+func (s *Server) healthReport() *HealthReport {
+	report := &HealthReport{
+		Status:        "ok",
+		UptimeSeconds: time.Since(s.startedAt).Round(time.Second).Seconds(),
+	}
+	s.CfgMu.Lock()
+	for _, pair := range s.Cfg.Pairs {
+		if pair.Enabled {
+			report.EnabledPairs++
+		}
+	}
+	s.CfgMu.Unlock()
+
+	if s.PairManager == nil {
+		return report
+	}
+	syncs := s.PairManager.SyncSlotsUsage()
+	report.SyncsRunning, report.SyncsQueued = syncs.InUse, syncs.Waiting
+	report.SchedulerRunning = s.PairManager.SchedulerRunning()
+	for _, status := range s.PairManager.ListPairStatuses() {
+		if status.WatcherActive {
+			report.ActiveWatchers++
+		}
+		if pairInError(status) {
+			report.PairsInError++
+		}
+	}
+	return report
+}
+
+// pairInError reports whether a pair's latest run, initial sync or source check failed
+func pairInError(status *core.PairStatus) bool {
+	return status.LastError != "" || status.InitialSyncError != "" || status.WatcherHalted || status.SourceMissing
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"FolderSynchronizer/internal/core"
+)
+
+// ===== READINESS =====
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *Server)
+		want  int
+	}{
+		{"initializing", func(s *Server) {}, http.StatusServiceUnavailable},
+		{"ready", func(s *Server) { s.MarkReady() }, http.StatusOK},
+		{"scheduler stopped", func(s *Server) { s.MarkReady(); s.PairManager.Close() }, http.StatusServiceUnavailable},
+		{"no pair manager", func(s *Server) { s.MarkReady(); s.PairManager = nil }, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			tt.setup(s)
+
+			if recorder := serve(t, s, http.MethodGet, "/readyz", "", nil); recorder.Code != tt.want {
+				t.Errorf("GET /readyz = %d, want %d", recorder.Code, tt.want)
+			}
+			// Liveness doesn't depend on readiness
+			if recorder := serve(t, s, http.MethodGet, "/healthz", "", nil); recorder.Code != http.StatusOK || recorder.Body.String() != "ok" {
+				t.Errorf("GET /healthz = %d %q, want 200 ok", recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
+
+func TestHealthzVerbose(t *testing.T) {
+	pair := newTestPair(t, "docs")
+	pair.Enabled = true
+	s := newTestServer(t, pair, newTestPair(t, "disabled"))
+
+	recorder := serve(t, s, http.MethodGet, "/healthz?verbose=1", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /healthz?verbose=1 = %d, want 200", recorder.Code)
+	}
+	var report HealthReport
+	decodeJSON(t, recorder, &report)
+	if report.Status != "ok" || !report.SchedulerRunning || report.EnabledPairs != 1 {
+		t.Errorf("report = %+v, want ok with a running scheduler and 1 enabled pair", report)
+	}
+
+	s.PairManager.Close()
+	recorder = serve(t, s, http.MethodGet, "/healthz?verbose=1", "", nil)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("verbose health with a stopped scheduler = %d, want 503", recorder.Code)
+	}
+	decodeJSON(t, recorder, &report)
+	if report.Status != "unavailable" {
+		t.Errorf("status = %q, want unavailable", report.Status)
+	}
+}
+
+func TestVerboseHealthRequiresAuthAndAllowedNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		allowed string
+		token   string
+		want    int
+	}{
+		{"probe from anywhere", "/healthz", "10.0.0.0/8", "", http.StatusOK},
+		{"verbose without credentials", "/healthz?verbose=1", "192.0.2.0/24", "", http.StatusUnauthorized},
+		{"verbose from a disallowed network", "/healthz?verbose=1", "10.0.0.0/8", "Bearer token", http.StatusForbidden},
+		{"verbose with credentials", "/healthz?verbose=1", "192.0.2.0/24", "Bearer token", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.Cfg.AuthToken = "token"
+			networks, err := parseAllowedNetworks([]string{tt.allowed})
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.allowedNets = networks
+			handler := s.restrictClients(s.requireAuth(s.routes()))
+
+			// httptest requests come from 192.0.2.1
+			request := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.token != "" {
+				request.Header.Set("Authorization", tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.url, recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+// ===== SYNC QUEUE =====
+
+func TestHealthReportsSyncSlots(t *testing.T) {
+	s := newTestServer(t)
+	s.PairManager.SetMaxConcurrentSyncs(3)
+
+	recorder := serve(t, s, http.MethodGet, "/healthz?verbose=1", "", nil)
+	var report map[string]any
+	decodeJSON(t, recorder, &report)
+	for _, field := range []string{"syncsRunning", "syncsQueued"} {
+		if value, ok := report[field]; !ok || value != float64(0) {
+			t.Errorf("%s = %v, want 0 while idle", field, value)
+		}
+	}
+
+	recorder = serve(t, s, http.MethodGet, "/api/info", "", nil)
+	var info struct {
+		Syncs core.LimiterUsage `json:"syncs"`
+	}
+	decodeJSON(t, recorder, &info)
+	if info.Syncs.Limit != 3 {
+		t.Errorf("info syncs = %+v, want limit 3", info.Syncs)
+	}
+}
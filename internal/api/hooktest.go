@@ -0,0 +1,56 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements testing a single hook definition before it is saved to a pair.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/core"
+)
+
+// ===== HOOK TEST STRUCTURES =====
+
+// HookTestRequest is the body of POST /api/hooks/test
+type HookTestRequest struct {
+	Hook   *cfg.Hook           `json:"hook"`   // Hook definition to run
+	Values core.HookTestValues `json:"values"` // Sample template values
+}
+
+// ===== HOOK TEST ENDPOINT =====
+
+// handleHookTest runs one hook definition with sample template values and
+// returns its HookStatus, without syncing anything or touching a pair.
+func (s *Server) handleHookTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req HookTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.Hook == nil {
+		http.Error(w, "hook is required", http.StatusBadRequest)
+		return
+	}
+	if err := cfg.ValidateHook(req.Hook); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, err := core.TestHook(r.Context(), req.Hook, req.Values)
+	if errors.Is(err, core.ErrUnknownHookType) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, status)
+}
@@ -0,0 +1,216 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements Idempotency-Key support for sync-triggering endpoints.
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ===== IDEMPOTENCY CONSTANTS =====
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-chosen key
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotencyReplayedHeader marks responses served from the idempotency cache
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	// Cache limits for remembered keys
+	IdempotencyKeyTTL    = 10 * time.Minute // How long a key's outcome is remembered
+	IdempotencyCacheSize = 256              // Maximum number of remembered keys (LRU)
+	MaxIdempotencyKeyLen = 255              // Maximum accepted key length
+)
+
+// ===== IDEMPOTENCY CACHE STRUCTURES =====
+
+// idempotencyEntry holds the recorded outcome of a request for a single key.
+// The done channel is closed once the first request has finished writing its response.
+type idempotencyEntry struct {
+	key     string        // Cache key (method + path and query + client key)
+	status  int           // Recorded HTTP status code
+	header  http.Header   // Recorded response headers
+	body    []byte        // Recorded response body
+	expires time.Time     // Expiration time of the recorded outcome
+	done    chan struct{} // Closed when the outcome is available
+}
+
+// idempotencyCache is a small in-memory LRU of recently seen idempotency keys.
+type idempotencyCache struct {
+	mutex    sync.Mutex               // Thread-safe access to entries
+	ttl      time.Duration            // Lifetime of recorded outcomes
+	capacity int                      // Maximum number of entries
+	order    *list.List               // LRU order, most recently used at front
+	items    map[string]*list.Element // Entries by key
+}
+
+// newIdempotencyCache creates an idempotency cache with the given TTL and capacity.
+func newIdempotencyCache(ttl time.Duration, capacity int) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:      ttl,
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// begin looks up a key and returns its entry. If the key is unknown or expired,
+// a new pending entry is created and owner is true: the caller must run the work
+// and then call complete or abandon.
+func (c *idempotencyCache) begin(key string) (entry *idempotencyEntry, owner bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, exists := c.items[key]; exists {
+		existing := element.Value.(*idempotencyEntry)
+		pending := existing.expires.IsZero()
+		if pending || time.Now().Before(existing.expires) {
+			c.order.MoveToFront(element)
+			return existing, false
+		}
+
+		// Expired outcome, forget it
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+
+	entry = &idempotencyEntry{key: key, done: make(chan struct{})}
+	c.items[key] = c.order.PushFront(entry)
+
+	// Evict least recently used entries that are already completed
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest == nil || oldest.Value.(*idempotencyEntry).expires.IsZero() {
+			break
+		}
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*idempotencyEntry).key)
+	}
+
+	return entry, true
+}
+
+// complete records the outcome of a pending entry and releases waiting requests.
+func (c *idempotencyCache) complete(entry *idempotencyEntry, status int, header http.Header, body []byte) {
+	c.mutex.Lock()
+	entry.status = status
+	entry.header = header
+	entry.body = body
+	entry.expires = time.Now().Add(c.ttl)
+	c.mutex.Unlock()
+
+	close(entry.done)
+}
+
+// abandon removes a pending entry without recording an outcome, so that a
+// retry with the same key runs the work again (used for server errors).
+func (c *idempotencyCache) abandon(entry *idempotencyEntry) {
+	c.mutex.Lock()
+	if element, exists := c.items[entry.key]; exists && element.Value == entry {
+		c.order.Remove(element)
+		delete(c.items, entry.key)
+	}
+	c.mutex.Unlock()
+
+	close(entry.done)
+}
+
+// ===== RESPONSE RECORDING =====
+
+// responseCapture wraps http.ResponseWriter to record the status and body
+// written by a handler while still passing them through to the client.
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rc *responseCapture) WriteHeader(code int) {
+	rc.status = code
+	rc.ResponseWriter.WriteHeader(code)
+}
+
+func (rc *responseCapture) Write(b []byte) (int, error) {
+	rc.body.Write(b)
+	return rc.ResponseWriter.Write(b)
+}
+
+// ===== IDEMPOTENCY MIDDLEWARE =====
+
+// idempotent wraps a sync-triggering handler with Idempotency-Key support.
+// Requests without the header are executed as usual. Requests carrying a key
+// that was seen within the TTL receive the recorded response instead of
+// re-running the work; concurrent duplicates wait for the first one to finish.
+// Server errors (5xx) are not remembered so that clients can retry them.
+func (s *Server) idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		clientKey := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+		if clientKey == "" || s.idempotency == nil {
+			next(w, r)
+			return
+		}
+
+		if len(clientKey) > MaxIdempotencyKeyLen {
+			http.Error(w, "idempotency key too long", http.StatusBadRequest)
+			return
+		}
+
+		// Scope keys per endpoint and query so the same key can't replay a
+		// different operation, such as ?async=true after a synchronous sync all
+		key := r.Method + " " + r.URL.RequestURI() + " " + clientKey
+
+		entry, owner := s.idempotency.begin(key)
+		if !owner {
+			select {
+			case <-entry.done:
+			case <-r.Context().Done():
+				return
+			}
+
+			if entry.expires.IsZero() {
+				// The original request failed and was abandoned, run again
+				s.idempotent(next)(w, r)
+				return
+			}
+
+			writeRecordedResponse(w, entry)
+			return
+		}
+
+		// A handler that panics never finishes; abandon its entry so duplicates
+		// waiting on it (and later retries) run the work again
+		finished := false
+		defer func() {
+			if !finished {
+				s.idempotency.abandon(entry)
+			}
+		}()
+
+		capture := &responseCapture{ResponseWriter: w, status: http.StatusOK}
+		next(capture, r)
+		finished = true
+
+		if capture.status >= http.StatusInternalServerError {
+			s.idempotency.abandon(entry)
+			return
+		}
+
+		s.idempotency.complete(entry, capture.status, w.Header().Clone(), capture.body.Bytes())
+	}
+}
+
+// writeRecordedResponse replays a recorded response to the client.
+func writeRecordedResponse(w http.ResponseWriter, entry *idempotencyEntry) {
+	for key, values := range entry.header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(entry.status)
+	_, _ = w.Write(entry.body)
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingHandler returns a handler answering with status and the number of
+// times it ran, and the counter itself
+func countingHandler(status int) (http.HandlerFunc, *atomic.Int32) {
+	runs := &atomic.Int32{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "run %d", runs.Add(1))
+	}, runs
+}
+
+// callIdempotent sends a POST with an optional Idempotency-Key through handler
+func callIdempotent(handler http.HandlerFunc, path, key string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, path, nil)
+	if key != "" {
+		request.Header.Set(IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	handler(recorder, request)
+	return recorder
+}
+
+func TestIdempotentRunsWorkOnce(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		first, again [2]string // path and key of both requests
+		wantRuns     int32
+	}{
+		{"same key replays", http.StatusOK, [2]string{"/sync", "k1"}, [2]string{"/sync", "k1"}, 1},
+		{"different keys run twice", http.StatusOK, [2]string{"/sync", "k1"}, [2]string{"/sync", "k2"}, 2},
+		{"no key runs twice", http.StatusOK, [2]string{"/sync", ""}, [2]string{"/sync", ""}, 2},
+		{"key scoped per endpoint", http.StatusOK, [2]string{"/sync", "k1"}, [2]string{"/other", "k1"}, 2},
+		{"key scoped per query", http.StatusOK, [2]string{"/sync", "k1"}, [2]string{"/sync?async=true", "k1"}, 2},
+		{"client errors are remembered", http.StatusConflict, [2]string{"/sync", "k1"}, [2]string{"/sync", "k1"}, 1},
+		{"server errors are retried", http.StatusInternalServerError, [2]string{"/sync", "k1"}, [2]string{"/sync", "k1"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			next, runs := countingHandler(tt.status)
+			handler := s.idempotent(next)
+
+			first := callIdempotent(handler, tt.first[0], tt.first[1])
+			again := callIdempotent(handler, tt.again[0], tt.again[1])
+
+			if got := runs.Load(); got != tt.wantRuns {
+				t.Fatalf("handler ran %d times, want %d", got, tt.wantRuns)
+			}
+			replayed := again.Header().Get(IdempotencyReplayedHeader) == "true"
+			if replayed != (tt.wantRuns == 1) {
+				t.Errorf("second response replayed = %v", replayed)
+			}
+			if tt.wantRuns == 1 && (again.Code != first.Code || again.Body.String() != first.Body.String()) {
+				t.Errorf("replayed %d %q, want %d %q", again.Code, again.Body.String(), first.Code, first.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdempotentRejectsLongKey(t *testing.T) {
+	s := newTestServer(t)
+	next, runs := countingHandler(http.StatusOK)
+
+	recorder := callIdempotent(s.idempotent(next), "/sync", string(make([]byte, MaxIdempotencyKeyLen+1)))
+	if recorder.Code != http.StatusBadRequest || runs.Load() != 0 {
+		t.Errorf("long key: status %d after %d runs, want 400 without running", recorder.Code, runs.Load())
+	}
+}
+
+func TestIdempotentConcurrentDuplicatesWait(t *testing.T) {
+	s := newTestServer(t)
+	release := make(chan struct{})
+	var runs atomic.Int32
+	handler := s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		runs.Add(1)
+		<-release
+		fmt.Fprint(w, "done")
+	})
+
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, 4)
+	for i := range recorders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recorders[i] = callIdempotent(handler, "/sync", "same")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := runs.Load(); got != 1 {
+		t.Errorf("handler ran %d times, want 1", got)
+	}
+	for i, recorder := range recorders {
+		if recorder.Body.String() != "done" {
+			t.Errorf("request %d got %q", i, recorder.Body.String())
+		}
+	}
+}
+
+func TestIdempotencyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newIdempotencyCache(time.Minute, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		entry, owner := cache.begin(key)
+		if !owner {
+			t.Fatalf("key %s already known", key)
+		}
+		cache.complete(entry, http.StatusOK, nil, nil)
+	}
+
+	if _, owner := cache.begin("a"); !owner {
+		t.Error("oldest key a survived beyond capacity")
+	}
+	if _, owner := cache.begin("c"); owner {
+		t.Error("newest key c was evicted")
+	}
+}
+
+func TestSyncAllIdempotencyKey(t *testing.T) {
+	s := newTestServer(t)
+	headers := map[string]string{IdempotencyKeyHeader: "retry-1"}
+
+	first := serve(t, s, http.MethodPost, "/api/syncAll", "", headers)
+	again := serve(t, s, http.MethodPost, "/api/syncAll", "", headers)
+	if first.Code != http.StatusOK || again.Code != http.StatusOK {
+		t.Fatalf("statuses %d and %d", first.Code, again.Code)
+	}
+	if again.Header().Get(IdempotencyReplayedHeader) != "true" || again.Body.String() != first.Body.String() {
+		t.Errorf("retry was not replayed: %q vs %q", again.Body.String(), first.Body.String())
+	}
+}
+
+func TestIdempotentPanicAbandonsEntry(t *testing.T) {
+	s := newTestServer(t)
+	var runs atomic.Int32
+	handler := s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+		if runs.Add(1) == 1 {
+			panic("handler failed")
+		}
+		fmt.Fprint(w, "ok")
+	})
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("handler panic was swallowed")
+			}
+		}()
+		callIdempotent(handler, "/sync", "k1")
+	}()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- callIdempotent(handler, "/sync", "k1") }()
+	select {
+	case recorder := <-done:
+		if recorder.Body.String() != "ok" || runs.Load() != 2 {
+			t.Errorf("retry got %q after %d runs, want a fresh run", recorder.Body.String(), runs.Load())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry blocked on the panicked request's entry")
+	}
+}
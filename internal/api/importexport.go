@@ -0,0 +1,196 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements exporting pair definitions and importing them on another machine.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	cfg "FolderSynchronizer/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== IMPORT/EXPORT CONSTANTS =====
+
+const (
+	// ExportFileName is the download name suggested for exported pairs
+	ExportFileName = "pairs-export.json"
+
+	// Import modes (?mode=)
+	ImportModeMerge   = "merge"   // Add imported pairs to the existing ones (default)
+	ImportModeReplace = "replace" // Remove existing pairs missing from the import
+
+	// ID collision handling (?onConflict=)
+	ImportConflictSkip      = "skip"      // Keep the existing pair (default)
+	ImportConflictOverwrite = "overwrite" // Replace the existing pair with the imported one
+)
+
+// ImportResult lists the pairs affected by an import
+type ImportResult struct {
+	Added   []string `json:"added"`   // Imported pairs that didn't exist
+	Updated []string `json:"updated"` // Existing pairs overwritten by imported ones
+	Skipped []string `json:"skipped"` // Imported pairs skipped because the ID exists
+	Removed []string `json:"removed"` // Existing pairs removed in replace mode
+}
+
+// ===== IMPORT/EXPORT ENDPOINTS =====
+
+// handleExportPairs returns the pair definitions as a downloadable JSON array,
+// without machine-specific global settings such as the listen address. Paths
+// loaded from environment references are exported as the references.
+func (s *Server) handleExportPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.CfgMu.Lock()
+	pairs := make([]cfg.Pair, 0, len(s.Cfg.Pairs))
+	for _, pair := range s.Cfg.Pairs {
+		pairs = append(pairs, *s.Cfg.PairWithEnvTemplates(pair))
+	}
+	s.CfgMu.Unlock()
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", ExportFileName))
+	writeJSON(w, pairs)
+}
+
+// handleImportPairs merges or replaces pairs with a JSON array as produced by the
+// export. Every imported pair is validated before anything changes; afterwards
+// added and overwritten pairs are (re)started or stopped by their Enabled flag.
+func (s *Server) handleImportPairs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	mode := r.URL.Query().Get("mode")
+	if mode == "" {
+		mode = ImportModeMerge
+	}
+	if mode != ImportModeMerge && mode != ImportModeReplace {
+		http.Error(w, "mode must be 'merge' or 'replace'", http.StatusBadRequest)
+		return
+	}
+	onConflict := r.URL.Query().Get("onConflict")
+	if onConflict == "" {
+		onConflict = ImportConflictSkip
+	}
+	if onConflict != ImportConflictSkip && onConflict != ImportConflictOverwrite {
+		http.Error(w, "onConflict must be 'skip' or 'overwrite'", http.StatusBadRequest)
+		return
+	}
+
+	var imported []*cfg.Pair
+	if err := json.NewDecoder(r.Body).Decode(&imported); err != nil {
+		http.Error(w, "invalid pairs document: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	seen := make(map[string]bool, len(imported))
+	for i, pair := range imported {
+		if pair == nil {
+			http.Error(w, fmt.Sprintf("pair %d: empty entry", i), http.StatusBadRequest)
+			return
+		}
+		if err := s.validateSubmittedPair(pair); err != nil {
+			http.Error(w, fmt.Sprintf("pair %d (%s): %v", i, pair.ID, err), http.StatusBadRequest)
+			return
+		}
+		if seen[pair.ID] {
+			http.Error(w, fmt.Sprintf("pair %d: duplicate id %s", i, pair.ID), http.StatusBadRequest)
+			return
+		}
+		seen[pair.ID] = true
+	}
+
+	result := s.importPairs(imported, mode, onConflict)
+	log.Info().
+		Str("mode", mode).
+		Strs("added", result.Added).
+		Strs("updated", result.Updated).
+		Strs("skipped", result.Skipped).
+		Strs("removed", result.Removed).
+		Msg("pairs imported")
+
+	writeJSON(w, result)
+}
+
+// importPairs applies validated pairs to the configuration, persists it and
+// reconciles the affected pairs. The config lock is held throughout so the
+// import can't interleave with other pair changes.
+func (s *Server) importPairs(imported []*cfg.Pair, mode, onConflict string) *ImportResult {
+	result := &ImportResult{Added: []string{}, Updated: []string{}, Skipped: []string{}, Removed: []string{}}
+
+	s.CfgMu.Lock()
+	defer s.CfgMu.Unlock()
+
+	index := make(map[string]int, len(s.Cfg.Pairs))
+	for i, pair := range s.Cfg.Pairs {
+		index[pair.ID] = i
+	}
+
+	var changed []*cfg.Pair
+	for _, pair := range imported {
+		i, exists := index[pair.ID]
+		switch {
+		case !exists:
+			pair.Rev = 1
+			s.Cfg.Pairs = append(s.Cfg.Pairs, pair)
+			result.Added = append(result.Added, pair.ID)
+			changed = append(changed, pair)
+		case onConflict == ImportConflictSkip:
+			result.Skipped = append(result.Skipped, pair.ID)
+		case pairsEqual(s.Cfg.Pairs[i], pair):
+			// Identical definition: nothing to restart
+		default:
+			pair.Rev = s.Cfg.Pairs[i].Rev + 1
+			s.Cfg.Pairs[i] = pair
+			result.Updated = append(result.Updated, pair.ID)
+			changed = append(changed, pair)
+		}
+	}
+
+	var removed []string
+	if mode == ImportModeReplace {
+		kept := s.Cfg.Pairs[:0]
+		for _, pair := range s.Cfg.Pairs {
+			if importedID(imported, pair.ID) {
+				kept = append(kept, pair)
+			} else {
+				removed = append(removed, pair.ID)
+			}
+		}
+		s.Cfg.Pairs = kept
+		sort.Strings(removed)
+		result.Removed = append(result.Removed, removed...)
+	}
+
+	_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
+
+	for _, id := range removed {
+		if err := s.PairManager.StopPair(id); err != nil {
+			log.Warn().Str("pair", id).Err(err).Msg("failed to stop removed pair")
+		}
+	}
+	// Like pairs created through the API, imported pairs follow Enabled alone,
+	// whatever the auto-start profile
+	for _, pair := range changed {
+		s.reconcilePair(pair, "")
+	}
+
+	return result
+}
+
+// importedID reports whether an import contains a pair with the given ID
+func importedID(imported []*cfg.Pair, id string) bool {
+	for _, pair := range imported {
+		if pair.ID == id {
+			return true
+		}
+	}
+	return false
+}
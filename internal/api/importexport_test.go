@@ -0,0 +1,45 @@
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportKeepsEnvTemplatesAcrossMachines(t *testing.T) {
+	exportRoot := t.TempDir()
+	t.Setenv("FS_TEST_SYNC_ROOT", exportRoot)
+	pair := newTestPair(t, "docs")
+	pair.Source = "${FS_TEST_SYNC_ROOT}/src"
+	pair.Target = "${FS_TEST_SYNC_ROOT}/dst"
+	source := newTestServer(t, pair)
+	if err := source.Cfg.ExpandPairEnv(pair); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := serve(t, source, http.MethodGet, "/api/pairs/export", "", nil)
+	if exported.Code != http.StatusOK {
+		t.Fatalf("GET /api/pairs/export = %d (%s)", exported.Code, exported.Body)
+	}
+	if body := exported.Body.String(); !strings.Contains(body, "${FS_TEST_SYNC_ROOT}/src") || strings.Contains(body, exportRoot) {
+		t.Fatalf("export %s carries the expanded paths, want the ${FS_TEST_SYNC_ROOT} references", body)
+	}
+
+	// The same document imported where the variable points elsewhere
+	importRoot := t.TempDir()
+	t.Setenv("FS_TEST_SYNC_ROOT", importRoot)
+	target := newTestServer(t)
+	imported := serve(t, target, http.MethodPost, "/api/pairs/import", exported.Body.String(), nil)
+	if imported.Code != http.StatusOK {
+		t.Fatalf("POST /api/pairs/import = %d (%s)", imported.Code, imported.Body)
+	}
+
+	if len(target.Cfg.Pairs) != 1 || target.Cfg.Pairs[0].Source != filepath.Join(importRoot, "src") {
+		t.Fatalf("imported pairs = %+v, want the source expanded below %s", target.Cfg.Pairs, importRoot)
+	}
+	reexported := serve(t, target, http.MethodGet, "/api/pairs/export", "", nil)
+	if body := reexported.Body.String(); !strings.Contains(body, "${FS_TEST_SYNC_ROOT}/dst") {
+		t.Errorf("re-export %s lost the ${FS_TEST_SYNC_ROOT} reference", body)
+	}
+}
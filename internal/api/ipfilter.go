@@ -0,0 +1,99 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements restricting API access to allowed client networks (AllowedCIDRs).
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== CLIENT NETWORK FILTER =====
+
+// parseAllowedNetworks parses CIDRs such as "192.168.1.0/24"; a plain address
+// allows that single host. An empty list allows every client.
+func parseAllowedNetworks(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, entry := range cidrs {
+		entry = strings.TrimSpace(entry)
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid allowed CIDR %q", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed CIDR %q: %w", entry, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// isProbe reports whether a request is a health probe, which stays reachable
+// from any network so orchestrators can always check the process. The verbose
+// health report is not a probe.
+func isProbe(r *http.Request) bool {
+	return (r.URL.Path == "/healthz" && !isVerboseHealth(r)) || r.URL.Path == "/readyz"
+}
+
+// restrictClients wraps a handler and rejects requests from clients outside the
+// allowed networks with 403. Without allowed networks every client is accepted.
+func (s *Server) restrictClients(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(s.allowedNets) == 0 || isProbe(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := clientIP(r, s.Cfg.TrustProxy)
+		if ip != nil {
+			for _, network := range s.allowedNets {
+				if network.Contains(ip) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+
+		log.Warn().
+			Str("path", r.URL.Path).
+			Str("remote", r.RemoteAddr).
+			Str("client_ip", ip.String()).
+			Msg("request from disallowed network")
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+}
+
+// clientIP returns the request's client address. Behind a trusted reverse proxy
+// the first X-Forwarded-For entry (or X-Real-IP) names the original client.
+func clientIP(r *http.Request, trustProxy bool) net.IP {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			if ip := net.ParseIP(strings.TrimSpace(first)); ip != nil {
+				return ip
+			}
+		}
+		if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
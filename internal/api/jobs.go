@@ -0,0 +1,158 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements background jobs for long-running requests such as async sync all.
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ===== JOB CONSTANTS =====
+
+const (
+	// Job states
+	JobRunning   = "running"
+	JobCompleted = "completed"
+
+	// Per-pair states within a job
+	JobPairPending   = "pending"
+	JobPairRunning   = "running"
+	JobPairSucceeded = "succeeded"
+	JobPairFailed    = "failed"
+
+	// Retention of finished jobs
+	JobRetention = time.Hour // How long a finished job can be polled
+	MaxJobs      = 100       // Jobs kept at most; the oldest finished ones are dropped first
+)
+
+// ===== JOB STRUCTURES =====
+
+// Job reports the progress and result of a background sync all
+type Job struct {
+	ID         string     `json:"id"`                   // Job identifier returned by the starting request
+	State      string     `json:"state"`                // JobRunning or JobCompleted
+	StartedAt  time.Time  `json:"startedAt"`            // When the job started
+	FinishedAt *time.Time `json:"finishedAt,omitempty"` // When the last pair finished
+	Files      int        `json:"files"`                // Files copied so far across all pairs
+	Bytes      int64      `json:"bytes"`                // Bytes copied so far across all pairs
+	Failed     int        `json:"failed"`               // Pairs whose sync failed
+	Pairs      []*JobPair `json:"pairs"`                // Per-pair progress, in sync order
+}
+
+// JobPair is the progress of one pair within a job
+type JobPair struct {
+	ID    string `json:"id"`              // Pair identifier
+	State string `json:"state"`           // JobPairPending, JobPairRunning, JobPairSucceeded or JobPairFailed
+	Files int    `json:"files"`           // Files copied
+	Bytes int64  `json:"bytes"`           // Bytes copied
+	Error string `json:"error,omitempty"` // Sync error of a failed pair
+}
+
+// jobStore keeps recent jobs in memory for polling
+type jobStore struct {
+	mutex sync.Mutex
+	jobs  map[string]*Job
+}
+
+// newJobStore creates an empty job store
+func newJobStore() *jobStore {
+	return &jobStore{jobs: make(map[string]*Job)}
+}
+
+// ===== JOB STORE OPERATIONS =====
+
+// create registers a running job for the given pairs
+func (js *jobStore) create(pairIDs []string) *Job {
+	job := &Job{
+		ID:        newJobID(),
+		State:     JobRunning,
+		StartedAt: time.Now(),
+		Pairs:     make([]*JobPair, 0, len(pairIDs)),
+	}
+	for _, id := range pairIDs {
+		job.Pairs = append(job.Pairs, &JobPair{ID: id, State: JobPairPending})
+	}
+
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	js.pruneLocked()
+	js.jobs[job.ID] = job
+	return job
+}
+
+// update applies fn to a job under the store lock
+func (js *jobStore) update(job *Job, fn func(job *Job)) {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	fn(job)
+}
+
+// get returns a snapshot of a job, or nil when it is unknown or expired
+func (js *jobStore) get(id string) *Job {
+	js.mutex.Lock()
+	defer js.mutex.Unlock()
+	js.pruneLocked()
+
+	job, exists := js.jobs[id]
+	if !exists {
+		return nil
+	}
+	snapshot := *job
+	snapshot.Pairs = make([]*JobPair, len(job.Pairs))
+	for i, pair := range job.Pairs {
+		pairCopy := *pair
+		snapshot.Pairs[i] = &pairCopy
+	}
+	return &snapshot
+}
+
+// pruneLocked drops expired finished jobs, and the oldest finished jobs while
+// the store is full. Must be called with the mutex held.
+func (js *jobStore) pruneLocked() {
+	for id, job := range js.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > JobRetention {
+			delete(js.jobs, id)
+		}
+	}
+	for len(js.jobs) >= MaxJobs {
+		var oldest *Job
+		for _, job := range js.jobs {
+			if job.FinishedAt != nil && (oldest == nil || job.FinishedAt.Before(*oldest.FinishedAt)) {
+				oldest = job
+			}
+		}
+		if oldest == nil {
+			return // Only running jobs left
+		}
+		delete(js.jobs, oldest.ID)
+	}
+}
+
+// newJobID returns a random job identifier
+func newJobID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// ===== JOB ENDPOINT =====
+
+// handleJob returns the progress of a background job: GET /api/jobs/{id}
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/jobs/")
+	job := s.jobs.get(id)
+	if job == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, job)
+}
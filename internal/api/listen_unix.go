@@ -0,0 +1,15 @@
+//go:build !windows
+
+// Package api provides listen error detection for Unix platforms.
+package api
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isAddrInUse reports whether binding the listen address failed because another
+// process already holds it.
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+// Package api provides listen error detection for Windows.
+package api
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isAddrInUse reports whether binding the listen address failed because another
+// process already holds it (or has it reserved with exclusive access).
+func isAddrInUse(err error) bool {
+	return errors.Is(err, windows.WSAEADDRINUSE) || errors.Is(err, windows.WSAEACCES)
+}
@@ -0,0 +1,59 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements reading and changing the log level at runtime.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog"
+)
+
+// ===== LOG LEVEL STRUCTURES =====
+
+// LogLevelRequest is the body of GET and PUT /api/loglevel
+type LogLevelRequest struct {
+	Level string `json:"level"` // trace, debug, info, warn, error, fatal or panic
+}
+
+// ===== LOG LEVEL ENDPOINT =====
+
+// handleLogLevel returns the current global log level (GET) or changes it (PUT).
+// The change applies immediately and lasts until restart.
+func (s *Server) handleLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, LogLevelRequest{Level: logging.GetLogLevel().String()})
+	case http.MethodPut:
+		var req LogLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		level, err := parseLogLevel(req.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logging.SetLogLevel(level)
+		writeJSON(w, LogLevelRequest{Level: level.String()})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// parseLogLevel accepts the named zerolog levels; empty, "disabled" and
+// numeric levels are rejected so a typo can't silence the log.
+func parseLogLevel(value string) (zerolog.Level, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	level, err := logging.ParseLogLevel(name)
+	if err != nil || name == "" || level == zerolog.Disabled || level.String() != name {
+		return zerolog.NoLevel, fmt.Errorf("invalid log level %q (use trace, debug, info, warn, error, fatal or panic)", value)
+	}
+	return level, nil
+}
@@ -0,0 +1,173 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements downloading the application log for bug reports.
+package api
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== LOG DOWNLOAD CONSTANTS =====
+
+const (
+	// MaxLogDownloadBytes caps the log data streamed by one download; larger
+	// logs are truncated to their newest content
+	MaxLogDownloadBytes = 100 << 20
+
+	// LogDownloadTimestampLayout names downloaded log files
+	LogDownloadTimestampLayout = "20060102-150405"
+)
+
+// Log file lookups, replaceable in tests
+var (
+	logFilePath     = logging.LogFilePath
+	rotatedLogFiles = logging.RotatedLogFiles
+)
+
+// ===== LOG DOWNLOAD ENDPOINT =====
+
+// handleLogDownload streams the current log file as an attachment, or with
+// ?rotated=true a zip of the current log and its rotated backups.
+func (s *Server) handleLogDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	current := logFilePath()
+	if current == "" {
+		http.Error(w, "file logging is not configured", http.StatusNotFound)
+		return
+	}
+
+	// Large logs over slow links can take longer than the write timeout
+	noWriteTimeout(w)
+
+	includeRotated, _ := strconv.ParseBool(r.URL.Query().Get("rotated"))
+	stamp := time.Now().Format(LogDownloadTimestampLayout)
+
+	if includeRotated {
+		s.writeLogArchive(w, current, "logs-"+stamp+".zip")
+		return
+	}
+
+	file, err := os.Open(current)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+
+	reader, size, err := newestLogContent(file, MaxLogDownloadBytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ext := filepath.Ext(current)
+	name := fmt.Sprintf("%s-%s%s", trimExt(filepath.Base(current)), stamp, ext)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := io.Copy(w, reader); err != nil {
+		log.Warn().Err(err).Msg("log download interrupted")
+	}
+}
+
+// writeLogArchive streams a zip with the current log and its rotated backups,
+// newest first, stopping once MaxLogDownloadBytes of log data has been added.
+func (s *Server) writeLogArchive(w http.ResponseWriter, current, name string) {
+	backups, err := rotatedLogFiles()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+
+	archive := zip.NewWriter(w)
+	defer archive.Close()
+
+	remaining := int64(MaxLogDownloadBytes)
+	for _, path := range append([]string{current}, backups...) {
+		if remaining <= 0 {
+			log.Warn().Str("file", path).Msg("log download size limit reached, skipping older logs")
+			break
+		}
+
+		written, err := addLogToArchive(archive, path, remaining)
+		if err != nil {
+			log.Warn().Err(err).Str("file", path).Msg("failed to add log to download")
+			continue
+		}
+		remaining -= written
+	}
+}
+
+// addLogToArchive adds the newest limit bytes of a log file to the archive.
+func addLogToArchive(archive *zip.Writer, path string, limit int64) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader, _, err := newestLogContent(file, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return 0, err
+	}
+	header.Method = zip.Deflate
+
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(entry, reader)
+}
+
+// newestLogContent returns a reader over the last limit bytes of a file and
+// the number of bytes it yields.
+func newestLogContent(file *os.File, limit int64) (io.Reader, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size := info.Size()
+	if size <= limit {
+		return io.LimitReader(file, size), size, nil
+	}
+
+	if _, err := file.Seek(size-limit, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+	return io.LimitReader(file, limit), limit, nil
+}
+
+// trimExt removes the extension from a file name.
+func trimExt(name string) string {
+	return name[:len(name)-len(filepath.Ext(name))]
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeLogFiles points the log download at a current log and rotated backups
+// in a temporary directory for the rest of the test
+func fakeLogFiles(t *testing.T, current string, backups ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	currentPath := filepath.Join(dir, "app.log")
+	if err := os.WriteFile(currentPath, []byte(current), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var backupPaths []string
+	for i, content := range backups {
+		path := filepath.Join(dir, fmt.Sprintf("app-backup%d.log", i))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		backupPaths = append(backupPaths, path)
+	}
+
+	previousPath, previousRotated := logFilePath, rotatedLogFiles
+	logFilePath = func() string { return currentPath }
+	rotatedLogFiles = func() ([]string, error) { return backupPaths, nil }
+	t.Cleanup(func() { logFilePath, rotatedLogFiles = previousPath, previousRotated })
+	return currentPath
+}
+
+func TestLogDownload(t *testing.T) {
+	fakeLogFiles(t, "current line\n")
+	s := newTestServer(t)
+
+	recorder := serve(t, s, http.MethodGet, "/api/logs/download", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /api/logs/download = %d, want 200", recorder.Code)
+	}
+	if got := recorder.Body.String(); got != "current line\n" {
+		t.Errorf("body = %q, want the current log", got)
+	}
+	headers := recorder.Header()
+	if got := headers.Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := headers.Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="app-`) || !strings.HasSuffix(got, `.log"`) {
+		t.Errorf("Content-Disposition = %q, want an app-<timestamp>.log attachment", got)
+	}
+	if got := headers.Get("Content-Length"); got != "13" {
+		t.Errorf("Content-Length = %q, want 13", got)
+	}
+	if got := headers.Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+}
+
+func TestLogDownloadWithRotatedBackups(t *testing.T) {
+	fakeLogFiles(t, "current\n", "yesterday\n", "last week\n")
+	s := newTestServer(t)
+
+	recorder := serve(t, s, http.MethodGet, "/api/logs/download?rotated=true", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET = %d, want 200", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/zip" {
+		t.Errorf("Content-Type = %q, want application/zip", got)
+	}
+	if got := recorder.Header().Get("Content-Disposition"); !strings.HasSuffix(got, `.zip"`) {
+		t.Errorf("Content-Disposition = %q, want a zip attachment", got)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, file := range archive.File {
+		entry, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(entry)
+		entry.Close()
+		contents = append(contents, string(data))
+	}
+	if want := []string{"current\n", "yesterday\n", "last week\n"}; strings.Join(contents, "|") != strings.Join(want, "|") {
+		t.Errorf("archive holds %q, want %q newest first", contents, want)
+	}
+}
+
+func TestLogDownloadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		logPath string
+		want    int
+	}{
+		{"file logging off", http.MethodGet, "", http.StatusNotFound},
+		{"log file missing", http.MethodGet, "missing.log", http.StatusNotFound},
+		{"wrong method", http.MethodPost, "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := logFilePath
+			path := tt.logPath
+			if path != "" {
+				path = filepath.Join(t.TempDir(), path)
+			}
+			logFilePath = func() string { return path }
+			t.Cleanup(func() { logFilePath = previous })
+
+			if recorder := serve(t, newTestServer(t), tt.method, "/api/logs/download", "", nil); recorder.Code != tt.want {
+				t.Errorf("%s = %d, want %d", tt.method, recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogDownloadRequiresAuth(t *testing.T) {
+	fakeLogFiles(t, "secret\n")
+	s := newTestServer(t)
+	s.Cfg.AuthToken = "token"
+	handler := s.requireAuth(s.routes())
+
+	for _, tt := range []struct {
+		header string
+		want   int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Bearer wrong", http.StatusUnauthorized},
+		{"Bearer token", http.StatusOK},
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/api/logs/download", nil)
+		if tt.header != "" {
+			request.Header.Set("Authorization", tt.header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != tt.want {
+			t.Errorf("Authorization %q = %d, want %d", tt.header, recorder.Code, tt.want)
+		}
+	}
+}
+
+func TestNewestLogContentKeepsTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		limit int64
+		want  string
+	}{
+		{100, "0123456789"},
+		{10, "0123456789"},
+		{4, "6789"},
+	}
+	for _, tt := range tests {
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader, size, err := newestLogContent(file, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(reader)
+		file.Close()
+		if string(data) != tt.want || size != int64(len(tt.want)) {
+			t.Errorf("limit %d: got %q (size %d), want %q", tt.limit, data, size, tt.want)
+		}
+	}
+}
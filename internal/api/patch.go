@@ -0,0 +1,91 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements partial pair updates: PATCH /api/pairs/{id} merges the fields
+// present in the request onto the stored pair, leaving all others untouched.
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== PARTIAL PAIR UPDATE =====
+
+// handlePatchPair applies a partial pair object to an existing pair. The merged
+// pair is validated and applied like a full update (PUT), including the
+// revision check.
+func (s *Server) handlePatchPair(w http.ResponseWriter, r *http.Request, id string) {
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	expected, err := expectedRev(r, patch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+
+	s.CfgMu.Lock()
+	var existing *cfg.Pair
+	for _, p := range s.Cfg.Pairs {
+		if p.ID == id {
+			existing = p
+			break
+		}
+	}
+	var merged *cfg.Pair
+	if existing != nil {
+		merged, err = mergePairPatch(existing, patch)
+	}
+	s.CfgMu.Unlock()
+
+	if existing == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.validateSubmittedPair(merged); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.applyPairUpdate(w, id, merged, expected)
+}
+
+// mergePairPatch returns a copy of pair with the fields of the JSON object patch
+// applied. Fields missing from the patch keep their value, so omitted hooks or
+// filters are never wiped. Present fields replace the stored value: a list given
+// as [] or null is emptied, and nested objects such as the schedule are merged
+// field by field. The pair ID can't be changed.
+func mergePairPatch(pair *cfg.Pair, patch []byte) (*cfg.Pair, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("patch must be a JSON object")
+	}
+
+	// Deep copy through JSON so the stored pair isn't modified
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return nil, err
+	}
+	var merged cfg.Pair
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(patch, &merged); err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+	if merged.ID != pair.ID {
+		return nil, fmt.Errorf("pair id can't be changed")
+	}
+	return &merged, nil
+}
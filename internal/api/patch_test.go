@@ -0,0 +1,137 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// patchablePair returns a disabled pair with hooks, filters and a schedule set
+func patchablePair(t *testing.T, id string) *cfg.Pair {
+	t.Helper()
+	pair := newTestPair(t, id)
+	pair.IncludeExt = []string{".jar"}
+	pair.ExcludeGlobs = []string{"**/*.bak"}
+	pair.Hooks = []cfg.Hook{{Command: &cfg.CommandHook{Executable: "echo", Args: []string{"copied"}}}}
+	pair.Schedule = scheduler.NewIntervalSchedule("1h")
+	pair.Rev = 4
+	return pair
+}
+
+func TestMergePairPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		check   func(t *testing.T, merged *cfg.Pair)
+		wantErr string
+	}{
+		{"single flag keeps the rest", `{"mirrorDeletes": true}`, func(t *testing.T, merged *cfg.Pair) {
+			if !merged.MirrorDeletes || len(merged.Hooks) != 1 || !slices.Equal(merged.IncludeExt, []string{".jar"}) {
+				t.Errorf("merged = %+v, want mirror deletes on and hooks and filters kept", merged)
+			}
+		}, ""},
+		{"list replaced", `{"includeExtensions": [".war", ".ear"]}`, func(t *testing.T, merged *cfg.Pair) {
+			if !slices.Equal(merged.IncludeExt, []string{".war", ".ear"}) || !slices.Equal(merged.ExcludeGlobs, []string{"**/*.bak"}) {
+				t.Errorf("filters = %q and %q, want only the include list replaced", merged.IncludeExt, merged.ExcludeGlobs)
+			}
+		}, ""},
+		{"empty list clears", `{"hooks": []}`, func(t *testing.T, merged *cfg.Pair) {
+			if len(merged.Hooks) != 0 {
+				t.Errorf("hooks = %+v, want none", merged.Hooks)
+			}
+		}, ""},
+		{"null clears", `{"excludeGlobs": null}`, func(t *testing.T, merged *cfg.Pair) {
+			if len(merged.ExcludeGlobs) != 0 || len(merged.Hooks) != 1 {
+				t.Errorf("merged = %+v, want exclude globs cleared and hooks kept", merged)
+			}
+		}, ""},
+		{"nested schedule merged", `{"schedule": {"interval": "2h"}}`, func(t *testing.T, merged *cfg.Pair) {
+			if merged.Schedule.Type != scheduler.ScheduleTypeInterval || merged.Schedule.Interval != "2h" {
+				t.Errorf("schedule = %+v, want an interval of 2h", merged.Schedule)
+			}
+		}, ""},
+		{"same id allowed", `{"id": "docs", "enabled": true}`, func(t *testing.T, merged *cfg.Pair) {
+			if !merged.Enabled {
+				t.Error("pair not enabled")
+			}
+		}, ""},
+		{"id change rejected", `{"id": "other"}`, nil, "can't be changed"},
+		{"not an object", `[{"mirrorDeletes": true}]`, nil, "JSON object"},
+		{"wrong type", `{"mirrorDeletes": "yes"}`, nil, "invalid patch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := patchablePair(t, "docs")
+			merged, err := mergePairPatch(pair, []byte(tt.patch))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("mergePairPatch() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, merged)
+
+			// The stored pair is left alone
+			if pair.MirrorDeletes || len(pair.Hooks) != 1 || pair.Schedule.Interval != "1h" {
+				t.Errorf("stored pair modified: %+v", pair)
+			}
+		})
+	}
+}
+
+func TestPatchPairEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		patch    string
+		ifMatch  string
+		wantCode int
+	}{
+		{"revision in the body", "docs", `{"rev": 4, "mirrorDeletes": true}`, "", http.StatusOK},
+		{"revision in If-Match", "docs", `{"mirrorDeletes": true}`, `"4"`, http.StatusOK},
+		{"any revision", "docs", `{"mirrorDeletes": true}`, "*", http.StatusOK},
+		{"stale revision", "docs", `{"rev": 3, "mirrorDeletes": true}`, "", http.StatusConflict},
+		{"missing revision", "docs", `{"mirrorDeletes": true}`, "", http.StatusPreconditionRequired},
+		{"unknown pair", "missing", `{"rev": 4, "mirrorDeletes": true}`, "", http.StatusNotFound},
+		{"invalid result", "docs", `{"rev": 4, "debounceMode": "sometimes"}`, "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, patchablePair(t, "docs"))
+			var headers map[string]string
+			if tt.ifMatch != "" {
+				headers = map[string]string{"If-Match": tt.ifMatch}
+			}
+
+			recorder := serve(t, s, http.MethodPatch, "/api/pairs/"+tt.id, tt.patch, headers)
+			if recorder.Code != tt.wantCode {
+				t.Fatalf("PATCH /api/pairs/%s = %d %q, want %d", tt.id, recorder.Code, recorder.Body, tt.wantCode)
+			}
+
+			stored := s.Cfg.Pairs[0]
+			if tt.wantCode != http.StatusOK {
+				if stored.MirrorDeletes || stored.Rev != 4 {
+					t.Errorf("failed patch changed the pair: %+v", stored)
+				}
+				return
+			}
+			var updated cfg.Pair
+			decodeJSON(t, recorder, &updated)
+			if !updated.MirrorDeletes || updated.Rev != 5 || recorder.Header().Get("ETag") == "" {
+				t.Errorf("response = %+v (ETag %q), want mirror deletes on at rev 5", updated, recorder.Header().Get("ETag"))
+			}
+			if !stored.MirrorDeletes || len(stored.Hooks) != 1 || !slices.Equal(stored.IncludeExt, []string{".jar"}) {
+				t.Errorf("stored pair = %+v, want only mirror deletes changed", stored)
+			}
+		})
+	}
+}
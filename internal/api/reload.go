@@ -0,0 +1,202 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements configuration reload with pair reconciliation.
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/core"
+	"FolderSynchronizer/internal/scheduler"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== RELOAD STRUCTURES =====
+
+// ReloadResult lists the pairs affected by a configuration reload
+type ReloadResult struct {
+	Added   []string `json:"added"`   // Pairs present only in the reloaded config
+	Removed []string `json:"removed"` // Pairs no longer present
+	Updated []string `json:"updated"` // Pairs whose settings changed
+}
+
+// ===== CONFIGURATION RELOAD =====
+
+// ReloadConfig re-reads the configuration file and reconciles running pairs
+// with it: removed pairs are stopped, new and changed pairs are (re)started or
+// stopped according to their enabled flag and the active profile, and global
+// settings are applied. Startup-only settings (listen address, API-only mode
+// and the active profile) keep their current values until restart.
+func (s *Server) ReloadConfig() (*ReloadResult, error) {
+	loaded, err := cfg.Load(s.Paths.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := scheduler.NewHolidayList(loaded.Holidays)
+	if err != nil {
+		return nil, err
+	}
+
+	s.CfgMu.Lock()
+	current := make(map[string]*cfg.Pair, len(s.Cfg.Pairs))
+	for _, pair := range s.Cfg.Pairs {
+		current[pair.ID] = pair
+	}
+
+	// Keep startup-only settings
+	loaded.Listen = s.Cfg.Listen
+	loaded.ListenPortFallback = s.Cfg.ListenPortFallback
+	loaded.APIOnly = s.Cfg.APIOnly
+	loaded.ActiveProfile = s.Cfg.ActiveProfile
+	loaded.AllowedCIDRs = s.Cfg.AllowedCIDRs
+	loaded.TrustProxy = s.Cfg.TrustProxy
+	loaded.TLSCertFile = s.Cfg.TLSCertFile
+	loaded.TLSKeyFile = s.Cfg.TLSKeyFile
+	loaded.TLSSelfSigned = s.Cfg.TLSSelfSigned
+	loaded.Overrides = s.Cfg.Overrides
+	profile := loaded.AutoStartProfile()
+
+	// Pairs edited on disk move past their in-memory revision, so updates based
+	// on the old definition are rejected
+	for _, pair := range loaded.Pairs {
+		if previous, existed := current[pair.ID]; existed && pair.Rev <= previous.Rev && !pairsEqual(previous, pair) {
+			pair.Rev = previous.Rev + 1
+		}
+	}
+
+	*s.Cfg = *loaded
+	s.CfgMu.Unlock()
+
+	// Apply global settings
+	core.SetMaxConcurrentHooks(loaded.MaxConcurrentHooks)
+	core.SetMaxOpenFiles(loaded.MaxOpenFiles)
+	core.SetMaxInitialSyncs(loaded.MaxInitialSyncs)
+	s.PairManager.SetMaxConcurrentSyncs(loaded.MaxConcurrentSyncs)
+	core.SetHookHistorySize(loaded.HookHistorySize)
+	core.SetCommandPolicy(loaded.BlockedCommands, loaded.BlockedCommandPatterns, loaded.AllowedCommands)
+	core.SetDefaultSchedule(loaded.DefaultSchedule)
+	s.PairManager.SetHolidays(holidays)
+
+	result := &ReloadResult{Added: []string{}, Removed: []string{}, Updated: []string{}}
+
+	for _, pair := range loaded.Pairs {
+		previous, existed := current[pair.ID]
+		delete(current, pair.ID)
+
+		if existed && pairsEqual(previous, pair) {
+			continue
+		}
+		if existed {
+			result.Updated = append(result.Updated, pair.ID)
+		} else {
+			result.Added = append(result.Added, pair.ID)
+		}
+
+		s.reconcilePair(pair, profile)
+	}
+
+	for id := range current {
+		result.Removed = append(result.Removed, id)
+		if s.pairRunning(id) {
+			if err := s.PairManager.StopPair(id); err != nil {
+				log.Warn().Str("pair", id).Err(err).Msg("failed to stop removed pair")
+			}
+		}
+	}
+	sort.Strings(result.Removed)
+
+	log.Info().
+		Strs("added", result.Added).
+		Strs("removed", result.Removed).
+		Strs("updated", result.Updated).
+		Msg("configuration reloaded")
+
+	return result, nil
+}
+
+// reconcilePair starts, restarts or stops a new or changed pair.
+func (s *Server) reconcilePair(pair *cfg.Pair, profile string) {
+	if !pair.Enabled || !cfg.MatchesProfile(pair, profile) {
+		if s.pairRunning(pair.ID) {
+			if err := s.PairManager.StopPair(pair.ID); err != nil {
+				log.Warn().Str("pair", pair.ID).Err(err).Msg("failed to stop pair")
+			}
+		}
+		return
+	}
+
+	// Restart a running instance with the new settings
+	if s.pairRunning(pair.ID) {
+		_ = s.PairManager.StopPair(pair.ID)
+	}
+	if err := s.PairManager.StartPair(pair); err != nil {
+		log.Error().Str("pair", pair.ID).Err(err).Msg("failed to start pair")
+	}
+}
+
+// pairRunning reports whether the pair manager has a running instance of a pair.
+func (s *Server) pairRunning(id string) bool {
+	return s.PairManager.IsPairRunning(id)
+}
+
+// pairsEqual compares two pair configurations by their serialized form,
+// ignoring the revision.
+func pairsEqual(a, b *cfg.Pair) bool {
+	left, errLeft := json.Marshal(withoutRev(a))
+	right, errRight := json.Marshal(withoutRev(b))
+	return errLeft == nil && errRight == nil && string(left) == string(right)
+}
+
+// ===== RELOAD ENDPOINT =====
+
+// handleReloadConfig reloads the configuration file on demand
+func (s *Server) handleReloadConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	result, err := s.ReloadConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// handleRestoreConfig rolls the config file back to a backup (?backup=N, 1 = newest)
+// and reloads it, reconciling pairs like a reload.
+func (s *Server) handleRestoreConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	backup, err := strconv.Atoi(r.URL.Query().Get("backup"))
+	if err != nil || backup < 1 {
+		http.Error(w, "backup must be a positive number", http.StatusBadRequest)
+		return
+	}
+
+	// Held so the restore can't interleave with a save from another request
+	s.CfgMu.Lock()
+	err = cfg.RestoreBackup(s.Paths.ConfigFile, backup)
+	s.CfgMu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Info().Int("backup", backup).Msg("configuration restored from backup")
+
+	result, err := s.ReloadConfig()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
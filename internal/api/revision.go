@@ -0,0 +1,75 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements optimistic concurrency for pair updates: every saved pair carries
+// a revision, and PUT/PATCH must name the revision they were based on.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== REVISION CONSTANTS =====
+
+// AnyRev is the expected revision of an If-Match: * request, which overwrites
+// whatever revision is stored
+const AnyRev = -1
+
+// errMissingRev is returned when an update names no expected revision
+var errMissingRev = errors.New("expected revision required: send If-Match with the pair's ETag or include \"rev\" in the body")
+
+// ===== REVISION HANDLING =====
+
+// pairETag formats a pair revision as an entity tag
+func pairETag(rev int) string {
+	return strconv.Quote(strconv.Itoa(rev))
+}
+
+// expectedRev returns the revision an update was based on: the If-Match header
+// when present, else the "rev" field of the JSON body.
+func expectedRev(r *http.Request, body []byte) (int, error) {
+	if match := strings.TrimSpace(r.Header.Get("If-Match")); match != "" {
+		if match == "*" {
+			return AnyRev, nil
+		}
+		tag := strings.Trim(strings.TrimPrefix(match, "W/"), `"`)
+		rev, err := strconv.Atoi(tag)
+		if err != nil || rev < 0 {
+			return 0, fmt.Errorf("invalid If-Match %q", match)
+		}
+		return rev, nil
+	}
+
+	var fields struct {
+		Rev *int `json:"rev"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil || fields.Rev == nil {
+		return 0, errMissingRev
+	}
+	return *fields.Rev, nil
+}
+
+// writeRevConflict reports an update based on a stale revision, with the
+// current one so the client can reload and retry
+func writeRevConflict(w http.ResponseWriter, id string, current int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", pairETag(current))
+	w.WriteHeader(http.StatusConflict)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"error": fmt.Sprintf("pair %s was changed since it was loaded (now at revision %d); reload and retry", id, current),
+		"rev":   current,
+	})
+}
+
+// withoutRev returns a copy of pair with the revision cleared, for comparing
+// definitions
+func withoutRev(pair *cfg.Pair) *cfg.Pair {
+	copied := *pair
+	copied.Rev = 0
+	return &copied
+}
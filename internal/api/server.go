@@ -4,15 +4,25 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"FolderSynchronizer/internal/buildinfo"
 	cfg "FolderSynchronizer/internal/config"
 	"FolderSynchronizer/internal/core"
+	"FolderSynchronizer/internal/metrics"
 	"FolderSynchronizer/internal/scheduler"
 	"FolderSynchronizer/internal/tray"
 
@@ -32,6 +42,13 @@ type Server struct {
 	PairManager *core.PairManager  // Manager for sync pairs instead of individual workers
 	ctx         context.Context    // Server context for graceful shutdown
 	cancel      context.CancelFunc // Cancel function for server context
+	idempotency *idempotencyCache  // Recently seen Idempotency-Key outcomes
+	jobs        *jobStore          // Background jobs started by async requests
+	webAssets   fs.FS              // Web UI assets (the embedded web directory)
+	hasIndex    bool               // Whether the web UI index page is present
+	ready       atomic.Bool        // Set by MarkReady once startup has finished
+	allowedNets []*net.IPNet       // Client networks allowed to reach the server (empty = all)
+	startedAt   time.Time          // Creation time, reported as uptime by /healthz?verbose=1
 }
 
 // PairWithStatus combines a sync pair with its current status information
@@ -47,6 +64,12 @@ type ScheduleExample struct {
 	Schedule    scheduler.Schedule `json:"schedule"`    // Schedule configuration
 }
 
+// SchedulePreviewResponse lists the upcoming run times of a schedule
+type SchedulePreviewResponse struct {
+	Timezone string      `json:"timezone"` // Timezone the run times are expressed in
+	NextRuns []time.Time `json:"nextRuns"` // Upcoming run times, earliest first
+}
+
 // statusRecorder wraps http.ResponseWriter to capture status codes for logging
 type statusRecorder struct {
 	http.ResponseWriter
@@ -58,60 +81,236 @@ func (sr *statusRecorder) WriteHeader(code int) {
 	sr.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap exposes the underlying writer to http.ResponseController (flushing for SSE)
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
 // ===== SERVER LIFECYCLE MANAGEMENT =====
 
 // NewServer creates a new HTTP server instance with the provided configuration
 func NewServer(paths cfg.Paths, conf *cfg.Config) (*Server, error) {
+	// Client network restriction, rejected early so a typo doesn't expose the API
+	allowedNets, err := parseAllowedNetworks(conf.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
-	pairManager, err := core.NewPairManager()
+	pairManager, err := core.NewPairManager(conf.MaxConcurrentSyncs)
 	if err != nil {
 		cancel()
 		return nil, err
 	}
 
+	// Restore scheduler run statistics saved by the previous run
+	if err := pairManager.EnableStatsPersistence(paths.StatsFile); err != nil {
+		log.Warn().Err(err).Msg("scheduler stats not restored")
+	}
+
+	// Holiday calendar for custom schedules with skipHolidays
+	if holidays, err := scheduler.NewHolidayList(conf.Holidays); err != nil {
+		log.Warn().Err(err).Msg("holiday calendar ignored")
+	} else {
+		pairManager.SetHolidays(holidays)
+	}
+
+	// Restore deferred mirror deletes recorded by the previous run
+	if err := core.SetPendingDeletesFile(paths.PendingDeletesFile); err != nil {
+		log.Warn().Err(err).Msg("pending deletes not restored")
+	}
+
+	// Checksum cache of hash comparisons, kept across runs
+	if !conf.DisableHashCache {
+		if err := core.SetHashCacheFile(paths.HashCacheFile); err != nil {
+			log.Warn().Err(err).Msg("hash cache not restored")
+		}
+	}
+
+	// Schedule adopted by pairs created without one
+	core.SetDefaultSchedule(conf.DefaultSchedule)
+
+	// Apply global hook concurrency limit
+	core.SetMaxConcurrentHooks(conf.MaxConcurrentHooks)
+
+	// Apply global open file limit for copies and hashing
+	core.SetMaxOpenFiles(conf.MaxOpenFiles)
+
+	// Apply global limit on concurrent watcher initial syncs
+	core.SetMaxInitialSyncs(conf.MaxInitialSyncs)
+
+	// Number of hook executions kept per pair
+	core.SetHookHistorySize(conf.HookHistorySize)
+
+	// Age after which leftover temporary copy files are removed
+	core.SetStaleTempAge(conf.StaleTempPeriod())
+
+	// Apply command hook blocklist extensions and allowlist
+	core.SetCommandPolicy(conf.BlockedCommands, conf.BlockedCommandPatterns, conf.AllowedCommands)
+
+	// Register Prometheus collectors served on /metrics
+	if err := metrics.Register(); err != nil {
+		log.Warn().Err(err).Msg("metrics registration failed")
+	}
+
+	// Builds without web assets serve a fallback page at /
+	hasIndex := hasIndexPage(webFS)
+	if !hasIndex && !conf.ServeAPIOnly() {
+		log.Warn().Msg("embedded web UI not found, serving fallback page")
+	}
+
 	return &Server{
 		Cfg:         conf,
 		Paths:       paths,
 		PairManager: pairManager,
 		ctx:         ctx,
 		cancel:      cancel,
+		idempotency: newIdempotencyCache(IdempotencyKeyTTL, IdempotencyCacheSize),
+		jobs:        newJobStore(),
+		webAssets:   webFS,
+		hasIndex:    hasIndex,
+		allowedNets: allowedNets,
+		startedAt:   time.Now(),
 	}, nil
 }
 
-// StartHTTP initializes and starts the HTTP server on the specified address
-func (s *Server) StartHTTP(listen string) *http.Server {
+// StartHTTP binds the listen address and starts serving HTTP (or HTTPS) on it.
+// A bind failure is returned rather than logged from the serving goroutine; when
+// the port is in use, up to ListenPortFallback following ports are tried. The
+// returned server's Addr is the address actually bound.
+func (s *Server) StartHTTP(listen string) (*http.Server, error) {
+	mux := s.routes()
+
+	// Bounded timeouts keep slow or stalled clients from holding connections open;
+	// long-lived responses lift the write deadline themselves (see noWriteTimeout)
+	readTimeout, writeTimeout, idleTimeout := s.Cfg.HTTPTimeouts()
+	hs := &http.Server{
+		Addr:         listen,
+		Handler:      logRequest(s.restrictClients(s.requireAuth(mux))),
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+
+	// HTTPS when a certificate is configured or self-signed TLS is enabled; a
+	// certificate problem keeps the server down rather than serving plain HTTP
+	certFile, keyFile, err := s.tlsFiles(listen)
+	if err != nil {
+		return nil, err
+	}
+	if certFile != "" {
+		// Fail now on an unreadable certificate instead of inside the serving goroutine
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return nil, fmt.Errorf("load tls certificate: %w", err)
+		}
+	}
+
+	listener, err := listenWithFallback(listen, s.Cfg.ListenPortFallback)
+	if err != nil {
+		return nil, err
+	}
+	hs.Addr = listener.Addr().String()
+
+	go func() {
+		var err error
+		if certFile != "" {
+			log.Info().Str("listen", hs.Addr).Str("cert", certFile).Msg("https server starting")
+			err = hs.ServeTLS(listener, certFile, keyFile)
+		} else {
+			log.Info().Str("listen", hs.Addr).Msg("http server starting")
+			err = hs.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("http server")
+		}
+	}()
+
+	return hs, nil
+}
+
+// routes registers the API, health and UI endpoints
+func (s *Server) routes() *http.ServeMux {
 	mux := http.NewServeMux()
 
 	// REST API endpoints
 	mux.HandleFunc("/api/pairs", s.handlePairs)
 	mux.HandleFunc("/api/pairs/", s.handlePairByID)
-	mux.HandleFunc("/api/syncAll", s.handleSyncAll)
+	mux.HandleFunc("/api/pairs/status", s.handleBatchPairStatus)
+	mux.HandleFunc("/api/pairs/export", s.handleExportPairs)
+	mux.HandleFunc("/api/pairs/import", s.handleImportPairs)
+	mux.HandleFunc("/api/syncAll", s.idempotent(s.handleSyncAll))
+	mux.HandleFunc("/api/jobs/", s.handleJob)
+	mux.HandleFunc("/api/hooks/test", s.handleHookTest)
 	mux.HandleFunc("/api/schedules/examples", s.handleScheduleExamples)
+	mux.HandleFunc("/api/schedules/preview", s.handleSchedulePreview)
+	mux.HandleFunc("/api/config/reload", s.handleReloadConfig)
+	mux.HandleFunc("/api/config/restore", s.handleRestoreConfig)
+	mux.HandleFunc("/api/logs/download", s.handleLogDownload)
+	mux.HandleFunc("/api/loglevel", s.handleLogLevel)
+	mux.HandleFunc("/api/info", s.handleInfo)
+	mux.HandleFunc("/api/version", s.handleVersion)
+	mux.HandleFunc("/api/events", s.handleEvents)
+	mux.HandleFunc("/api/events/stream", s.handleEventStream)
+
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", metrics.Handler())
+
+	// Health check endpoint (liveness: the process is up; ?verbose=1 adds component status)
+	mux.HandleFunc("/healthz", s.handleHealth)
+
+	// Readiness endpoint: 503 until startup finished and while the scheduler is down
+	mux.HandleFunc("/readyz", s.handleReady)
+
+	// Static UI files (not registered in API-only mode, so UI routes return 404)
+	if !s.Cfg.ServeAPIOnly() {
+		mux.HandleFunc("/", s.serveIndex)
+		mux.Handle("/web/", staticHandler(s.webAssets))
+	} else {
+		log.Info().Msg("api-only mode: web UI disabled")
+	}
 
-	// Health check endpoint
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte("ok"))
-	})
+	return mux
+}
 
-	// Static UI files
-	mux.HandleFunc("/", s.serveIndex)
-	mux.Handle("/web/", http.FileServer(http.FS(webFS)))
+// listenWithFallback binds listen, moving on to the next of up to fallback
+// following ports while the port is held by another process.
+func listenWithFallback(listen string, fallback int) (net.Listener, error) {
+	listener, err := net.Listen("tcp", listen)
+	if err == nil || !isAddrInUse(err) {
+		return listener, err
+	}
 
-	hs := &http.Server{
-		Addr:    listen,
-		Handler: logRequest(mux),
+	host, portText, splitErr := net.SplitHostPort(listen)
+	port, portErr := strconv.Atoi(portText)
+	if splitErr != nil || portErr != nil || port == 0 {
+		return nil, fmt.Errorf("listen address %s is already in use by another process", listen)
 	}
 
-	go func() {
-		log.Info().Str("listen", listen).Msg("http server starting")
-		if err := hs.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error().Err(err).Msg("http server")
+	for next := port + 1; next <= port+fallback && next <= 65535; next++ {
+		address := net.JoinHostPort(host, strconv.Itoa(next))
+		listener, nextErr := net.Listen("tcp", address)
+		if nextErr == nil {
+			log.Warn().Str("configured", listen).Str("listen", address).Msg("listen port in use, using the next free port")
+			return listener, nil
 		}
-	}()
+		if !isAddrInUse(nextErr) {
+			return nil, nextErr
+		}
+	}
 
-	return hs
+	if fallback > 0 {
+		return nil, fmt.Errorf("listen address %s and the %d following ports are already in use", listen, fallback)
+	}
+	return nil, fmt.Errorf("listen address %s is already in use by another process (set listenPortFallback to try other ports)", listen)
+}
+
+// noWriteTimeout clears the server write deadline for a response that streams
+// for longer than the configured write timeout (event stream, log download)
+func noWriteTimeout(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Debug().Err(err).Msg("failed to clear write deadline")
+	}
 }
 
 // ShutdownHTTP gracefully shuts down the HTTP server
@@ -121,8 +320,39 @@ func (s *Server) ShutdownHTTP(hs *http.Server) {
 	_ = hs.Shutdown(ctx)
 }
 
+// MarkReady reports the server as ready on /readyz. Call it once pairs have been
+// started, so traffic isn't routed to an instance that is still initializing.
+func (s *Server) MarkReady() {
+	s.ready.Store(true)
+	log.Info().Msg("server ready")
+}
+
+// handleReady answers the readiness probe
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	if !s.ready.Load() || s.PairManager == nil || !s.PairManager.SchedulerRunning() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ready"))
+}
+
+// Drain reports the server as not ready and lets running syncs finish, up to
+// the configured drain timeout, before Close cancels them.
+func (s *Server) Drain() {
+	s.ready.Store(false)
+	if s.PairManager == nil {
+		return
+	}
+	s.CfgMu.Lock()
+	timeout := s.Cfg.DrainPeriod()
+	s.CfgMu.Unlock()
+	s.PairManager.Drain(timeout)
+}
+
 // Close cancels the server's background context and closes the pair manager
 func (s *Server) Close() {
+	s.ready.Store(false)
 	if s.cancel != nil {
 		s.cancel()
 	}
@@ -150,26 +380,43 @@ func logRequest(next http.Handler) http.Handler {
 
 // ===== STATIC FILE HANDLERS =====
 
-// serveIndex serves the main index.html file
+// serveIndex serves the main index.html file, or a built-in fallback page
+// when the build has no embedded web UI
 func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Path != "/" {
 		http.NotFound(w, r)
 		return
 	}
 
-	b, err := webFS.ReadFile("web/index.html")
-	if err != nil {
+	if !s.hasIndex {
+		serveFallbackIndex(w)
+		return
+	}
+
+	if err := serveEmbeddedFile(w, r, s.webAssets, IndexAssetPath, IndexCacheControl); err != nil {
 		log.Error().Err(err).Msg("serve index")
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
-
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
-	_, _ = w.Write(b)
 }
 
 // ===== API HANDLERS =====
 
+// SyncAllPairs returns the pairs a "sync all" covers, from the API or the tray:
+// enabled pairs that aren't paused.
+func (s *Server) SyncAllPairs() []*cfg.Pair {
+	s.CfgMu.Lock()
+	defer s.CfgMu.Unlock()
+
+	pairs := make([]*cfg.Pair, 0, len(s.Cfg.Pairs))
+	for _, p := range s.Cfg.Pairs {
+		if p.Enabled && !p.Paused {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs
+}
+
 // handleSyncAll triggers synchronization for all enabled pairs
 func (s *Server) handleSyncAll(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -177,22 +424,31 @@ func (s *Server) handleSyncAll(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get all pairs and trigger synchronization through PairManager
-	s.CfgMu.Lock()
-	pairs := make([]*cfg.Pair, len(s.Cfg.Pairs))
-	copy(pairs, s.Cfg.Pairs)
-	s.CfgMu.Unlock()
+	// Enabled, unpaused pairs at the time of the request
+	pairs := s.SyncAllPairs()
+
+	// ?async=true returns a job to poll at /api/jobs/{id} instead of waiting
+	if async, _ := strconv.ParseBool(r.URL.Query().Get("async")); async {
+		ids := make([]string, len(pairs))
+		for i, p := range pairs {
+			ids[i] = p.ID
+		}
+		job := s.jobs.create(ids)
+		go s.runSyncAllJob(job, pairs)
+
+		w.Header().Set("Location", "/api/jobs/"+job.ID)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		writeJSON(w, map[string]string{"jobId": job.ID})
+		return
+	}
 
 	totalFiles := 0
 	var totalBytes int64
 
 	for _, p := range pairs {
-		if !p.Enabled {
-			continue
-		}
-
 		// Use direct synchronization for syncAll operation
-		copier := &core.Copier{}
+		copier := s.PairManager.NewCopier()
 		files, bytes, err := copier.CompareAndSync(s.ctx, p)
 		if err != nil {
 			log.Error().Str("pair", p.ID).Err(err).Msg("sync all failed for pair")
@@ -208,34 +464,139 @@ func (s *Server) handleSyncAll(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// runSyncAllJob syncs the pairs one after another in the background, recording
+// each pair's outcome in the job
+func (s *Server) runSyncAllJob(job *Job, pairs []*cfg.Pair) {
+	for i, p := range pairs {
+		s.jobs.update(job, func(job *Job) {
+			job.Pairs[i].State = JobPairRunning
+		})
+
+		copier := s.PairManager.NewCopier()
+		files, bytes, err := copier.CompareAndSync(s.ctx, p)
+		if err != nil {
+			log.Error().Str("pair", p.ID).Str("job", job.ID).Err(err).Msg("sync all failed for pair")
+		}
+
+		s.jobs.update(job, func(job *Job) {
+			pair := job.Pairs[i]
+			pair.Files, pair.Bytes = files, bytes
+			job.Files += files
+			job.Bytes += bytes
+			if err != nil {
+				pair.State = JobPairFailed
+				pair.Error = err.Error()
+				job.Failed++
+			} else {
+				pair.State = JobPairSucceeded
+			}
+		})
+	}
+
+	s.jobs.update(job, func(job *Job) {
+		now := time.Now()
+		job.State = JobCompleted
+		job.FinishedAt = &now
+	})
+	log.Info().Str("job", job.ID).Int("pairs", len(pairs)).Msg("sync all job completed")
+}
+
+// handleInfo reports runtime information such as the use of global limits
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"openFiles":    core.OpenFilesUsage(),
+		"hooks":        core.HookSlotsUsage(),
+		"initialSyncs": core.InitialSyncsUsage(),
+		"syncs":        s.PairManager.SyncSlotsUsage(),
+	})
+}
+
+// handleVersion returns the version information of the running binary
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeJSON(w, buildinfo.Get())
+}
+
 // handlePairs manages the collection of sync pairs (GET, POST)
 func (s *Server) handlePairs(w http.ResponseWriter, r *http.Request) {
-	s.CfgMu.Lock()
-	defer s.CfgMu.Unlock()
-
 	switch r.Method {
 	case http.MethodGet:
-		s.handleGetPairs(w)
+		s.CfgMu.Lock()
+		defer s.CfgMu.Unlock()
+		s.handleGetPairs(w, r)
 	case http.MethodPost:
+		// Takes the config lock itself once the pair is validated
 		s.handleCreatePair(w, r)
 	default:
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-// handleGetPairs returns all pairs with their current status
-func (s *Server) handleGetPairs(w http.ResponseWriter) {
-	pairs := make([]*PairWithStatus, len(s.Cfg.Pairs))
-	for i, p := range s.Cfg.Pairs {
+// handleGetPairs returns all pairs with their current status.
+// The optional "tag" query parameter limits the result to pairs with that tag.
+func (s *Server) handleGetPairs(w http.ResponseWriter, r *http.Request) {
+	tag := r.URL.Query().Get("tag")
+
+	pairs := make([]*PairWithStatus, 0, len(s.Cfg.Pairs))
+	for _, p := range s.Cfg.Pairs {
+		if tag != "" && !p.HasTag(tag) {
+			continue
+		}
 		status, _ := s.PairManager.GetPairStatus(p.ID)
-		pairs[i] = &PairWithStatus{
+		pairs = append(pairs, &PairWithStatus{
 			Pair:   *p,
 			Status: status,
-		}
+		})
 	}
 	writeJSON(w, pairs)
 }
 
+// validateSubmittedPair expands environment references in a pair created or updated
+// through the API, validates it and, when RequireExistingSource is set, checks that
+// its source directory exists. A one-time run must lie in the future unless it is
+// unchanged from the stored pair. Must be called without the config lock held.
+func (s *Server) validateSubmittedPair(p *cfg.Pair) error {
+	s.CfgMu.Lock()
+	err := s.Cfg.ExpandPairEnv(p)
+	requireSource := s.Cfg.RequireExistingSource
+	var previous *scheduler.Schedule
+	for _, stored := range s.Cfg.Pairs {
+		if stored.ID == p.ID {
+			schedule := stored.Schedule
+			previous = &schedule
+			break
+		}
+	}
+	s.CfgMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := core.ValidatePair(p); err != nil {
+		return err
+	}
+	if err := core.ValidateRunAtChange(previous, &p.Schedule); err != nil {
+		return err
+	}
+	if requireSource {
+		for _, source := range p.SourceRoots() {
+			if err := cfg.ValidateSourceDir(source); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // handleCreatePair creates a new sync pair
 func (s *Server) handleCreatePair(w http.ResponseWriter, r *http.Request) {
 	var p cfg.Pair
@@ -244,11 +605,14 @@ func (s *Server) handleCreatePair(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := core.ValidatePair(&p); err != nil {
+	if err := s.validateSubmittedPair(&p); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	s.CfgMu.Lock()
+	defer s.CfgMu.Unlock()
+
 	// Check ID uniqueness
 	for _, existing := range s.Cfg.Pairs {
 		if existing.ID == p.ID {
@@ -260,6 +624,7 @@ func (s *Server) handleCreatePair(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	p.Rev = 1
 	s.Cfg.Pairs = append(s.Cfg.Pairs, &p)
 	_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
 	log.Info().Str("pair", p.ID).Msg("pair created")
@@ -293,8 +658,12 @@ func (s *Server) handlePairByID(w http.ResponseWriter, r *http.Request) {
 // handlePairCRUD handles CRUD operations for individual pairs
 func (s *Server) handlePairCRUD(w http.ResponseWriter, r *http.Request, id string) {
 	switch r.Method {
+	case http.MethodGet:
+		s.handleGetPair(w, id)
 	case http.MethodPut:
 		s.handleUpdatePair(w, r, id)
+	case http.MethodPatch:
+		s.handlePatchPair(w, r, id)
 	case http.MethodDelete:
 		s.handleDeletePair(w, id)
 	default:
@@ -302,55 +671,103 @@ func (s *Server) handlePairCRUD(w http.ResponseWriter, r *http.Request, id strin
 	}
 }
 
+// handleGetPair returns a single pair's configuration with its current status
+// (absent while the pair isn't running), like one entry of GET /api/pairs
+func (s *Server) handleGetPair(w http.ResponseWriter, id string) {
+	s.CfgMu.Lock()
+	var pair *PairWithStatus
+	for _, p := range s.Cfg.Pairs {
+		if p.ID == id {
+			pair = &PairWithStatus{Pair: *p}
+			break
+		}
+	}
+	s.CfgMu.Unlock()
+
+	if pair == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	pair.Status, _ = s.PairManager.GetPairStatus(id)
+	w.Header().Set("ETag", pairETag(pair.Rev))
+	writeJSON(w, pair)
+}
+
 // handleUpdatePair updates an existing sync pair
 func (s *Server) handleUpdatePair(w http.ResponseWriter, r *http.Request, id string) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	var incoming cfg.Pair
-	if err := json.NewDecoder(r.Body).Decode(&incoming); err != nil {
+	if err := json.Unmarshal(body, &incoming); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	expected, err := expectedRev(r, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
 
-	if err := core.ValidatePair(&incoming); err != nil {
+	if err := s.validateSubmittedPair(&incoming); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	s.applyPairUpdate(w, id, &incoming, expected)
+}
+
+// applyPairUpdate replaces a validated pair in the configuration when the stored
+// revision is the expected one, bumps the revision, saves it and starts, updates
+// or stops the pair to match, then writes the stored pair
+func (s *Server) applyPairUpdate(w http.ResponseWriter, id string, incoming *cfg.Pair, expected int) {
 	// Update under config lock
 	s.CfgMu.Lock()
-	updated := false
-	var oldPtr *cfg.Pair
+	index := -1
 	for i := range s.Cfg.Pairs {
 		if s.Cfg.Pairs[i].ID == id {
-			oldPtr = s.Cfg.Pairs[i]
-			s.Cfg.Pairs[i] = &incoming
-			updated = true
+			index = i
 			break
 		}
 	}
 
-	if !updated {
+	if index < 0 {
 		s.CfgMu.Unlock()
 		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
 
+	// Reject updates based on a revision someone else has since replaced
+	current := s.Cfg.Pairs[index].Rev
+	if expected != AnyRev && expected != current {
+		s.CfgMu.Unlock()
+		writeRevConflict(w, id, current)
+		return
+	}
+	incoming.Rev = current + 1
+	s.Cfg.Pairs[index] = incoming
+
 	_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
-	s.CfgMu.Unlock()
 
-	// Update through PairManager
+	// Update through PairManager, still under the config lock so concurrent
+	// enable/disable calls can't interleave with it
 	if incoming.Enabled {
-		if oldPtr == nil || !oldPtr.Enabled {
-			// Pair was disabled, now enabling
-			s.PairManager.StartPair(&incoming)
+		if !s.PairManager.IsPairRunning(id) {
+			// Pair was stopped, now starting
+			s.PairManager.StartPair(incoming)
 		} else {
-			// Pair was enabled, updating configuration
-			s.PairManager.UpdatePair(&incoming)
+			// Pair was running, updating configuration
+			s.PairManager.UpdatePair(incoming)
 		}
 	} else {
 		// Disabling pair
 		s.PairManager.StopPair(id)
 	}
+	s.CfgMu.Unlock()
 
+	w.Header().Set("ETag", pairETag(incoming.Rev))
 	writeJSON(w, incoming)
 }
 
@@ -384,14 +801,30 @@ func (s *Server) handlePairActions(w http.ResponseWriter, r *http.Request, id, a
 		s.handleStartPair(w, id)
 	case http.MethodPost + " stop":
 		s.handleStopPair(w, id)
+	case http.MethodPost + " pause":
+		s.handleSetPaused(w, id, true)
+	case http.MethodPost + " unpause":
+		s.handleSetPaused(w, id, false)
 	case http.MethodPost + " sync":
-		s.handleSyncPair(w, id)
+		s.idempotent(func(w http.ResponseWriter, r *http.Request) {
+			s.handleSyncPair(w, id)
+		})(w, r)
 	case http.MethodGet + " status":
 		s.handleGetPairStatus(w, id)
 	case http.MethodGet + " hook-status":
 		s.handleGetHookStatus(w, id)
+	case http.MethodGet + " hook-history":
+		s.handleGetHookHistory(w, id)
 	case http.MethodPost + " test-hook":
 		s.handleTestHook(w, id)
+	case http.MethodPost + " dry-run":
+		s.handleDryRun(w, r, id)
+	case http.MethodGet + " diff":
+		s.handleDiff(w, r, id)
+	case http.MethodGet + " pending-deletes":
+		s.handleGetPendingDeletes(w, id)
+	case http.MethodPost + " apply-deletes":
+		s.handleApplyDeletes(w, r, id)
 	default:
 		http.Error(w, "not found", http.StatusNotFound)
 	}
@@ -415,15 +848,101 @@ func (s *Server) handleStopPair(w http.ResponseWriter, id string) {
 	writeJSON(w, map[string]string{"status": "stopped"})
 }
 
+// handleSetPaused pauses or unpauses a sync pair
+func (s *Server) handleSetPaused(w http.ResponseWriter, id string, paused bool) {
+	if err := s.SetPaused(id, paused); err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	status := "unpaused"
+	if paused {
+		status = "paused"
+	}
+	writeJSON(w, map[string]string{"status": status})
+}
+
 // handleSyncPair triggers immediate synchronization for a pair
 func (s *Server) handleSyncPair(w http.ResponseWriter, id string) {
 	if err := s.PairManager.SyncPairNow(id); err != nil {
+		if errors.Is(err, core.ErrPairPaused) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	writeJSON(w, map[string]string{"status": "sync started"})
 }
 
+// handleDryRun plans a sync for a pair without modifying the target and
+// returns the planned copies, deletions and detected renames
+func (s *Server) handleDryRun(w http.ResponseWriter, r *http.Request, id string) {
+	p := s.findPair(id)
+	if p == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	copier := &core.Copier{}
+	result, err := copier.DryRun(r.Context(), p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
+// handleDiff reports how the pair's targets diverge from its sources, one entry
+// per target, without copying or deleting anything
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request, id string) {
+	p := s.findPair(id)
+	if p == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	diffs, err := core.DiffPair(r.Context(), p)
+	if errors.Is(err, core.ErrDiffArchive) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, diffs)
+}
+
+// handleGetPendingDeletes lists target files waiting to be deleted (deferred mirror deletes)
+func (s *Server) handleGetPendingDeletes(w http.ResponseWriter, id string) {
+	if s.findPair(id) == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, core.PendingDeletes(id))
+}
+
+// handleApplyDeletes deletes the pair's pending target files now
+func (s *Server) handleApplyDeletes(w http.ResponseWriter, r *http.Request, id string) {
+	p := s.findPair(id)
+	if p == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	result, err := core.ApplyPendingDeletes(r.Context(), p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, result)
+}
+
 // handleGetPairStatus returns the current status of a sync pair
 func (s *Server) handleGetPairStatus(w http.ResponseWriter, id string) {
 	status, err := s.PairManager.GetPairStatus(id)
@@ -434,6 +953,52 @@ func (s *Server) handleGetPairStatus(w http.ResponseWriter, id string) {
 	writeJSON(w, status)
 }
 
+// BatchStatusRequest is the request body for the batch pair status endpoint
+type BatchStatusRequest struct {
+	IDs []string `json:"ids"` // Pair IDs to fetch status for
+}
+
+// BatchStatusResponse maps pair IDs to their status and lists unknown IDs
+type BatchStatusResponse struct {
+	Statuses map[string]*core.PairStatus `json:"statuses"` // Status by pair ID
+	NotFound []string                    `json:"notFound"` // Requested IDs without a status
+}
+
+// handleBatchPairStatus returns the status of several pairs in one request.
+// Only POST is handled here; other methods fall through to the pair-by-ID
+// handler so a pair that happens to be named "status" stays reachable.
+func (s *Server) handleBatchPairStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.handlePairByID(w, r)
+		return
+	}
+
+	var req BatchStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := BatchStatusResponse{
+		Statuses: make(map[string]*core.PairStatus, len(req.IDs)),
+		NotFound: []string{},
+	}
+
+	for _, id := range req.IDs {
+		if _, seen := resp.Statuses[id]; seen {
+			continue
+		}
+		status, err := s.PairManager.GetPairStatus(id)
+		if err != nil {
+			resp.NotFound = append(resp.NotFound, id)
+			continue
+		}
+		resp.Statuses[id] = status
+	}
+
+	writeJSON(w, resp)
+}
+
 // handleGetHookStatus returns the last hook execution status
 func (s *Server) handleGetHookStatus(w http.ResponseWriter, id string) {
 	if st, ok := core.GetLastHookStatus(id); ok {
@@ -443,6 +1008,15 @@ func (s *Server) handleGetHookStatus(w http.ResponseWriter, id string) {
 	}
 }
 
+// handleGetHookHistory returns the pair's recent hook executions, newest first
+func (s *Server) handleGetHookHistory(w http.ResponseWriter, id string) {
+	if s.findPair(id) == nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, core.GetHookHistory(id))
+}
+
 // handleTestHook executes hooks for testing purposes
 func (s *Server) handleTestHook(w http.ResponseWriter, id string) {
 	p := s.findPair(id)
@@ -477,12 +1051,20 @@ func (s *Server) handleScheduleExamples(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	now := time.Now()
+	tomorrowAt3 := time.Date(now.Year(), now.Month(), now.Day()+1, 3, 0, 0, 0, now.Location())
+
 	examples := []ScheduleExample{
 		{
 			Name:        "Watcher (File Changes)",
 			Description: "Monitor file changes and sync immediately",
 			Schedule:    scheduler.NewWatcherSchedule(),
 		},
+		{
+			Name:        "Polling watch (network drives)",
+			Description: "Compare source and target every minute where file events are unreliable (SMB/NFS)",
+			Schedule:    scheduler.NewPollSchedule("1m"),
+		},
 		{
 			Name:        "Every 15 minutes",
 			Description: "Sync every 15 minutes continuously",
@@ -513,6 +1095,11 @@ func (s *Server) handleScheduleExamples(w http.ResponseWriter, r *http.Request)
 			Description: "Every 30 minutes from 8 AM to 8 PM on workdays",
 			Schedule:    scheduler.NewCronSchedule("0 */30 8-20 * * 1-5"),
 		},
+		{
+			Name:        "Once tomorrow at 3 AM",
+			Description: "Single run at 3:00 AM tomorrow, then the schedule disables itself",
+			Schedule:    scheduler.NewOnceSchedule(tomorrowAt3),
+		},
 		{
 			Name:        "Weekend maintenance",
 			Description: "Saturday and Sunday at 2:00 AM",
@@ -523,6 +1110,48 @@ func (s *Server) handleScheduleExamples(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, examples)
 }
 
+// handleSchedulePreview returns the next run times of a schedule without saving it.
+// The request body is a schedule; the optional "count" query parameter sets how
+// many run times are returned (default 5).
+func (s *Server) handleSchedulePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var schedule scheduler.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count := scheduler.DefaultPreviewCount
+	if raw := r.URL.Query().Get("count"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
+		}
+		count = parsed
+	}
+
+	s.CfgMu.Lock()
+	holidays, _ := scheduler.NewHolidayList(s.Cfg.Holidays)
+	s.CfgMu.Unlock()
+
+	runs, err := scheduler.PreviewRuns(schedule, time.Now(), count, holidays)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timezone := schedule.Timezone
+	if timezone == "" {
+		timezone = time.Local.String()
+	}
+	writeJSON(w, SchedulePreviewResponse{Timezone: timezone, NextRuns: runs})
+}
+
 // ===== UTILITY FUNCTIONS =====
 
 // findPair locates a sync pair by ID (thread-safe)
@@ -537,9 +1166,14 @@ func (s *Server) findPair(id string) *cfg.Pair {
 	return nil
 }
 
-// SetEnabled updates a pair's enabled flag, starts/stops worker, and persists config
+// SetEnabled updates a pair's enabled flag, starts/stops worker, and persists config.
+// The config lock is held until the pair manager has acted, so interleaved calls
+// (tray and API toggling at once) apply in order and the last one wins; the running
+// state is compared instead of the previous flag so a pair left out of sync converges.
 func (s *Server) SetEnabled(id string, enabled bool) error {
 	s.CfgMu.Lock()
+	defer s.CfgMu.Unlock()
+
 	var p *cfg.Pair
 	for i := range s.Cfg.Pairs {
 		if s.Cfg.Pairs[i].ID == id {
@@ -548,24 +1182,52 @@ func (s *Server) SetEnabled(id string, enabled bool) error {
 		}
 	}
 	if p == nil {
-		s.CfgMu.Unlock()
 		return http.ErrMissingFile
 	}
 
-	prev := p.Enabled
-	p.Enabled = enabled
-	_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
-	s.CfgMu.Unlock()
+	if p.Enabled != enabled {
+		p.Enabled = enabled
+		p.Rev++
+		_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
+	}
 
-	if prev == enabled {
+	if s.PairManager.IsPairRunning(id) == enabled {
 		return nil
 	}
-
 	if enabled {
 		return s.PairManager.StartPair(p)
-	} else {
-		return s.PairManager.StopPair(id)
 	}
+	return s.PairManager.StopPair(id)
+}
+
+// SetPaused updates a pair's paused flag, pauses/resumes a started pair, and persists config
+func (s *Server) SetPaused(id string, paused bool) error {
+	s.CfgMu.Lock()
+	defer s.CfgMu.Unlock()
+
+	var p *cfg.Pair
+	for i := range s.Cfg.Pairs {
+		if s.Cfg.Pairs[i].ID == id {
+			p = s.Cfg.Pairs[i]
+			break
+		}
+	}
+	if p == nil {
+		return http.ErrMissingFile
+	}
+
+	prev := p.Paused
+	if prev != paused {
+		p.Paused = paused
+		p.Rev++
+		_ = cfg.Save(s.Paths.ConfigFile, s.Cfg)
+	}
+
+	// Stopped pairs pick up the flag when they are started
+	if prev == paused || !s.PairManager.IsPairRunning(id) {
+		return nil
+	}
+	return s.PairManager.UpdatePair(p)
 }
 
 // ListPairsSummary returns brief information about pairs for tray display
@@ -574,10 +1236,21 @@ func (s *Server) ListPairsSummary() []tray.PairSummary {
 	defer s.CfgMu.Unlock()
 	res := make([]tray.PairSummary, 0, len(s.Cfg.Pairs))
 	for _, p := range s.Cfg.Pairs {
-		res = append(res, tray.PairSummary{
+		summary := tray.PairSummary{
 			ID:      p.ID,
 			Enabled: p.Enabled,
-		})
+			Paused:  p.Paused,
+		}
+
+		// Run status is only available for started pairs
+		if status, err := s.PairManager.GetPairStatus(p.ID); err == nil {
+			summary.LastRun = status.LastRun
+			summary.RunCount = status.RunCount
+			summary.FailCount = status.FailCount
+			summary.LastError = status.LastError
+		}
+
+		res = append(res, summary)
 	}
 	return res
 }
@@ -618,6 +1291,9 @@ func hooksEqual(a, b []cfg.Hook) bool {
 		if !stringSlicesEqual(a[i].MatchGlobs, b[i].MatchGlobs) {
 			return false
 		}
+		if a[i].TimeoutMs != b[i].TimeoutMs || a[i].MaxRetries != b[i].MaxRetries || a[i].TriggerOnlyForGlob != b[i].TriggerOnlyForGlob {
+			return false
+		}
 
 		// Compare HTTP hooks
 		ah, bh := a[i].HTTP, b[i].HTTP
@@ -644,7 +1320,7 @@ func hooksEqual(a, b []cfg.Hook) bool {
 			return false
 		}
 		if ac != nil && bc != nil {
-			if ac.Executable != bc.Executable || ac.WorkDir != bc.WorkDir {
+			if ac.Executable != bc.Executable || ac.WorkDir != bc.WorkDir || ac.StdinTemplate != bc.StdinTemplate {
 				return false
 			}
 			if !stringSlicesEqual(ac.Args, bc.Args) {
@@ -659,6 +1335,25 @@ func hooksEqual(a, b []cfg.Hook) bool {
 				}
 			}
 		}
+
+		// Compare gRPC hooks
+		ag, bg := a[i].GRPC, b[i].GRPC
+		if (ag == nil) != (bg == nil) {
+			return false
+		}
+		if ag != nil && bg != nil {
+			if ag.Target != bg.Target || ag.Method != bg.Method || ag.BodyTemplate != bg.BodyTemplate || ag.Insecure != bg.Insecure {
+				return false
+			}
+			if len(ag.Metadata) != len(bg.Metadata) {
+				return false
+			}
+			for k, v := range ag.Metadata {
+				if bg.Metadata[k] != v {
+					return false
+				}
+			}
+		}
 	}
 	return true
 }
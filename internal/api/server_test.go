@@ -0,0 +1,441 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/core"
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// ===== TEST HELPERS =====
+
+// newTestServer returns a server over the given pairs, saving its config to a
+// temporary file. Pairs aren't started.
+func newTestServer(t *testing.T, pairs ...*cfg.Pair) *Server {
+	t.Helper()
+	pairManager, err := core.NewPairManager(core.DefaultMaxConcurrentSyncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		pairManager.Close()
+	})
+
+	dir := t.TempDir()
+	return &Server{
+		Cfg:         &cfg.Config{Pairs: pairs},
+		Paths:       cfg.Paths{ConfigFile: filepath.Join(dir, "config.json")},
+		PairManager: pairManager,
+		ctx:         ctx,
+		cancel:      cancel,
+		idempotency: newIdempotencyCache(IdempotencyKeyTTL, IdempotencyCacheSize),
+		jobs:        newJobStore(),
+	}
+}
+
+// newTestPair returns a disabled pair syncing a fresh source to a fresh target
+func newTestPair(t *testing.T, id string) *cfg.Pair {
+	t.Helper()
+	return &cfg.Pair{
+		ID:           id,
+		Source:       t.TempDir(),
+		Target:       t.TempDir(),
+		SyncStrategy: "mtime",
+	}
+}
+
+// serve sends a request through the server's routes
+func serve(t *testing.T, s *Server, method, url, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	request := httptest.NewRequest(method, url, strings.NewReader(body))
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	s.routes().ServeHTTP(recorder, request)
+	return recorder
+}
+
+// decodeJSON decodes a recorded JSON response
+func decodeJSON(t *testing.T, recorder *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %q: %v", recorder.Body.String(), err)
+	}
+}
+
+// ===== API-ONLY MODE =====
+
+func TestAPIOnlyModeServesNoUI(t *testing.T) {
+	tests := []struct {
+		name     string
+		apiOnly  func(conf *cfg.Config)
+		uiStatus int
+	}{
+		{"ui enabled", func(conf *cfg.Config) {}, http.StatusOK},
+		{"api-only config", func(conf *cfg.Config) { conf.APIOnly = true }, http.StatusNotFound},
+		{"api-only flag", func(conf *cfg.Config) { conf.Overrides.APIOnly = true }, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.webAssets, s.hasIndex = webFS, hasIndexPage(webFS)
+			tt.apiOnly(s.Cfg)
+
+			for _, url := range []string{"/", "/web/app.js", "/web/styles.css"} {
+				if recorder := serve(t, s, http.MethodGet, url, "", nil); recorder.Code != tt.uiStatus {
+					t.Errorf("GET %s = %d, want %d", url, recorder.Code, tt.uiStatus)
+				}
+			}
+			for _, url := range []string{"/api/pairs", "/api/version", "/healthz"} {
+				if recorder := serve(t, s, http.MethodGet, url, "", nil); recorder.Code != http.StatusOK {
+					t.Errorf("GET %s = %d, want 200", url, recorder.Code)
+				}
+			}
+		})
+	}
+}
+
+// ===== BATCH STATUS =====
+
+func TestBatchPairStatus(t *testing.T) {
+	first, second := newTestPair(t, "first"), newTestPair(t, "second")
+	s := newTestServer(t, first, second)
+	for _, pair := range []*cfg.Pair{first, second} {
+		pair.Schedule = scheduler.NewCronSchedule("0 0 0 * * *")
+		if err := s.PairManager.StartPair(pair); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		wantStatuses []string
+		wantNotFound []string
+	}{
+		{"existing pairs", `{"ids":["first","second"]}`, []string{"first", "second"}, nil},
+		{"mixed existing and missing", `{"ids":["first","ghost"]}`, []string{"first"}, []string{"ghost"}},
+		{"duplicates collapse", `{"ids":["second","second"]}`, []string{"second"}, nil},
+		{"only missing", `{"ids":["ghost"]}`, nil, []string{"ghost"}},
+		{"empty list", `{"ids":[]}`, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(t, s, http.MethodPost, "/api/pairs/status", tt.body, nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("POST /api/pairs/status = %d %s", recorder.Code, recorder.Body.String())
+			}
+			var resp BatchStatusResponse
+			decodeJSON(t, recorder, &resp)
+
+			var ids []string
+			for id, status := range resp.Statuses {
+				if status.ID != id {
+					t.Errorf("status under %q has ID %q", id, status.ID)
+				}
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.wantStatuses) {
+				t.Errorf("statuses for %v, want %v", ids, tt.wantStatuses)
+			}
+			if !slices.Equal(resp.NotFound, tt.wantNotFound) {
+				t.Errorf("notFound = %v, want %v", resp.NotFound, tt.wantNotFound)
+			}
+		})
+	}
+
+	if recorder := serve(t, s, http.MethodPost, "/api/pairs/status", "not json", nil); recorder.Code != http.StatusBadRequest {
+		t.Errorf("malformed body = %d, want 400", recorder.Code)
+	}
+}
+
+// ===== PROFILES AND TAGS =====
+
+func TestReloadStartsOnlyProfilePairs(t *testing.T) {
+	tests := []struct {
+		name        string
+		profile     func(conf *cfg.Config)
+		wantRunning []string
+	}{
+		{"no profile starts all", func(conf *cfg.Config) {}, []string{"heavy", "light", "untagged"}},
+		{"configured profile", func(conf *cfg.Config) { conf.ActiveProfile = "light" }, []string{"light"}},
+		{"profile flag", func(conf *cfg.Config) { conf.Overrides.ActiveProfile = "LIGHT" }, []string{"light"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			tt.profile(s.Cfg)
+
+			var pairs []*cfg.Pair
+			for _, id := range []string{"heavy", "light", "untagged"} {
+				pair := newTestPair(t, id)
+				pair.Enabled = true
+				pair.Schedule = scheduler.NewCronSchedule("0 0 0 * * *")
+				if id != "untagged" {
+					pair.Tags = []string{id}
+				}
+				pairs = append(pairs, pair)
+			}
+			if err := cfg.Save(s.Paths.ConfigFile, &cfg.Config{Pairs: pairs}); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := s.ReloadConfig(); err != nil {
+				t.Fatalf("ReloadConfig: %v", err)
+			}
+			var running []string
+			for _, pair := range pairs {
+				if s.PairManager.IsPairRunning(pair.ID) {
+					running = append(running, pair.ID)
+				}
+			}
+			if !slices.Equal(running, tt.wantRunning) {
+				t.Errorf("running pairs %v, want %v", running, tt.wantRunning)
+			}
+		})
+	}
+}
+
+func TestGetPairsFiltersByTag(t *testing.T) {
+	light, heavy, untagged := newTestPair(t, "light"), newTestPair(t, "heavy"), newTestPair(t, "untagged")
+	light.Tags = []string{"light", "laptop"}
+	heavy.Tags = []string{"heavy"}
+	s := newTestServer(t, light, heavy, untagged)
+
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{"/api/pairs", []string{"light", "heavy", "untagged"}},
+		{"/api/pairs?tag=light", []string{"light"}},
+		{"/api/pairs?tag=Laptop", []string{"light"}},
+		{"/api/pairs?tag=unknown", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			recorder := serve(t, s, http.MethodGet, tt.url, "", nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d", tt.url, recorder.Code)
+			}
+			var pairs []PairWithStatus
+			decodeJSON(t, recorder, &pairs)
+			var ids []string
+			for _, pair := range pairs {
+				ids = append(ids, pair.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("GET %s returned %v, want %v", tt.url, ids, tt.want)
+			}
+		})
+	}
+}
+
+// ===== PAIR PATH VALIDATION =====
+
+func TestCreatePairValidatesPaths(t *testing.T) {
+	root := t.TempDir()
+	existing := filepath.Join(root, "src")
+	if err := os.Mkdir(existing, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		source        string
+		target        string
+		requireSource bool
+		want          int
+		wantErr       string
+	}{
+		{"valid", existing, filepath.Join(root, "dst"), true, http.StatusOK, ""},
+		{"target inside source", existing, filepath.Join(existing, "copy"), false, http.StatusBadRequest, "is inside source"},
+		{"source inside target", existing, root, false, http.StatusBadRequest, "is inside target"},
+		{"missing source allowed", filepath.Join(root, "later"), filepath.Join(root, "dst"), false, http.StatusOK, ""},
+		{"missing source required", filepath.Join(root, "later"), filepath.Join(root, "dst"), true, http.StatusBadRequest, "does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.Cfg.RequireExistingSource = tt.requireSource
+			body, _ := json.Marshal(cfg.Pair{ID: "new", Source: tt.source, Target: tt.target})
+
+			recorder := serve(t, s, http.MethodPost, "/api/pairs", string(body), nil)
+			if recorder.Code != tt.want {
+				t.Fatalf("POST /api/pairs = %d (%s), want %d", recorder.Code, recorder.Body, tt.want)
+			}
+			if tt.wantErr != "" && !strings.Contains(recorder.Body.String(), tt.wantErr) {
+				t.Errorf("error %q doesn't mention %q", recorder.Body, tt.wantErr)
+			}
+		})
+	}
+}
+
+// ===== PAUSED PAIRS =====
+
+func TestPausedPairSurvivesRestart(t *testing.T) {
+	pair := newTestPair(t, "paused")
+	pair.Enabled = true
+	pair.Schedule = scheduler.NewIntervalSchedule("1h")
+	if err := os.WriteFile(filepath.Join(pair.Source, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := newTestServer(t, pair)
+	if err := s.PairManager.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder := serve(t, s, http.MethodPost, "/api/pairs/paused/pause", "", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("pause = %d: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := serve(t, s, http.MethodPost, "/api/pairs/paused/sync", "", nil); recorder.Code != http.StatusConflict {
+		t.Errorf("sync of a paused pair = %d, want 409", recorder.Code)
+	}
+
+	// Restart: a new server loads the saved config and starts enabled pairs
+	restarted := newTestServer(t)
+	restarted.Paths = s.Paths
+	if _, err := restarted.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	loaded := restarted.findPair("paused")
+	if loaded == nil || !loaded.Enabled || !loaded.Paused {
+		t.Fatalf("reloaded pair = %+v, want enabled and paused", loaded)
+	}
+	var status core.PairStatus
+	decodeJSON(t, serve(t, restarted, http.MethodGet, "/api/pairs/paused/status", "", nil), &status)
+	if !status.Paused || !restarted.PairManager.IsPairRunning("paused") {
+		t.Errorf("restarted pair paused %v, running %v, want both", status.Paused, restarted.PairManager.IsPairRunning("paused"))
+	}
+	if err := restarted.PairManager.SyncPairNow("paused"); !errors.Is(err, core.ErrPairPaused) {
+		t.Errorf("SyncPairNow after restart = %v, want ErrPairPaused", err)
+	}
+	if _, err := os.Stat(filepath.Join(pair.Target, "a.txt")); !os.IsNotExist(err) {
+		t.Error("paused pair synced")
+	}
+
+	// Unpausing resumes syncing
+	if recorder := serve(t, restarted, http.MethodPost, "/api/pairs/paused/unpause", "", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("unpause = %d: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := serve(t, restarted, http.MethodPost, "/api/pairs/paused/sync", "", nil); recorder.Code != http.StatusOK {
+		t.Fatalf("sync after unpause = %d: %s", recorder.Code, recorder.Body)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(pair.Target, "a.txt")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("unpaused pair didn't sync")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// ===== PENDING DELETES =====
+
+func TestPendingDeletesEndpoints(t *testing.T) {
+	pair := newTestPair(t, "deferred")
+	pair.MirrorDeletes = true
+	pair.MirrorDeleteMode = cfg.MirrorDeleteDeferred
+	s := newTestServer(t, pair)
+
+	if err := os.WriteFile(filepath.Join(pair.Target, "orphan.txt"), []byte("orphan"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := (&core.Copier{}).CompareAndSync(context.Background(), pair); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _, _ = core.ApplyPendingDeletes(context.Background(), pair) })
+
+	var pending []core.PendingDelete
+	decodeJSON(t, serve(t, s, http.MethodGet, "/api/pairs/deferred/pending-deletes", "", nil), &pending)
+	if len(pending) != 1 || pending[0].Path != "orphan.txt" {
+		t.Fatalf("pending deletes = %+v, want orphan.txt", pending)
+	}
+
+	recorder := serve(t, s, http.MethodPost, "/api/pairs/deferred/apply-deletes", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("apply-deletes = %d: %s", recorder.Code, recorder.Body)
+	}
+	var result core.SyncResult
+	decodeJSON(t, recorder, &result)
+	if result.FilesDeleted != 1 {
+		t.Errorf("apply-deletes deleted %d files, want 1", result.FilesDeleted)
+	}
+	if _, err := os.Stat(filepath.Join(pair.Target, "orphan.txt")); !os.IsNotExist(err) {
+		t.Error("orphan.txt still in the target")
+	}
+
+	decodeJSON(t, serve(t, s, http.MethodGet, "/api/pairs/deferred/pending-deletes", "", nil), &pending)
+	if len(pending) != 0 {
+		t.Errorf("pending deletes after apply = %+v", pending)
+	}
+
+	for _, url := range []string{"/api/pairs/missing/pending-deletes", "/api/pairs/missing/apply-deletes"} {
+		method := http.MethodGet
+		if strings.HasSuffix(url, "apply-deletes") {
+			method = http.MethodPost
+		}
+		if recorder := serve(t, s, method, url, "", nil); recorder.Code != http.StatusNotFound {
+			t.Errorf("%s %s = %d, want 404", method, url, recorder.Code)
+		}
+	}
+}
+
+// ===== SYNC ALL =====
+
+func TestSyncAllSkipsDisabledAndPausedPairs(t *testing.T) {
+	active := newTestPair(t, "active")
+	active.Enabled = true
+	paused := newTestPair(t, "paused")
+	paused.Enabled = true
+	paused.Paused = true
+	disabled := newTestPair(t, "disabled")
+
+	for _, pair := range []*cfg.Pair{active, paused, disabled} {
+		if err := os.WriteFile(filepath.Join(pair.Source, "file.txt"), []byte(pair.ID), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := newTestServer(t, active, paused, disabled)
+
+	var ids []string
+	for _, pair := range s.SyncAllPairs() {
+		ids = append(ids, pair.ID)
+	}
+	if strings.Join(ids, ",") != "active" {
+		t.Fatalf("SyncAllPairs() = %v, want [active]", ids)
+	}
+
+	recorder := serve(t, s, http.MethodPost, "/api/syncAll", "", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("POST /api/syncAll = %d %s", recorder.Code, recorder.Body.String())
+	}
+	for _, pair := range []*cfg.Pair{active, paused, disabled} {
+		_, err := os.Stat(filepath.Join(pair.Target, "file.txt"))
+		if synced := err == nil; synced != (pair == active) {
+			t.Errorf("pair %s synced = %v", pair.ID, synced)
+		}
+	}
+}
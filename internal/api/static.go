@@ -0,0 +1,145 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file serves the embedded web UI with explicit content types and cache headers.
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ===== STATIC ASSET CONSTANTS =====
+
+const (
+	// IndexCacheControl makes browsers revalidate the index page on every load
+	IndexCacheControl = "no-cache"
+
+	// AssetCacheControl lets browsers reuse assets for an hour before revalidating
+	AssetCacheControl = "public, max-age=3600"
+
+	// IndexAssetPath is the embedded web UI entry page
+	IndexAssetPath = "web/index.html"
+)
+
+// fallbackIndexHTML is served at / when the build has no embedded web UI
+const fallbackIndexHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>FolderSynchronizer</title>
+</head>
+<body>
+<h1>FolderSynchronizer is running</h1>
+<p>The web UI is not included in this build. The REST API is available:</p>
+<ul>
+<li><a href="/api/pairs">/api/pairs</a> &ndash; sync pairs and status</li>
+<li><a href="/api/schedules/examples">/api/schedules/examples</a> &ndash; schedule examples</li>
+<li><a href="/healthz">/healthz</a> &ndash; health check</li>
+<li><a href="/readyz">/readyz</a> &ndash; readiness check</li>
+<li><a href="/metrics">/metrics</a> &ndash; Prometheus metrics</li>
+</ul>
+</body>
+</html>
+`
+
+// assetContentTypes maps asset extensions to content types, overriding Go's
+// platform-dependent detection for modern web formats
+var assetContentTypes = map[string]string{
+	".html":        "text/html; charset=utf-8",
+	".css":         "text/css; charset=utf-8",
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".webmanifest": "application/manifest+json",
+	".wasm":        "application/wasm",
+	".svg":         "image/svg+xml",
+	".png":         "image/png",
+	".jpg":         "image/jpeg",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+}
+
+// ===== ETAG CACHE =====
+
+// assetETags caches content hashes of embedded assets, which never change at runtime
+var assetETags sync.Map
+
+// assetETag returns a strong ETag for an embedded file, or "" if unreadable.
+func assetETag(fsys fs.FS, name string) string {
+	if etag, cached := assetETags.Load(name); cached {
+		return etag.(string)
+	}
+
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	assetETags.Store(name, etag)
+	return etag
+}
+
+// setAssetHeaders sets content type, ETag and caching headers for an asset.
+func setAssetHeaders(w http.ResponseWriter, fsys fs.FS, name, cacheControl string) {
+	if contentType, known := assetContentTypes[strings.ToLower(path.Ext(name))]; known {
+		w.Header().Set("Content-Type", contentType)
+	}
+	if etag := assetETag(fsys, name); etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	w.Header().Set("Cache-Control", cacheControl)
+}
+
+// ===== STATIC HANDLERS =====
+
+// hasIndexPage reports whether the embedded filesystem contains the UI index page.
+func hasIndexPage(fsys fs.FS) bool {
+	info, err := fs.Stat(fsys, IndexAssetPath)
+	return err == nil && !info.IsDir()
+}
+
+// serveFallbackIndex writes the built-in page shown when the web UI is missing.
+func serveFallbackIndex(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", IndexCacheControl)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(fallbackIndexHTML))
+}
+
+// staticHandler wraps http.FileServer over an embedded filesystem, adding
+// explicit content types and cache headers. Conditional requests carrying a
+// matching If-None-Match are answered with 304 by the file server.
+func staticHandler(fsys fs.FS) http.Handler {
+	fileServer := http.FileServer(http.FS(fsys))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+		if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+			setAssetHeaders(w, fsys, name, AssetCacheControl)
+		}
+		fileServer.ServeHTTP(w, r)
+	})
+}
+
+// serveEmbeddedFile writes a single embedded file with asset headers,
+// honoring conditional requests.
+func serveEmbeddedFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name, cacheControl string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	setAssetHeaders(w, fsys, name, cacheControl)
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(data))
+	return nil
+}
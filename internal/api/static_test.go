@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// ===== STATIC ASSETS =====
+
+func TestStaticAssetHeaders(t *testing.T) {
+	s := newTestServer(t)
+	s.webAssets = fstest.MapFS{
+		"web/index.html":       {Data: []byte("<!DOCTYPE html><title>ui</title>")},
+		"web/module.mjs":       {Data: []byte("export const x = 1")},
+		"web/engine.wasm":      {Data: []byte("\x00asm\x01\x00\x00\x00")},
+		"web/styles.css":       {Data: []byte("body {}")},
+		"web/site.webmanifest": {Data: []byte("{}")},
+		"web/font.woff2":       {Data: []byte("wOF2")},
+	}
+	s.hasIndex = hasIndexPage(s.webAssets)
+
+	tests := []struct {
+		url          string
+		contentType  string
+		cacheControl string
+	}{
+		{"/", "text/html; charset=utf-8", IndexCacheControl},
+		{"/web/module.mjs", "text/javascript; charset=utf-8", AssetCacheControl},
+		{"/web/engine.wasm", "application/wasm", AssetCacheControl},
+		{"/web/styles.css", "text/css; charset=utf-8", AssetCacheControl},
+		{"/web/site.webmanifest", "application/manifest+json", AssetCacheControl},
+		{"/web/font.woff2", "font/woff2", AssetCacheControl},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			recorder := serve(t, s, http.MethodGet, tt.url, "", nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d, want 200", tt.url, recorder.Code)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := recorder.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.cacheControl)
+			}
+
+			// A revalidation with the current ETag is answered without a body
+			etag := recorder.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("no ETag")
+			}
+			revalidated := serve(t, s, http.MethodGet, tt.url, "", map[string]string{"If-None-Match": etag})
+			if revalidated.Code != http.StatusNotModified {
+				t.Errorf("conditional GET = %d, want 304", revalidated.Code)
+			}
+		})
+	}
+}
+
+// ===== FALLBACK INDEX =====
+
+func TestFallbackIndexWhenUIMissing(t *testing.T) {
+	tests := []struct {
+		name   string
+		assets fstest.MapFS
+	}{
+		{"empty assets", fstest.MapFS{}},
+		{"assets without index", fstest.MapFS{"web/app.js": {Data: []byte("app()")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			s.webAssets, s.hasIndex = tt.assets, hasIndexPage(tt.assets)
+			if s.hasIndex {
+				t.Fatal("assets reported an index page")
+			}
+
+			recorder := serve(t, s, http.MethodGet, "/", "", nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("GET / = %d, want 200", recorder.Code)
+			}
+			if got := recorder.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+			if body := recorder.Body.String(); !strings.Contains(body, "FolderSynchronizer is running") || !strings.Contains(body, `href="/api/pairs"`) {
+				t.Errorf("fallback page missing status or API link: %s", body)
+			}
+
+			// Other paths aren't answered with the fallback page
+			if recorder := serve(t, s, http.MethodGet, "/missing", "", nil); recorder.Code != http.StatusNotFound {
+				t.Errorf("GET /missing = %d, want 404", recorder.Code)
+			}
+		})
+	}
+}
+
+func TestEmbeddedAssetsHaveIndex(t *testing.T) {
+	if !hasIndexPage(webFS) {
+		t.Errorf("embedded web assets lack %s", IndexAssetPath)
+	}
+}
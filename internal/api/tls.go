@@ -0,0 +1,141 @@
+// Package api provides HTTP server functionality for the FolderSynchronizer application.
+// This file implements HTTPS certificates: configured files or a generated self-signed one.
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== TLS CONSTANTS =====
+
+const (
+	// SelfSignedCertValidity is how long a generated certificate is valid
+	SelfSignedCertValidity = 365 * 24 * time.Hour
+
+	// SelfSignedRenewBefore regenerates a certificate this close to its expiry
+	SelfSignedRenewBefore = 7 * 24 * time.Hour
+)
+
+// ===== CERTIFICATE SELECTION =====
+
+// tlsFiles returns the certificate and key files to serve HTTPS with, or empty
+// names when TLS is not enabled. With TLSSelfSigned and no configured
+// certificate, a self-signed one for the listen host is generated (or reused)
+// next to the config.
+func (s *Server) tlsFiles(listen string) (string, string, error) {
+	configuredCert, configuredKey, selfSigned := s.Cfg.TLSSettings()
+	if configuredCert != "" {
+		return configuredCert, configuredKey, nil
+	}
+	if !selfSigned {
+		return "", "", nil
+	}
+
+	certFile, keyFile := s.Paths.SelfSignedCertFile, s.Paths.SelfSignedKeyFile
+	if certFile == "" || keyFile == "" {
+		return "", "", errors.New("no location for the self-signed certificate")
+	}
+
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		host = ""
+	}
+	if selfSignedCertValid(certFile, keyFile, host) {
+		return certFile, keyFile, nil
+	}
+	if err := generateSelfSignedCert(certFile, keyFile, host); err != nil {
+		return "", "", fmt.Errorf("generate self-signed certificate: %w", err)
+	}
+	log.Info().Str("cert", certFile).Str("host", host).Msg("generated self-signed TLS certificate")
+	return certFile, keyFile, nil
+}
+
+// selfSignedCertValid reports whether a previously generated certificate can be
+// reused: it loads with its key, covers host and isn't about to expire.
+func selfSignedCertValid(certFile, keyFile, host string) bool {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return false
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return false
+	}
+	if time.Until(cert.NotAfter) < SelfSignedRenewBefore {
+		return false
+	}
+	return certHostName(host) == "" || cert.VerifyHostname(certHostName(host)) == nil
+}
+
+// certHostName returns the name a certificate for the listen host must cover;
+// bind-all addresses are reached through localhost.
+func certHostName(host string) string {
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		return ""
+	}
+	return host
+}
+
+// ===== CERTIFICATE GENERATION =====
+
+// generateSelfSignedCert writes a new ECDSA certificate and key valid for host,
+// localhost and the loopback addresses.
+func generateSelfSignedCert(certFile, keyFile, host string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"FolderSynchronizer"}, CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(SelfSignedCertValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if name := certHostName(host); name != "" {
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, name)
+			template.Subject.CommonName = name
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o644)
+}
@@ -41,21 +41,26 @@ const fmtDate = (timestamp) => {
 const api = {
     list: () => fetchJSON('/api/pairs'),
     create: (pair) => fetchJSON('/api/pairs', { method: 'POST', body: JSON.stringify(pair) }),
-    update: (id, pair) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}`, { method: 'PUT', body: JSON.stringify(pair) }),
+    update: (id, pair, rev) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}`, {
+        method: 'PUT',
+        headers: { 'If-Match': `"${rev}"` },
+        body: JSON.stringify(pair)
+    }),
     remove: (id) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}`, { method: 'DELETE' }),
     start: (id) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}/start`, { method: 'POST' }),
     stop: (id) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}/stop`, { method: 'POST' }),
     sync: (id) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}/sync`, { method: 'POST' }),
     syncAll: () => fetchJSON('/api/syncAll', { method: 'POST' }),
     testHook: (id) => fetchJSON(`/api/pairs/${encodeURIComponent(id)}/test-hook`, { method: 'POST' }),
-    examples: () => fetchJSON('/api/schedules/examples')
+    examples: () => fetchJSON('/api/schedules/examples'),
+    version: () => fetchJSON('/api/version')
 };
 
 /**
  * HTTP utility for JSON API calls
  */
 async function fetchJSON(url, opts = {}) {
-    const headers = { 'Content-Type': 'application/json' };
+    const headers = { 'Content-Type': 'application/json', ...opts.headers };
     const res = await fetch(url, { ...opts, headers });
     const txt = await res.text();
 
@@ -152,7 +157,7 @@ function createPairCard(pair) {
 
     const lastRun = pair.status?.lastRun ? fmtDate(pair.status.lastRun) : '—';
     const nextRun = pair.status?.nextRun ? fmtDate(pair.status.nextRun)
-        : (isRunning && pair.status?.scheduleType === 'watcher' ? 'Watching…' : '—');
+        : (isRunning && ['watcher', 'poll'].includes(pair.status?.scheduleType) ? 'Watching…' : '—');
 
     card.innerHTML = `
         <div class="sync-header" onclick="headerClick(event, this.parentElement)">
@@ -206,10 +211,15 @@ function createPairCard(pair) {
  */
 function renderPathsSection(pair) {
     return `
+        ${pair.sources?.length > 1 ? `
+        <div class="detail-field">
+            <div class="field-label">Source directories</div>
+            <div class="field-value short">${pair.sources.map(esc).join('<br>')}</div>
+        </div>` : `
         <div class="detail-field">
             <div class="field-label">Source directory</div>
             <div class="field-value short">${esc(pair.source)}</div>
-        </div>
+        </div>`}
         <div class="detail-field">
             <div class="field-label">Target directory</div>
             <div class="field-value short">${esc(pair.target)}</div>
@@ -284,6 +294,7 @@ function renderScheduleInfo(pair) {
     const schedule = pair.schedule || {};
     switch (schedule.type) {
         case 'watcher': return 'File watcher (sync on changes)';
+        case 'poll': return `Polling watch every ${schedule.interval || '?'}`;
         case 'interval': return `Every ${schedule.interval || '?'}`;
         case 'cron': return `Cron: ${schedule.cronExpr || '?'}`;
         case 'custom': return `Custom schedule`;
@@ -328,6 +339,11 @@ function uiToSchedule() {
     if (type === 'watcher') return { type: 'watcher' };
     if (type === 'disabled') return { type: 'disabled' };
 
+    if (type === 'poll') {
+        const seconds = parseInt($('#schedule-poll-seconds').value) || 60;
+        return { type: 'poll', interval: `${seconds}s` };
+    }
+
     if (type === 'interval') {
         const startDateTime = $('#schedule-start-datetime').value;
         const endDateTime = $('#schedule-end-datetime').value;
@@ -411,6 +427,11 @@ function scheduleToUI(schedule) {
         populateIntervalSchedule(schedule);
     }
 
+    if (type === 'poll') {
+        const pollMatch = schedule.interval?.match(/^(\d+)s?$/);
+        $('#schedule-poll-seconds').value = pollMatch ? pollMatch[1] : 60;
+    }
+
     if (type === 'cron') {
         $('#schedule-cron').value = schedule.cronExpr || '';
     }
@@ -543,6 +564,7 @@ const fields = {
 };
 
 let editId = null;
+let editRev = 0; // Revision of the pair being edited, sent back so concurrent edits conflict
 let editingEnabled = false;
 let currentHooks = [];
 
@@ -571,6 +593,7 @@ function closeModal() {
 
 function resetForm() {
     editId = null;
+    editRev = 0;
     editingEnabled = false;
     form.reset();
     currentHooks = [];
@@ -580,6 +603,7 @@ function resetForm() {
 
 function fillForm(pair) {
     editId = pair.id;
+    editRev = pair.rev || 0;
     editingEnabled = !!pair.enabled;
 
     fields.id.value = pair.id;
@@ -1354,7 +1378,7 @@ async function handleFormSubmit(event) {
         $('#saveBtn').disabled = true;
 
         if (editId) {
-            await api.update(editId, pair);
+            await api.update(editId, pair, editRev);
         } else {
             await api.create(pair);
         }
@@ -1392,8 +1416,22 @@ document.addEventListener('DOMContentLoaded', () => {
     initializeTabs();
     initializeEventHandlers();
     refresh();
+    showVersion();
 });
 
+/**
+ * Show the application version in the page footer
+ */
+async function showVersion() {
+    try {
+        const info = await api.version();
+        const commit = info.commit ? ` (${info.commit.slice(0, 7)})` : '';
+        $('#appVersion').textContent = `FolderSynchronizer ${info.version}${commit}`;
+    } catch {
+        // The version is informational only
+    }
+}
+
 // ===== GLOBAL FUNCTION EXPORTS =====
 
 /**
@@ -0,0 +1,84 @@
+// Package buildinfo provides version information for the FolderSynchronizer application.
+// It combines values set at build time with the VCS metadata embedded by the Go toolchain.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// ===== BUILD-TIME VALUES =====
+
+// Set at build time, e.g.
+//
+//	go build -ldflags="-X FolderSynchronizer/internal/buildinfo.Version=1.2.0" ./cmd/foldersyncronizer
+//
+// Commit and BuildDate fall back to the VCS revision and commit time recorded by
+// the Go toolchain when built from a repository checkout.
+var (
+	Version   = "0.2.0" // Application version
+	Commit    = ""      // VCS revision the binary was built from
+	BuildDate = ""      // Build (or commit) time, RFC3339
+)
+
+// ===== VERSION INFORMATION =====
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`             // Application version
+	Commit    string `json:"commit,omitempty"`    // VCS revision
+	Modified  bool   `json:"modified,omitempty"`  // Built from a working tree with uncommitted changes
+	BuildDate string `json:"buildDate,omitempty"` // Build or commit time
+	GoVersion string `json:"goVersion"`           // Go toolchain version
+	GOOS      string `json:"goos"`                // Target operating system
+	GOARCH    string `json:"goarch"`              // Target architecture
+}
+
+// Get returns the version information of the running binary.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildDate == "" {
+				info.BuildDate = setting.Value
+			}
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// String formats the information for the -version flag
+func (i Info) String() string {
+	commit := i.Commit
+	if commit == "" {
+		commit = "unknown"
+	} else if i.Modified {
+		commit += " (modified)"
+	}
+	buildDate := i.BuildDate
+	if buildDate == "" {
+		buildDate = "unknown"
+	}
+	return fmt.Sprintf("%s\ncommit: %s\nbuilt: %s\ngo: %s %s/%s",
+		i.Version, commit, buildDate, i.GoVersion, i.GOOS, i.GOARCH)
+}
@@ -5,15 +5,21 @@ package config
 
 import (
 	"FolderSynchronizer/internal/scheduler"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
 )
 
 // ===== CONSTANTS =====
@@ -27,6 +33,59 @@ const (
 	DefaultDebounceMs  = 500
 	DefaultCopyWorkers = 4
 	DefaultRetries     = 3
+	DefaultCopyRetries = 2 // Copy retries when only CopyRetryDelayMs is configured
+
+	DefaultMaxConcurrentHooks = 8   // Hooks executing at once across all pairs
+	DefaultMaxOpenFiles       = 256 // Files held open by copy and hash operations across all pairs
+	DefaultMaxInitialSyncs    = 2   // Watcher initial syncs running at once across all pairs
+	DefaultMaxConcurrentSyncs = 4   // Full sync runs executing at once across all pairs
+	DefaultHookHistorySize    = 50  // Hook executions kept per pair
+	DefaultConfigBackups      = 5   // Previous config versions kept as <config>.bak.1..N
+
+	DefaultWatchPollInterval = 30 * time.Second // Polling period of watchers that hit the OS watch limit
+	DefaultStaleTempAge      = time.Hour        // Age after which leftover temporary copy files are removed
+	DefaultDrainTimeout      = 30 * time.Second // Time running syncs get to finish at shutdown
+
+	DefaultHTTPReadTimeout  = 30 * time.Second  // Time a client gets to send a full request (imports included)
+	DefaultHTTPWriteTimeout = 60 * time.Second  // Time a response may take to write; streams and downloads are exempt
+	DefaultHTTPIdleTimeout  = 120 * time.Second // Time an idle keep-alive connection stays open
+)
+
+// Archive formats for Pair.ArchiveMode
+const (
+	ArchiveFormatZip   = "zip"
+	ArchiveFormatTarGz = "tar.gz"
+)
+
+// Symlink handling modes for Pair.SymlinkMode
+const (
+	SymlinkModeFollow   = "follow"    // Copy the content the link points to, descending into linked directories
+	SymlinkModeCopyLink = "copy-link" // Recreate the link itself in the target
+	SymlinkModeSkip     = "skip"      // Leave links out of the sync
+)
+
+// Symlink target rewrites for Pair.SymlinkRewrite
+const (
+	SymlinkRewriteRelative = "relative" // Absolute targets inside the source become relative to the link
+	SymlinkRewriteTarget   = "target"   // Absolute targets inside the source are moved under the target root
+)
+
+// Watcher debounce modes for Pair.DebounceMode
+const (
+	DebounceModeFile  = "file"  // Each changed file is copied once its own events settle
+	DebounceModeBatch = "batch" // A burst of events anywhere in the source triggers one comparison pass
+)
+
+// Handling of a relative path present in several sources, for Pair.SourceConflict
+const (
+	SourceConflictFirst = "first" // The earliest source in the list provides the file
+	SourceConflictError = "error" // The earliest source provides the file and the collision fails the run
+)
+
+// Mirror delete modes for Pair.MirrorDeleteMode
+const (
+	MirrorDeleteInline   = "inline"   // Delete target files as soon as a sync finds them orphaned
+	MirrorDeleteDeferred = "deferred" // Record orphaned target files and delete them when applied
 )
 
 // ===== CONFIGURATION STRUCTURES =====
@@ -34,8 +93,99 @@ const (
 // Config represents the root configuration that is persisted to disk and served via API.
 // It acts as an in-memory state holder for sync pairs managed by the core.
 type Config struct {
-	Listen string  `json:"listen"` // HTTP server listen address
-	Pairs  []*Pair `json:"pairs"`  // Collection of sync pair configurations
+	Listen  string  `json:"listen" yaml:"listen"`                       // HTTP server listen address
+	APIOnly bool    `json:"apiOnly,omitempty" yaml:"apiOnly,omitempty"` // Serve only the REST API, without the embedded web UI
+	Pairs   []*Pair `json:"pairs" yaml:"pairs"`                         // Collection of sync pair configurations
+
+	// When the listen port is held by another process, try this many following
+	// ports before giving up (startup only; 0 exits with an error instead)
+	ListenPortFallback int `json:"listenPortFallback,omitempty" yaml:"listenPortFallback,omitempty"`
+
+	// Auto-start profile. When set, only enabled pairs tagged with this profile
+	// start automatically, so one config can serve several machines.
+	ActiveProfile string `json:"activeProfile,omitempty" yaml:"activeProfile,omitempty"`
+
+	// Schedule given to pairs created or loaded without one (watcher when unset)
+	DefaultSchedule scheduler.Schedule `json:"defaultSchedule" yaml:"defaultSchedule"`
+
+	// Holiday dates ("YYYY-MM-DD") skipped by custom schedules with skipHolidays
+	Holidays []string `json:"holidays,omitempty" yaml:"holidays,omitempty"`
+
+	// Optional API authentication. When set, /api/* and /metrics require either
+	// "Authorization: Bearer <token>" / "X-API-Key: <token>" or basic credentials.
+	AuthToken     string `json:"authToken,omitempty" yaml:"authToken,omitempty"`         // Static API token
+	BasicAuthUser string `json:"basicAuthUser,omitempty" yaml:"basicAuthUser,omitempty"` // HTTP basic auth user name
+	BasicAuthPass string `json:"basicAuthPass,omitempty" yaml:"basicAuthPass,omitempty"` // HTTP basic auth password
+
+	// Reject pairs created or updated through the API whose source isn't an existing
+	// directory (off by default: sources on removable or network drives may be offline)
+	RequireExistingSource bool `json:"requireExistingSource,omitempty" yaml:"requireExistingSource,omitempty"`
+
+	// Hash comparisons reuse SHA256 sums of files whose size and modification time are
+	// unchanged, cached in hash-cache.json next to the config. Disable to always re-read
+	// files, e.g. when tools rewrite content while preserving modification times.
+	DisableHashCache bool `json:"disableHashCache,omitempty" yaml:"disableHashCache,omitempty"`
+
+	// Optional client network restriction (startup only). When set, requests from
+	// other addresses get 403; /healthz and /readyz stay reachable.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty" yaml:"allowedCIDRs,omitempty"` // e.g. ["127.0.0.1/32", "192.168.1.0/24"]
+	TrustProxy   bool     `json:"trustProxy,omitempty" yaml:"trustProxy,omitempty"`     // Take the client IP from X-Forwarded-For / X-Real-IP
+
+	// Optional HTTPS (startup only). With a certificate and key the server speaks
+	// only TLS; TLSSelfSigned generates a certificate for the listen host, kept
+	// next to the config, when none is configured.
+	TLSCertFile   string `json:"tlsCertFile,omitempty" yaml:"tlsCertFile,omitempty"`     // PEM certificate (chain)
+	TLSKeyFile    string `json:"tlsKeyFile,omitempty" yaml:"tlsKeyFile,omitempty"`       // PEM private key
+	TLSSelfSigned bool   `json:"tlsSelfSigned,omitempty" yaml:"tlsSelfSigned,omitempty"` // Generate a self-signed certificate
+
+	// Global limits
+	MaxConcurrentHooks int `json:"maxConcurrentHooks,omitempty" yaml:"maxConcurrentHooks,omitempty"` // Hooks executing at once across all pairs
+	MaxOpenFiles       int `json:"maxOpenFiles,omitempty" yaml:"maxOpenFiles,omitempty"`             // Files held open by copies and hashing across all pairs
+	MaxInitialSyncs    int `json:"maxInitialSyncs,omitempty" yaml:"maxInitialSyncs,omitempty"`       // Watcher initial syncs running at once across all pairs
+	MaxConcurrentSyncs int `json:"maxConcurrentSyncs,omitempty" yaml:"maxConcurrentSyncs,omitempty"` // Full sync runs executing at once across all pairs; more are queued
+	HookHistorySize    int `json:"hookHistorySize,omitempty" yaml:"hookHistorySize,omitempty"`       // Hook executions kept per pair for /hook-history
+	ConfigBackups      int `json:"configBackups,omitempty" yaml:"configBackups,omitempty"`           // Previous config versions kept on every save
+
+	// Temporary copy files (*.tmp) in targets older than this are leftovers of an
+	// interrupted copy and removed at startup and after each sync. Duration such as "1h".
+	StaleTempAge string `json:"staleTempAge,omitempty" yaml:"staleTempAge,omitempty"`
+
+	// At shutdown, running syncs get this long to finish before they are cancelled;
+	// no new syncs start meanwhile. Duration such as "30s"; "0s" cancels right away.
+	DrainTimeout string `json:"drainTimeout,omitempty" yaml:"drainTimeout,omitempty"`
+
+	// HTTP server timeouts (startup only), durations such as "30s". They bound slow
+	// clients when the server listens on a non-loopback address; "0s" disables one.
+	// The event stream and log download are not subject to the write timeout.
+	HTTPReadTimeout  string `json:"httpReadTimeout,omitempty" yaml:"httpReadTimeout,omitempty"`   // Reading the whole request (default 30s)
+	HTTPWriteTimeout string `json:"httpWriteTimeout,omitempty" yaml:"httpWriteTimeout,omitempty"` // Writing the response (default 60s)
+	HTTPIdleTimeout  string `json:"httpIdleTimeout,omitempty" yaml:"httpIdleTimeout,omitempty"`   // Idle keep-alive connections (default 120s)
+
+	// Command hook safety checks. The built-in blocklists (rm, format, shutdown,
+	// "--recursive", ...) always apply; these lists extend them or exempt trusted tools.
+	BlockedCommands        []string `json:"blockedCommands,omitempty" yaml:"blockedCommands,omitempty"`               // Extra executable names to reject
+	BlockedCommandPatterns []string `json:"blockedCommandPatterns,omitempty" yaml:"blockedCommandPatterns,omitempty"` // Extra command line substrings to reject
+	AllowedCommands        []string `json:"allowedCommands,omitempty" yaml:"allowedCommands,omitempty"`               // Executable names exempt from all checks
+
+	// Startup behavior. Scheduled (non-watcher) pairs otherwise wait for their
+	// next scheduled run after a restart; see also Pair.RunOnStartup.
+	SyncOnStartup bool `json:"syncOnStartup,omitempty" yaml:"syncOnStartup,omitempty"` // Sync every auto-started scheduled pair once at startup
+
+	// Command line overrides for this run only; never written to the config file
+	Overrides Overrides `json:"-" yaml:"-"`
+
+	envTemplates map[string]envTemplate // Raw ${VAR} values of expanded pair fields, restored on save
+}
+
+// Overrides holds settings given on the command line. They take precedence over
+// the configured values for the running process without changing them, so a
+// config saved through the API keeps what the file said.
+type Overrides struct {
+	APIOnly       bool   // -api-only
+	ActiveProfile string // -profile, replacing the configured auto-start profile
+	TLSCertFile   string // -tls-cert, replacing the configured certificate
+	TLSKeyFile    string // -tls-key, replacing the configured key
+	TLSSelfSigned bool   // -tls-selfsigned
 }
 
 // Pair represents a single source->target sync configuration with all its settings.
@@ -43,64 +193,208 @@ type Config struct {
 // to keep the configuration file small and focused on core settings.
 type Pair struct {
 	// Core identification and state
-	ID      string `json:"id"`      // Unique identifier for the sync pair
-	Enabled bool   `json:"enabled"` // Whether this pair is active
+	ID      string `json:"id" yaml:"id"`           // Unique identifier for the sync pair
+	Enabled bool   `json:"enabled" yaml:"enabled"` // Whether this pair is active
+
+	// Paused pairs stay enabled (and shown as active) but run neither scheduled
+	// syncs nor a watcher until unpaused. Persisted, so a pause survives restarts.
+	Paused bool `json:"paused,omitempty" yaml:"paused,omitempty"`
+
+	// Revision, bumped by the server on every save of the pair. Updates through the
+	// API must name the revision they were based on (If-Match or "rev").
+	Rev int `json:"rev" yaml:"rev,omitempty"`
 
 	// Path configuration
-	Source string `json:"source"` // Source directory path
-	Target string `json:"target"` // Target directory path
+	Source string `json:"source" yaml:"source"` // Source directory path
+	Target string `json:"target" yaml:"target"` // Target directory path
+
+	// Several source directories merged into the one target. When set, the list holds
+	// every source in priority order and Source is its first entry. A target file is
+	// only mirror-deleted once it is missing from all sources.
+	Sources        []string `json:"sources,omitempty" yaml:"sources,omitempty"`
+	SourceConflict string   `json:"sourceConflict,omitempty" yaml:"sourceConflict,omitempty"` // "first" (default) or "error"
+
+	// Several target directories the source is replicated to. When set, the list holds
+	// every target and Target is its first entry. A failing target doesn't stop the others.
+	Targets []string `json:"targets,omitempty" yaml:"targets,omitempty"`
 
 	// File filtering
-	IncludeExt   []string `json:"includeExtensions"` // File extensions to include (e.g., [".jar", ".war"])
-	ExcludeGlobs []string `json:"excludeGlobs"`      // Glob patterns to exclude (e.g., ["**/*.bak"])
+	IncludeExt   []string `json:"includeExtensions" yaml:"includeExtensions"`           // File extensions to include (e.g., [".jar", ".war"])
+	IncludeGlobs []string `json:"includeGlobs,omitempty" yaml:"includeGlobs,omitempty"` // Glob patterns to include, relative to source (e.g., ["build/**"])
+	ExcludeGlobs []string `json:"excludeGlobs" yaml:"excludeGlobs"`                     // Glob patterns to exclude (e.g., ["**/*.bak"])
+	MinFileSize  int64    `json:"minFileSize,omitempty" yaml:"minFileSize,omitempty"`   // Skip files smaller than this many bytes (0 = no limit)
+	MaxFileSize  int64    `json:"maxFileSize,omitempty" yaml:"maxFileSize,omitempty"`   // Skip files larger than this many bytes (0 = no limit)
+
+	// Match extensions and globs (filters, hook filters, trigger globs) with exact
+	// letter case; by default "**/*.jpg" also matches "Photo.JPG"
+	CaseSensitive bool `json:"caseSensitive,omitempty" yaml:"caseSensitive,omitempty"`
+
+	// Directory levels below the source root that are walked and watched (0 = unlimited);
+	// 1 syncs the root's files and those of its direct subdirectories
+	MaxDepth int `json:"maxDepth,omitempty" yaml:"maxDepth,omitempty"`
+
+	// Leave out dotfiles and dot-directories, and on Windows entries with the Hidden
+	// or System attribute (e.g. .DS_Store, .git, Thumbs.db)
+	SkipHidden bool `json:"skipHidden,omitempty" yaml:"skipHidden,omitempty"`
+
+	// Create every source directory in the target, including empty ones
+	SyncEmptyDirs bool `json:"syncEmptyDirs,omitempty" yaml:"syncEmptyDirs,omitempty"`
+
+	// Unicode normalization applied to relative paths when building target paths:
+	// "" (none), "nfc" or "nfd". Keeps names stable between macOS and other systems.
+	UnicodeNormalization string `json:"unicodeNormalization,omitempty" yaml:"unicodeNormalization,omitempty"`
+
+	// Handling of symlinks in the source: "follow" (default), "copy-link" or "skip".
+	// Followed directory links that lead back into their own ancestors are not descended.
+	SymlinkMode string `json:"symlinkMode,omitempty" yaml:"symlinkMode,omitempty"`
+
+	// Rewrite of absolute symlink targets when symlinks are recreated in the target (copy-link):
+	// "" (keep as is), "relative" or "target". Links pointing outside the source
+	// root are never rewritten.
+	SymlinkRewrite string `json:"symlinkRewrite,omitempty" yaml:"symlinkRewrite,omitempty"`
 
 	// Synchronization behavior
-	SyncStrategy  string `json:"syncStrategy"`  // "mtime" or "hash" comparison strategy
-	DebounceMs    int    `json:"debounceMs"`    // Milliseconds to wait before processing file changes
-	MirrorDeletes bool   `json:"mirrorDeletes"` // Whether to delete files in target that don't exist in source
+	SyncStrategy  string `json:"syncStrategy" yaml:"syncStrategy"`   // "mtime", "hash" or "delta" comparison strategy
+	DebounceMs    int    `json:"debounceMs" yaml:"debounceMs"`       // Milliseconds to wait before processing file changes
+	MirrorDeletes bool   `json:"mirrorDeletes" yaml:"mirrorDeletes"` // Whether to delete files in target that don't exist in source
+	WatchTarget   bool   `json:"watchTarget" yaml:"watchTarget"`     // Watcher mode: also watch target and repair out-of-band changes
+	DetectMoves   bool   `json:"detectMoves" yaml:"detectMoves"`     // Match new source files to orphaned target files (same size+hash) as renames
+
+	// Watcher debounce: "file" (default) debounces each changed path on its own,
+	// "batch" coalesces a burst of events anywhere in the source into one comparison pass
+	DebounceMode string `json:"debounceMode,omitempty" yaml:"debounceMode,omitempty"`
+
+	// Deferred mirror deletes. In "deferred" mode orphaned target files are only
+	// recorded as pending deletes, applied on demand (POST /api/pairs/{id}/apply-deletes)
+	// or by ApplyDeletesSchedule, leaving a window to notice source mistakes.
+	MirrorDeleteMode     string              `json:"mirrorDeleteMode,omitempty" yaml:"mirrorDeleteMode,omitempty"`         // "inline" (default) or "deferred"
+	ApplyDeletesSchedule *scheduler.Schedule `json:"applyDeletesSchedule,omitempty" yaml:"applyDeletesSchedule,omitempty"` // When pending deletes are applied automatically
+
+	// Watcher mode: when the initial sync fails, don't start watching until a
+	// manual retry (POST /api/pairs/{id}/sync) instead of proceeding regardless
+	HaltOnInitialSyncError bool `json:"haltOnInitialSyncError,omitempty" yaml:"haltOnInitialSyncError,omitempty"`
+
+	// Watcher mode: start watching without the initial full sync and rely on events;
+	// changes made while the pair wasn't watching are picked up by the next manual sync
+	SkipInitialSync bool `json:"skipInitialSync,omitempty" yaml:"skipInitialSync,omitempty"`
+
+	// Integrity checking. VerifyAfterCopy re-reads both files after every copy to
+	// compare SHA256 hashes, roughly tripling the I/O per copied file. Default off.
+	VerifyAfterCopy bool `json:"verifyAfterCopy,omitempty" yaml:"verifyAfterCopy,omitempty"` // Re-hash source and target after each copy
+
+	// Periodic deep check for the mtime strategy: every Nth full sync compares files
+	// by SHA256 instead, catching in-place edits that kept both size and mtime.
+	PeriodicHashCheck int `json:"periodicHashCheck,omitempty" yaml:"periodicHashCheck,omitempty"` // Hash-compare every Nth run (0 = never)
+
+	// Archive mode. Instead of mirroring, each sync writes the filtered source
+	// files into a timestamped archive in the target directory ("zip" or "tar.gz").
+	ArchiveMode string `json:"archiveMode,omitempty" yaml:"archiveMode,omitempty"` // Archive format, empty for a mirrored tree
+	ArchiveKeep int    `json:"archiveKeep,omitempty" yaml:"archiveKeep,omitempty"` // Number of newest archives to keep (0 = all)
+
+	// Capacity precheck. Before a full sync, plan the copies and abort when the
+	// target lacks free space or, on Unix, free inodes for the planned files.
+	PrecheckDiskSpace bool `json:"precheckDiskSpace,omitempty" yaml:"precheckDiskSpace,omitempty"`
+
+	// Rate limiting. Minimum time between the end of one sync of this pair and
+	// the start of the next, whatever triggered it (schedule, manual, sync all or
+	// watcher initial sync). Runs arriving too soon are skipped as rate limited.
+	MinRunInterval string `json:"minRunInterval,omitempty" yaml:"minRunInterval,omitempty"` // Duration such as "5m" (empty = no limit)
 
 	// Performance tuning
-	CopyWorkers    int `json:"copyWorkers,omitempty"`    // Number of concurrent copy operations
-	HookMaxRetries int `json:"hookMaxRetries,omitempty"` // Maximum retry attempts for failed hooks
+	CopyWorkers    int `json:"copyWorkers,omitempty" yaml:"copyWorkers,omitempty"`       // Number of concurrent copy operations
+	HookMaxRetries int `json:"hookMaxRetries,omitempty" yaml:"hookMaxRetries,omitempty"` // Maximum retry attempts for failed hooks
+
+	// Watch setup throttling for large trees. Directories are added to the watcher
+	// in batches, yielding to other work between batches.
+	WatchSetupBatchSize int `json:"watchSetupBatchSize,omitempty" yaml:"watchSetupBatchSize,omitempty"` // Directories per batch (0 = default 1000)
+	WatchSetupPauseMs   int `json:"watchSetupPauseMs,omitempty" yaml:"watchSetupPauseMs,omitempty"`     // Pause between batches (0 = just yield)
+
+	// Watchers that exhaust the OS watch limit (inotify on Linux) fall back to
+	// running a comparison pass every WatchPollInterval
+	WatchPollInterval string `json:"watchPollInterval,omitempty" yaml:"watchPollInterval,omitempty"` // Duration such as "1m" (empty = 30s)
+
+	// Copy retries for transient failures such as file locks. When both are 0 the
+	// built-in schedule is used; otherwise delays double from CopyRetryDelayMs.
+	CopyRetries      int `json:"copyRetries,omitempty" yaml:"copyRetries,omitempty"`           // Number of retries after a failed copy
+	CopyRetryDelayMs int `json:"copyRetryDelayMs,omitempty" yaml:"copyRetryDelayMs,omitempty"` // Base delay before the first retry
+
+	// Deferred re-attempts of a watcher copy that still failed after the retries above,
+	// before the file is given up and reported as failed (0 = until it succeeds)
+	ReconcileMaxAttempts int `json:"reconcileMaxAttempts,omitempty" yaml:"reconcileMaxAttempts,omitempty"`
 
 	// Automation and notifications
-	Hooks []Hook `json:"hooks"` // Post-sync notification/action hooks
+	Hooks               []Hook `json:"hooks" yaml:"hooks"`                                                 // Post-sync notification/action hooks
+	HookBatch           bool   `json:"hookBatch,omitempty" yaml:"hookBatch,omitempty"`                     // Full syncs fire each hook once with all copied files ({{.Files}}); watcher events stay per file
+	AllowUnsafeCommands bool   `json:"allowUnsafeCommands,omitempty" yaml:"allowUnsafeCommands,omitempty"` // Trusted pair: run command hooks failing safety checks (logged)
 
 	// Scheduling configuration
-	Schedule scheduler.Schedule `json:"schedule"` // When and how often to sync
+	Schedule     scheduler.Schedule `json:"schedule" yaml:"schedule"`                             // When and how often to sync
+	RunOnStartup bool               `json:"runOnStartup,omitempty" yaml:"runOnStartup,omitempty"` // Sync once right after auto-start, besides the schedule
 
 	// User interface
-	Description string `json:"description,omitempty"` // Human-readable description for UI display
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"` // Human-readable description for UI display
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`               // Free-form labels used for filtering and auto-start profiles
+
+	// Diagnostics: also write this pair's log events to pair-<id>.log in the logs
+	// directory, rotated like the main log
+	LogFile bool `json:"logFile,omitempty" yaml:"logFile,omitempty"`
 
 	// Extensibility
-	Extra map[string]string `json:"extra,omitempty"` // Additional custom fields for future use
+	Extra map[string]string `json:"extra,omitempty" yaml:"extra,omitempty"` // Additional custom fields for future use
 }
 
 // Hook represents a post-sync action that can be triggered when files are synchronized.
 // Hooks can be either HTTP requests or command executions, with optional file filtering.
 type Hook struct {
-	MatchExtensions []string     `json:"matchExtensions"`   // File extensions that trigger this hook
-	MatchGlobs      []string     `json:"matchGlobs"`        // Glob patterns that trigger this hook
-	HTTP            *HTTPHook    `json:"http,omitempty"`    // HTTP request configuration
-	Command         *CommandHook `json:"command,omitempty"` // Command execution configuration
+	MatchExtensions []string     `json:"matchExtensions" yaml:"matchExtensions"`     // File extensions that trigger this hook
+	MatchGlobs      []string     `json:"matchGlobs" yaml:"matchGlobs"`               // Glob patterns that trigger this hook
+	HTTP            *HTTPHook    `json:"http,omitempty" yaml:"http,omitempty"`       // HTTP request configuration
+	Command         *CommandHook `json:"command,omitempty" yaml:"command,omitempty"` // Command execution configuration
+	GRPC            *GRPCHook    `json:"grpc,omitempty" yaml:"grpc,omitempty"`       // gRPC call configuration
+
+	// Sentinel trigger, e.g. "**/MANIFEST". The hook fires only when a file matching
+	// this glob is synced: per file it fires for that file alone, in batch mode
+	// (Pair.HookBatch) it fires once with the whole batch in {{.Files}}.
+	TriggerOnlyForGlob string `json:"triggerOnlyForGlob,omitempty" yaml:"triggerOnlyForGlob,omitempty"`
+
+	// Execution limits. TimeoutMs bounds each attempt (0 = 20s for HTTP and gRPC,
+	// 5m for commands). MaxRetries applies to HTTP and gRPC hooks; 0 falls back to
+	// the pair's HookMaxRetries.
+	TimeoutMs  int `json:"timeoutMs,omitempty" yaml:"timeoutMs,omitempty"`   // Per-attempt timeout in milliseconds
+	MaxRetries int `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"` // Retries after a failed attempt
 }
 
 // HTTPHook configures an HTTP request to be made after successful file synchronization.
 // Supports templating in the body to include information about synchronized files.
 type HTTPHook struct {
-	Method       string            `json:"method"`       // HTTP method (GET, POST, PUT, etc.)
-	URL          string            `json:"url"`          // Target URL for the request
-	Headers      map[string]string `json:"headers"`      // HTTP headers to include
-	BodyTemplate string            `json:"bodyTemplate"` // Request body template with variable substitution
+	Method       string            `json:"method" yaml:"method"`             // HTTP method (GET, POST, PUT, etc.)
+	URL          string            `json:"url" yaml:"url"`                   // Target URL for the request
+	Headers      map[string]string `json:"headers" yaml:"headers"`           // HTTP headers to include
+	BodyTemplate string            `json:"bodyTemplate" yaml:"bodyTemplate"` // Request body template with variable substitution
 }
 
 // CommandHook configures a command to be executed after successful file synchronization.
 // Supports environment variable injection and working directory specification.
 type CommandHook struct {
-	Executable string            `json:"executable"`        // Command or executable to run
-	Args       []string          `json:"args"`              // Command line arguments
-	WorkDir    string            `json:"workDir,omitempty"` // Working directory for command execution
-	EnvVars    map[string]string `json:"envVars,omitempty"` // Environment variables to set
+	Executable string            `json:"executable" yaml:"executable"`               // Command or executable to run
+	Args       []string          `json:"args" yaml:"args"`                           // Command line arguments
+	WorkDir    string            `json:"workDir,omitempty" yaml:"workDir,omitempty"` // Working directory for command execution
+	EnvVars    map[string]string `json:"envVars,omitempty" yaml:"envVars,omitempty"` // Environment variables to set
+
+	// StdinTemplate is rendered with the same variables as Args and piped to the
+	// command's stdin, for tools that read a JSON payload instead of arguments.
+	StdinTemplate string `json:"stdinTemplate,omitempty" yaml:"stdinTemplate,omitempty"`
+}
+
+// GRPCHook configures a unary gRPC call to be made after successful file synchronization.
+// The call uses a fixed contract: the request is a google.protobuf.Struct built from
+// the JSON produced by the body template, and any response message is accepted.
+type GRPCHook struct {
+	Target       string            `json:"target" yaml:"target"`                         // Server address (host:port)
+	Method       string            `json:"method" yaml:"method"`                         // Full method name, e.g. "/deploy.Notifier/FileSynced"
+	Metadata     map[string]string `json:"metadata,omitempty" yaml:"metadata,omitempty"` // Request metadata (gRPC headers)
+	BodyTemplate string            `json:"bodyTemplate" yaml:"bodyTemplate"`             // JSON object template with variable substitution
+	Insecure     bool              `json:"insecure,omitempty" yaml:"insecure,omitempty"` // Use plaintext instead of TLS
 }
 
 // ===== PATH MANAGEMENT =====
@@ -108,9 +402,14 @@ type CommandHook struct {
 // Paths holds resolved directory paths used by the application for configuration,
 // logs, and other persistent data storage.
 type Paths struct {
-	ConfigDir  string // Directory containing configuration files
-	ConfigFile string // Full path to the main configuration file
-	LogsDir    string // Directory for log file storage
+	ConfigDir          string // Directory containing configuration files
+	ConfigFile         string // Full path to the main configuration file
+	LogsDir            string // Directory for log file storage
+	StatsFile          string // Persisted scheduler run statistics
+	PendingDeletesFile string // Persisted deferred mirror deletes
+	HashCacheFile      string // Persisted checksum cache of hash comparisons
+	SelfSignedCertFile string // Generated certificate for TLSSelfSigned
+	SelfSignedKeyFile  string // Private key of the generated certificate
 }
 
 // ResolvePaths determines appropriate configuration directories based on the operating system
@@ -126,9 +425,14 @@ func ResolvePaths(configOverride string) (Paths, error) {
 			return Paths{}, fmt.Errorf("failed to resolve config override path: %w", err)
 		}
 		return Paths{
-			ConfigDir:  filepath.Dir(abs),
-			ConfigFile: abs,
-			LogsDir:    filepath.Join(filepath.Dir(abs), "logs"),
+			ConfigDir:          filepath.Dir(abs),
+			ConfigFile:         abs,
+			LogsDir:            filepath.Join(filepath.Dir(abs), "logs"),
+			StatsFile:          filepath.Join(filepath.Dir(abs), "scheduler-stats.json"),
+			PendingDeletesFile: filepath.Join(filepath.Dir(abs), "pending-deletes.json"),
+			HashCacheFile:      filepath.Join(filepath.Dir(abs), "hash-cache.json"),
+			SelfSignedCertFile: filepath.Join(filepath.Dir(abs), "tls-cert.pem"),
+			SelfSignedKeyFile:  filepath.Join(filepath.Dir(abs), "tls-key.pem"),
 		}, nil
 	}
 
@@ -150,9 +454,14 @@ func ResolvePaths(configOverride string) (Paths, error) {
 	}
 
 	return Paths{
-		ConfigDir:  dir,
-		ConfigFile: filepath.Join(dir, "config.json"),
-		LogsDir:    filepath.Join(dir, "logs"),
+		ConfigDir:          dir,
+		ConfigFile:         filepath.Join(dir, "config.json"),
+		LogsDir:            filepath.Join(dir, "logs"),
+		StatsFile:          filepath.Join(dir, "scheduler-stats.json"),
+		PendingDeletesFile: filepath.Join(dir, "pending-deletes.json"),
+		HashCacheFile:      filepath.Join(dir, "hash-cache.json"),
+		SelfSignedCertFile: filepath.Join(dir, "tls-cert.pem"),
+		SelfSignedKeyFile:  filepath.Join(dir, "tls-key.pem"),
 	}, nil
 }
 
@@ -184,10 +493,21 @@ func Load(path string) (*Config, error) {
 	}
 
 	var config Config
-	if err := json.Unmarshal(data, &config); err != nil {
+	if isYAMLPath(path) {
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("invalid config YAML: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("invalid config JSON: %w", err)
 	}
 
+	// Resolve ${VAR} references in paths and hook settings
+	for i, pair := range config.Pairs {
+		if err := config.ExpandPairEnv(pair); err != nil {
+			return nil, fmt.Errorf("pair %d (%s): %w", i, pair.ID, err)
+		}
+	}
+
 	// Apply default values and validate
 	applyDefaults(&config)
 	if err := validateConfig(&config); err != nil {
@@ -199,18 +519,31 @@ func Load(path string) (*Config, error) {
 
 // Save persists the configuration to disk atomically using a temporary file
 // to ensure data integrity even if the operation is interrupted.
+// The format follows the file extension, like Load. The previous version is
+// kept as a rotated backup (see ConfigBackups).
 func Save(path string, config *Config) error {
-	tempPath := path + ".tmp"
-
-	data, err := json.MarshalIndent(config, "", "  ")
+	data, err := marshalConfig(path, config.withEnvTemplates())
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	return writeConfigFile(path, data, config.ConfigBackups)
+}
+
+// writeConfigFile writes data to path through a temporary file. Backups are
+// rotated after the temp file is complete and before it is renamed into place;
+// the current file is copied rather than moved, so path always holds a complete
+// config and a failed backup never blocks the save.
+func writeConfigFile(path string, data []byte, backups int) error {
+	tempPath := path + ".tmp"
 
 	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
 		return fmt.Errorf("failed to write temp config file: %w", err)
 	}
 
+	if err := rotateBackups(path, backups); err != nil {
+		log.Warn().Err(err).Str("config", path).Msg("config backup failed")
+	}
+
 	if err := os.Rename(tempPath, path); err != nil {
 		// Clean up temp file on failure
 		_ = os.Remove(tempPath)
@@ -220,13 +553,245 @@ func Save(path string, config *Config) error {
 	return nil
 }
 
+// ===== CONFIGURATION BACKUPS =====
+
+// BackupPath returns the path of the nth most recent backup of a config file (1 = newest).
+func BackupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+// rotateBackups shifts <path>.bak.1..N-1 up by one, dropping the oldest, and
+// copies the current config to <path>.bak.1. Nothing happens without a current
+// config or when backups are disabled.
+func rotateBackups(path string, backups int) error {
+	if backups <= 0 {
+		return nil
+	}
+	current, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_ = os.Remove(BackupPath(path, backups))
+	for n := backups - 1; n >= 1; n-- {
+		if err := os.Rename(BackupPath(path, n), BackupPath(path, n+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	// Written through a temp file so a crash never leaves a truncated backup
+	backupTemp := BackupPath(path, 1) + ".tmp"
+	if err := os.WriteFile(backupTemp, current, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(backupTemp, BackupPath(path, 1)); err != nil {
+		_ = os.Remove(backupTemp)
+		return err
+	}
+	return nil
+}
+
+// RestoreBackup replaces the config at path with its nth backup after checking
+// that the backup is a valid config. The replaced version becomes backup 1, so a
+// restore can itself be undone.
+func RestoreBackup(path string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("invalid backup number %d", n)
+	}
+	backupPath := BackupPath(path, n)
+
+	data, err := os.ReadFile(backupPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("backup %d not found", n)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read backup %d: %w", n, err)
+	}
+
+	restored, err := Load(backupPath)
+	if err != nil {
+		return fmt.Errorf("backup %d: %w", n, err)
+	}
+	return writeConfigFile(path, data, restored.ConfigBackups)
+}
+
+// isYAMLPath reports whether a config path uses YAML (.yaml/.yml); anything else is JSON.
+// Backups (<config>.bak.N) use the format of the config they were taken from.
+func isYAMLPath(path string) bool {
+	if i := strings.LastIndex(path, ".bak."); i >= 0 {
+		path = path[:i]
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// marshalConfig encodes the configuration in the format matching path
+func marshalConfig(path string, config *Config) ([]byte, error) {
+	if !isYAMLPath(path) {
+		return json.MarshalIndent(config, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // ===== CONFIGURATION UTILITIES =====
 
+// HasTag reports whether the pair carries the given tag (case-insensitive).
+func (p *Pair) HasTag(tag string) bool {
+	for _, pairTag := range p.Tags {
+		if strings.EqualFold(pairTag, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SourceRoots returns the pair's source directories in priority order.
+func (p *Pair) SourceRoots() []string {
+	if len(p.Sources) > 0 {
+		return p.Sources
+	}
+	return []string{p.Source}
+}
+
+// TargetRoots returns the pair's target directories.
+func (p *Pair) TargetRoots() []string {
+	if len(p.Targets) > 0 {
+		return p.Targets
+	}
+	return []string{p.Target}
+}
+
+// ForTarget returns a shallow copy of the pair syncing to a single target.
+func (p *Pair) ForTarget(target string) *Pair {
+	clone := *p
+	clone.Target = target
+	clone.Targets = nil
+	return &clone
+}
+
+// MinRunGap returns the pair's minimum run interval, or 0 when unset or invalid.
+func (p *Pair) MinRunGap() time.Duration {
+	if p.MinRunInterval == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(p.MinRunInterval)
+	if err != nil || duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// WatchPollPeriod returns the polling period used when the watcher falls back to polling.
+func (p *Pair) WatchPollPeriod() time.Duration {
+	duration, err := time.ParseDuration(p.WatchPollInterval)
+	if err != nil || duration <= 0 {
+		return DefaultWatchPollInterval
+	}
+	return duration
+}
+
+// StaleTempPeriod returns the age after which leftover temporary copy files are removed.
+func (c *Config) StaleTempPeriod() time.Duration {
+	age, err := time.ParseDuration(c.StaleTempAge)
+	if err != nil || age <= 0 {
+		return DefaultStaleTempAge
+	}
+	return age
+}
+
+// DrainPeriod returns how long running syncs may take to finish at shutdown.
+func (c *Config) DrainPeriod() time.Duration {
+	timeout, err := time.ParseDuration(c.DrainTimeout)
+	if err != nil || timeout < 0 {
+		return DefaultDrainTimeout
+	}
+	return timeout
+}
+
+// HTTPTimeouts returns the read, write and idle timeouts of the HTTP server.
+// Unset or invalid values fall back to their defaults; 0 means no timeout.
+func (c *Config) HTTPTimeouts() (read, write, idle time.Duration) {
+	return httpTimeout(c.HTTPReadTimeout, DefaultHTTPReadTimeout),
+		httpTimeout(c.HTTPWriteTimeout, DefaultHTTPWriteTimeout),
+		httpTimeout(c.HTTPIdleTimeout, DefaultHTTPIdleTimeout)
+}
+
+// httpTimeout parses a configured HTTP timeout, using fallback when unset or invalid
+func httpTimeout(value string, fallback time.Duration) time.Duration {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		return fallback
+	}
+	return timeout
+}
+
+// ServeAPIOnly reports whether the server runs without the embedded web UI,
+// configured or by command line override.
+func (c *Config) ServeAPIOnly() bool {
+	return c.APIOnly || c.Overrides.APIOnly
+}
+
+// AutoStartProfile returns the auto-start profile in effect: the command line
+// one when given, else the configured ActiveProfile.
+func (c *Config) AutoStartProfile() string {
+	if c.Overrides.ActiveProfile != "" {
+		return c.Overrides.ActiveProfile
+	}
+	return c.ActiveProfile
+}
+
+// TLSSettings returns the HTTPS settings in effect: a certificate and key given
+// on the command line replace the configured pair, and -tls-selfsigned enables
+// a self-signed certificate.
+func (c *Config) TLSSettings() (certFile, keyFile string, selfSigned bool) {
+	certFile, keyFile = c.TLSCertFile, c.TLSKeyFile
+	if c.Overrides.TLSCertFile != "" {
+		certFile, keyFile = c.Overrides.TLSCertFile, c.Overrides.TLSKeyFile
+	}
+	return certFile, keyFile, c.TLSSelfSigned || c.Overrides.TLSSelfSigned
+}
+
+// TLSEnabled reports whether the server is configured to serve HTTPS.
+func (c *Config) TLSEnabled() bool {
+	certFile, _, selfSigned := c.TLSSettings()
+	return certFile != "" || selfSigned
+}
+
+// MatchesProfile reports whether a pair belongs to the auto-start profile.
+// Every pair matches when no profile is active.
+func MatchesProfile(pair *Pair, profile string) bool {
+	return profile == "" || pair.HasTag(profile)
+}
+
 // createDefaultConfig returns a new configuration with sensible defaults
 func createDefaultConfig() *Config {
 	return &Config{
-		Listen: DefaultListen,
-		Pairs:  []*Pair{},
+		Listen:             DefaultListen,
+		Pairs:              []*Pair{},
+		MaxConcurrentHooks: DefaultMaxConcurrentHooks,
+		MaxOpenFiles:       DefaultMaxOpenFiles,
+		MaxInitialSyncs:    DefaultMaxInitialSyncs,
+		MaxConcurrentSyncs: DefaultMaxConcurrentSyncs,
+		HookHistorySize:    DefaultHookHistorySize,
+		ConfigBackups:      DefaultConfigBackups,
+		DefaultSchedule:    scheduler.NewWatcherSchedule(),
 	}
 }
 
@@ -236,18 +801,39 @@ func applyDefaults(config *Config) {
 	if config.Listen == "" {
 		config.Listen = DefaultListen
 	}
+	if config.MaxConcurrentHooks == 0 {
+		config.MaxConcurrentHooks = DefaultMaxConcurrentHooks
+	}
+	if config.MaxOpenFiles == 0 {
+		config.MaxOpenFiles = DefaultMaxOpenFiles
+	}
+	if config.MaxInitialSyncs == 0 {
+		config.MaxInitialSyncs = DefaultMaxInitialSyncs
+	}
+	if config.MaxConcurrentSyncs == 0 {
+		config.MaxConcurrentSyncs = DefaultMaxConcurrentSyncs
+	}
+	if config.HookHistorySize == 0 {
+		config.HookHistorySize = DefaultHookHistorySize
+	}
+	if config.ConfigBackups == 0 {
+		config.ConfigBackups = DefaultConfigBackups
+	}
+	if config.DefaultSchedule.Type == "" {
+		config.DefaultSchedule = scheduler.NewWatcherSchedule()
+	}
 
 	// Set pair defaults
 	for _, pair := range config.Pairs {
-		applyPairDefaults(pair)
+		applyPairDefaults(pair, config.DefaultSchedule)
 	}
 }
 
 // applyPairDefaults sets default values for a sync pair configuration
-func applyPairDefaults(pair *Pair) {
+func applyPairDefaults(pair *Pair, defaultSchedule scheduler.Schedule) {
 	// Set default schedule if not specified
 	if pair.Schedule.Type == "" {
-		pair.Schedule = scheduler.NewWatcherSchedule()
+		pair.Schedule = defaultSchedule
 	}
 
 	// Set performance defaults
@@ -270,6 +856,9 @@ func applyPairDefaults(pair *Pair) {
 	if pair.IncludeExt == nil {
 		pair.IncludeExt = []string{}
 	}
+	if pair.IncludeGlobs == nil {
+		pair.IncludeGlobs = []string{}
+	}
 	if pair.ExcludeGlobs == nil {
 		pair.ExcludeGlobs = []string{}
 	}
@@ -283,6 +872,67 @@ func validateConfig(config *Config) error {
 	if config.Listen == "" {
 		return errors.New("listen address cannot be empty")
 	}
+	if config.ListenPortFallback < 0 {
+		return errors.New("listen port fallback cannot be negative")
+	}
+	if config.MaxConcurrentHooks < 0 {
+		return errors.New("max concurrent hooks cannot be negative")
+	}
+	if config.MaxOpenFiles < 0 {
+		return errors.New("max open files cannot be negative")
+	}
+	if config.MaxInitialSyncs < 0 {
+		return errors.New("max initial syncs cannot be negative")
+	}
+	if config.MaxConcurrentSyncs < 0 {
+		return errors.New("max concurrent syncs cannot be negative")
+	}
+	if config.HookHistorySize < 0 {
+		return errors.New("hook history size cannot be negative")
+	}
+	if config.ConfigBackups < 0 {
+		return errors.New("config backups cannot be negative")
+	}
+	if config.StaleTempAge != "" {
+		if age, err := time.ParseDuration(config.StaleTempAge); err != nil || age <= 0 {
+			return fmt.Errorf("invalid stale temp age %q (must be a positive duration)", config.StaleTempAge)
+		}
+	}
+	if config.DrainTimeout != "" {
+		if timeout, err := time.ParseDuration(config.DrainTimeout); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid drain timeout %q (must be a non-negative duration)", config.DrainTimeout)
+		}
+	}
+	for _, setting := range []struct{ name, value string }{
+		{"http read timeout", config.HTTPReadTimeout},
+		{"http write timeout", config.HTTPWriteTimeout},
+		{"http idle timeout", config.HTTPIdleTimeout},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		if timeout, err := time.ParseDuration(setting.value); err != nil || timeout < 0 {
+			return fmt.Errorf("invalid %s %q (must be a non-negative duration)", setting.name, setting.value)
+		}
+	}
+	if (config.TLSCertFile == "") != (config.TLSKeyFile == "") {
+		return errors.New("tls certificate and key files must be set together")
+	}
+	if config.BasicAuthUser != "" && config.BasicAuthPass == "" {
+		return errors.New("basic auth password cannot be empty when a user is set")
+	}
+	for _, entry := range config.AllowedCIDRs {
+		entry = strings.TrimSpace(entry)
+		if _, _, err := net.ParseCIDR(entry); err != nil && net.ParseIP(entry) == nil {
+			return fmt.Errorf("invalid allowed CIDR %q", entry)
+		}
+	}
+	if err := scheduler.ValidateSchedule(config.DefaultSchedule); err != nil {
+		return fmt.Errorf("default schedule: %w", err)
+	}
+	if _, err := scheduler.NewHolidayList(config.Holidays); err != nil {
+		return err
+	}
 
 	// Validate each pair
 	for i, pair := range config.Pairs {
@@ -299,22 +949,32 @@ func validatePair(pair *Pair) error {
 	if pair.ID == "" {
 		return errors.New("pair ID cannot be empty")
 	}
+	if err := ValidateSources(pair); err != nil {
+		return err
+	}
+	if err := ValidateTargets(pair); err != nil {
+		return err
+	}
 	if pair.Source == "" {
 		return errors.New("source path cannot be empty")
 	}
 	if pair.Target == "" {
 		return errors.New("target path cannot be empty")
 	}
-	if pair.Source == pair.Target {
-		return errors.New("source and target paths cannot be the same")
+	for _, source := range pair.SourceRoots() {
+		for _, target := range pair.TargetRoots() {
+			if err := ValidatePairPaths(source, target); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Validate sync strategy
 	switch pair.SyncStrategy {
-	case "mtime", "hash", "":
+	case "mtime", "hash", "delta", "":
 		// Valid strategies (empty will be defaulted)
 	default:
-		return fmt.Errorf("invalid sync strategy: %s (must be 'mtime' or 'hash')", pair.SyncStrategy)
+		return fmt.Errorf("invalid sync strategy: %s (must be 'mtime', 'hash' or 'delta')", pair.SyncStrategy)
 	}
 
 	// Validate performance settings
@@ -327,6 +987,72 @@ func validatePair(pair *Pair) error {
 	if pair.HookMaxRetries < 0 {
 		return errors.New("hook max retries cannot be negative")
 	}
+	if pair.CopyRetries < 0 {
+		return errors.New("copy retries cannot be negative")
+	}
+	if pair.CopyRetryDelayMs < 0 {
+		return errors.New("copy retry delay cannot be negative")
+	}
+	if pair.ReconcileMaxAttempts < 0 {
+		return errors.New("reconcile max attempts cannot be negative")
+	}
+	if pair.PeriodicHashCheck < 0 {
+		return errors.New("periodic hash check cannot be negative")
+	}
+	if pair.WatchSetupBatchSize < 0 {
+		return errors.New("watch setup batch size cannot be negative")
+	}
+	if pair.WatchSetupPauseMs < 0 {
+		return errors.New("watch setup pause cannot be negative")
+	}
+	if err := ValidateWatchPollInterval(pair.WatchPollInterval); err != nil {
+		return err
+	}
+
+	// Validate Unicode normalization form
+	switch strings.ToLower(pair.UnicodeNormalization) {
+	case "", "nfc", "nfd":
+	default:
+		return fmt.Errorf("invalid unicode normalization: %s (must be 'nfc' or 'nfd')", pair.UnicodeNormalization)
+	}
+
+	// Validate symlink handling
+	if err := ValidateSymlinkMode(pair.SymlinkMode); err != nil {
+		return err
+	}
+	switch pair.SymlinkRewrite {
+	case "", SymlinkRewriteRelative, SymlinkRewriteTarget:
+	default:
+		return fmt.Errorf("invalid symlink rewrite: %s (must be 'relative' or 'target')", pair.SymlinkRewrite)
+	}
+
+	// Validate file size limits
+	if err := ValidateFileSizeLimits(pair.MinFileSize, pair.MaxFileSize); err != nil {
+		return err
+	}
+	if pair.MaxDepth < 0 {
+		return fmt.Errorf("invalid max depth: %d (must be 0 or positive)", pair.MaxDepth)
+	}
+
+	// Validate archive settings
+	if err := ValidateArchiveSettings(pair); err != nil {
+		return err
+	}
+
+	// Validate rate limiting
+	if err := ValidateMinRunInterval(pair.MinRunInterval); err != nil {
+		return err
+	}
+
+	// Validate deferred mirror deletes
+	if err := ValidateMirrorDeleteMode(pair); err != nil {
+		return err
+	}
+
+	// Validate watcher debounce mode
+	if err := ValidateDebounceMode(pair.DebounceMode); err != nil {
+		return err
+	}
 
 	// Validate hooks
 	for j, hook := range pair.Hooks {
@@ -338,14 +1064,300 @@ func validatePair(pair *Pair) error {
 	return nil
 }
 
+// ValidateFileSizeLimits checks that file size limits are non-negative and that
+// the maximum is not below the minimum when both are set (0 max means no limit).
+func ValidateFileSizeLimits(minSize, maxSize int64) error {
+	if minSize < 0 {
+		return errors.New("min file size cannot be negative")
+	}
+	if maxSize < 0 {
+		return errors.New("max file size cannot be negative")
+	}
+	if maxSize > 0 && maxSize < minSize {
+		return fmt.Errorf("max file size (%d) must be greater than or equal to min file size (%d)", maxSize, minSize)
+	}
+	return nil
+}
+
+// ValidateArchiveSettings checks the archive format and retention of a pair.
+// Archive mode produces whole-tree snapshots, so it can't run as a file watcher.
+func ValidateArchiveSettings(pair *Pair) error {
+	if pair.ArchiveKeep < 0 {
+		return errors.New("archive keep count cannot be negative")
+	}
+
+	switch strings.ToLower(pair.ArchiveMode) {
+	case "":
+		return nil
+	case ArchiveFormatZip, ArchiveFormatTarGz:
+	default:
+		return fmt.Errorf("invalid archive mode: %s (must be 'zip' or 'tar.gz')", pair.ArchiveMode)
+	}
+
+	if pair.Schedule.Type == scheduler.ScheduleTypeWatcher {
+		return errors.New("archive mode requires an interval, cron, custom or disabled schedule")
+	}
+	return nil
+}
+
+// ValidateMirrorDeleteMode checks the mirror delete mode and the schedule that
+// applies deferred deletes.
+func ValidateMirrorDeleteMode(pair *Pair) error {
+	switch pair.MirrorDeleteMode {
+	case "", MirrorDeleteInline, MirrorDeleteDeferred:
+	default:
+		return fmt.Errorf("invalid mirror delete mode: %s (must be 'inline' or 'deferred')", pair.MirrorDeleteMode)
+	}
+
+	if pair.ApplyDeletesSchedule == nil {
+		return nil
+	}
+	if pair.ApplyDeletesSchedule.Type == scheduler.ScheduleTypeWatcher {
+		return errors.New("apply deletes schedule cannot be a watcher schedule")
+	}
+	if err := scheduler.ValidateSchedule(*pair.ApplyDeletesSchedule); err != nil {
+		return fmt.Errorf("apply deletes schedule: %w", err)
+	}
+	return nil
+}
+
+// ValidateSymlinkMode checks the symlink handling mode of a pair.
+func ValidateSymlinkMode(mode string) error {
+	switch mode {
+	case "", SymlinkModeFollow, SymlinkModeCopyLink, SymlinkModeSkip:
+		return nil
+	default:
+		return fmt.Errorf("invalid symlink mode: %s (must be 'follow', 'copy-link' or 'skip')", mode)
+	}
+}
+
+// ValidateDebounceMode checks the watcher debounce mode of a pair.
+func ValidateDebounceMode(mode string) error {
+	switch mode {
+	case "", DebounceModeFile, DebounceModeBatch:
+		return nil
+	default:
+		return fmt.Errorf("invalid debounce mode: %s (must be 'file' or 'batch')", mode)
+	}
+}
+
+// ValidateSources checks the source list of a multi-source pair: no empty or
+// nested entries and a known conflict mode. A pair given only the list gets
+// Source set to its first entry; a Source that differs from it is rejected.
+func ValidateSources(pair *Pair) error {
+	switch pair.SourceConflict {
+	case "", SourceConflictFirst, SourceConflictError:
+	default:
+		return fmt.Errorf("invalid source conflict mode: %s (must be 'first' or 'error')", pair.SourceConflict)
+	}
+	if len(pair.Sources) == 0 {
+		return nil
+	}
+
+	if pair.Source == "" {
+		pair.Source = pair.Sources[0]
+	} else if pair.Source != pair.Sources[0] {
+		return fmt.Errorf("source %s must be the first entry of sources", pair.Source)
+	}
+
+	for i, source := range pair.Sources {
+		if source == "" {
+			return fmt.Errorf("sources[%d] cannot be empty", i)
+		}
+		resolved := resolvePath(source)
+		for _, other := range pair.Sources[:i] {
+			resolvedOther := resolvePath(other)
+			if resolved == resolvedOther {
+				return fmt.Errorf("source %s is listed twice", source)
+			}
+			if pathWithin(resolved, resolvedOther) || pathWithin(resolvedOther, resolved) {
+				return fmt.Errorf("sources %s and %s are nested", other, source)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTargets checks the target list of a fan-out pair: no empty, duplicate
+// or nested entries. A pair given only the list gets Target set to its first
+// entry; a Target that differs from it is rejected.
+func ValidateTargets(pair *Pair) error {
+	if len(pair.Targets) == 0 {
+		return nil
+	}
+
+	if pair.Target == "" {
+		pair.Target = pair.Targets[0]
+	} else if pair.Target != pair.Targets[0] {
+		return fmt.Errorf("target %s must be the first entry of targets", pair.Target)
+	}
+
+	for i, target := range pair.Targets {
+		if target == "" {
+			return fmt.Errorf("targets[%d] cannot be empty", i)
+		}
+		resolved := resolvePath(target)
+		for _, other := range pair.Targets[:i] {
+			resolvedOther := resolvePath(other)
+			if resolved == resolvedOther {
+				return fmt.Errorf("target %s is listed twice", target)
+			}
+			if pathWithin(resolved, resolvedOther) || pathWithin(resolvedOther, resolved) {
+				return fmt.Errorf("targets %s and %s are nested", other, target)
+			}
+		}
+	}
+
+	// Pending deletes are tracked per pair, not per target
+	if len(pair.Targets) > 1 && pair.DefersMirrorDeletes() {
+		return errors.New("deferred mirror deletes are not supported with several targets")
+	}
+	return nil
+}
+
+// DefersMirrorDeletes reports whether mirror deletes are recorded instead of applied.
+func (p *Pair) DefersMirrorDeletes() bool {
+	return p.MirrorDeletes && p.MirrorDeleteMode == MirrorDeleteDeferred
+}
+
+// ValidatePairPaths checks that source and target are different and that neither
+// is nested inside the other, which would copy the tree into itself endlessly.
+// Paths are compared after cleaning and resolving symlinks; parts that don't
+// exist yet (targets are created on demand) are compared as given.
+func ValidatePairPaths(source, target string) error {
+	resolvedSource := resolvePath(source)
+	resolvedTarget := resolvePath(target)
+
+	if resolvedSource == resolvedTarget {
+		return errors.New("source and target paths cannot be the same")
+	}
+	if pathWithin(resolvedTarget, resolvedSource) {
+		return fmt.Errorf("target %s is inside source %s", target, source)
+	}
+	if pathWithin(resolvedSource, resolvedTarget) {
+		return fmt.Errorf("source %s is inside target %s", source, target)
+	}
+	return nil
+}
+
+// ValidateSourceDir checks that a pair's source exists and is a directory.
+func ValidateSourceDir(source string) error {
+	info, err := os.Stat(source)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("source %s does not exist", source)
+	}
+	if err != nil {
+		return fmt.Errorf("source %s is not accessible: %w", source, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("source %s is not a directory", source)
+	}
+	return nil
+}
+
+// resolvePath returns the absolute, cleaned form of a path with symlinks resolved
+// for its longest existing prefix. On Windows it is also lower-cased, matching the
+// case-insensitive file system.
+func resolvePath(path string) string {
+	path = strings.TrimPrefix(path, `\\?\`)
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		absolute = filepath.Clean(path)
+	}
+
+	resolved := absolute
+	missing := ""
+	for current := absolute; ; {
+		if real, err := filepath.EvalSymlinks(current); err == nil {
+			resolved = filepath.Join(real, missing)
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		missing = filepath.Join(filepath.Base(current), missing)
+		current = parent
+	}
+
+	if runtime.GOOS == "windows" {
+		resolved = strings.ToLower(resolved)
+	}
+	return resolved
+}
+
+// pathWithin reports whether path lies below dir (both resolved by resolvePath).
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || filepath.IsAbs(rel) {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ValidateMinRunInterval checks that a minimum run interval is empty or a
+// non-negative duration.
+func ValidateMinRunInterval(interval string) error {
+	if interval == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return fmt.Errorf("invalid min run interval %s: %w", interval, err)
+	}
+	if duration < 0 {
+		return errors.New("min run interval cannot be negative")
+	}
+	return nil
+}
+
+// ValidateWatchPollInterval checks that a watch polling interval is empty or a
+// positive duration.
+func ValidateWatchPollInterval(interval string) error {
+	if interval == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return fmt.Errorf("invalid watch poll interval %s: %w", interval, err)
+	}
+	if duration <= 0 {
+		return errors.New("watch poll interval must be positive")
+	}
+	return nil
+}
+
+// validGRPCMethod reports whether a method name has the "/service/method" form.
+func validGRPCMethod(method string) bool {
+	parts := strings.Split(method, "/")
+	return len(parts) == 3 && parts[0] == "" && parts[1] != "" && parts[2] != ""
+}
+
+// ValidateHook checks a single hook definition, such as one tested before it is saved
+func ValidateHook(hook *Hook) error {
+	return validateHook(hook)
+}
+
 // validateHook performs validation on a hook configuration
 func validateHook(hook *Hook) error {
-	// Must have either HTTP or Command configuration, but not both
-	if hook.HTTP == nil && hook.Command == nil {
-		return errors.New("hook must have either HTTP or Command configuration")
+	// Must have exactly one of HTTP, Command or gRPC configuration
+	configured := 0
+	for _, present := range []bool{hook.HTTP != nil, hook.Command != nil, hook.GRPC != nil} {
+		if present {
+			configured++
+		}
+	}
+	if configured == 0 {
+		return errors.New("hook must have either HTTP, Command or gRPC configuration")
+	}
+	if configured > 1 {
+		return errors.New("hook cannot have more than one of HTTP, Command and gRPC configurations")
+	}
+	if hook.TimeoutMs < 0 {
+		return errors.New("hook timeout cannot be negative")
 	}
-	if hook.HTTP != nil && hook.Command != nil {
-		return errors.New("hook cannot have both HTTP and Command configurations")
+	if hook.MaxRetries < 0 {
+		return errors.New("hook max retries cannot be negative")
 	}
 
 	// Validate HTTP hook
@@ -365,5 +1377,15 @@ func validateHook(hook *Hook) error {
 		}
 	}
 
+	// Validate gRPC hook
+	if hook.GRPC != nil {
+		if hook.GRPC.Target == "" {
+			return errors.New("gRPC hook target cannot be empty")
+		}
+		if !validGRPCMethod(hook.GRPC.Method) {
+			return fmt.Errorf("invalid gRPC hook method %q (must be /package.Service/Method)", hook.GRPC.Method)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"FolderSynchronizer/internal/scheduler"
+)
+
+func TestSaveOmitsOverrides(t *testing.T) {
+	for _, name := range []string{"config.json", "config.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			conf := createDefaultConfig()
+			conf.ActiveProfile = "office"
+			conf.Overrides = Overrides{
+				APIOnly:       true,
+				ActiveProfile: "laptop",
+				TLSCertFile:   "/etc/override/cert.pem",
+				TLSKeyFile:    "/etc/override/key.pem",
+				TLSSelfSigned: true,
+			}
+
+			if !conf.ServeAPIOnly() {
+				t.Fatal("ServeAPIOnly() = false with the -api-only override")
+			}
+			if got := conf.AutoStartProfile(); got != "laptop" {
+				t.Fatalf("AutoStartProfile() = %q, want the -profile override", got)
+			}
+			if certFile, keyFile, selfSigned := conf.TLSSettings(); certFile != "/etc/override/cert.pem" || keyFile != "/etc/override/key.pem" || !selfSigned {
+				t.Fatalf("TLSSettings() = %q, %q, %v, want the command line overrides", certFile, keyFile, selfSigned)
+			}
+			if err := Save(path, conf); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, leaked := range []string{"apionly", "laptop", "override", "tlsselfsigned"} {
+				if strings.Contains(strings.ToLower(string(data)), leaked) {
+					t.Errorf("saved config contains override %q:\n%s", leaked, data)
+				}
+			}
+
+			loaded, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if loaded.ServeAPIOnly() {
+				t.Error("reloaded config serves API only; the override was persisted")
+			}
+			if got := loaded.AutoStartProfile(); got != "office" {
+				t.Errorf("reloaded AutoStartProfile() = %q, want the configured %q", got, "office")
+			}
+			if loaded.TLSEnabled() {
+				t.Error("reloaded config enables TLS; the overrides were persisted")
+			}
+		})
+	}
+}
+
+func TestMatchesProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		profile string
+		want    bool
+	}{
+		{"no profile matches untagged", nil, "", true},
+		{"no profile matches tagged", []string{"heavy"}, "", true},
+		{"tag matches", []string{"light"}, "light", true},
+		{"tag matches ignoring case", []string{"Light"}, "LIGHT", true},
+		{"one of several tags", []string{"laptop", "light"}, "light", true},
+		{"other tag", []string{"heavy"}, "light", false},
+		{"untagged outside profile", nil, "light", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesProfile(&Pair{Tags: tt.tags}, tt.profile); got != tt.want {
+				t.Errorf("MatchesProfile(%q, %q) = %v, want %v", tt.tags, tt.profile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAppliesDefaultSchedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaults     string // defaultSchedule JSON, "" to leave it out
+		pairSchedule string // pair schedule JSON, "" to leave it out
+		wantType     scheduler.ScheduleType
+		wantInterval string
+		wantErr      bool
+	}{
+		{"watcher without a default", "", "", scheduler.ScheduleTypeWatcher, "", false},
+		{"configured default adopted", `{"type":"interval","interval":"15m"}`, "", scheduler.ScheduleTypeInterval, "15m", false},
+		{"explicit pair schedule kept", `{"type":"interval","interval":"15m"}`, `{"type":"interval","interval":"1h"}`, scheduler.ScheduleTypeInterval, "1h", false},
+		{"invalid default rejected", `{"type":"interval","interval":"soon"}`, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := `{"id":"docs","source":"/src","target":"/dst"`
+			if tt.pairSchedule != "" {
+				pair += `,"schedule":` + tt.pairSchedule
+			}
+			config := `{"pairs":[` + pair + `}]`
+			if tt.defaults != "" {
+				config += `,"defaultSchedule":` + tt.defaults
+			}
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(config+"}"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded, err := Load(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load accepted an invalid default schedule")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if schedule := loaded.Pairs[0].Schedule; schedule.Type != tt.wantType || schedule.Interval != tt.wantInterval {
+				t.Errorf("pair schedule = %s %q, want %s %q", schedule.Type, schedule.Interval, tt.wantType, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestValidatePairPaths(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "src")
+	if err := os.MkdirAll(filepath.Join(source, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	linkToSource := filepath.Join(root, "link")
+	hasLink := os.Symlink(source, linkToSource) == nil
+
+	tests := []struct {
+		name    string
+		source  string
+		target  string
+		wantErr string
+		link    bool
+	}{
+		{"siblings", source, filepath.Join(root, "dst"), "", false},
+		{"shared name prefix", source, source + "2", "", false},
+		{"same path", source, source, "cannot be the same", false},
+		{"same after cleaning", source, source + string(filepath.Separator) + ".", "cannot be the same", false},
+		{"target inside source", source, filepath.Join(source, "sub"), "is inside source", false},
+		{"missing target inside source", source, filepath.Join(source, "new", "dir"), "is inside source", false},
+		{"source inside target", filepath.Join(source, "sub"), source, "is inside target", false},
+		{"target inside source through a link", source, filepath.Join(linkToSource, "sub"), "is inside source", true},
+		{"same through a link", linkToSource, source, "cannot be the same", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.link && !hasLink {
+				t.Skip("symlinks unavailable")
+			}
+			err := ValidatePairPaths(tt.source, tt.target)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePairPaths() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePairPaths() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSourceDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{"directory", dir, ""},
+		{"missing", filepath.Join(dir, "missing"), "does not exist"},
+		{"file", file, "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSourceDir(tt.source)
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("ValidateSourceDir() = %v, want error %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package config provides environment variable expansion for the FolderSynchronizer configuration.
+// Pair paths and hook settings may reference ${VAR} or $VAR; they are expanded when loaded while
+// the saved file keeps the references, so one config can be deployed to differently laid out machines.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ===== ENVIRONMENT EXPANSION =====
+
+// envTemplate remembers the raw form of a field expanded at load time
+type envTemplate struct {
+	raw      string // Value as written in the config file
+	expanded string // Value after expansion
+}
+
+// expandEnv resolves ${VAR} and $VAR references, failing when a variable is unset.
+func expandEnv(value string) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var missing []string
+	expanded := os.Expand(value, func(name string) string {
+		resolved, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return resolved
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s referenced in %q is not set", strings.Join(missing, ", "), value)
+	}
+	return expanded, nil
+}
+
+// visitEnvFields calls fn for each pair field subject to expansion (sources, targets,
+// command hook working directories and environment values, HTTP hook URLs) and
+// stores the value it returns. Names identify the field within the pair.
+func visitEnvFields(pair *Pair, fn func(name, value string) (string, error)) error {
+	visit := func(name string, field *string) error {
+		value, err := fn(name, *field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*field = value
+		return nil
+	}
+
+	if err := visit("source", &pair.Source); err != nil {
+		return err
+	}
+	for i := range pair.Sources {
+		if err := visit(fmt.Sprintf("sources[%d]", i), &pair.Sources[i]); err != nil {
+			return err
+		}
+	}
+	if err := visit("target", &pair.Target); err != nil {
+		return err
+	}
+	for i := range pair.Targets {
+		if err := visit(fmt.Sprintf("targets[%d]", i), &pair.Targets[i]); err != nil {
+			return err
+		}
+	}
+
+	for i := range pair.Hooks {
+		hook := &pair.Hooks[i]
+		if hook.Command != nil {
+			if err := visit(fmt.Sprintf("hooks[%d].command.workDir", i), &hook.Command.WorkDir); err != nil {
+				return err
+			}
+			for key, value := range hook.Command.EnvVars {
+				if err := visit(fmt.Sprintf("hooks[%d].command.envVars.%s", i, key), &value); err != nil {
+					return err
+				}
+				hook.Command.EnvVars[key] = value
+			}
+		}
+		if hook.HTTP != nil {
+			if err := visit(fmt.Sprintf("hooks[%d].http.url", i), &hook.HTTP.URL); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ExpandPairEnv expands environment variable references in a pair and records
+// the raw values, which Save writes back instead of the expanded ones.
+// The caller must hold whatever lock guards the configuration.
+func (c *Config) ExpandPairEnv(pair *Pair) error {
+	return visitEnvFields(pair, func(name, value string) (string, error) {
+		expanded, err := expandEnv(value)
+		if err != nil || expanded == value {
+			return value, err
+		}
+		if c.envTemplates == nil {
+			c.envTemplates = make(map[string]envTemplate)
+		}
+		c.envTemplates[pair.ID+"/"+name] = envTemplate{raw: value, expanded: expanded}
+		return expanded, nil
+	})
+}
+
+// withEnvTemplates returns a copy of the configuration for saving, in which
+// fields still holding their expanded value are replaced by the raw reference.
+// Fields changed since loading are saved as they are.
+func (c *Config) withEnvTemplates() *Config {
+	if len(c.envTemplates) == 0 {
+		return c
+	}
+
+	saved := *c
+	saved.Pairs = make([]*Pair, len(c.Pairs))
+	for i, pair := range c.Pairs {
+		saved.Pairs[i] = c.PairWithEnvTemplates(pair)
+	}
+	return &saved
+}
+
+// PairWithEnvTemplates returns a copy of a pair in which fields still holding
+// their expanded value carry the raw environment reference again, as Save
+// writes them. The caller must hold whatever lock guards the configuration.
+func (c *Config) PairWithEnvTemplates(pair *Pair) *Pair {
+	clone := clonePairForSave(pair)
+	_ = visitEnvFields(clone, func(name, value string) (string, error) {
+		if template, ok := c.envTemplates[pair.ID+"/"+name]; ok && template.expanded == value {
+			return template.raw, nil
+		}
+		return value, nil
+	})
+	return clone
+}
+
+// clonePairForSave copies a pair deeply enough that visitEnvFields can change
+// the copy without touching the original.
+func clonePairForSave(pair *Pair) *Pair {
+	clone := *pair
+	if pair.Sources != nil {
+		clone.Sources = append([]string(nil), pair.Sources...)
+	}
+	if pair.Targets != nil {
+		clone.Targets = append([]string(nil), pair.Targets...)
+	}
+	if pair.Hooks != nil {
+		clone.Hooks = make([]Hook, len(pair.Hooks))
+		copy(clone.Hooks, pair.Hooks)
+	}
+	for i := range clone.Hooks {
+		hook := &clone.Hooks[i]
+		if hook.Command != nil {
+			command := *hook.Command
+			if command.EnvVars != nil {
+				command.EnvVars = make(map[string]string, len(hook.Command.EnvVars))
+				for key, value := range hook.Command.EnvVars {
+					command.EnvVars[key] = value
+				}
+			}
+			hook.Command = &command
+		}
+		if hook.HTTP != nil {
+			http := *hook.HTTP
+			hook.HTTP = &http
+		}
+	}
+	return &clone
+}
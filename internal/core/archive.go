@@ -0,0 +1,253 @@
+// Package core provides archive-mode synchronization for the FolderSynchronizer application.
+// Instead of mirroring files, the filtered source tree is written into a timestamped
+// .zip or .tar.gz archive in the target directory, keeping only the newest archives.
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== ARCHIVE CONSTANTS =====
+
+const (
+	// ArchiveTimestampLayout names archives so they sort chronologically
+	ArchiveTimestampLayout = "20060102-150405"
+)
+
+// ===== ARCHIVE SYNC =====
+
+// archiveSync writes the filtered source files into a new archive in the
+// target directory, prunes old archives and fires hooks with the archive path.
+// In a dry run only the files that would be archived are counted.
+func (c *Copier) archiveSync(ctx context.Context, pair *cfg.Pair) (*SyncResult, error) {
+	result := &SyncResult{}
+
+	archiveName := archiveFileName(pair, time.Now())
+	archivePath := filepath.Join(pair.Target, archiveName)
+
+	if c.dryRun {
+		err := walkArchiveSources(ctx, c, pair, result, func(string, string, os.FileInfo) error { return nil })
+		return result, err
+	}
+
+	if err := os.MkdirAll(pair.Target, DefaultDirPerms); err != nil {
+		return result, err
+	}
+
+	// Write to a temp file so a failed run never leaves a truncated archive
+	tempPath := tempPathFor(archivePath)
+	file, err := createTempFile(tempPath)
+	if err != nil {
+		return result, err
+	}
+
+	writeErr := writeArchive(ctx, c, pair, file, result)
+	closeErr := file.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(tempPath)
+		return result, writeErr
+	}
+
+	if err := replaceTarget(tempPath, archivePath); err != nil {
+		_ = os.Remove(tempPath)
+		return result, err
+	}
+
+	logging.ForPair(pair.ID).Info().
+		Str("pair", pair.ID).
+		Str("archive", archivePath).
+		Int("files", result.FilesCopied).
+		Int64("bytes", result.BytesCopied).
+		Msg("archive created")
+
+	result.FilesDeleted = pruneArchives(pair)
+
+	// Summary hook: one invocation for the archive instead of one per file
+	RunHooks(ctx, pair, archiveName)
+
+	return result, nil
+}
+
+// writeArchive streams the filtered source files into w in the pair's format.
+func writeArchive(ctx context.Context, c *Copier, pair *cfg.Pair, w io.Writer, result *SyncResult) error {
+	switch strings.ToLower(pair.ArchiveMode) {
+	case cfg.ArchiveFormatZip:
+		zipWriter := zip.NewWriter(w)
+		err := walkArchiveSources(ctx, c, pair, result, func(path, name string, info os.FileInfo) error {
+			header, err := zip.FileInfoHeader(info)
+			if err != nil {
+				return err
+			}
+			header.Name = name
+			header.Method = zip.Deflate
+
+			entry, err := zipWriter.CreateHeader(header)
+			if err != nil {
+				return err
+			}
+			return copyFileInto(entry, path)
+		})
+		if err != nil {
+			return err
+		}
+		return zipWriter.Close()
+
+	case cfg.ArchiveFormatTarGz:
+		gzipWriter := gzip.NewWriter(w)
+		tarWriter := tar.NewWriter(gzipWriter)
+		err := walkArchiveSources(ctx, c, pair, result, func(path, name string, info os.FileInfo) error {
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = name
+
+			if err := tarWriter.WriteHeader(header); err != nil {
+				return err
+			}
+			return copyFileInto(tarWriter, path)
+		})
+		if err != nil {
+			return err
+		}
+		if err := tarWriter.Close(); err != nil {
+			return err
+		}
+		return gzipWriter.Close()
+
+	default:
+		return fmt.Errorf("unsupported archive format: %s", pair.ArchiveMode)
+	}
+}
+
+// walkArchiveSources calls add for every source file passing the pair's
+// filters, with the slash-separated archive entry name, and updates counters.
+// The target directory is skipped when it is nested inside the source. With
+// several sources, an entry name already added from an earlier source is skipped.
+func walkArchiveSources(ctx context.Context, c *Copier, pair *cfg.Pair, result *SyncResult, add func(path, name string, info os.FileInfo) error) error {
+	targetRoot := filepath.Clean(pair.Target)
+	added := make(map[string]bool)
+
+	for _, root := range pair.SourceRoots() {
+		err := walkSource(pair, root, func(path, relativePath string, dirEntry fs.DirEntry) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			if dirEntry.IsDir() {
+				if filepath.Clean(path) == targetRoot {
+					return filepath.SkipDir
+				}
+				if dirBeyondMaxDepth(pair, relativePath) {
+					result.DirsBeyondDepth++
+				}
+				return nil
+			}
+
+			info, err := dirEntry.Info()
+			if err != nil {
+				return err
+			}
+
+			// Only regular files are archived
+			name := NormalizePath(relativePath)
+			if !info.Mode().IsRegular() || added[name] || !c.shouldSyncFile(pair, path, relativePath, info) {
+				result.FilesSkipped++
+				return nil
+			}
+
+			if err := add(path, name, info); err != nil {
+				return fmt.Errorf("archive %s: %w", relativePath, err)
+			}
+			added[name] = true
+
+			result.FilesCopied++
+			result.BytesCopied += info.Size()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFileInto copies a file's content into an archive entry.
+func copyFileInto(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.CopyBuffer(w, file, make([]byte, CopyBufferSize))
+	return err
+}
+
+// ===== ARCHIVE RETENTION =====
+
+// archiveFileName builds the timestamped archive name for a pair.
+func archiveFileName(pair *cfg.Pair, at time.Time) string {
+	return pair.ID + "-" + at.Format(ArchiveTimestampLayout) + "." + strings.ToLower(pair.ArchiveMode)
+}
+
+// pruneArchives deletes the pair's oldest archives beyond ArchiveKeep and
+// returns the number removed. ArchiveKeep of 0 keeps every archive.
+func pruneArchives(pair *cfg.Pair) int {
+	if pair.ArchiveKeep <= 0 {
+		return 0
+	}
+
+	entries, err := os.ReadDir(pair.Target)
+	if err != nil {
+		logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Err(err).Msg("archive pruning skipped")
+		return 0
+	}
+
+	prefix := pair.ID + "-"
+	suffix := "." + strings.ToLower(pair.ArchiveMode)
+
+	var archives []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) {
+			archives = append(archives, name)
+		}
+	}
+
+	if len(archives) <= pair.ArchiveKeep {
+		return 0
+	}
+
+	// Timestamped names sort oldest first
+	sort.Strings(archives)
+
+	removed := 0
+	for _, name := range archives[:len(archives)-pair.ArchiveKeep] {
+		if err := os.Remove(filepath.Join(pair.Target, name)); err != nil {
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("archive", name).Err(err).Msg("failed to prune archive")
+			continue
+		}
+		removed++
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("archive", name).Msg("pruned old archive")
+	}
+
+	return removed
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// readArchive returns the entry names and contents of a zip or tar.gz archive
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	entries := make(map[string]string)
+
+	if strings.HasSuffix(path, ".zip") {
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			entry, err := file.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := io.ReadAll(entry)
+			entry.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries[file.Name] = string(data)
+		}
+		return entries
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries[header.Name] = string(data)
+	}
+	return entries
+}
+
+// listArchives returns the archive names in a pair's target, oldest first
+func listArchives(t *testing.T, pair *cfg.Pair) []string {
+	t.Helper()
+	entries, err := os.ReadDir(pair.Target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ===== ARCHIVE CONTENT =====
+
+func TestArchiveContainsFilteredFiles(t *testing.T) {
+	for _, format := range []string{cfg.ArchiveFormatZip, cfg.ArchiveFormatTarGz} {
+		t.Run(format, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.ArchiveMode = format
+			pair.IncludeExt = []string{".txt"}
+			pair.ExcludeGlobs = []string{"**/scratch/**"}
+			writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "alpha")
+			writeTestFile(t, filepath.Join(pair.Source, "docs", "b.txt"), "bravo")
+			writeTestFile(t, filepath.Join(pair.Source, "image.png"), "png")
+			writeTestFile(t, filepath.Join(pair.Source, "scratch", "c.txt"), "charlie")
+
+			files, err := syncTestPair(t, pair)
+			if err != nil {
+				t.Fatalf("archive sync: %v", err)
+			}
+			if files != 2 {
+				t.Errorf("archived %d files, want 2", files)
+			}
+
+			archives := listArchives(t, pair)
+			if len(archives) != 1 || !strings.HasSuffix(archives[0], "."+format) {
+				t.Fatalf("target holds %v, want a single .%s archive", archives, format)
+			}
+			got := readArchive(t, filepath.Join(pair.Target, archives[0]))
+			want := map[string]string{"a.txt": "alpha", "docs/b.txt": "bravo"}
+			if len(got) != len(want) {
+				t.Errorf("archive holds %v, want %v", got, want)
+			}
+			for name, content := range want {
+				if got[name] != content {
+					t.Errorf("entry %s = %q, want %q", name, got[name], content)
+				}
+			}
+		})
+	}
+}
+
+func TestArchiveDryRunWritesNothing(t *testing.T) {
+	pair := newTestPair(t)
+	pair.ArchiveMode = cfg.ArchiveFormatZip
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "alpha")
+
+	result, err := (&Copier{}).DryRun(context.Background(), pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FilesCopied != 1 {
+		t.Errorf("dry run counted %d files, want 1", result.FilesCopied)
+	}
+	if archives := listArchives(t, pair); len(archives) != 0 {
+		t.Errorf("dry run wrote %v", archives)
+	}
+}
+
+// ===== ARCHIVE RETENTION =====
+
+func TestPruneArchives(t *testing.T) {
+	tests := []struct {
+		name        string
+		keep        int
+		existing    int
+		wantRemoved int
+	}{
+		{"keep all", 0, 4, 0},
+		{"under the limit", 5, 3, 0},
+		{"prunes oldest", 2, 5, 3},
+		{"keep one", 1, 3, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.ArchiveMode = cfg.ArchiveFormatTarGz
+			pair.ArchiveKeep = tt.keep
+
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			var created []string
+			for i := 0; i < tt.existing; i++ {
+				name := archiveFileName(pair, start.Add(time.Duration(i)*time.Hour))
+				writeTestFile(t, filepath.Join(pair.Target, name), "old")
+				created = append(created, name)
+			}
+			// Files that aren't this pair's archives are never pruned
+			writeTestFile(t, filepath.Join(pair.Target, "other-20200101-000000.tar.gz"), "foreign")
+			writeTestFile(t, filepath.Join(pair.Target, pair.ID+"-20200101-000000.zip"), "other format")
+
+			if removed := pruneArchives(pair); removed != tt.wantRemoved {
+				t.Errorf("pruneArchives() = %d, want %d", removed, tt.wantRemoved)
+			}
+
+			want := append([]string{"other-20200101-000000.tar.gz", pair.ID + "-20200101-000000.zip"}, created[tt.wantRemoved:]...)
+			slices.Sort(want)
+			if got := listArchives(t, pair); !slices.Equal(got, want) {
+				t.Errorf("target holds %v, want %v", got, want)
+			}
+		})
+	}
+}
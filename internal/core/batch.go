@@ -0,0 +1,118 @@
+// Package core provides batch debouncing of watcher events for the FolderSynchronizer application.
+// In batch mode a burst of source events, such as a tool rewriting a whole directory, is coalesced
+// into a single comparison pass over the pair instead of one debounced copy per file.
+package core
+
+import (
+	"FolderSynchronizer/internal/logging"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+// batchSyncKey is the debouncer key shared by all source events of a batch mode pair
+const batchSyncKey = "batch:"
+
+// ===== BATCH STATE =====
+
+// batchState coalesces events into comparison passes, one at a time.
+type batchState struct {
+	mutex   sync.Mutex
+	events  int  // Events not yet covered by a finished pass
+	running bool // Whether a pass is in progress
+	pending bool // Events arrived during the running pass; run another one afterwards
+	stopped bool // Set once the worker is stopping; no new passes start
+}
+
+// reset prepares the state for a (re)started worker.
+func (b *batchState) reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.events = 0
+	b.running = false
+	b.pending = false
+	b.stopped = false
+}
+
+// stop prevents new passes from starting.
+func (b *batchState) stop() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.stopped = true
+}
+
+// ===== BATCH SYNC =====
+
+// queueBatchSync counts an event and (re)starts the pair-wide debounce window.
+func (w *PairWorker) queueBatchSync(debouncer *Debouncer) {
+	w.batch.mutex.Lock()
+	w.batch.events++
+	w.batch.mutex.Unlock()
+
+	debouncer.Trigger(batchSyncKey, w.runBatchSync)
+}
+
+// runBatchSync runs a comparison pass once the event burst has settled. Events
+// arriving during the pass lead to one more pass when it ends. A pass refused by
+// the pair's MinRunInterval is re-attempted after the interval.
+func (w *PairWorker) runBatchSync() {
+	b := &w.batch
+	b.mutex.Lock()
+	if b.stopped {
+		b.mutex.Unlock()
+		return
+	}
+	if b.running {
+		b.pending = true
+		b.mutex.Unlock()
+		return
+	}
+	b.running = true
+	w.wg.Add(1)
+	b.mutex.Unlock()
+	defer w.wg.Done()
+
+	pair := w.Pair
+	for {
+		b.mutex.Lock()
+		events := b.events
+		b.pending = false
+		b.mutex.Unlock()
+
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Int("events", events).Msg("batch sync starting")
+
+		copier := &Copier{syncs: w.syncs}
+		copiedFiles, copiedBytes, err := copier.CompareAndSync(w.ctx, pair)
+
+		b.mutex.Lock()
+		switch {
+		case errors.Is(err, ErrRateLimited):
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Err(err).Msg("batch sync postponed")
+			b.running = false
+			b.mutex.Unlock()
+			time.AfterFunc(max(pair.MinRunGap(), time.Second), w.runBatchSync)
+			return
+		case err != nil:
+			if w.ctx.Err() == nil {
+				logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("batch sync failed")
+			}
+		default:
+			logging.ForPair(pair.ID).Info().
+				Str("pair", pair.ID).
+				Int("events", events).
+				Int("files", copiedFiles).
+				Int64("bytes", copiedBytes).
+				Msg("batch sync completed")
+		}
+
+		b.events -= events
+		if !b.pending || b.stopped {
+			b.running = false
+			b.mutex.Unlock()
+			return
+		}
+		b.mutex.Unlock()
+	}
+}
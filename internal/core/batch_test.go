@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// syncRunsStarted returns the number of comparison passes begun for a pair
+func syncRunsStarted(pairID string) int {
+	pairRuns.mutex.Lock()
+	defer pairRuns.mutex.Unlock()
+	return pairRuns.started[pairID]
+}
+
+func TestBatchDebounceCoalescesBurst(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		wantPasses int
+	}{
+		{"file mode copies each file", cfg.DebounceModeFile, 0},
+		{"batch mode runs one pass", cfg.DebounceModeBatch, 1},
+	}
+
+	const burst = 50
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.DebounceMode = tt.mode
+			forgetPairRuns(t, pair.ID)
+			w, watcher := newSetupWorker(t, pair)
+			w.batch.reset()
+			t.Cleanup(w.batch.stop)
+			debouncer := NewDebouncer(50)
+			t.Cleanup(debouncer.Close)
+
+			// A tool rewrites a whole directory at once
+			for i := range burst {
+				path := filepath.Join(pair.Source, "build", fmt.Sprintf("file%02d.txt", i))
+				writeTestFile(t, path, "data")
+				w.handleFileSystemEvent(fsnotify.Event{Name: path, Op: fsnotify.Write}, watcher, debouncer)
+			}
+
+			waitFor(t, 5*time.Second, "burst copied", func() bool {
+				for i := range burst {
+					if readTestFile(t, filepath.Join(pair.Target, "build", fmt.Sprintf("file%02d.txt", i))) != "data" {
+						return false
+					}
+				}
+				return true
+			})
+			w.wg.Wait()
+			if got := syncRunsStarted(pair.ID); got != tt.wantPasses {
+				t.Errorf("comparison passes = %d, want %d", got, tt.wantPasses)
+			}
+		})
+	}
+}
+
+func TestBatchSyncRerunsForEventsDuringPass(t *testing.T) {
+	pair := newTestPair(t)
+	pair.DebounceMode = cfg.DebounceModeBatch
+	forgetPairRuns(t, pair.ID)
+	w, _ := newSetupWorker(t, pair)
+	w.batch.reset()
+	t.Cleanup(w.batch.stop)
+
+	// An event arriving while a pass runs is covered by one more pass
+	w.batch.mutex.Lock()
+	w.batch.events = 3
+	w.batch.running = true
+	w.batch.mutex.Unlock()
+	w.runBatchSync()
+	if !w.batch.pending {
+		t.Fatal("event during a running pass wasn't marked pending")
+	}
+	w.batch.mutex.Lock()
+	w.batch.running = false
+	w.batch.mutex.Unlock()
+
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "a")
+	w.runBatchSync()
+	if got := syncRunsStarted(pair.ID); got != 1 {
+		t.Errorf("comparison passes = %d, want 1", got)
+	}
+	if w.batch.events != 0 || w.batch.running {
+		t.Errorf("batch state after the pass: events %d, running %v", w.batch.events, w.batch.running)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "a.txt")) != "a" {
+		t.Error("batch pass didn't copy the file")
+	}
+}
+
+func TestStoppedBatchStartsNoPass(t *testing.T) {
+	pair := newTestPair(t)
+	pair.DebounceMode = cfg.DebounceModeBatch
+	forgetPairRuns(t, pair.ID)
+	w, _ := newSetupWorker(t, pair)
+	w.batch.reset()
+	w.batch.stop()
+
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "a")
+	w.runBatchSync()
+	if got := syncRunsStarted(pair.ID); got != 0 {
+		t.Errorf("stopped worker ran %d passes", got)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "a.txt")) != "" {
+		t.Error("stopped worker copied a file")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+// Package core provides cross-device rename detection for Unix platforms.
+package core
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDeviceError reports whether a rename failed because source and destination
+// are on different filesystems (EXDEV) or the destination is a mount point, such as
+// a bind-mounted file, which can't be replaced (EBUSY).
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, syscall.EXDEV) || errors.Is(err, syscall.EBUSY)
+}
@@ -0,0 +1,109 @@
+//go:build !windows
+
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// failRenames makes target renames fail with errno for the rest of the test
+func failRenames(t *testing.T, errno syscall.Errno) {
+	t.Helper()
+	previous := renameTarget
+	renameTarget = func(from, to string) error {
+		return &os.LinkError{Op: "rename", Old: from, New: to, Err: errno}
+	}
+	t.Cleanup(func() { renameTarget = previous })
+}
+
+func TestIsCrossDeviceError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"cross-device link", &os.LinkError{Op: "rename", Err: syscall.EXDEV}, true},
+		{"busy mount point", &os.LinkError{Op: "rename", Err: syscall.EBUSY}, true},
+		{"permission denied", &os.LinkError{Op: "rename", Err: syscall.EACCES}, false},
+		{"other error", errors.New("disk on fire"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isCrossDeviceError(tt.err); got != tt.want {
+				t.Errorf("isCrossDeviceError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplaceTargetCrossDeviceFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		errno       syscall.Errno
+		wantErr     bool
+		wantContent string // target content afterwards
+	}{
+		{"cross-device overwrites in place", syscall.EXDEV, false, "new content"},
+		{"mount point overwrites in place", syscall.EBUSY, false, "new content"},
+		{"other failures are returned", syscall.EACCES, true, "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failRenames(t, tt.errno)
+			dir := t.TempDir()
+			tempPath, targetPath := filepath.Join(dir, "a.txt.tmp"), filepath.Join(dir, "a.txt")
+			writeTestFile(t, targetPath, "old")
+			writeTestFile(t, tempPath, "new content")
+			stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+			if err := os.Chtimes(tempPath, stamp, stamp); err != nil {
+				t.Fatal(err)
+			}
+
+			err := replaceTarget(tempPath, targetPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("replaceTarget() = %v, want error %v", err, tt.wantErr)
+			}
+			if got := readTestFile(t, targetPath); got != tt.wantContent {
+				t.Errorf("target = %q, want %q", got, tt.wantContent)
+			}
+			if tt.wantErr {
+				return
+			}
+			if _, err := os.Stat(tempPath); !os.IsNotExist(err) {
+				t.Errorf("temporary file left behind: %v", err)
+			}
+			if info, err := os.Stat(targetPath); err != nil || !info.ModTime().Equal(stamp) {
+				t.Errorf("target mtime = %v (%v), want %v", info.ModTime(), err, stamp)
+			}
+		})
+	}
+}
+
+func TestSyncCopiesAcrossDevices(t *testing.T) {
+	failRenames(t, syscall.EXDEV)
+	pair := newTestPair(t)
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "data")
+	writeTestFile(t, filepath.Join(pair.Target, "b.txt"), "old")
+	writeTestFile(t, filepath.Join(pair.Source, "b.txt"), "updated")
+
+	if files, err := syncTestPair(t, pair); err != nil || files != 2 {
+		t.Fatalf("sync copied %d files, err %v, want 2", files, err)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "a.txt")) != "data" || readTestFile(t, filepath.Join(pair.Target, "b.txt")) != "updated" {
+		t.Error("cross-device copies have the wrong content")
+	}
+	entries, err := os.ReadDir(pair.Target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("target holds %d entries, want no temporary files left", len(entries))
+	}
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+// Package core provides cross-device rename detection for Windows.
+package core
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isCrossDeviceError reports whether a rename failed because source and destination
+// are on different volumes, e.g. when the target directory is a mounted folder.
+func isCrossDeviceError(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE)
+}
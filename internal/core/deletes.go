@@ -0,0 +1,242 @@
+// Package core provides deferred mirror deletes for the FolderSynchronizer application.
+// In deferred mode, target files orphaned by a sync are recorded as pending deletes and
+// persisted; they are deleted only when applied, manually or by a scheduled job.
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== PENDING DELETE STRUCTURES =====
+
+// ApplyDeletesTaskSuffix is appended to a pair ID to name its scheduled apply-deletes task
+const ApplyDeletesTaskSuffix = "#apply-deletes"
+
+// PendingDelete is a target file waiting to be deleted in deferred mirror delete mode.
+type PendingDelete struct {
+	Path      string    `json:"path"`      // Target path relative to the target root (slash-separated)
+	FirstSeen time.Time `json:"firstSeen"` // When the file was first found without a source
+}
+
+// pendingDeleteStore holds pending deletes per pair and persists them to a file.
+type pendingDeleteStore struct {
+	mutex sync.Mutex
+	path  string                          // Persistence file (empty keeps pending deletes in memory)
+	pairs map[string]map[string]time.Time // Pair ID -> relative path -> first seen
+}
+
+// pendingDeletes is the process-wide store shared by syncs, watchers and the API
+var pendingDeletes = &pendingDeleteStore{pairs: make(map[string]map[string]time.Time)}
+
+// SetPendingDeletesFile loads pending deletes saved at path and persists later
+// changes there. A missing file yields no pending deletes.
+func SetPendingDeletesFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read pending deletes file: %w", err)
+	}
+
+	saved := make(map[string][]PendingDelete)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return fmt.Errorf("failed to parse pending deletes file: %w", err)
+		}
+	}
+
+	pendingDeletes.mutex.Lock()
+	defer pendingDeletes.mutex.Unlock()
+
+	pendingDeletes.path = path
+	pendingDeletes.pairs = make(map[string]map[string]time.Time, len(saved))
+	for pairID, entries := range saved {
+		paths := make(map[string]time.Time, len(entries))
+		for _, entry := range entries {
+			paths[entry.Path] = entry.FirstSeen
+		}
+		pendingDeletes.pairs[pairID] = paths
+	}
+	return nil
+}
+
+// PendingDeletes returns the pending deletes of a pair sorted by path.
+func PendingDeletes(pairID string) []PendingDelete {
+	pendingDeletes.mutex.Lock()
+	defer pendingDeletes.mutex.Unlock()
+	return pendingDeletes.listLocked(pairID)
+}
+
+// listLocked returns a pair's pending deletes. Must be called with the mutex held.
+func (s *pendingDeleteStore) listLocked(pairID string) []PendingDelete {
+	entries := make([]PendingDelete, 0, len(s.pairs[pairID]))
+	for path, firstSeen := range s.pairs[pairID] {
+		entries = append(entries, PendingDelete{Path: path, FirstSeen: firstSeen})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// replace sets a pair's pending deletes to the orphans found by a full sync,
+// keeping the first-seen time of paths that were already pending.
+func (s *pendingDeleteStore) replace(pairID string, paths []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now()
+	previous := s.pairs[pairID]
+	current := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if firstSeen, ok := previous[path]; ok {
+			current[path] = firstSeen
+		} else {
+			current[path] = now
+		}
+	}
+
+	if len(current) == 0 {
+		delete(s.pairs, pairID)
+	} else {
+		s.pairs[pairID] = current
+	}
+	s.saveLocked()
+}
+
+// add records a single pending delete, e.g. from a watcher remove event.
+func (s *pendingDeleteStore) add(pairID, path string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.pairs[pairID] == nil {
+		s.pairs[pairID] = make(map[string]time.Time)
+	}
+	if _, exists := s.pairs[pairID][path]; !exists {
+		s.pairs[pairID][path] = time.Now()
+		s.saveLocked()
+	}
+}
+
+// remove drops resolved pending deletes of a pair.
+func (s *pendingDeleteStore) remove(pairID string, paths []string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, path := range paths {
+		delete(s.pairs[pairID], path)
+	}
+	if len(s.pairs[pairID]) == 0 {
+		delete(s.pairs, pairID)
+	}
+	s.saveLocked()
+}
+
+// saveLocked writes pending deletes atomically. Must be called with the mutex held.
+func (s *pendingDeleteStore) saveLocked() {
+	if s.path == "" {
+		return
+	}
+
+	saved := make(map[string][]PendingDelete, len(s.pairs))
+	for pairID := range s.pairs {
+		saved[pairID] = s.listLocked(pairID)
+	}
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal pending deletes")
+		return
+	}
+
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		log.Error().Err(err).Str("path", s.path).Msg("failed to save pending deletes")
+		return
+	}
+	if err := os.Rename(tempPath, s.path); err != nil {
+		_ = os.Remove(tempPath)
+		log.Error().Err(err).Str("path", s.path).Msg("failed to save pending deletes")
+	}
+}
+
+// ===== DEFERRED DELETE RECORDING =====
+
+// deferTargetDelete records a target file removed from source while watching.
+func deferTargetDelete(pair *cfg.Pair, targetPath string) {
+	pendingDeletes.add(pair.ID, NormalizePath(RelPath(pair.Target, targetPath)))
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", targetPath).Msg("delete deferred (mirror)")
+}
+
+// ===== APPLYING PENDING DELETES =====
+
+// ApplyPendingDeletes deletes a pair's pending target files whose source is
+// still missing, then removes target directories left without a source
+// counterpart. Files whose source reappeared are dropped from the list; files
+// that fail to delete stay pending.
+func ApplyPendingDeletes(ctx context.Context, pair *cfg.Pair) (*SyncResult, error) {
+	startTime := time.Now()
+	result := &SyncResult{}
+
+	var resolved []string
+	defer func() { pendingDeletes.remove(pair.ID, resolved) }()
+
+	for _, entry := range PendingDeletes(pair.ID) {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		relativePath := filepath.FromSlash(entry.Path)
+		if sourceExistsFor(pair, relativePath) {
+			resolved = append(resolved, entry.Path)
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", entry.Path).Msg("pending delete dropped, source exists again")
+			continue
+		}
+
+		if err := removeOwnedFile(filepath.Join(pair.Target, relativePath)); err != nil && !os.IsNotExist(err) {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("file", entry.Path).Err(err).Msg("failed to delete target file")
+			result.Errors = append(result.Errors, err)
+			continue
+		} else if err == nil {
+			result.FilesDeleted++
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", entry.Path).Msg("deleted (deferred mirror)")
+			recordEvent(EventFileDeleted, pair.ID, entry.Path, "deferred mirror")
+		}
+		resolved = append(resolved, entry.Path)
+	}
+
+	// Remove directories emptied by the deletes
+	var directories []string
+	err := filepath.WalkDir(pair.Target, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if dirEntry.IsDir() && path != pair.Target {
+			directories = append(directories, path)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return result, err
+	}
+	(&Copier{pair: pair}).mirrorDirectoryDeletions(pair, directories, result)
+
+	result.Duration = time.Since(startTime)
+	logging.ForPair(pair.ID).Info().
+		Str("pair", pair.ID).
+		Int("deleted", result.FilesDeleted).
+		Int("dirs_deleted", result.DirsDeleted).
+		Int("errors", len(result.Errors)).
+		Msg("pending deletes applied")
+
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// newDeferredPair returns a test pair recording mirror deletes as pending,
+// with its pending deletes dropped when the test ends
+func newDeferredPair(t *testing.T) *cfg.Pair {
+	t.Helper()
+	pair := newTestPair(t)
+	pair.MirrorDeletes = true
+	pair.MirrorDeleteMode = cfg.MirrorDeleteDeferred
+	t.Cleanup(func() { pendingDeletes.replace(pair.ID, nil) })
+	return pair
+}
+
+// pendingPaths returns the paths of a pair's pending deletes
+func pendingPaths(pairID string) []string {
+	var paths []string
+	for _, entry := range PendingDeletes(pairID) {
+		paths = append(paths, entry.Path)
+	}
+	return paths
+}
+
+func TestDeferredMirrorDeletesAccumulate(t *testing.T) {
+	pair := newDeferredPair(t)
+	for _, name := range []string{"keep.txt", "old/a.txt", "b.txt"} {
+		writeTestFile(t, filepath.Join(pair.Source, name), name)
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing source files records pending deletes instead of deleting
+	if err := os.Remove(filepath.Join(pair.Source, "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+	first := PendingDeletes(pair.ID)
+	if len(first) != 1 || first[0].Path != "b.txt" {
+		t.Fatalf("pending deletes = %+v, want b.txt", first)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "b.txt")) == "" {
+		t.Error("deferred mode deleted the target file inline")
+	}
+
+	// Later syncs add new orphans and keep the first-seen time of known ones
+	if err := os.RemoveAll(filepath.Join(pair.Source, "old")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+	second := PendingDeletes(pair.ID)
+	if len(second) != 2 || second[0].Path != "b.txt" || second[1].Path != "old/a.txt" {
+		t.Fatalf("pending deletes = %+v, want b.txt and old/a.txt", second)
+	}
+	if !second[0].FirstSeen.Equal(first[0].FirstSeen) {
+		t.Errorf("first-seen time of b.txt changed from %v to %v", first[0].FirstSeen, second[0].FirstSeen)
+	}
+}
+
+func TestApplyPendingDeletes(t *testing.T) {
+	pair := newDeferredPair(t)
+	for _, name := range []string{"keep.txt", "old/a.txt", "b.txt", "back.txt"} {
+		writeTestFile(t, filepath.Join(pair.Source, name), name)
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"old", "b.txt", "back.txt"} {
+		if err := os.RemoveAll(filepath.Join(pair.Source, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+
+	// A source file restored before the apply is spared
+	writeTestFile(t, filepath.Join(pair.Source, "back.txt"), "back.txt")
+
+	result, err := ApplyPendingDeletes(context.Background(), pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.FilesDeleted != 2 || result.DirsDeleted != 1 {
+		t.Errorf("deleted %d files and %d directories, want 2 and 1", result.FilesDeleted, result.DirsDeleted)
+	}
+	tests := []struct {
+		name   string
+		exists bool
+	}{
+		{"keep.txt", true},
+		{"back.txt", true},
+		{"b.txt", false},
+		{"old/a.txt", false},
+		{"old", false},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(pair.Target, tt.name))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("target %s exists = %v, want %v", tt.name, exists, tt.exists)
+		}
+	}
+	if paths := pendingPaths(pair.ID); len(paths) != 0 {
+		t.Errorf("pending deletes left after apply: %v", paths)
+	}
+}
+
+func TestPendingDeletesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pending-deletes.json")
+	if err := SetPendingDeletesFile(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetPendingDeletesFile("") })
+
+	pair := newDeferredPair(t)
+	pendingDeletes.add(pair.ID, "a.txt")
+	pendingDeletes.add(pair.ID, "dir/b.txt")
+	saved := PendingDeletes(pair.ID)
+
+	// Reloading the file restores the same entries
+	if err := SetPendingDeletesFile(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := PendingDeletes(pair.ID)
+	if len(loaded) != len(saved) {
+		t.Fatalf("loaded %+v, want %+v", loaded, saved)
+	}
+	for i := range saved {
+		if loaded[i].Path != saved[i].Path || !loaded[i].FirstSeen.Equal(saved[i].FirstSeen) {
+			t.Errorf("entry %d = %+v, want %+v", i, loaded[i], saved[i])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetPendingDeletesFile(path); err == nil {
+		t.Error("corrupt pending deletes file accepted")
+	}
+}
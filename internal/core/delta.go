@@ -0,0 +1,310 @@
+// Package core provides rsync-style delta transfer for the FolderSynchronizer application.
+// When a pair uses the delta strategy and the target already exists, the target is split into
+// blocks and the source is scanned with a rolling checksum; blocks found unchanged are copied
+// from the old target into the temporary file and only the remaining bytes come from the source.
+package core
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// Block size bounds; the size used is about the square root of the target size
+	DeltaMinBlockSize = 2 * 1024   // 2KB
+	DeltaMaxBlockSize = 128 * 1024 // 128KB
+
+	// DeltaMinFileSize is the target size below which a plain copy is cheaper than a delta
+	DeltaMinFileSize = 64 * 1024 // 64KB
+
+	// deltaLiteralFlush bounds the unmatched source bytes buffered before they are written
+	deltaLiteralFlush = 1024 * 1024 // 1MB
+)
+
+// ===== BLOCK SIGNATURE =====
+
+// deltaSignature indexes the blocks of an existing target by weak checksum.
+type deltaSignature struct {
+	blockSize int
+	weak      map[uint32][]int    // Block indices by weak checksum
+	strong    [][sha256.Size]byte // SHA256 per block
+}
+
+// deltaBlockSize picks the block size for a target of the given size.
+func deltaBlockSize(size int64) int {
+	blockSize := int(math.Sqrt(float64(size)))
+	blockSize = (blockSize + 1023) &^ 1023 // Round up to a whole KB
+	return min(max(blockSize, DeltaMinBlockSize), DeltaMaxBlockSize)
+}
+
+// buildDeltaSignature reads the target once and checksums each full block.
+// A trailing partial block isn't indexed; matching source bytes are sent as literals.
+func buildDeltaSignature(target *os.File, size int64) (*deltaSignature, error) {
+	blockSize := deltaBlockSize(size)
+	blocks := int(size / int64(blockSize))
+	sig := &deltaSignature{
+		blockSize: blockSize,
+		weak:      make(map[uint32][]int, blocks),
+		strong:    make([][sha256.Size]byte, 0, blocks),
+	}
+
+	reader := bufio.NewReaderSize(io.NewSectionReader(target, 0, size), CopyBufferSize)
+	block := make([]byte, blockSize)
+	for i := 0; i < blocks; i++ {
+		if _, err := io.ReadFull(reader, block); err != nil {
+			return nil, err
+		}
+		weak := newRollingChecksum(block).sum()
+		sig.weak[weak] = append(sig.weak[weak], i)
+		sig.strong = append(sig.strong, sha256.Sum256(block))
+	}
+	return sig, nil
+}
+
+// match returns the target block equal to window, or -1.
+func (s *deltaSignature) match(weak uint32, window []byte) int {
+	candidates := s.weak[weak]
+	if len(candidates) == 0 {
+		return -1
+	}
+	strong := sha256.Sum256(window)
+	for _, index := range candidates {
+		if s.strong[index] == strong {
+			return index
+		}
+	}
+	return -1
+}
+
+// ===== ROLLING CHECKSUM =====
+
+// rollingChecksum is the Adler-32 style weak checksum used by rsync, which can
+// slide over the data one byte at a time.
+type rollingChecksum struct {
+	a, b   uint32
+	length uint32
+}
+
+// newRollingChecksum computes the checksum of a full window.
+func newRollingChecksum(window []byte) rollingChecksum {
+	r := rollingChecksum{length: uint32(len(window))}
+	for i, c := range window {
+		r.a += uint32(c)
+		r.b += uint32(len(window)-i) * uint32(c)
+	}
+	return r
+}
+
+// roll moves the window one byte forward.
+func (r *rollingChecksum) roll(out, in byte) {
+	r.a += uint32(in) - uint32(out)
+	r.b += r.a - r.length*uint32(out)
+}
+
+// sum returns the 32-bit weak checksum.
+func (r rollingChecksum) sum() uint32 {
+	return r.a&0xffff | r.b<<16
+}
+
+// ===== DELTA COPY =====
+
+// copyDelta rewrites the target from the source, reusing blocks of the existing target
+// that are unchanged, even when they moved. The result is assembled in a temporary file
+// and renamed over the target like copyAtomic. Without a usable target it falls back to
+// a full copy. Returns the size of the written file.
+func copyDelta(sourcePath, targetPath string) (int64, error) {
+	targetInfo, err := os.Stat(targetPath)
+	if err != nil || !targetInfo.Mode().IsRegular() || targetInfo.Size() < DeltaMinFileSize {
+		return copyAtomic(sourcePath, targetPath)
+	}
+
+	tempPath := tempPathFor(targetPath)
+
+	// Mark paths as written by the app so target drift detection ignores them
+	ownWrites.begin(tempPath, targetPath)
+	defer ownWrites.end(tempPath, targetPath)
+
+	// Reserve handles for the source, old target and temporary files
+	release, err := acquireOpenFiles(context.Background(), 3)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	sourceFile, err := os.Open(sourcePath)
+	if err != nil {
+		return 0, err
+	}
+	defer sourceFile.Close()
+
+	targetFile, err := os.Open(targetPath)
+	if err != nil {
+		return 0, err
+	}
+
+	started := time.Now()
+	sig, err := buildDeltaSignature(targetFile, targetInfo.Size())
+	if err != nil {
+		targetFile.Close()
+		return 0, err
+	}
+
+	tempFile, err := createTempFile(tempPath)
+	if err != nil {
+		targetFile.Close()
+		return 0, err
+	}
+
+	stats, copyErr := writeDelta(tempFile, sourceFile, targetFile, sig)
+
+	// The old target must be closed before it's replaced (required on Windows)
+	targetFile.Close()
+	if closeErr := tempFile.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+
+	if copyErr != nil {
+		os.Remove(tempPath) // Clean up on failure
+		return stats.written(), copyErr
+	}
+
+	// Preserve file modification time as best effort
+	if sourceInfo, err := os.Stat(sourcePath); err == nil {
+		os.Chtimes(tempPath, time.Now(), sourceInfo.ModTime())
+	}
+
+	// Atomic rename to final destination
+	if err := replaceTarget(tempPath, targetPath); err != nil {
+		os.Remove(tempPath) // Clean up on failure
+		return stats.written(), err
+	}
+
+	log.Debug().
+		Str("file", targetPath).
+		Int("block_size", sig.blockSize).
+		Int64("matched_bytes", stats.matched).
+		Int64("literal_bytes", stats.literal).
+		Dur("duration", time.Since(started)).
+		Msg("delta copy")
+
+	return stats.written(), nil
+}
+
+// deltaStats counts how the new file was assembled.
+type deltaStats struct {
+	matched int64 // Bytes reused from the old target
+	literal int64 // Bytes read from the source
+}
+
+// written returns the size of the assembled file.
+func (s deltaStats) written() int64 {
+	return s.matched + s.literal
+}
+
+// writeDelta scans the source with a rolling window of one block and writes either
+// matching target blocks or literal source bytes to out.
+func writeDelta(out io.Writer, source io.Reader, target io.ReaderAt, sig *deltaSignature) (deltaStats, error) {
+	var stats deltaStats
+	blockSize := sig.blockSize
+	writer := bufio.NewWriterSize(out, CopyBufferSize)
+	block := make([]byte, blockSize)
+
+	// buf[literal:pos] are unmatched bytes not yet written; buf[pos:pos+blockSize] is the window
+	buf := make([]byte, 0, deltaLiteralFlush+2*blockSize)
+	literal, pos := 0, 0
+	eof := false
+	var rolling rollingChecksum
+	rollingValid := false
+
+	flushLiteral := func() error {
+		if pos > literal {
+			if _, err := writer.Write(buf[literal:pos]); err != nil {
+				return err
+			}
+			stats.literal += int64(pos - literal)
+			literal = pos
+		}
+		return nil
+	}
+
+	for {
+		// Keep the window and the byte after it in the buffer
+		if len(buf)-pos < blockSize+1 && !eof {
+			if err := flushLiteral(); err != nil {
+				return stats, err
+			}
+			remaining := copy(buf[:cap(buf)], buf[pos:])
+			buf = buf[:remaining]
+			literal, pos = 0, 0
+
+			n, err := io.ReadFull(source, buf[remaining:cap(buf)])
+			buf = buf[:remaining+n]
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				eof = true
+			} else if err != nil {
+				return stats, err
+			}
+		}
+
+		if len(buf)-pos < blockSize {
+			// Less than a block left: the rest can only be literal
+			pos = len(buf)
+			if err := flushLiteral(); err != nil {
+				return stats, err
+			}
+			break
+		}
+
+		window := buf[pos : pos+blockSize]
+		if !rollingValid {
+			rolling = newRollingChecksum(window)
+			rollingValid = true
+		}
+
+		if index := sig.match(rolling.sum(), window); index >= 0 {
+			if err := flushLiteral(); err != nil {
+				return stats, err
+			}
+			if _, err := target.ReadAt(block, int64(index)*int64(blockSize)); err != nil {
+				return stats, err
+			}
+			if _, err := writer.Write(block); err != nil {
+				return stats, err
+			}
+			stats.matched += int64(blockSize)
+			pos += blockSize
+			literal = pos
+			rollingValid = false
+			continue
+		}
+
+		if pos+blockSize >= len(buf) {
+			// Final window without a match
+			pos = len(buf)
+			if err := flushLiteral(); err != nil {
+				return stats, err
+			}
+			break
+		}
+		rolling.roll(buf[pos], buf[pos+blockSize])
+		pos++
+
+		if pos-literal >= deltaLiteralFlush {
+			if err := flushLiteral(); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	return stats, writer.Flush()
+}
@@ -0,0 +1,34 @@
+// Package core provides directory depth limits for the FolderSynchronizer application.
+// A pair's MaxDepth keeps syncs and watch setup from descending arbitrarily deep, so a
+// pair pointed at a huge tree (such as a home directory) stays bounded.
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== DEPTH LIMIT =====
+
+// pathDepth returns the number of elements of a relative path ("." is 0).
+func pathDepth(relativePath string) int {
+	cleaned := filepath.ToSlash(filepath.Clean(relativePath))
+	if cleaned == "." || cleaned == "" {
+		return 0
+	}
+	return strings.Count(cleaned, "/") + 1
+}
+
+// dirBeyondMaxDepth reports whether the directory at relativePath (relative to
+// the source root) is deeper than the pair's MaxDepth allows.
+func dirBeyondMaxDepth(pair *cfg.Pair, relativePath string) bool {
+	return pair.MaxDepth > 0 && pathDepth(relativePath) > pair.MaxDepth
+}
+
+// pathBeyondMaxDepth reports whether a file or directory at relativePath lies
+// inside a directory deeper than the pair's MaxDepth allows.
+func pathBeyondMaxDepth(pair *cfg.Pair, relativePath string) bool {
+	return pair.MaxDepth > 0 && pathDepth(relativePath)-1 > pair.MaxDepth
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// deepTreeFiles are the files of a five-level source tree, one per level
+var deepTreeFiles = []string{
+	"root.txt",
+	"l1/one.txt",
+	"l1/l2/two.txt",
+	"l1/l2/l3/three.txt",
+	"l1/l2/l3/l4/four.txt",
+}
+
+func TestMaxDepthLimitsSync(t *testing.T) {
+	tests := []struct {
+		name            string
+		maxDepth        int
+		wantCopied      int
+		wantDirsSkipped int
+	}{
+		{"unlimited", 0, 5, 0},
+		{"one level", 1, 2, 1},
+		{"three levels", 3, 4, 1},
+		{"deeper than the tree", 10, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.MaxDepth = tt.maxDepth
+			for _, path := range deepTreeFiles {
+				writeTestFile(t, filepath.Join(pair.Source, path), path)
+			}
+
+			plan, err := (&Copier{}).DryRun(context.Background(), pair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.DirsBeyondDepth != tt.wantDirsSkipped {
+				t.Errorf("DirsBeyondDepth = %d, want %d", plan.DirsBeyondDepth, tt.wantDirsSkipped)
+			}
+
+			files, err := syncTestPair(t, pair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if files != tt.wantCopied {
+				t.Errorf("sync copied %d files, want %d", files, tt.wantCopied)
+			}
+			for i, path := range deepTreeFiles {
+				want := tt.maxDepth == 0 || i <= tt.maxDepth
+				if copied := readTestFile(t, filepath.Join(pair.Target, path)) != ""; copied != want {
+					t.Errorf("%s copied = %v, want %v", path, copied, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPathBeyondMaxDepth(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxDepth int
+		path     string
+		wantDir  bool // dirBeyondMaxDepth
+		wantPath bool // pathBeyondMaxDepth
+	}{
+		{"unlimited", 0, "a/b/c/d/e.txt", false, false},
+		{"file in the root", 1, "e.txt", false, false},
+		{"file at the limit", 1, "a/e.txt", true, false},
+		{"file below the limit", 1, "a/b/e.txt", true, true},
+		{"directory at the limit", 2, "a/b", false, false},
+		{"directory below the limit", 2, "a/b/c", true, false},
+		{"inside a directory below the limit", 2, "a/b/c/d", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := &cfg.Pair{MaxDepth: tt.maxDepth}
+			path := filepath.FromSlash(tt.path)
+			if got := dirBeyondMaxDepth(pair, path); got != tt.wantDir {
+				t.Errorf("dirBeyondMaxDepth(%q) = %v, want %v", tt.path, got, tt.wantDir)
+			}
+			if got := pathBeyondMaxDepth(pair, path); got != tt.wantPath {
+				t.Errorf("pathBeyondMaxDepth(%q) = %v, want %v", tt.path, got, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+// Package core provides source/target divergence reports for the FolderSynchronizer application.
+// A diff walks a pair's sources and targets like a sync would, without copying or deleting
+// anything, and lists the files a sync (with mirror deletes) would act on.
+package core
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== DIFF CONSTANTS =====
+
+// Reasons a source file differs from its target copy
+const (
+	ChangeReasonSize  = "size"  // Sizes differ
+	ChangeReasonMTime = "mtime" // Same size, modification times differ beyond the tolerance
+	ChangeReasonHash  = "hash"  // Contents differ (hash and delta strategies)
+)
+
+// ErrDiffArchive is returned when a diff is requested for an archive-mode pair,
+// whose target holds archives rather than a mirror of the source.
+var ErrDiffArchive = errors.New("diff is not available for archive pairs")
+
+// ===== DIFF STRUCTURES =====
+
+// PairDiff lists how one target diverges from the pair's sources. Paths are
+// relative and use forward slashes.
+type PairDiff struct {
+	Target       string        `json:"target"`       // Target directory
+	OnlyInSource []string      `json:"onlyInSource"` // New source files a sync would copy
+	OnlyInTarget []string      `json:"onlyInTarget"` // Target files without a source, removed by mirror deletes
+	Changed      []ChangedFile `json:"changed"`      // Files a sync would copy over the target
+	Identical    int           `json:"identical"`    // Files already in sync
+	Filtered     int           `json:"filtered"`     // Source files left out by the pair's filters
+}
+
+// ChangedFile is a source file that differs from its target copy
+type ChangedFile struct {
+	Path   string `json:"path"`   // Relative path
+	Reason string `json:"reason"` // ChangeReasonSize, ChangeReasonMTime or ChangeReasonHash
+}
+
+// ===== DIFF =====
+
+// DiffPair compares the pair's sources with each of its targets using the pair's
+// sync strategy and filters, without modifying anything.
+func DiffPair(ctx context.Context, pair *cfg.Pair) ([]*PairDiff, error) {
+	if pair.ArchiveMode != "" {
+		return nil, ErrDiffArchive
+	}
+
+	diffs := make([]*PairDiff, 0, len(pair.TargetRoots()))
+	for _, target := range pair.TargetRoots() {
+		diff, err := diffTarget(ctx, pair.ForTarget(target))
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// diffTarget compares the sources of a single-target pair with its target.
+func diffTarget(ctx context.Context, pair *cfg.Pair) (*PairDiff, error) {
+	diff := &PairDiff{
+		Target:       pair.Target,
+		OnlyInSource: []string{},
+		OnlyInTarget: []string{},
+		Changed:      []ChangedFile{},
+	}
+	copier := &Copier{dryRun: true}
+	provided := make(map[string]bool) // Relative paths provided by an earlier source
+
+	for _, root := range pair.SourceRoots() {
+		err := walkSource(pair, root, func(path, relativePath string, dirEntry fs.DirEntry) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// Links recreated as links (SymlinkMode "copy-link") aren't compared
+			if dirEntry.IsDir() || dirEntry.Type()&fs.ModeSymlink != 0 || provided[relativePath] {
+				return nil
+			}
+
+			sourceInfo, err := dirEntry.Info()
+			if err != nil {
+				return err
+			}
+			if !copier.shouldSyncFile(pair, path, relativePath, sourceInfo) {
+				diff.Filtered++
+				return nil
+			}
+			provided[relativePath] = true
+
+			targetPath := targetPathFor(pair, relativePath)
+			targetInfo, err := os.Stat(targetPath)
+			if os.IsNotExist(err) {
+				diff.OnlyInSource = append(diff.OnlyInSource, NormalizePath(relativePath))
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			reason, err := copier.changeReason(path, targetPath, sourceInfo, targetInfo, pair.SyncStrategy)
+			if err != nil {
+				return err
+			}
+			if reason == "" {
+				diff.Identical++
+			} else {
+				diff.Changed = append(diff.Changed, ChangedFile{Path: NormalizePath(relativePath), Reason: reason})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Target files whose source is gone, as mirror deletes would find them
+	if IsDirectoryExists(pair.Target) {
+		err := filepath.WalkDir(pair.Target, func(path string, dirEntry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if dirEntry.IsDir() {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(pair.Target, path)
+			if err != nil {
+				return err
+			}
+			if !sourceExistsFor(pair, relativePath) {
+				diff.OnlyInTarget = append(diff.OnlyInTarget, NormalizePath(relativePath))
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(diff.OnlyInSource)
+	sort.Strings(diff.OnlyInTarget)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Path < diff.Changed[j].Path })
+	return diff, nil
+}
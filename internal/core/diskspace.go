@@ -0,0 +1,96 @@
+// Package core provides target disk capacity prechecks for the FolderSynchronizer application.
+// Before a full sync the planned copy volume is compared with the free bytes and,
+// on Unix, the free inodes of the target filesystem.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== PRECHECK ERRORS =====
+
+var (
+	// ErrInsufficientSpace is returned when the planned copies don't fit in the target's free space.
+	ErrInsufficientSpace = errors.New("insufficient disk space on target")
+
+	// ErrInsufficientInodes is returned when the planned copies need more inodes than the target has free.
+	ErrInsufficientInodes = errors.New("insufficient inodes on target")
+)
+
+// ===== DISK USAGE =====
+
+// diskUsage describes the free capacity of a filesystem.
+type diskUsage struct {
+	freeBytes   uint64 // Bytes available to unprivileged users
+	freeInodes  uint64 // Free inodes (file slots)
+	inodesKnown bool   // Whether the filesystem reports a meaningful inode limit
+}
+
+// targetDiskUsage queries free capacity for a path; replaceable for testing.
+var targetDiskUsage = diskUsageOf
+
+// ===== PRECHECK =====
+
+// precheckTargetCapacity plans the sync without copying and verifies that the
+// target filesystem can hold the planned files, both in bytes and in inodes.
+func precheckTargetCapacity(ctx context.Context, pair *cfg.Pair) error {
+	// Each target of a fan-out pair needs room for the whole plan
+	if len(pair.Targets) > 1 {
+		for _, target := range pair.Targets {
+			if err := precheckTargetCapacity(ctx, pair.ForTarget(target)); err != nil {
+				return fmt.Errorf("target %s: %w", target, err)
+			}
+		}
+		return nil
+	}
+
+	planner := &Copier{pair: pair, dryRun: true}
+	plan, err := planner.performSync(ctx, pair)
+	if err != nil {
+		return err
+	}
+	if plan.FilesCopied == 0 {
+		return nil
+	}
+
+	usage, err := targetDiskUsage(existingAncestor(pair.Target))
+	if err != nil {
+		// Capacity unknown: don't block the sync on a failed query
+		logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Err(err).Msg("disk capacity precheck skipped")
+		return nil
+	}
+
+	// Replaced files reuse their inode; only new files consume one, but the
+	// plan doesn't distinguish them, so all copies are counted conservatively.
+	if plan.BytesCopied > 0 && uint64(plan.BytesCopied) > usage.freeBytes {
+		return fmt.Errorf("%w: need %d bytes, %d available", ErrInsufficientSpace, plan.BytesCopied, usage.freeBytes)
+	}
+
+	if usage.inodesKnown && uint64(plan.FilesCopied) > usage.freeInodes {
+		return fmt.Errorf("%w: need %d files, %d inodes free", ErrInsufficientInodes, plan.FilesCopied, usage.freeInodes)
+	}
+
+	return nil
+}
+
+// existingAncestor returns the path itself or its nearest existing parent,
+// so capacity can be queried before the target directory is created.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
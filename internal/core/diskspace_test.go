@@ -0,0 +1,80 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// fakeDiskUsage replaces the target capacity query for the rest of the test
+func fakeDiskUsage(t *testing.T, usage diskUsage, err error) {
+	t.Helper()
+	previous := targetDiskUsage
+	targetDiskUsage = func(string) (diskUsage, error) { return usage, err }
+	t.Cleanup(func() { targetDiskUsage = previous })
+}
+
+func TestPrecheckTargetCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    int // files of 10 bytes each in the source
+		usage    diskUsage
+		queryErr error
+		wantErr  error
+	}{
+		{"fits", 3, diskUsage{freeBytes: 1000, freeInodes: 100, inodesKnown: true}, nil, nil},
+		{"too few bytes", 3, diskUsage{freeBytes: 20, freeInodes: 100, inodesKnown: true}, nil, ErrInsufficientSpace},
+		{"too few inodes", 3, diskUsage{freeBytes: 1000, freeInodes: 2, inodesKnown: true}, nil, ErrInsufficientInodes},
+		{"exactly enough inodes", 3, diskUsage{freeBytes: 1000, freeInodes: 3, inodesKnown: true}, nil, nil},
+		{"inodes unknown", 3, diskUsage{freeBytes: 1000}, nil, nil},
+		{"query failure doesn't block", 3, diskUsage{}, errors.New("statfs failed"), nil},
+		{"nothing to copy", 0, diskUsage{}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDiskUsage(t, tt.usage, tt.queryErr)
+			pair := newTestPair(t)
+			for i := 0; i < tt.files; i++ {
+				writeTestFile(t, filepath.Join(pair.Source, string(rune('a'+i))+".txt"), "0123456789")
+			}
+
+			err := precheckTargetCapacity(context.Background(), pair)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("precheckTargetCapacity() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSyncAbortsOnInsufficientInodes(t *testing.T) {
+	fakeDiskUsage(t, diskUsage{freeBytes: 1 << 30, freeInodes: 1, inodesKnown: true}, nil)
+	pair := newTestPair(t)
+	pair.PrecheckDiskSpace = true
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "a")
+	writeTestFile(t, filepath.Join(pair.Source, "b.txt"), "b")
+
+	if _, err := syncTestPair(t, pair); !errors.Is(err, ErrInsufficientInodes) {
+		t.Fatalf("sync error = %v, want ErrInsufficientInodes", err)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "a.txt")) != "" {
+		t.Error("sync copied files despite the failed precheck")
+	}
+
+	// Without the precheck toggle the inode guard doesn't run
+	pair.PrecheckDiskSpace = false
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Errorf("sync without precheck: %v", err)
+	}
+}
+
+func TestDiskUsageOfReportsCapacity(t *testing.T) {
+	usage, err := diskUsageOf(t.TempDir())
+	if err != nil {
+		t.Fatalf("diskUsageOf: %v", err)
+	}
+	if usage.freeBytes == 0 {
+		t.Error("temporary directory reports no free bytes")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+// Package core provides target disk capacity queries for Unix platforms.
+package core
+
+import "syscall"
+
+// diskUsageOf reports free bytes and free inodes of the filesystem holding path.
+func diskUsageOf(path string) (diskUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return diskUsage{}, err
+	}
+
+	return diskUsage{
+		freeBytes:  uint64(stat.Bavail) * uint64(stat.Bsize),
+		freeInodes: uint64(stat.Ffree),
+		// Filesystems without a fixed inode table (e.g. btrfs) report zero total inodes
+		inodesKnown: stat.Files > 0,
+	}, nil
+}
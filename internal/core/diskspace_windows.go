@@ -0,0 +1,22 @@
+//go:build windows
+
+// Package core provides target disk capacity queries for Windows.
+package core
+
+import "golang.org/x/sys/windows"
+
+// diskUsageOf reports free bytes of the volume holding path. Windows has no
+// inode concept, so the inode check is skipped.
+func diskUsageOf(path string) (diskUsage, error) {
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return diskUsage{}, err
+	}
+
+	var freeBytesAvailable, totalBytes, totalFreeBytes uint64
+	if err := windows.GetDiskFreeSpaceEx(pathPtr, &freeBytesAvailable, &totalBytes, &totalFreeBytes); err != nil {
+		return diskUsage{}, err
+	}
+
+	return diskUsage{freeBytes: freeBytesAvailable}, nil
+}
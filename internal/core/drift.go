@@ -0,0 +1,232 @@
+// Package core provides target drift detection for the FolderSynchronizer application.
+// It watches a pair's target directory and repairs files modified or deleted out of band.
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// OwnWriteSuppressionWindow is how long after the app writes a target path
+	// events for that path are attributed to the app and ignored by drift detection
+	OwnWriteSuppressionWindow = 2 * time.Second
+
+	// targetEventKeyPrefix separates target debounce keys from source keys
+	targetEventKeyPrefix = "target:"
+)
+
+// ===== OWN WRITE TRACKING =====
+
+// ownWriteTracker remembers target paths the application itself is writing or
+// has recently written, so the target watcher doesn't react to its own changes.
+type ownWriteTracker struct {
+	mutex  sync.Mutex
+	active map[string]int       // Paths with writes in progress
+	recent map[string]time.Time // Completion time of the latest write per path
+}
+
+// ownWrites is the process-wide tracker shared by all copy and delete operations
+var ownWrites = &ownWriteTracker{
+	active: make(map[string]int),
+	recent: make(map[string]time.Time),
+}
+
+// begin marks the start of a write to the given paths.
+func (t *ownWriteTracker) begin(paths ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, path := range paths {
+		t.active[path]++
+	}
+}
+
+// end marks the completion of a write to the given paths.
+func (t *ownWriteTracker) end(paths ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	for _, path := range paths {
+		if t.active[path] <= 1 {
+			delete(t.active, path)
+		} else {
+			t.active[path]--
+		}
+		t.recent[path] = now
+	}
+
+	// Drop expired entries to keep the map bounded
+	for path, at := range t.recent {
+		if now.Sub(at) > OwnWriteSuppressionWindow {
+			delete(t.recent, path)
+		}
+	}
+}
+
+// isOwn reports whether a path is being written by the app or was written
+// within the suppression window.
+func (t *ownWriteTracker) isOwn(path string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.active[path] > 0 {
+		return true
+	}
+	at, exists := t.recent[path]
+	return exists && time.Since(at) <= OwnWriteSuppressionWindow
+}
+
+// removeOwnedFile deletes a target file while marking it as an own write.
+func removeOwnedFile(path string) error {
+	ownWrites.begin(path)
+	defer ownWrites.end(path)
+	hashCache.forget(path)
+	return os.Remove(path)
+}
+
+// ===== TARGET DRIFT STATISTICS =====
+
+// driftStats tracks target-watch activity of a worker for status reporting.
+type driftStats struct {
+	mutex     sync.Mutex
+	active    bool       // Whether the target watcher is running
+	repairs   int        // Number of drift repairs performed
+	lastDrift *time.Time // Time of the latest drift repair
+}
+
+// setActive records whether the target watcher is running.
+func (s *driftStats) setActive(active bool) {
+	s.mutex.Lock()
+	s.active = active
+	s.mutex.Unlock()
+}
+
+// recordRepair records a drift repair.
+func (s *driftStats) recordRepair() {
+	s.mutex.Lock()
+	now := time.Now()
+	s.repairs++
+	s.lastDrift = &now
+	s.mutex.Unlock()
+}
+
+// snapshot returns a consistent copy of the statistics.
+func (s *driftStats) snapshot() (active bool, repairs int, lastDrift *time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.active, s.repairs, s.lastDrift
+}
+
+// ===== TARGET WATCHER =====
+
+// newTargetWatcher creates an fsnotify watcher over the pair's target tree.
+func (w *PairWorker) newTargetWatcher() (*fsnotify.Watcher, error) {
+	pair := w.Pair
+
+	if err := os.MkdirAll(pair.Target, DefaultDirPerms); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.addDirectoriesToWatcher(watcher, pair.Target, nil); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("target", pair.Target).Msg("target watcher started")
+	return watcher, nil
+}
+
+// handleTargetEvent processes events from the target watcher, ignoring the
+// application's own writes and debouncing drift repairs per path.
+func (w *PairWorker) handleTargetEvent(event fsnotify.Event, watcher *fsnotify.Watcher, debouncer *Debouncer) {
+	pair := w.Pair
+
+	if event.Name == "" || ownWrites.isOwn(event.Name) {
+		return
+	}
+
+	// Track newly created target directories
+	if event.Op&fsnotify.Create == fsnotify.Create {
+		if w.handleDirectoryCreation(event.Name, watcher) {
+			return
+		}
+	}
+
+	relativePath := RelPath(pair.Target, event.Name)
+
+	debouncer.Trigger(targetEventKeyPrefix+event.Name, func() {
+		w.repairTargetDrift(relativePath)
+	})
+}
+
+// repairTargetDrift restores a target file from source after an out-of-band
+// modification, or deletes it when it has no source counterpart and the pair
+// mirrors deletions.
+func (w *PairWorker) repairTargetDrift(relativePath string) {
+	pair := w.Pair
+
+	nativeRelPath := filepath.FromSlash(relativePath)
+	sourcePath := sourcePathFor(pair, nativeRelPath)
+	targetPath := filepath.Join(pair.Target, nativeRelPath)
+
+	// Re-check after debounce: the app may have written the path meanwhile
+	if ownWrites.isOwn(targetPath) {
+		return
+	}
+
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		if os.IsNotExist(err) && pair.MirrorDeletes && IsFileExists(targetPath) && !sourceExistsFor(pair, nativeRelPath) {
+			if err := removeOwnedFile(targetPath); err != nil {
+				logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("file", relativePath).Err(err).Msg("drift repair delete failed")
+				return
+			}
+			w.drift.recordRepair()
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("file", relativePath).Msg("target drift repaired (deleted)")
+		}
+		return
+	}
+
+	if sourceInfo.IsDir() || !isManagedFile(pair, sourcePath, relativePath, sourceInfo) {
+		return
+	}
+
+	copier := &Copier{pair: pair}
+	changed, err := copier.isFileChanged(sourcePath, pair, nativeRelPath)
+	if err != nil || !changed {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), DefaultDirPerms); err != nil {
+		return
+	}
+
+	if _, err := copyPairFile(pair, sourcePath, targetPath); err != nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("file", relativePath).Err(err).Msg("drift repair copy failed")
+		return
+	}
+
+	w.drift.recordRepair()
+	logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("file", relativePath).Msg("target drift repaired (restored from source)")
+}
+
+// isManagedFile reports whether a source file is covered by the pair's filters.
+func isManagedFile(pair *cfg.Pair, sourcePath, relativePath string, info os.FileInfo) bool {
+	copier := &Copier{pair: pair}
+	return copier.shouldSyncFile(pair, sourcePath, relativePath, info)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"FolderSynchronizer/internal/scheduler"
+)
+
+func TestOwnWriteTracker(t *testing.T) {
+	tracker := &ownWriteTracker{active: make(map[string]int), recent: make(map[string]time.Time)}
+
+	tracker.begin("a", "b")
+	tracker.begin("a")
+	tracker.end("a")
+	if !tracker.isOwn("a") || !tracker.isOwn("b") {
+		t.Error("paths being written should be own writes")
+	}
+	tracker.end("a", "b")
+	if !tracker.isOwn("a") {
+		t.Error("a path written within the suppression window should be an own write")
+	}
+	if tracker.isOwn("c") {
+		t.Error("an untouched path should not be an own write")
+	}
+
+	tracker.recent["a"] = time.Now().Add(-2 * OwnWriteSuppressionWindow)
+	if tracker.isOwn("a") {
+		t.Error("a write older than the suppression window should not be an own write")
+	}
+}
+
+func TestRepairTargetDrift(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        string // source content, "" for no source file
+		target        string // target content after the out-of-band change, "" for deleted
+		mirrorDeletes bool
+		want          string // target content after the repair, "" for absent
+		wantRepairs   int
+	}{
+		{"modified target restored", "original", "tampered!", false, "original", 1},
+		{"deleted target restored", "original", "", false, "original", 1},
+		{"unchanged target left alone", "original", "original", false, "original", 0},
+		{"stray target deleted when mirroring", "", "stray", true, "", 1},
+		{"stray target kept without mirroring", "", "stray", false, "stray", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.MirrorDeletes = tt.mirrorDeletes
+			sourcePath := filepath.Join(pair.Source, "app.cfg")
+			targetPath := filepath.Join(pair.Target, "app.cfg")
+			if tt.source != "" {
+				writeTestFile(t, sourcePath, tt.source)
+				if tt.target == tt.source {
+					if _, err := syncTestPair(t, pair); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if tt.target != "" && tt.target != tt.source {
+				writeTestFile(t, targetPath, tt.target)
+			}
+
+			worker := &PairWorker{Pair: pair}
+			ownWrites.mutex.Lock()
+			delete(ownWrites.recent, targetPath)
+			ownWrites.mutex.Unlock()
+			worker.repairTargetDrift("app.cfg")
+
+			if got := readTestFile(t, targetPath); got != tt.want {
+				t.Errorf("target = %q, want %q", got, tt.want)
+			}
+			if _, repairs, _ := worker.drift.snapshot(); repairs != tt.wantRepairs {
+				t.Errorf("repairs = %d, want %d", repairs, tt.wantRepairs)
+			}
+		})
+	}
+}
+
+func TestWatchTargetRestoresModifiedFile(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Schedule = scheduler.NewWatcherSchedule()
+	pair.WatchTarget = true
+	pair.DebounceMs = 20
+	targetPath := filepath.Join(pair.Target, "app.cfg")
+	writeTestFile(t, filepath.Join(pair.Source, "app.cfg"), "original")
+
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the initial sync", func() bool { return readTestFile(t, targetPath) == "original" })
+	waitFor(t, 5*time.Second, "the target watcher", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.TargetWatchActive
+	})
+
+	// Changes right after the app's own copy are attributed to the app
+	time.Sleep(OwnWriteSuppressionWindow + 100*time.Millisecond)
+	if err := os.WriteFile(targetPath, []byte("tampered!"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	waitFor(t, 5*time.Second, "the drift repair", func() bool { return readTestFile(t, targetPath) == "original" })
+	waitFor(t, 5*time.Second, "the repair to be reported", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.DriftRepairs > 0 && status.LastDriftRepair != nil
+	})
+}
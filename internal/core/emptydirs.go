@@ -0,0 +1,72 @@
+// Package core provides directory tree mirroring for the FolderSynchronizer application.
+// Files bring their parent directories along when copied; with a pair's SyncEmptyDirs
+// every source directory is created in the target, so empty folders survive a sync.
+// Mirror deletes remove target directories whose source directory is gone.
+package core
+
+import (
+	"os"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== DIRECTORY MIRRORING =====
+
+// syncDirectory creates the target counterpart of a source directory during a
+// full sync. Excluded and ignored directories are left out; a dry run only counts.
+func (c *Copier) syncDirectory(pair *cfg.Pair, sourcePath, relativePath string, result *SyncResult) {
+	if !directoryIncluded(pair, sourcePath, relativePath) {
+		return
+	}
+
+	targetPath := targetPathFor(pair, relativePath)
+	if IsDirectoryExists(targetPath) {
+		return
+	}
+	if c.dryRun {
+		result.DirsCreated++
+		return
+	}
+
+	if err := createTargetDirectory(targetPath); err != nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("dir", relativePath).Err(err).Msg("failed to create target directory")
+		result.Errors = append(result.Errors, err)
+		return
+	}
+	result.DirsCreated++
+	logging.ForPair(pair.ID).Debug().Str("pair", pair.ID).Str("dir", relativePath).Msg("created directory")
+}
+
+// syncDirectoryToTargets creates the target counterparts of a source directory
+// created while watching.
+func (w *PairWorker) syncDirectoryToTargets(sourcePath, relativePath string) {
+	pair := w.Pair
+	if !pair.SyncEmptyDirs || !directoryIncluded(pair, sourcePath, relativePath) {
+		return
+	}
+
+	for _, target := range pair.TargetRoots() {
+		targetPath := targetPathFor(pair.ForTarget(target), relativePath)
+		if err := createTargetDirectory(targetPath); err != nil {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("target", target).Str("dir", relativePath).Err(err).Msg("failed to create target directory")
+		}
+	}
+}
+
+// directoryIncluded reports whether a source directory passes the pair's exclude
+// globs and .syncignore files. Include filters describe files and don't apply.
+func directoryIncluded(pair *cfg.Pair, sourcePath, relativePath string) bool {
+	sourceRoot, ok := sourceRootOf(pair, sourcePath)
+	if !ok {
+		sourceRoot = pair.Source
+	}
+	return !MatchesExclude(pair.ExcludeGlobs, sourcePath, pair.CaseSensitive) && !IsSyncIgnored(pair.ID, sourceRoot, relativePath)
+}
+
+// createTargetDirectory creates a target directory and its parents.
+func createTargetDirectory(targetPath string) error {
+	ownWrites.begin(targetPath)
+	defer ownWrites.end(targetPath)
+	return os.MkdirAll(targetPath, 0o755)
+}
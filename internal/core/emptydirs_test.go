@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeSourceDirs creates (empty) directories below the source root
+func makeSourceDirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSyncEmptyDirs(t *testing.T) {
+	tests := []struct {
+		name        string
+		syncEmpty   bool
+		wantDirs    map[string]bool // target directory exists
+		wantCreated int
+	}{
+		{"enabled", true, map[string]bool{"empty": true, "scaffold/a/b": true, "cache": false, "with-file": true}, 5},
+		{"disabled", false, map[string]bool{"empty": false, "scaffold/a/b": false, "cache": false, "with-file": true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SyncEmptyDirs = tt.syncEmpty
+			pair.ExcludeGlobs = []string{"**/cache"}
+			makeSourceDirs(t, pair.Source, "empty", "scaffold/a/b", "cache")
+			writeTestFile(t, filepath.Join(pair.Source, "with-file", "x.txt"), "x")
+
+			plan, err := (&Copier{}).DryRun(context.Background(), pair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plan.DirsCreated != tt.wantCreated {
+				t.Errorf("planned DirsCreated = %d, want %d", plan.DirsCreated, tt.wantCreated)
+			}
+			if IsDirectoryExists(filepath.Join(pair.Target, "empty")) {
+				t.Error("dry run created a directory")
+			}
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatal(err)
+			}
+			for dir, want := range tt.wantDirs {
+				if got := IsDirectoryExists(filepath.Join(pair.Target, dir)); got != want {
+					t.Errorf("target %s exists = %v, want %v", dir, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyDirsRemovedWithMirrorDeletes(t *testing.T) {
+	pair := newTestPair(t)
+	pair.SyncEmptyDirs = true
+	pair.MirrorDeletes = true
+	makeSourceDirs(t, pair.Source, "keep", "drop/nested")
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+	if !IsDirectoryExists(filepath.Join(pair.Target, "drop", "nested")) {
+		t.Fatal("empty directory not created")
+	}
+
+	if err := os.RemoveAll(filepath.Join(pair.Source, "drop")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+	if IsDirectoryExists(filepath.Join(pair.Target, "drop")) {
+		t.Error("directory removed from the source is still in the target")
+	}
+	if !IsDirectoryExists(filepath.Join(pair.Target, "keep")) {
+		t.Error("empty directory still in the source was deleted")
+	}
+}
+
+func TestWatcherCreatesEmptyDirectory(t *testing.T) {
+	tests := []struct {
+		name      string
+		syncEmpty bool
+	}{
+		{"enabled", true},
+		{"disabled", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SyncEmptyDirs = tt.syncEmpty
+			w, watcher := newSetupWorker(t, pair)
+			makeSourceDirs(t, pair.Source, "new/sub")
+
+			if !w.handleDirectoryCreation(filepath.Join(pair.Source, "new"), watcher) {
+				t.Fatal("directory creation not handled")
+			}
+			for _, dir := range []string{"new", filepath.Join("new", "sub")} {
+				if got := IsDirectoryExists(filepath.Join(pair.Target, dir)); got != tt.syncEmpty {
+					t.Errorf("target %s exists = %v, want %v", dir, got, tt.syncEmpty)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Package core provides the activity event log for the FolderSynchronizer application.
+// Syncs, file copies and deletions, hook results and pair lifecycle changes are recorded
+// as one chronological, bounded in-memory stream that the API serves and tails live.
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// ===== EVENT TYPES =====
+
+const (
+	EventSyncStarted  = "sync_started"  // A full sync run began
+	EventSyncFinished = "sync_finished" // A full sync run ended (Success reports the outcome)
+	EventFileCopied   = "file_copied"   // A file was copied to the target
+	EventFileDeleted  = "file_deleted"  // A target file was deleted (mirror deletes)
+	EventFileMoved    = "file_moved"    // A target file or directory was moved after a source rename
+	EventHook         = "hook"          // A hook finished (Success reports the outcome)
+	EventPairStarted  = "pair_started"  // A pair was started
+	EventPairStopped  = "pair_stopped"  // A pair was stopped
+	EventPairPaused   = "pair_paused"   // A pair was paused
+	EventPairResumed  = "pair_resumed"  // A paused pair was resumed
+
+	EventRunLimitReached = "run_limit_reached" // A pair's schedule used up its maxRuns
+
+	// DefaultEventLogSize is the number of events kept in memory
+	DefaultEventLogSize = 1000
+
+	// eventSubscriberBuffer is the backlog of a live subscriber before events are dropped for it
+	eventSubscriberBuffer = 64
+)
+
+// ===== EVENT LOG =====
+
+// Event is a single entry of the activity feed.
+type Event struct {
+	Seq     uint64    `json:"seq"`               // Monotonic sequence number
+	Time    time.Time `json:"time"`              // When the event happened
+	Type    string    `json:"type"`              // One of the Event* constants
+	Pair    string    `json:"pair,omitempty"`    // Pair the event belongs to
+	File    string    `json:"file,omitempty"`    // Relative file path, when about a file
+	Success *bool     `json:"success,omitempty"` // Outcome for sync_finished and hook events
+	Message string    `json:"message,omitempty"` // Additional details
+}
+
+// EventLog is a bounded ring buffer of events with live subscribers.
+// It is safe for concurrent use.
+type EventLog struct {
+	mutex       sync.Mutex
+	entries     []Event                 // Recorded events, at most size
+	next        int                     // Slot overwritten by the next event once full
+	size        int                     // Maximum number of events kept
+	seq         uint64                  // Sequence number of the latest event
+	subscribers map[chan Event]struct{} // Live tails (SSE)
+}
+
+// Events is the process-wide activity feed
+var Events = NewEventLog(DefaultEventLogSize)
+
+// NewEventLog creates an event log keeping the latest size events (at least one).
+func NewEventLog(size int) *EventLog {
+	return &EventLog{
+		size:        max(size, 1),
+		subscribers: make(map[chan Event]struct{}),
+	}
+}
+
+// Append records an event, assigning its sequence number and, if unset, its time.
+// Subscribers that can't keep up miss the event rather than blocking the caller.
+func (l *EventLog) Append(event Event) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.seq++
+	event.Seq = l.seq
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if len(l.entries) < l.size {
+		l.entries = append(l.entries, event)
+	} else {
+		l.entries[l.next] = event
+		l.next = (l.next + 1) % l.size
+	}
+
+	for ch := range l.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Since returns the kept events newer than since (all when since is zero), oldest first.
+func (l *EventLog) Since(since time.Time) []Event {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	result := make([]Event, 0, len(l.entries))
+	for i := range l.entries {
+		event := l.entries[(l.next+i)%len(l.entries)]
+		if event.Time.After(since) {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+// Subscribe returns a channel receiving events appended from now on, and the
+// function ending the subscription.
+func (l *EventLog) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, eventSubscriberBuffer)
+
+	l.mutex.Lock()
+	l.subscribers[ch] = struct{}{}
+	l.mutex.Unlock()
+
+	return ch, func() {
+		l.mutex.Lock()
+		delete(l.subscribers, ch)
+		l.mutex.Unlock()
+	}
+}
+
+// recordEvent appends an event to the process-wide feed
+func recordEvent(eventType, pairID, file, message string) {
+	Events.Append(Event{Type: eventType, Pair: pairID, File: file, Message: message})
+}
+
+// recordOutcome appends an event carrying a success flag to the process-wide feed
+func recordOutcome(eventType, pairID, file string, success bool, message string) {
+	Events.Append(Event{Type: eventType, Pair: pairID, File: file, Success: &success, Message: message})
+}
@@ -0,0 +1,112 @@
+// Package core provides fan-out to several targets for the FolderSynchronizer application.
+// A pair with a target list replicates its source to each target in turn. Every target is
+// compared and mirrored on its own, a failing target doesn't stop the others, and hooks
+// fire once per file however many targets it was copied to.
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== FAN-OUT RESULTS =====
+
+// TargetResult reports the outcome of a fan-out run for one target.
+type TargetResult struct {
+	Target       string     `json:"target"`            // Target directory
+	FilesCopied  int        `json:"filesCopied"`       // Files copied to this target
+	BytesCopied  int64      `json:"bytesCopied"`       // Bytes copied to this target
+	FilesDeleted int        `json:"filesDeleted"`      // Files deleted from this target (mirror mode)
+	FilesSkipped int        `json:"filesSkipped"`      // Files unchanged in this target
+	Renames      []RenameOp `json:"renames,omitempty"` // Planned renames in this target (dry run)
+	Errors       []string   `json:"errors,omitempty"`  // Errors of this target
+}
+
+// ===== FAN-OUT SYNC =====
+
+// fanOutSync syncs the pair to each of its targets and aggregates the results.
+// Errors of a target are collected and the remaining targets still run.
+func (c *Copier) fanOutSync(ctx context.Context, pair *cfg.Pair) (*SyncResult, error) {
+	result := &SyncResult{}
+	hookedFiles := make(map[string]bool)
+
+	for _, target := range pair.TargetRoots() {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		targetPair := pair.ForTarget(target)
+		copier := &Copier{pair: targetPair, dryRun: c.dryRun, hashCheck: c.hashCheck, hookedFiles: hookedFiles}
+		targetResult, err := copier.performSync(ctx, targetPair)
+		if c.dryRun && pair.DetectMoves {
+			copier.applyRenamePlan(targetResult, planRenames(copier.newFiles, copier.orphans))
+		}
+		c.batchedFiles = append(c.batchedFiles, copier.batchedFiles...)
+
+		summary := TargetResult{
+			Target:       target,
+			FilesCopied:  targetResult.FilesCopied,
+			BytesCopied:  targetResult.BytesCopied,
+			FilesDeleted: targetResult.FilesDeleted,
+			FilesSkipped: targetResult.FilesSkipped,
+			Renames:      targetResult.Renames,
+		}
+		if err != nil {
+			targetResult.Errors = append(targetResult.Errors, err)
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("target", target).Err(err).Msg("sync to target failed")
+		}
+		for _, targetErr := range targetResult.Errors {
+			summary.Errors = append(summary.Errors, targetErr.Error())
+			result.Errors = append(result.Errors, fmt.Errorf("target %s: %w", target, targetErr))
+		}
+
+		result.FilesCopied += targetResult.FilesCopied
+		result.BytesCopied += targetResult.BytesCopied
+		result.FilesDeleted += targetResult.FilesDeleted
+		result.DirsDeleted += targetResult.DirsDeleted
+		result.DirsCreated += targetResult.DirsCreated
+		result.FilesSkipped += targetResult.FilesSkipped
+		result.DirsBeyondDepth = targetResult.DirsBeyondDepth // Every target walks the same sources
+		result.HiddenSkipped = targetResult.HiddenSkipped
+		result.Targets = append(result.Targets, summary)
+	}
+
+	return result, nil
+}
+
+// ===== WATCHER FAN-OUT =====
+
+// copyToOtherTargets copies a file the watcher already copied to the first target
+// to the remaining targets of a fan-out pair.
+func (w *PairWorker) copyToOtherTargets(sourcePath, relativePath string) {
+	pair := w.Pair
+	for _, target := range pair.TargetRoots()[1:] {
+		targetPair := pair.ForTarget(target)
+		targetPath := targetPathFor(targetPair, relativePath)
+		if err := os.MkdirAll(filepath.Dir(targetPath), DefaultDirPerms); err != nil {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("target", target).Str("file", relativePath).Err(err).Msg("copy failed")
+			continue
+		}
+		if _, err := copyWithRetry(w.ctx, targetPair, sourcePath, targetPath); err != nil {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("target", target).Str("file", relativePath).Err(err).Msg("copy failed after retries")
+			continue
+		}
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("target", target).Str("file", relativePath).Msg("copied (event)")
+	}
+}
+
+// removeTargetFiles deletes a file from every target. It reports whether any target file was removed.
+func removeTargetFiles(pair *cfg.Pair, relativePath string) bool {
+	removed := false
+	for _, target := range pair.TargetRoots() {
+		if removeOwnedFile(targetPathFor(pair.ForTarget(target), relativePath)) == nil {
+			removed = true
+		}
+	}
+	return removed
+}
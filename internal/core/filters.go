@@ -1,10 +1,17 @@
 // Package core provides file filtering functionality for the FolderSynchronizer application.
-// It handles include/exclude pattern matching using file extensions and glob patterns.
+// It handles include/exclude pattern matching using file extensions and glob patterns,
+// and gitignore-style .syncignore files placed in the source tree.
 package core
 
 import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
@@ -13,23 +20,28 @@ import (
 
 // MatchesInclude checks if a file should be included based on extension filtering.
 // If the include list is empty, all files are included by default.
-// Extension matching is case-insensitive for better cross-platform compatibility.
+// Extension matching is case-insensitive for better cross-platform compatibility,
+// unless caseSensitive is set. Multi-part extensions such as ".tar.gz" are matched
+// against the end of the file name.
 //
 // Parameters:
-//   - extensions: List of allowed file extensions (e.g., [".jpg", ".png"])
+//   - extensions: List of allowed file extensions (e.g., [".jpg", ".png", ".tar.gz"])
 //   - filePath: Full or relative path to the file being checked
+//   - caseSensitive: Whether "Photo.JPG" must not match ".jpg"
 //
 // Returns:
 //   - true if the file should be included, false otherwise
-func MatchesInclude(extensions []string, filePath string) bool {
+func MatchesInclude(extensions []string, filePath string, caseSensitive bool) bool {
 	// Empty include list means include all files
 	if len(extensions) == 0 {
 		return true
 	}
 
-	fileExt := strings.ToLower(filepath.Ext(filePath))
+	fileName := foldCase(filepath.Base(filePath), caseSensitive)
+	fileExt := filepath.Ext(fileName)
 	for _, allowedExt := range extensions {
-		if strings.ToLower(allowedExt) == fileExt {
+		allowedExt = foldCase(allowedExt, caseSensitive)
+		if allowedExt == fileExt || isMultiPartExtension(allowedExt) && matchesMultiPartExtension(fileName, allowedExt) {
 			return true
 		}
 	}
@@ -37,53 +49,66 @@ func MatchesInclude(extensions []string, filePath string) bool {
 	return false
 }
 
+// foldCase lowercases s for case-insensitive matching, or returns it unchanged.
+func foldCase(s string, caseSensitive bool) string {
+	if caseSensitive {
+		return s
+	}
+	return strings.ToLower(s)
+}
+
+// isMultiPartExtension reports whether an extension has more than one part, like ".tar.gz".
+func isMultiPartExtension(extension string) bool {
+	return strings.Count(strings.TrimPrefix(extension, "."), ".") > 0
+}
+
+// matchesMultiPartExtension reports whether a file name ends with a multi-part
+// extension. The extension must follow a non-empty base name, so ".tar.gz"
+// matches "archive.tar.gz" but not a file named ".tar.gz".
+func matchesMultiPartExtension(fileName, extension string) bool {
+	if !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return len(fileName) > len(extension) && strings.HasSuffix(fileName, extension)
+}
+
 // MatchesExclude checks if a file should be excluded based on glob pattern matching.
 // Uses doublestar library for advanced glob pattern support including ** wildcards.
 // Path separators are normalized to forward slashes for consistent matching.
+// Matching ignores case unless caseSensitive is set.
 //
 // Parameters:
 //   - globs: List of glob patterns to exclude (e.g., ["**/*.tmp", "**/node_modules/**"])
 //   - filePath: Full or relative path to the file being checked
+//   - caseSensitive: Whether "**/*.tmp" must not match "X.TMP"
 //
 // Returns:
 //   - true if the file should be excluded, false otherwise
-func MatchesExclude(globs []string, filePath string) bool {
-	if len(globs) == 0 {
-		return false
-	}
-
-	// Normalize path separators for consistent glob matching
-	normalizedPath := filepath.ToSlash(filePath)
-
-	for _, pattern := range globs {
-		if matched, _ := doublestar.PathMatch(pattern, normalizedPath); matched {
-			return true
-		}
-	}
-
-	return false
+func MatchesExclude(globs []string, filePath string, caseSensitive bool) bool {
+	return MatchesAnyGlob(globs, filePath, caseSensitive)
 }
 
 // MatchesAnyGlob checks if a file path matches any of the provided glob patterns.
 // This is a generic utility function that can be used for both include and exclude scenarios.
-// Uses the same path normalization as MatchesExclude for consistency.
+// Uses the same path normalization and case handling as MatchesExclude for consistency.
 //
 // Parameters:
 //   - globs: List of glob patterns to match against
 //   - filePath: Full or relative path to the file being checked
+//   - caseSensitive: Whether letter case must match exactly
 //
 // Returns:
 //   - true if any pattern matches the file path, false otherwise
-func MatchesAnyGlob(globs []string, filePath string) bool {
+func MatchesAnyGlob(globs []string, filePath string, caseSensitive bool) bool {
 	if len(globs) == 0 {
 		return false
 	}
 
 	// Normalize path separators for consistent glob matching
-	normalizedPath := filepath.ToSlash(filePath)
+	normalizedPath := foldCase(filepath.ToSlash(filePath), caseSensitive)
 
 	for _, pattern := range globs {
-		if matched, _ := doublestar.PathMatch(pattern, normalizedPath); matched {
+		if matched, _ := doublestar.PathMatch(foldCase(pattern, caseSensitive), normalizedPath); matched {
 			return true
 		}
 	}
@@ -93,31 +118,252 @@ func MatchesAnyGlob(globs []string, filePath string) bool {
 
 // ===== COMPOSITE FILTERING FUNCTIONS =====
 
+// MatchesIncludeFilters checks if a file passes the include filters of a pair.
+// A file is included when it matches any include extension OR any include glob.
+// If both lists are empty, all files are included by default. Extensions are
+// matched against the file name and globs against the whole path, so pass the
+// path relative to the source root for globs such as "build/**".
+//
+// Parameters:
+//   - extensions: List of allowed file extensions (e.g., [".jpg", ".png"])
+//   - globs: List of glob patterns to include (e.g., ["build/**"])
+//   - filePath: Path to the file being checked (relative to the source for glob matching)
+//   - caseSensitive: Whether letter case must match exactly
+//
+// Returns:
+//   - true if the file should be included, false otherwise
+func MatchesIncludeFilters(extensions []string, globs []string, filePath string, caseSensitive bool) bool {
+	// Empty include lists mean include all files
+	if len(extensions) == 0 && len(globs) == 0 {
+		return true
+	}
+
+	// Check extension match
+	if len(extensions) > 0 && MatchesInclude(extensions, filePath, caseSensitive) {
+		return true
+	}
+
+	// Check glob pattern match
+	if len(globs) > 0 && MatchesAnyGlob(globs, filePath, caseSensitive) {
+		return true
+	}
+
+	return false
+}
+
 // ShouldIncludeFile determines if a file should be included in synchronization
-// based on both include extensions and exclude glob patterns.
+// based on include extensions, include glob patterns and exclude glob patterns.
 // This is the main entry point for file filtering decisions.
 //
 // Parameters:
-//   - includeExtensions: List of allowed file extensions (empty means include all)
+//   - includeExtensions: List of allowed file extensions
+//   - includeGlobs: List of glob patterns to include
 //   - excludeGlobs: List of glob patterns to exclude
 //   - filePath: Full or relative path to the file being checked
+//   - caseSensitive: Whether letter case must match exactly
 //
 // Returns:
 //   - true if the file should be synchronized, false otherwise
-func ShouldIncludeFile(includeExtensions []string, excludeGlobs []string, filePath string) bool {
-	// First check if file extension is allowed
-	if !MatchesInclude(includeExtensions, filePath) {
+func ShouldIncludeFile(includeExtensions []string, includeGlobs []string, excludeGlobs []string, filePath string, caseSensitive bool) bool {
+	// First check if file matches any include filter
+	if !MatchesIncludeFilters(includeExtensions, includeGlobs, filePath, caseSensitive) {
 		return false
 	}
 
 	// Then check if file is excluded by any glob pattern
-	if MatchesExclude(excludeGlobs, filePath) {
+	if MatchesExclude(excludeGlobs, filePath, caseSensitive) {
 		return false
 	}
 
 	return true
 }
 
+// ===== SYNCIGNORE FILES =====
+
+const (
+	// SyncIgnoreFileName is the per-directory ignore file read from the source tree
+	SyncIgnoreFileName = ".syncignore"
+
+	// SyncIgnoreRecheckInterval is how long a cached ignore file is trusted before
+	// it is checked for changes again
+	SyncIgnoreRecheckInterval = 2 * time.Second
+
+	// MaxSyncIgnoreCacheDirs bounds the directories cached per pair; a full cache
+	// is cleared and refilled, as at the start of each full sync
+	MaxSyncIgnoreCacheDirs = 10000
+)
+
+// syncIgnoreRule is one pattern line of a .syncignore file.
+type syncIgnoreRule struct {
+	pattern string // Glob relative to the ignore file's directory
+	negate  bool   // "!pattern" re-includes a path excluded by an earlier rule
+	dirOnly bool   // "pattern/" matches directories only
+}
+
+// syncIgnoreFile is a parsed ignore file with the state used to detect changes.
+type syncIgnoreFile struct {
+	rules     []syncIgnoreRule
+	modTime   time.Time // Modification time when parsed (zero if the file is missing)
+	size      int64     // Size when parsed
+	checkedAt time.Time // Last time the file was checked for changes
+}
+
+// syncIgnoreCache holds parsed ignore files by pair and directory. Directories
+// without an ignore file are cached too, so each pair's cache is bounded and
+// dropped when a full sync starts or the pair stops.
+var syncIgnoreCache = struct {
+	mutex sync.Mutex
+	pairs map[string]map[string]*syncIgnoreFile // pair ID -> directory -> ignore file
+}{pairs: make(map[string]map[string]*syncIgnoreFile)}
+
+// parseSyncIgnore parses gitignore-style patterns: blank lines and "#" comments
+// are skipped, "!" negates, a trailing "/" matches directories only, and a
+// pattern containing another "/" is anchored to the file's directory while
+// others match at any depth below it. "\#" and "\!" escape a literal first character.
+func parseSyncIgnore(data []byte) []syncIgnoreRule {
+	var rules []syncIgnoreRule
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule syncIgnoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\#`) || strings.HasPrefix(line, `\!`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		if strings.Contains(line, "/") {
+			rule.pattern = strings.TrimPrefix(line, "/")
+		} else {
+			rule.pattern = "**/" + line
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// loadSyncIgnore returns the rules of the ignore file in dir (nil when there is
+// none) from the pair's cache, re-reading it when its size or modification time changed.
+func loadSyncIgnore(pairID, dir string) []syncIgnoreRule {
+	syncIgnoreCache.mutex.Lock()
+	defer syncIgnoreCache.mutex.Unlock()
+
+	files := syncIgnoreCache.pairs[pairID]
+	if files == nil || len(files) >= MaxSyncIgnoreCacheDirs {
+		files = make(map[string]*syncIgnoreFile)
+		syncIgnoreCache.pairs[pairID] = files
+	}
+
+	now := time.Now()
+	cached, exists := files[dir]
+	if exists && now.Sub(cached.checkedAt) < SyncIgnoreRecheckInterval {
+		return cached.rules
+	}
+
+	ignorePath := filepath.Join(dir, SyncIgnoreFileName)
+	info, err := os.Stat(ignorePath)
+	if err != nil {
+		files[dir] = &syncIgnoreFile{checkedAt: now}
+		return nil
+	}
+	if exists && info.ModTime().Equal(cached.modTime) && info.Size() == cached.size {
+		cached.checkedAt = now
+		return cached.rules
+	}
+
+	data, err := os.ReadFile(ignorePath)
+	if err != nil {
+		files[dir] = &syncIgnoreFile{checkedAt: now}
+		return nil
+	}
+	rules := parseSyncIgnore(data)
+	files[dir] = &syncIgnoreFile{
+		rules:     rules,
+		modTime:   info.ModTime(),
+		size:      info.Size(),
+		checkedAt: now,
+	}
+	return rules
+}
+
+// InvalidateSyncIgnore drops a pair's cached rules of the ignore file at path, so
+// the next check re-reads it. The watcher calls it when an ignore file changes.
+func InvalidateSyncIgnore(pairID, path string) {
+	syncIgnoreCache.mutex.Lock()
+	defer syncIgnoreCache.mutex.Unlock()
+	delete(syncIgnoreCache.pairs[pairID], filepath.Dir(path))
+}
+
+// ResetSyncIgnoreCache drops everything cached for a pair. Called when a full
+// sync starts, which re-reads the ignore files of the whole tree anyway, and
+// when the pair stops.
+func ResetSyncIgnoreCache(pairID string) {
+	syncIgnoreCache.mutex.Lock()
+	defer syncIgnoreCache.mutex.Unlock()
+	delete(syncIgnoreCache.pairs, pairID)
+}
+
+// IsSyncIgnored reports whether a file (path relative to sourceRoot) of a pair is
+// excluded by .syncignore files in the source root or the directories above the
+// file. Rules of deeper files follow those of their parents and the last matching
+// rule wins; as with gitignore, a file inside an excluded directory can't be re-included.
+func IsSyncIgnored(pairID, sourceRoot, relativePath string) bool {
+	relativePath = filepath.ToSlash(relativePath)
+	parts := strings.Split(relativePath, "/")
+
+	// Rules in effect, by the ignore file's directory relative to the root
+	type scopedRules struct {
+		base  string
+		rules []syncIgnoreRule
+	}
+	var scopes []scopedRules
+
+	for i := range parts {
+		dir := path.Join(parts[:i]...)
+		if rules := loadSyncIgnore(pairID, filepath.Join(sourceRoot, filepath.FromSlash(dir))); len(rules) > 0 {
+			scopes = append(scopes, scopedRules{base: dir, rules: rules})
+		}
+		if len(scopes) == 0 {
+			continue
+		}
+
+		// Check the directory or file at this level with the rules collected so far
+		candidate := path.Join(parts[:i+1]...)
+		isDir := i < len(parts)-1
+		ignored := false
+		for _, scope := range scopes {
+			rel := candidate
+			if scope.base != "" {
+				rel = strings.TrimPrefix(candidate, scope.base+"/")
+			}
+			for _, rule := range scope.rules {
+				if rule.dirOnly && !isDir {
+					continue
+				}
+				if matched, _ := doublestar.Match(rule.pattern, rel); matched {
+					ignored = !rule.negate
+				}
+			}
+		}
+		if ignored {
+			return true
+		}
+	}
+	return false
+}
+
 // ===== HOOK FILTERING FUNCTIONS =====
 
 // ShouldTriggerHook determines if a hook should be triggered for a given file
@@ -127,22 +373,23 @@ func ShouldIncludeFile(includeExtensions []string, excludeGlobs []string, filePa
 //   - hookExtensions: List of extensions that trigger this hook
 //   - hookGlobs: List of glob patterns that trigger this hook
 //   - filePath: Full or relative path to the file being checked
+//   - caseSensitive: Whether letter case must match exactly
 //
 // Returns:
 //   - true if the hook should be triggered, false otherwise
-func ShouldTriggerHook(hookExtensions []string, hookGlobs []string, filePath string) bool {
+func ShouldTriggerHook(hookExtensions []string, hookGlobs []string, filePath string, caseSensitive bool) bool {
 	// If both lists are empty, hook triggers for all files
 	if len(hookExtensions) == 0 && len(hookGlobs) == 0 {
 		return true
 	}
 
 	// Check extension match
-	if len(hookExtensions) > 0 && MatchesInclude(hookExtensions, filePath) {
+	if len(hookExtensions) > 0 && MatchesInclude(hookExtensions, filePath, caseSensitive) {
 		return true
 	}
 
 	// Check glob pattern match
-	if len(hookGlobs) > 0 && MatchesAnyGlob(hookGlobs, filePath) {
+	if len(hookGlobs) > 0 && MatchesAnyGlob(hookGlobs, filePath, caseSensitive) {
 		return true
 	}
 
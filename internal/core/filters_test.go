@@ -0,0 +1,247 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShouldIncludeFile(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeExt   []string
+		includeGlobs []string
+		excludeGlobs []string
+		path         string
+		want         bool
+	}{
+		{"no filters include all", nil, nil, nil, "docs/readme.md", true},
+		{"extension match", []string{".jar"}, nil, nil, "lib/app.jar", true},
+		{"extension mismatch", []string{".jar"}, nil, nil, "lib/app.txt", false},
+		{"glob match regardless of extension", nil, []string{"build/**"}, nil, "build/out/app.bin", true},
+		{"glob mismatch", nil, []string{"build/**"}, nil, "src/main.go", false},
+		{"extension or glob, extension side", []string{".jar"}, []string{"build/**"}, nil, "lib/app.jar", true},
+		{"extension or glob, glob side", []string{".jar"}, []string{"build/**"}, nil, "build/app.txt", true},
+		{"extension or glob, neither", []string{".jar"}, []string{"build/**"}, nil, "src/app.txt", false},
+		{"exclude wins over extension", []string{".jar"}, nil, []string{"**/*-sources.jar"}, "lib/app-sources.jar", false},
+		{"exclude wins over glob", nil, []string{"build/**"}, []string{"build/tmp/**"}, "build/tmp/cache.bin", false},
+		{"exclude without includes", nil, nil, []string{"**/*.tmp"}, "a/b.tmp", false},
+		{"exclude miss keeps included file", []string{".jar"}, []string{"build/**"}, []string{"**/*.tmp"}, "build/app.bin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShouldIncludeFile(tt.includeExt, tt.includeGlobs, tt.excludeGlobs, tt.path, false); got != tt.want {
+				t.Errorf("ShouldIncludeFile(%q, %q, %q, %q) = %v, want %v", tt.includeExt, tt.includeGlobs, tt.excludeGlobs, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncHonorsIncludeGlobs(t *testing.T) {
+	pair := newTestPair(t)
+	pair.IncludeExt = []string{".jar"}
+	pair.IncludeGlobs = []string{"build/**"}
+	pair.ExcludeGlobs = []string{"**/scratch/**"}
+
+	files := map[string]bool{
+		"lib/app.jar":             true,
+		"build/out/app.bin":       true,
+		"build/scratch/cache.bin": false,
+		"src/main.go":             false,
+	}
+	for path := range files {
+		writeTestFile(t, filepath.Join(pair.Source, path), path)
+	}
+
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	for path, want := range files {
+		if copied := readTestFile(t, filepath.Join(pair.Target, path)) != ""; copied != want {
+			t.Errorf("%s copied = %v, want %v", path, copied, want)
+		}
+	}
+}
+
+func TestMatchesIncludeCaseSensitivity(t *testing.T) {
+	tests := []struct {
+		name          string
+		extensions    []string
+		path          string
+		caseSensitive bool
+		want          bool
+	}{
+		{"insensitive upper file", []string{".jpg"}, "Photo.JPG", false, true},
+		{"insensitive upper extension", []string{".JPG"}, "photo.jpg", false, true},
+		{"sensitive exact", []string{".JPG"}, "Photo.JPG", true, true},
+		{"sensitive mismatch", []string{".jpg"}, "Photo.JPG", true, false},
+		{"sensitive multi-part", []string{".tar.gz"}, "a.TAR.GZ", true, false},
+		{"insensitive multi-part", []string{".tar.gz"}, "a.TAR.GZ", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesInclude(tt.extensions, tt.path, tt.caseSensitive); got != tt.want {
+				t.Errorf("MatchesInclude(%q, %q, %v) = %v, want %v", tt.extensions, tt.path, tt.caseSensitive, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesIncludeMultiPartExtensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions []string
+		path       string
+		want       bool
+	}{
+		{"tar.gz", []string{".tar.gz"}, "dist/archive.tar.gz", true},
+		{"tar.gz needs both parts", []string{".tar.gz"}, "dist/archive.gz", false},
+		{"tar.gz isn't a plain tar", []string{".tar.gz"}, "dist/archive.tar", false},
+		{"min.js", []string{".min.js"}, "web/app.min.js", true},
+		{"min.js skips unminified", []string{".min.js"}, "web/app.js", false},
+		{"plain jar", []string{".jar"}, "lib/app.jar", true},
+		{"plain jar mismatch", []string{".jar"}, "lib/app.jar.bak", false},
+		{"last part still matches", []string{".gz"}, "dist/archive.tar.gz", true},
+		{"ignores case", []string{".TAR.GZ"}, "dist/Archive.Tar.Gz", true},
+		{"without leading dot", []string{"tar.gz"}, "dist/archive.tar.gz", true},
+		{"needs a base name", []string{".tar.gz"}, "dist/.tar.gz", false},
+		{"any listed extension", []string{".jar", ".min.js"}, "web/app.min.js", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesInclude(tt.extensions, tt.path, false); got != tt.want {
+				t.Errorf("MatchesInclude(%q, %q) = %v, want %v", tt.extensions, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesAnyGlobCaseSensitivity(t *testing.T) {
+	tests := []struct {
+		name          string
+		globs         []string
+		path          string
+		caseSensitive bool
+		want          bool
+	}{
+		{"insensitive", []string{"**/*.tmp"}, "dir/X.TMP", false, true},
+		{"sensitive mismatch", []string{"**/*.tmp"}, "dir/X.TMP", true, false},
+		{"sensitive exact", []string{"**/*.TMP"}, "dir/X.TMP", true, true},
+		{"insensitive directory", []string{"Build/**"}, "build/app.jar", false, true},
+		{"sensitive directory", []string{"Build/**"}, "build/app.jar", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesAnyGlob(tt.globs, tt.path, tt.caseSensitive); got != tt.want {
+				t.Errorf("MatchesAnyGlob(%q, %q, %v) = %v, want %v", tt.globs, tt.path, tt.caseSensitive, got, tt.want)
+			}
+			if got := MatchesExclude(tt.globs, tt.path, tt.caseSensitive); got != tt.want {
+				t.Errorf("MatchesExclude(%q, %q, %v) = %v, want %v", tt.globs, tt.path, tt.caseSensitive, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldTriggerHookCaseSensitivity(t *testing.T) {
+	if !ShouldTriggerHook([]string{".jar"}, nil, "APP.JAR", false) {
+		t.Error("hook should fire for APP.JAR when matching ignores case")
+	}
+	if ShouldTriggerHook([]string{".jar"}, nil, "APP.JAR", true) {
+		t.Error("hook should not fire for APP.JAR when matching is case-sensitive")
+	}
+}
+
+// caseSensitiveFSOrSkip skips the test where dir can't hold names differing only in case
+func caseSensitiveFSOrSkip(t *testing.T, dir string) {
+	t.Helper()
+	writeTestFile(t, filepath.Join(dir, "probe"), "lower")
+	writeTestFile(t, filepath.Join(dir, "PROBE"), "upper")
+	if readTestFile(t, filepath.Join(dir, "probe")) != "lower" {
+		t.Skip("file system ignores case")
+	}
+	os.Remove(filepath.Join(dir, "probe"))
+	os.Remove(filepath.Join(dir, "PROBE"))
+}
+
+func TestSyncCaseSensitiveFilters(t *testing.T) {
+	tests := []struct {
+		name          string
+		caseSensitive bool
+		want          map[string]bool // copied per source file
+	}{
+		{"case-insensitive", false, map[string]bool{"Photo.JPG": true, "photo.jpg": true, "CACHE/x.bin": false, "cache/y.bin": false}},
+		{"case-sensitive", true, map[string]bool{"Photo.JPG": true, "photo.jpg": false, "CACHE/x.bin": true, "cache/y.bin": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			caseSensitiveFSOrSkip(t, pair.Source)
+			pair.CaseSensitive = tt.caseSensitive
+			pair.IncludeExt = []string{".JPG", ".bin"}
+			pair.ExcludeGlobs = []string{"**/cache/**"}
+			for path := range tt.want {
+				writeTestFile(t, filepath.Join(pair.Source, path), path)
+			}
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatal(err)
+			}
+			for path, want := range tt.want {
+				if copied := readTestFile(t, filepath.Join(pair.Target, path)) == path; copied != want {
+					t.Errorf("%s copied = %v, want %v", path, copied, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncIgnoreCacheIsPerPairAndReset(t *testing.T) {
+	source := t.TempDir()
+	writeTestFile(t, filepath.Join(source, SyncIgnoreFileName), "*.log\n")
+
+	if !IsSyncIgnored("a", source, "debug.log") {
+		t.Fatal("debug.log should be ignored by *.log")
+	}
+	if IsSyncIgnored("a", source, "notes.txt") {
+		t.Fatal("notes.txt should not be ignored")
+	}
+
+	// A change within the recheck interval is only seen after a reset
+	writeTestFile(t, filepath.Join(source, SyncIgnoreFileName), "*.txt\n")
+	if !IsSyncIgnored("b", source, "notes.txt") {
+		t.Error("another pair should read the ignore file itself, not pair a's cache")
+	}
+	ResetSyncIgnoreCache("a")
+	if !IsSyncIgnored("a", source, "notes.txt") {
+		t.Error("reset pair should see the new rules")
+	}
+
+	ResetSyncIgnoreCache("a")
+	ResetSyncIgnoreCache("b")
+	syncIgnoreCache.mutex.Lock()
+	defer syncIgnoreCache.mutex.Unlock()
+	if _, ok := syncIgnoreCache.pairs["a"]; ok {
+		t.Error("reset left pair a's cache behind")
+	}
+}
+
+func TestSyncIgnoreCacheIsBounded(t *testing.T) {
+	const pairID = "bounded"
+	t.Cleanup(func() { ResetSyncIgnoreCache(pairID) })
+	source := t.TempDir()
+
+	for i := 0; i < MaxSyncIgnoreCacheDirs+10; i++ {
+		loadSyncIgnore(pairID, filepath.Join(source, fmt.Sprintf("dir%d", i)))
+	}
+	syncIgnoreCache.mutex.Lock()
+	defer syncIgnoreCache.mutex.Unlock()
+	if n := len(syncIgnoreCache.pairs[pairID]); n > MaxSyncIgnoreCacheDirs {
+		t.Errorf("cache holds %d directories, want at most %d", n, MaxSyncIgnoreCacheDirs)
+	}
+}
@@ -0,0 +1,154 @@
+// Package core provides gRPC hook execution for the FolderSynchronizer application.
+// gRPC hooks make a unary call with a fixed contract: the rendered body template is
+// sent as a google.protobuf.Struct and any response message is accepted.
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/cenkalti/backoff/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ===== GRPC HOOK CONSTANTS =====
+
+const (
+	// GRPCTimeout bounds a single gRPC hook attempt unless Hook.TimeoutMs is set,
+	// matching the HTTP hook timeout
+	GRPCTimeout = HTTPTimeout
+)
+
+// retryableGRPCCodes are status codes worth retrying; other failures are permanent
+var retryableGRPCCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.DeadlineExceeded:  true,
+	codes.ResourceExhausted: true,
+	codes.Aborted:           true,
+}
+
+// ===== GRPC HOOK EXECUTION =====
+
+// executeGRPCHook executes a gRPC hook with retry logic and returns its status
+func executeGRPCHook(ctx context.Context, pairID string, hook *cfg.Hook, retries int, data hookTemplateData) HookStatus {
+	startTime := time.Now()
+
+	// Validate target and method
+	target := strings.TrimSpace(hook.GRPC.Target)
+	if target == "" {
+		return hookFailure(data, "grpc", "empty target")
+	}
+	method := strings.TrimSpace(hook.GRPC.Method)
+
+	// Build the request message from the body template
+	request, err := buildGRPCRequest(hook.GRPC.BodyTemplate, data)
+	if err != nil {
+		return hookFailure(data, "grpc", err.Error())
+	}
+
+	// Dial lazily; the connection is established on the first call
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(grpcCredentials(hook.GRPC)))
+	if err != nil {
+		return hookFailure(data, "grpc", "connection setup error: "+err.Error())
+	}
+	defer conn.Close()
+
+	if len(hook.GRPC.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(hook.GRPC.Metadata))
+	}
+
+	// Execute with retry logic
+	timeout := hookTimeout(hook, GRPCTimeout)
+	operation := func() error {
+		// Hold a hook slot only for the call, not during backoff waits
+		release, err := acquireHookSlot(ctx)
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("cancelled while waiting for a hook slot: %w", err))
+		}
+		defer release()
+
+		callCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		err = conn.Invoke(callCtx, method, request, &emptypb.Empty{})
+		if err != nil && !retryableGRPCCodes[status.Code(err)] {
+			return backoff.Permanent(err)
+		}
+		return err
+	}
+
+	backoffStrategy := createBackoffStrategy(ctx, retries)
+	if err := backoff.Retry(operation, backoffStrategy); err != nil {
+		return hookFailure(data, "grpc", grpcErrorInfo(err))
+	}
+
+	logging.ForPair(pairID).Info().
+		Str("pair", pairID).
+		Str("file", data.label()).
+		Str("method", method).
+		Dur("duration", time.Since(startTime)).
+		Msg("grpc hook success")
+
+	return HookStatus{
+		Timestamp: time.Now(),
+		File:      data.label(),
+		HookType:  "grpc",
+		Success:   true,
+		Info:      fmt.Sprintf("gRPC OK %s in %s", method, time.Since(startTime).Round(time.Millisecond)),
+	}
+}
+
+// buildGRPCRequest renders the body template and converts the resulting JSON
+// object into a Struct message. An empty template sends an empty Struct.
+func buildGRPCRequest(bodyTemplate string, data hookTemplateData) (*structpb.Struct, error) {
+	bodyText, err := executeTemplate(bodyTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("template error: %w", err)
+	}
+
+	fields := map[string]any{}
+	if strings.TrimSpace(bodyText) != "" {
+		if err := json.Unmarshal([]byte(bodyText), &fields); err != nil {
+			return nil, fmt.Errorf("body is not a JSON object: %w", err)
+		}
+	}
+
+	request, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, fmt.Errorf("body conversion error: %w", err)
+	}
+	return request, nil
+}
+
+// grpcCredentials returns plaintext or TLS (system roots) transport credentials
+func grpcCredentials(config *cfg.GRPCHook) credentials.TransportCredentials {
+	if config.Insecure {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewClientTLSFromCert(nil, "")
+}
+
+// grpcErrorInfo formats a gRPC error as "CODE: message" for hook status display
+func grpcErrorInfo(err error) string {
+	if st, ok := status.FromError(err); ok {
+		message := st.Message()
+		if len(message) > MaxTruncationSize {
+			message = message[:MaxTruncationSize] + "…"
+		}
+		return fmt.Sprintf("gRPC %s: %s", st.Code(), message)
+	}
+	return err.Error()
+}
@@ -0,0 +1,162 @@
+// Package core provides the persistent checksum cache of the FolderSynchronizer application.
+// Hash comparisons reuse the SHA256 of a file computed earlier as long as its size and
+// modification time are unchanged, so scheduled runs of hash pairs only read changed files.
+// The cache is saved next to the configuration at the end of each sync run.
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== HASH CACHE STRUCTURES =====
+
+// hashCacheEntry is the hash of a file as it was when hashed.
+type hashCacheEntry struct {
+	Size    int64     `json:"size"`    // File size when hashed
+	ModTime time.Time `json:"modTime"` // Modification time when hashed
+	Hash    string    `json:"hash"`    // SHA256, hex-encoded
+}
+
+// hashCacheStore holds cached file hashes by absolute path and persists them to a file.
+type hashCacheStore struct {
+	mutex   sync.Mutex
+	path    string                    // Persistence file; empty disables the cache
+	entries map[string]hashCacheEntry // Absolute file path -> cached hash
+	dirty   bool                      // Entries changed since the last save
+}
+
+// hashCache is the process-wide cache shared by all pairs
+var hashCache = &hashCacheStore{entries: make(map[string]hashCacheEntry)}
+
+// SetHashCacheFile loads the hash cache saved at path and enables caching, saving
+// later changes there. A missing or unreadable file starts an empty cache; an
+// empty path disables caching.
+func SetHashCacheFile(path string) error {
+	hashCache.mutex.Lock()
+	defer hashCache.mutex.Unlock()
+
+	hashCache.path = path
+	hashCache.entries = make(map[string]hashCacheEntry)
+	hashCache.dirty = false
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read hash cache file: %w", err)
+	}
+	if err := json.Unmarshal(data, &hashCache.entries); err != nil {
+		hashCache.entries = make(map[string]hashCacheEntry)
+		return fmt.Errorf("failed to parse hash cache file: %w", err)
+	}
+	return nil
+}
+
+// ===== CACHED HASHING =====
+
+// cachedFileHash returns the SHA256 of a file, reusing the cached hash while the
+// file's size and modification time match. Without a cache file it always hashes.
+func cachedFileHash(filePath string) (string, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+	key, err := filepath.Abs(filePath)
+	if err != nil {
+		key = filePath
+	}
+
+	if hash, ok := hashCache.lookup(key, info); ok {
+		return hash, nil
+	}
+
+	hash, err := calculateFileHash(filePath)
+	if err != nil {
+		return "", err
+	}
+	hashCache.store(key, info, hash)
+	return hash, nil
+}
+
+// lookup returns the cached hash of a file still matching its size and modification time.
+func (s *hashCacheStore) lookup(key string, info os.FileInfo) (string, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.path == "" {
+		return "", false
+	}
+	entry, ok := s.entries[key]
+	if !ok || entry.Size != info.Size() || !entry.ModTime.Equal(info.ModTime()) {
+		return "", false
+	}
+	return entry.Hash, true
+}
+
+// store records the hash of a file.
+func (s *hashCacheStore) store(key string, info os.FileInfo, hash string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.path == "" {
+		return
+	}
+	s.entries[key] = hashCacheEntry{Size: info.Size(), ModTime: info.ModTime(), Hash: hash}
+	s.dirty = true
+}
+
+// forget drops the cached hash of a file the app rewrote or deleted. A rewritten
+// target keeps the source's modification time, so its stale entry could still match.
+func (s *hashCacheStore) forget(filePath string) {
+	key, err := filepath.Abs(filePath)
+	if err != nil {
+		key = filePath
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.entries[key]; ok {
+		delete(s.entries, key)
+		s.dirty = true
+	}
+}
+
+// save persists the cache if it changed since the last save.
+func (s *hashCacheStore) save() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.path == "" || !s.dirty {
+		return
+	}
+
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to marshal hash cache")
+		return
+	}
+
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		log.Error().Err(err).Str("path", s.path).Msg("failed to save hash cache")
+		return
+	}
+	if err := os.Rename(tempPath, s.path); err != nil {
+		_ = os.Remove(tempPath)
+		log.Error().Err(err).Str("path", s.path).Msg("failed to save hash cache")
+		return
+	}
+	s.dirty = false
+}
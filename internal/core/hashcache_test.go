@@ -0,0 +1,139 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useHashCache enables the checksum cache saved at path until the test ends
+func useHashCache(tb testing.TB, path string) {
+	tb.Helper()
+	if err := SetHashCacheFile(path); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = SetHashCacheFile("") })
+}
+
+func TestCachedFileHashInvalidation(t *testing.T) {
+	stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	tests := []struct {
+		name       string
+		cacheFile  bool
+		content    string // rewritten content, same size as "version=1" unless stated
+		modTime    time.Time
+		wantCached bool
+	}{
+		{"unchanged size and mtime reuses the hash", true, "version=2", stamp, true},
+		{"changed mtime rehashes", true, "version=2", stamp.Add(time.Minute), false},
+		{"changed size rehashes", true, "version=10", stamp, false},
+		{"disabled cache always hashes", false, "version=2", stamp, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cacheFile := ""
+			if tt.cacheFile {
+				cacheFile = filepath.Join(t.TempDir(), "hash-cache.json")
+			}
+			useHashCache(t, cacheFile)
+			path := filepath.Join(t.TempDir(), "config.ini")
+			writeTestFile(t, path, "version=1")
+			if err := os.Chtimes(path, stamp, stamp); err != nil {
+				t.Fatal(err)
+			}
+			first, err := cachedFileHash(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			writeTestFile(t, path, tt.content)
+			if err := os.Chtimes(path, tt.modTime, tt.modTime); err != nil {
+				t.Fatal(err)
+			}
+			second, err := cachedFileHash(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached := second == first; cached != tt.wantCached {
+				t.Errorf("second hash reused the first: %v, want %v", cached, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestHashCachePersists(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "hash-cache.json")
+	useHashCache(t, cacheFile)
+	path := filepath.Join(t.TempDir(), "a.txt")
+	writeTestFile(t, path, "data")
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := cachedFileHash(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashCache.save()
+
+	// A restart loads the saved hashes
+	useHashCache(t, cacheFile)
+	if got, ok := hashCache.lookup(path, info); !ok || got != hash {
+		t.Fatalf("reloaded cache lookup = %q, %v, want %q", got, ok, hash)
+	}
+
+	// A forgotten file is hashed again and the removal is saved
+	hashCache.forget(path)
+	if _, ok := hashCache.lookup(path, info); ok {
+		t.Error("forgotten file still cached")
+	}
+	hashCache.save()
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), path) {
+		t.Error("saved cache still lists the forgotten file")
+	}
+}
+
+func TestCorruptHashCacheStartsEmpty(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "hash-cache.json")
+	writeTestFile(t, cacheFile, "{not json")
+	if err := SetHashCacheFile(cacheFile); err == nil {
+		t.Error("corrupt cache file loaded without error")
+	}
+	t.Cleanup(func() { _ = SetHashCacheFile("") })
+	if len(hashCache.entries) != 0 {
+		t.Errorf("corrupt cache left %d entries", len(hashCache.entries))
+	}
+}
+
+func BenchmarkCachedFileHash(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "large.bin")
+	if err := os.WriteFile(path, make([]byte, 8<<20), 0o644); err != nil {
+		b.Fatal(err)
+	}
+
+	benchmarks := []struct {
+		name      string
+		cacheFile string
+	}{
+		{"uncached", ""},
+		{"cached", filepath.Join(b.TempDir(), "hash-cache.json")},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			useHashCache(b, bm.cacheFile)
+			b.SetBytes(8 << 20)
+			for b.Loop() {
+				if _, err := cachedFileHash(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
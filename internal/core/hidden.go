@@ -0,0 +1,62 @@
+// Package core provides hidden file handling for the FolderSynchronizer application.
+// With a pair's SkipHidden set, dotfiles and dot-directories (and, on Windows, entries
+// with the Hidden or System attribute) are left out of syncs and ignored by the watcher.
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== HIDDEN ENTRIES =====
+
+// isHidden reports whether the pair skips the entry at relativePath as hidden:
+// any element of the path starts with a dot, or info carries the platform's
+// hidden attribute. info may be nil when the entry no longer exists.
+func isHidden(pair *cfg.Pair, relativePath string, info os.FileInfo) bool {
+	if !pair.SkipHidden {
+		return false
+	}
+	if hasHiddenName(relativePath) {
+		return true
+	}
+	return info != nil && hasHiddenAttribute(info)
+}
+
+// isHiddenEntry is isHidden for an entry reported by a directory walk.
+func isHiddenEntry(pair *cfg.Pair, relativePath string, dirEntry fs.DirEntry) bool {
+	if !pair.SkipHidden {
+		return false
+	}
+	info, err := dirEntry.Info()
+	if err != nil {
+		info = nil
+	}
+	return isHidden(pair, relativePath, info)
+}
+
+// isHiddenPath is isHidden for a path on disk, reading its attributes.
+func isHiddenPath(pair *cfg.Pair, path, relativePath string) bool {
+	if !pair.SkipHidden {
+		return false
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		info = nil
+	}
+	return isHidden(pair, relativePath, info)
+}
+
+// hasHiddenName reports whether an element of a relative path is a dot name.
+func hasHiddenName(relativePath string) bool {
+	for _, element := range strings.Split(filepath.ToSlash(relativePath), "/") {
+		if len(element) > 1 && element != ".." && element[0] == '.' {
+			return true
+		}
+	}
+	return false
+}
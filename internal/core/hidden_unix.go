@@ -0,0 +1,12 @@
+//go:build !windows
+
+// Package core provides hidden file attribute detection for Unix platforms.
+package core
+
+import "os"
+
+// hasHiddenAttribute reports whether a file is hidden by attribute. Unix
+// platforms only hide dotfiles, which hasHiddenName already covers.
+func hasHiddenAttribute(info os.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+// Package core provides hidden file attribute detection for Windows.
+package core
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// hasHiddenAttribute reports whether a file has the Hidden or System attribute,
+// such as Thumbs.db or desktop.ini.
+func hasHiddenAttribute(info os.FileInfo) bool {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	return ok && data.FileAttributes&(windows.FILE_ATTRIBUTE_HIDDEN|windows.FILE_ATTRIBUTE_SYSTEM) != 0
+}
@@ -1,23 +1,28 @@
 // Package core provides hook execution functionality for the FolderSynchronizer application.
-// It handles HTTP webhooks and command execution after file synchronization events.
+// It handles HTTP webhooks, gRPC calls and command execution after file synchronization events.
 package core
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"text/template"
 	"time"
 
 	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+	"FolderSynchronizer/internal/metrics"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/rs/zerolog/log"
@@ -26,8 +31,12 @@ import (
 // ===== CONSTANTS AND CONFIGURATION =====
 
 const (
-	// HTTP client timeout for webhook requests
-	HTTPTimeout = 20 * time.Second
+	// Default per-attempt timeouts, overridden by Hook.TimeoutMs
+	HTTPTimeout    = 20 * time.Second
+	CommandTimeout = 5 * time.Minute
+
+	// Grace period for a killed command's output pipes to close
+	CommandWaitDelay = 2 * time.Second
 
 	// Maximum size limits for response/output reading
 	MaxSuccessResponseSize = 4096 // 4KB for successful HTTP responses
@@ -36,31 +45,103 @@ const (
 	MaxDisplaySnippetSize  = 1000 // 1KB for display snippets
 	MaxTruncationSize      = 2000 // 2KB before truncating error bodies
 
-	// Backoff configuration for HTTP retries
+	// Backoff configuration for HTTP and gRPC retries
 	RetryInitialInterval = 300 * time.Millisecond
-	RetryMaxElapsedTime  = 3 * time.Second
+	RetryMaxInterval     = 3 * time.Second
+
+	// Default limit of hooks executing at the same time across all pairs
+	DefaultMaxConcurrentHooks = 8
+
+	// Default number of hook executions kept per pair
+	DefaultHookHistorySize = 50
 )
 
-// Security: List of potentially dangerous commands to block
+// Security: Built-in list of potentially dangerous commands to block
 var dangerousCommands = []string{
 	"rm", "rmdir", "del", "erase", "format", "mkfs",
 	"shutdown", "reboot", "halt", "poweroff",
 	"dd", "fdisk", "parted",
 }
 
-// Security: List of dangerous command patterns to detect
+// Security: Built-in list of dangerous command patterns to detect
 var dangerousPatterns = []string{
 	" rm -rf", " rm -r ", " del /s", " :> ", " >/dev/sd",
 	"--force", "--recursive", "/f /s", "sudo rm",
 }
 
+// commandPolicy holds the command hook safety lists in effect
+type commandPolicy struct {
+	blocked  []string        // Blocked executable names (built-in plus configured)
+	patterns []string        // Blocked command line patterns (built-in plus configured)
+	allowed  map[string]bool // Executable names exempt from all checks
+}
+
+// activeCommandPolicy is the policy used by isCommandSafe
+var activeCommandPolicy atomic.Pointer[commandPolicy]
+
+func init() {
+	SetCommandPolicy(nil, nil, nil)
+}
+
+// SetCommandPolicy extends the built-in command hook blocklists with extra
+// executables and patterns, and exempts the allowed executables from all checks.
+// Names and patterns are matched case-insensitively.
+func SetCommandPolicy(blocked, patterns, allowed []string) {
+	policy := &commandPolicy{
+		blocked:  append(slices.Clone(dangerousCommands), lowerAll(blocked)...),
+		patterns: append(slices.Clone(dangerousPatterns), lowerAll(patterns)...),
+		allowed:  make(map[string]bool, len(allowed)),
+	}
+	for _, name := range lowerAll(allowed) {
+		policy.allowed[name] = true
+	}
+	activeCommandPolicy.Store(policy)
+}
+
+// lowerAll returns the trimmed, lower-cased non-empty entries of values
+func lowerAll(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// ===== HOOK CONCURRENCY LIMIT =====
+
+// hookLimiter bounds concurrent hook executions (HTTP, gRPC and command) across all pairs
+var hookLimiter atomic.Pointer[Semaphore]
+
+func init() {
+	hookLimiter.Store(NewSemaphore(DefaultMaxConcurrentHooks))
+}
+
+// SetMaxConcurrentHooks changes the global hook concurrency limit.
+// A value of zero or less removes the limit. Hooks already running keep
+// their slot in the previous limiter until they finish.
+func SetMaxConcurrentHooks(limit int) {
+	hookLimiter.Store(NewSemaphore(limit))
+	log.Info().Int("max_concurrent_hooks", limit).Msg("hook concurrency limit set")
+}
+
+// acquireHookSlot waits for a free hook execution slot and returns its release function.
+func acquireHookSlot(ctx context.Context) (func(), error) {
+	limiter := hookLimiter.Load()
+	if err := limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return limiter.Release, nil
+}
+
 // ===== HOOK STATUS TRACKING =====
 
 // HookStatus represents the execution status of a hook for UI display
 type HookStatus struct {
 	Timestamp time.Time `json:"timestamp"` // When the hook was executed
 	File      string    `json:"file"`      // File that triggered the hook
-	HookType  string    `json:"hookType"`  // Type of hook ("http" or "command")
+	HookType  string    `json:"hookType"`  // Type of hook ("http", "grpc" or "command")
 	Success   bool      `json:"success"`   // Whether execution was successful
 	Info      string    `json:"info"`      // Additional information or error details
 }
@@ -71,36 +152,135 @@ func (s HookStatus) String() string {
 	return string(data)
 }
 
+// hookRing is a bounded ring buffer of hook statuses
+type hookRing struct {
+	entries []HookStatus // Recorded statuses, at most the history size
+	next    int          // Slot overwritten by the next status once full
+}
+
+// add records a status, overwriting the oldest one when the ring is full
+func (r *hookRing) add(status HookStatus, size int) {
+	if len(r.entries) < size {
+		r.entries = append(r.entries, status)
+		return
+	}
+	r.entries[r.next] = status
+	r.next = (r.next + 1) % len(r.entries)
+}
+
+// latest returns the most recently recorded status
+func (r *hookRing) latest() HookStatus {
+	return r.entries[(r.next+len(r.entries)-1)%len(r.entries)]
+}
+
+// newestFirst returns the recorded statuses from newest to oldest
+func (r *hookRing) newestFirst() []HookStatus {
+	result := make([]HookStatus, 0, len(r.entries))
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		result = append(result, r.entries[(r.next+i)%len(r.entries)])
+	}
+	return result
+}
+
 // Global hook status tracking (thread-safe)
 var (
 	hookStatusMutex sync.Mutex
-	lastHookStatus  = make(map[string]HookStatus) // pairID -> latest hook status
+	hookHistory     = make(map[string]*hookRing) // pairID -> recent hook statuses
+	hookHistorySize = DefaultHookHistorySize     // Statuses kept per pair
 )
 
-// SetLastHookStatus records the latest hook execution status for a sync pair
+// SetHookHistorySize changes how many hook executions are kept per pair.
+// Values below 1 keep only the latest one. Existing histories keep their newest entries.
+func SetHookHistorySize(size int) {
+	size = max(size, 1)
+
+	hookStatusMutex.Lock()
+	defer hookStatusMutex.Unlock()
+
+	hookHistorySize = size
+	for pairID, ring := range hookHistory {
+		kept := ring.newestFirst()
+		kept = kept[:min(len(kept), size)]
+		resized := &hookRing{}
+		for i := len(kept) - 1; i >= 0; i-- {
+			resized.add(kept[i], size)
+		}
+		hookHistory[pairID] = resized
+	}
+}
+
+// SetLastHookStatus records a hook execution status in the pair's history
 func SetLastHookStatus(pairID string, status HookStatus) {
+	metrics.HookExecutions.WithLabelValues(pairID, status.HookType, metrics.ResultLabel(status.Success)).Inc()
+
+	message := status.HookType
+	if !status.Success {
+		info := status.Info
+		if len(info) > MaxDisplaySnippetSize {
+			info = info[:MaxDisplaySnippetSize] + "…"
+		}
+		message += ": " + info
+	}
+	recordOutcome(EventHook, pairID, status.File, status.Success, message)
+
 	hookStatusMutex.Lock()
 	defer hookStatusMutex.Unlock()
-	lastHookStatus[pairID] = status
+
+	ring, exists := hookHistory[pairID]
+	if !exists {
+		ring = &hookRing{}
+		hookHistory[pairID] = ring
+	}
+	ring.add(status, hookHistorySize)
 }
 
 // GetLastHookStatus retrieves the latest hook execution status for a sync pair
 func GetLastHookStatus(pairID string) (HookStatus, bool) {
 	hookStatusMutex.Lock()
 	defer hookStatusMutex.Unlock()
-	status, exists := lastHookStatus[pairID]
-	return status, exists
+
+	ring, exists := hookHistory[pairID]
+	if !exists {
+		return HookStatus{}, false
+	}
+	return ring.latest(), true
+}
+
+// GetHookHistory returns the recent hook executions of a sync pair, newest first
+func GetHookHistory(pairID string) []HookStatus {
+	hookStatusMutex.Lock()
+	defer hookStatusMutex.Unlock()
+
+	ring, exists := hookHistory[pairID]
+	if !exists {
+		return []HookStatus{}
+	}
+	return ring.newestFirst()
 }
 
 // ===== TEMPLATE DATA STRUCTURES =====
 
-// hookTemplateData contains variables available for hook templates
+// hookTemplateData contains variables available for hook templates.
+// Per-file hooks fill the file fields; batch hooks (Pair.HookBatch) leave them
+// empty and list every synced file in Files instead.
 type hookTemplateData struct {
-	RelPath    string // Relative path of the synchronized file
-	Basename   string // Base filename without directory
-	SourcePath string // Full path in source directory
-	TargetPath string // Full path in target directory
-	Timestamp  string // Current timestamp for the hook execution
+	RelPath    string   // Relative path of the synchronized file
+	Basename   string   // Base filename without directory
+	SourcePath string   // Full path in source directory
+	TargetPath string   // Full path in target directory
+	Timestamp  string   // Current timestamp for the hook execution
+	Files      []string // Batch mode: relative paths of all synced files matching the hook
+	Count      int      // Number of files the hook fires for (1 per file, len(Files) in batch mode)
+	Size       int64    // Target file size in bytes (only filled when a template uses it)
+	Checksum   string   // Target file SHA256 in hex (only hashed when a template uses it)
+}
+
+// label identifies the files a hook fired for in logs and hook statuses
+func (d hookTemplateData) label() string {
+	if d.Files != nil {
+		return fmt.Sprintf("%d files", len(d.Files))
+	}
+	return d.RelPath
 }
 
 // ===== SECURITY VALIDATION =====
@@ -108,10 +288,16 @@ type hookTemplateData struct {
 // isCommandSafe performs security validation on command hooks to prevent
 // execution of potentially dangerous operations
 func isCommandSafe(executable string, args []string) bool {
+	policy := activeCommandPolicy.Load()
 	executableBase := strings.ToLower(filepath.Base(executable))
 
+	// Allowlisted executables are trusted with any arguments
+	if policy.allowed[executableBase] || policy.allowed[strings.TrimSuffix(executableBase, filepath.Ext(executableBase))] {
+		return true
+	}
+
 	// Check against known dangerous commands
-	for _, dangerousCmd := range dangerousCommands {
+	for _, dangerousCmd := range policy.blocked {
 		if executableBase == dangerousCmd || strings.HasPrefix(executableBase, dangerousCmd+".") {
 			return false
 		}
@@ -119,7 +305,7 @@ func isCommandSafe(executable string, args []string) bool {
 
 	// Check full command line against dangerous patterns
 	fullCommand := strings.ToLower(executable + " " + strings.Join(args, " "))
-	for _, pattern := range dangerousPatterns {
+	for _, pattern := range policy.patterns {
 		if strings.Contains(fullCommand, pattern) {
 			return false
 		}
@@ -186,6 +372,10 @@ func detectHookType(hook *cfg.Hook) string {
 		return "command"
 	}
 
+	if hook.GRPC != nil && strings.TrimSpace(hook.GRPC.Target) != "" {
+		return "grpc"
+	}
+
 	return "unknown"
 }
 
@@ -193,7 +383,7 @@ func detectHookType(hook *cfg.Hook) string {
 
 // RunHooks executes all configured hooks for a sync pair when a file is synchronized.
 // It filters hooks based on file extensions and glob patterns, then executes
-// appropriate HTTP or command hooks with template substitution.
+// appropriate HTTP, gRPC or command hooks with template substitution.
 func RunHooks(ctx context.Context, pair *cfg.Pair, relPath string) {
 	if len(pair.Hooks) == 0 {
 		return
@@ -203,67 +393,196 @@ func RunHooks(ctx context.Context, pair *cfg.Pair, relPath string) {
 	templateData := hookTemplateData{
 		RelPath:    relPath,
 		Basename:   filepath.Base(relPath),
-		SourcePath: filepath.Join(pair.Source, relPath),
-		TargetPath: filepath.Join(pair.Target, relPath),
+		SourcePath: sourcePathFor(pair, relPath),
+		TargetPath: targetPathFor(pair, relPath),
 		Timestamp:  time.Now().Format(time.RFC3339),
+		Count:      1,
 	}
 
 	// Execute each configured hook
+	facts := targetFileFacts{path: templateData.TargetPath}
+	for i := range pair.Hooks {
+		hook := &pair.Hooks[i]
+
+		// Apply hook filtering based on file extensions and globs, or on the sentinel glob
+		if hook.TriggerOnlyForGlob != "" {
+			if !MatchesAnyGlob([]string{hook.TriggerOnlyForGlob}, relPath, pair.CaseSensitive) {
+				continue
+			}
+		} else if !shouldTriggerHook(hook, relPath, pair.CaseSensitive) {
+			continue
+		}
+
+		// Size and checksum cost a stat and a full read, so only templates using them pay
+		data := templateData
+		templates := hookTemplateText(hook)
+		if strings.Contains(templates, ".Size") {
+			data.Size = facts.size(pair.ID)
+		}
+		if strings.Contains(templates, ".Checksum") {
+			data.Checksum = facts.checksum(pair.ID)
+		}
+
+		runHook(ctx, pair, hook, data)
+	}
+}
+
+// targetFileFacts lazily computes and caches a target file's size and hash
+// for the hooks fired by one RunHooks call
+type targetFileFacts struct {
+	path      string // Target file path
+	sizeValue int64  // Cached size
+	sizeDone  bool   // Whether the size was looked up
+	hashValue string // Cached SHA256 checksum
+	hashDone  bool   // Whether the file was hashed
+}
+
+// size returns the target file size, or 0 when it can't be determined
+func (f *targetFileFacts) size(pairID string) int64 {
+	if !f.sizeDone {
+		f.sizeDone = true
+		if info, err := os.Stat(f.path); err == nil {
+			f.sizeValue = info.Size()
+		} else {
+			logging.ForPair(pairID).Warn().Str("pair", pairID).Str("file", f.path).Err(err).Msg("hook template size unavailable")
+		}
+	}
+	return f.sizeValue
+}
+
+// hookFileHash hashes a target file for hook templates; replaceable for testing.
+var hookFileHash = calculateFileHash
+
+// checksum returns the target file SHA256, or "" when it can't be computed
+func (f *targetFileFacts) checksum(pairID string) string {
+	if !f.hashDone {
+		f.hashDone = true
+		if hash, err := hookFileHash(f.path); err == nil {
+			f.hashValue = hash
+		} else {
+			logging.ForPair(pairID).Warn().Str("pair", pairID).Str("file", f.path).Err(err).Msg("hook template checksum unavailable")
+		}
+	}
+	return f.hashValue
+}
+
+// hookTemplateText joins every template of a hook, for detecting which variables it uses
+func hookTemplateText(hook *cfg.Hook) string {
+	var parts []string
+	if hook.HTTP != nil {
+		parts = append(parts, hook.HTTP.BodyTemplate)
+	}
+	if hook.Command != nil {
+		parts = append(parts, hook.Command.StdinTemplate)
+		parts = append(parts, hook.Command.Args...)
+	}
+	if hook.GRPC != nil {
+		parts = append(parts, hook.GRPC.BodyTemplate)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// RunBatchHooks fires each configured hook of a pair once for all files synced
+// by a run (Pair.HookBatch). A hook only sees the files matching its filters in
+// {{.Files}} and is skipped when none match, or when it has a TriggerOnlyForGlob
+// that no synced file matches.
+func RunBatchHooks(ctx context.Context, pair *cfg.Pair, relPaths []string) {
+	if len(pair.Hooks) == 0 || len(relPaths) == 0 {
+		return
+	}
+
+	timestamp := time.Now().Format(time.RFC3339)
 	for i := range pair.Hooks {
 		hook := &pair.Hooks[i]
 
-		// Apply hook filtering based on file extensions and globs
-		if !shouldTriggerHook(hook, relPath) {
+		// Wait for the sentinel file before firing
+		if hook.TriggerOnlyForGlob != "" && !slices.ContainsFunc(relPaths, func(relPath string) bool {
+			return MatchesAnyGlob([]string{hook.TriggerOnlyForGlob}, relPath, pair.CaseSensitive)
+		}) {
 			continue
 		}
 
-		// Execute hook based on its type
-		switch detectHookType(hook) {
-		case "http":
-			executeHTTPHook(ctx, pair.ID, hook, templateData)
-		case "command":
-			executeCommandHook(ctx, pair.ID, hook, templateData)
-		default:
-			log.Warn().
-				Str("pair", pair.ID).
-				Str("file", relPath).
-				Msg("unknown hook type")
-
-			SetLastHookStatus(pair.ID, HookStatus{
-				Timestamp: time.Now(),
-				File:      relPath,
-				HookType:  "unknown",
-				Success:   false,
-				Info:      "unknown hook type",
-			})
+		files := make([]string, 0, len(relPaths))
+		for _, relPath := range relPaths {
+			if shouldTriggerHook(hook, relPath, pair.CaseSensitive) {
+				files = append(files, relPath)
+			}
+		}
+		if len(files) == 0 {
+			continue
 		}
+
+		runHook(ctx, pair, hook, hookTemplateData{
+			Timestamp: timestamp,
+			Files:     files,
+			Count:     len(files),
+		})
 	}
 }
 
+// runHook executes a single hook based on its type and records its status
+func runHook(ctx context.Context, pair *cfg.Pair, hook *cfg.Hook, data hookTemplateData) {
+	var status HookStatus
+	switch detectHookType(hook) {
+	case "http":
+		status = executeHTTPHook(ctx, pair.ID, hook, hookRetries(pair, hook), data)
+	case "command":
+		status = executeCommandHook(ctx, pair.ID, hook, pair.AllowUnsafeCommands, data)
+	case "grpc":
+		status = executeGRPCHook(ctx, pair.ID, hook, hookRetries(pair, hook), data)
+	default:
+		logging.ForPair(pair.ID).Warn().
+			Str("pair", pair.ID).
+			Str("file", data.label()).
+			Msg("unknown hook type")
+
+		status = hookFailure(data, "unknown", "unknown hook type")
+	}
+	SetLastHookStatus(pair.ID, status)
+}
+
 // shouldTriggerHook determines if a hook should be executed for the given file
-func shouldTriggerHook(hook *cfg.Hook, filePath string) bool {
+func shouldTriggerHook(hook *cfg.Hook, filePath string, caseSensitive bool) bool {
 	// If no filters are specified, trigger for all files
 	if len(hook.MatchExtensions) == 0 && len(hook.MatchGlobs) == 0 {
 		return true
 	}
 
 	// Check extension match using the corrected function name
-	if len(hook.MatchExtensions) > 0 && MatchesInclude(hook.MatchExtensions, filePath) {
+	if len(hook.MatchExtensions) > 0 && MatchesInclude(hook.MatchExtensions, filePath, caseSensitive) {
 		return true
 	}
 
 	// Check glob pattern match
-	if len(hook.MatchGlobs) > 0 && MatchesAnyGlob(hook.MatchGlobs, filePath) {
+	if len(hook.MatchGlobs) > 0 && MatchesAnyGlob(hook.MatchGlobs, filePath, caseSensitive) {
 		return true
 	}
 
 	return false
 }
 
+// hookTimeout returns the per-attempt timeout of a hook, or fallback when unset
+func hookTimeout(hook *cfg.Hook, fallback time.Duration) time.Duration {
+	if hook.TimeoutMs > 0 {
+		return time.Duration(hook.TimeoutMs) * time.Millisecond
+	}
+	return fallback
+}
+
+// hookRetries returns the retry count of a hook, defaulting to the pair's HookMaxRetries
+func hookRetries(pair *cfg.Pair, hook *cfg.Hook) int {
+	if hook.MaxRetries > 0 {
+		return hook.MaxRetries
+	}
+	return pair.HookMaxRetries
+}
+
 // ===== HTTP HOOK EXECUTION =====
 
-// executeHTTPHook executes an HTTP webhook with retry logic and proper error handling
-func executeHTTPHook(ctx context.Context, pairID string, hook *cfg.Hook, data hookTemplateData) {
+// executeHTTPHook executes an HTTP webhook with retry logic and returns its status.
+// Each attempt builds a fresh request and holds a hook slot only while it runs,
+// so backoff waits don't block other hooks.
+func executeHTTPHook(ctx context.Context, pairID string, hook *cfg.Hook, retries int, data hookTemplateData) HookStatus {
 	startTime := time.Now()
 
 	// Validate and prepare HTTP method
@@ -275,53 +594,61 @@ func executeHTTPHook(ctx context.Context, pairID string, hook *cfg.Hook, data ho
 	// Validate URL
 	url := strings.TrimSpace(hook.HTTP.URL)
 	if url == "" {
-		setHookFailure(pairID, data, "http", "empty URL")
-		return
+		return hookFailure(data, "http", "empty URL")
 	}
 
 	// Process body template
 	bodyText, err := executeTemplate(hook.HTTP.BodyTemplate, data)
 	if err != nil {
-		setHookFailure(pairID, data, "http", "template error: "+err.Error())
-		return
-	}
-
-	// Prepare request body
-	var bodyReader io.Reader
-	if strings.EqualFold(method, http.MethodGet) || bodyText == "" {
-		bodyReader = nil // No body for GET requests or empty body
-	} else {
-		bodyReader = strings.NewReader(bodyText)
-	}
-
-	// Create HTTP request
-	request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		setHookFailure(pairID, data, "http", "request creation error: "+err.Error())
-		return
+		return hookFailure(data, "http", "template error: "+err.Error())
 	}
 
-	// Set headers
-	setHTTPHeaders(request, hook.HTTP.Headers, bodyReader != nil)
+	// No body for GET requests or an empty body
+	hasBody := !strings.EqualFold(method, http.MethodGet) && bodyText != ""
 
 	// Execute with retry logic
-	client := &http.Client{Timeout: HTTPTimeout}
+	client := &http.Client{Timeout: hookTimeout(hook, HTTPTimeout)}
 
+	var info string
 	operation := func() error {
-		return executeHTTPRequest(client, request, pairID, data, startTime)
+		release, err := acquireHookSlot(ctx)
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("cancelled while waiting for a hook slot: %w", err))
+		}
+		defer release()
+
+		var bodyReader io.Reader
+		if hasBody {
+			bodyReader = strings.NewReader(bodyText)
+		}
+		request, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("request creation error: %w", err))
+		}
+		setHTTPHeaders(request, hook.HTTP.Headers, hasBody)
+
+		info, err = executeHTTPRequest(client, request, startTime)
+		return err
 	}
 
-	backoffStrategy := createBackoffStrategy(ctx)
+	backoffStrategy := createBackoffStrategy(ctx, retries)
 	if err := backoff.Retry(operation, backoffStrategy); err != nil {
-		setHookFailure(pairID, data, "http", err.Error())
-		return
+		return hookFailure(data, "http", err.Error())
 	}
 
-	log.Info().
+	logging.ForPair(pairID).Info().
 		Str("pair", pairID).
-		Str("file", data.RelPath).
+		Str("file", data.label()).
 		Dur("duration", time.Since(startTime)).
 		Msg("http hook success")
+
+	return HookStatus{
+		Timestamp: time.Now(),
+		File:      data.label(),
+		HookType:  "http",
+		Success:   true,
+		Info:      info,
+	}
 }
 
 // setHTTPHeaders configures HTTP headers for webhook requests
@@ -349,11 +676,12 @@ func setHTTPHeaders(request *http.Request, headers map[string]string, hasBody bo
 	}
 }
 
-// executeHTTPRequest performs the actual HTTP request with proper response handling
-func executeHTTPRequest(client *http.Client, request *http.Request, pairID string, data hookTemplateData, startTime time.Time) error {
+// executeHTTPRequest performs the actual HTTP request and returns the status
+// info of a successful response
+func executeHTTPRequest(client *http.Client, request *http.Request, startTime time.Time) (string, error) {
 	response, err := client.Do(request)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer response.Body.Close()
 
@@ -374,62 +702,77 @@ func executeHTTPRequest(client *http.Client, request *http.Request, pairID strin
 		if snippet != "" {
 			info += " — " + snippet
 		}
-
-		SetLastHookStatus(pairID, HookStatus{
-			Timestamp: time.Now(),
-			File:      data.RelPath,
-			HookType:  "http",
-			Success:   true,
-			Info:      info,
-		})
-
-		return nil
+		return info, nil
 	}
 
 	// Handle error responses
 	bodyBytes, _ := io.ReadAll(io.LimitReader(response.Body, MaxErrorResponseSize))
-	return &httpStatusError{
+	return "", &httpStatusError{
 		Code: response.StatusCode,
 		Body: string(bodyBytes),
 	}
 }
 
-// createBackoffStrategy creates an exponential backoff strategy for HTTP retries
-func createBackoffStrategy(ctx context.Context) backoff.BackOffContext {
+// createBackoffStrategy creates an exponential backoff strategy allowing the given
+// number of retries after the first attempt
+func createBackoffStrategy(ctx context.Context, retries int) backoff.BackOffContext {
 	exponentialBackoff := backoff.NewExponentialBackOff()
 	exponentialBackoff.InitialInterval = RetryInitialInterval
-	exponentialBackoff.MaxElapsedTime = RetryMaxElapsedTime
-	return backoff.WithContext(exponentialBackoff, ctx)
+	exponentialBackoff.MaxInterval = RetryMaxInterval
+	exponentialBackoff.MaxElapsedTime = 0 // Bounded by the retry count instead
+	return backoff.WithContext(backoff.WithMaxRetries(exponentialBackoff, uint64(max(retries, 0))), ctx)
 }
 
 // ===== COMMAND HOOK EXECUTION =====
 
-// executeCommandHook executes a command hook with security validation and proper error handling
-func executeCommandHook(ctx context.Context, pairID string, hook *cfg.Hook, data hookTemplateData) {
+// executeCommandHook executes a command hook with security validation and returns its status.
+// With allowUnsafe the safety checks are skipped (Pair.AllowUnsafeCommands).
+func executeCommandHook(ctx context.Context, pairID string, hook *cfg.Hook, allowUnsafe bool, data hookTemplateData) HookStatus {
+	release, err := acquireHookSlot(ctx)
+	if err != nil {
+		return hookFailure(data, "command", "cancelled while waiting for a hook slot: "+err.Error())
+	}
+	defer release()
+
 	startTime := time.Now()
 
 	// Validate command configuration
 	if hook.Command == nil || strings.TrimSpace(hook.Command.Executable) == "" {
-		setHookFailure(pairID, data, "command", "empty command")
-		return
+		return hookFailure(data, "command", "empty command")
 	}
 
 	// Process argument templates
 	args, err := processCommandArguments(hook.Command.Args, data)
 	if err != nil {
-		setHookFailure(pairID, data, "command", "template error: "+err.Error())
-		return
+		return hookFailure(data, "command", "template error: "+err.Error())
 	}
 
-	// Security validation
+	// Process stdin template
+	stdinText, err := executeTemplate(hook.Command.StdinTemplate, data)
+	if err != nil {
+		return hookFailure(data, "command", "stdin template error: "+err.Error())
+	}
+
+	// Security validation, bypassed only for trusted pairs
 	if !isCommandSafe(hook.Command.Executable, args) {
-		setHookFailure(pairID, data, "command", "command rejected by safety checks")
-		return
+		if !allowUnsafe {
+			return hookFailure(data, "command", "command rejected by safety checks")
+		}
+		logging.ForPair(pairID).Warn().
+			Str("pair", pairID).
+			Str("executable", hook.Command.Executable).
+			Strs("args", args).
+			Msg("UNSAFE COMMAND: running command hook that fails safety checks (allowUnsafeCommands is set)")
 	}
 
-	// Create and configure command
-	cmd := exec.CommandContext(ctx, hook.Command.Executable, args...)
-	configureCommand(cmd, hook.Command)
+	// Create and configure command; it is killed when the timeout expires
+	timeout := hookTimeout(hook, CommandTimeout)
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, hook.Command.Executable, args...)
+	cmd.WaitDelay = CommandWaitDelay
+	configureCommand(cmd, hook.Command, stdinText)
 
 	// Execute command
 	output, err := cmd.CombinedOutput()
@@ -437,11 +780,15 @@ func executeCommandHook(ctx context.Context, pairID string, hook *cfg.Hook, data
 	if len(outputStr) > MaxCommandOutputSize {
 		outputStr = outputStr[:MaxCommandOutputSize] + "…"
 	}
+	if err != nil && errors.Is(cmdCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("command timed out after %s: %w", timeout, err)
+		outputStr = strings.TrimSpace(err.Error() + "\n" + outputStr)
+	}
 
 	if err != nil {
-		log.Error().
+		logging.ForPair(pairID).Error().
 			Str("pair", pairID).
-			Str("file", data.RelPath).
+			Str("file", data.label()).
 			Str("type", "command").
 			Dur("duration", time.Since(startTime)).
 			Str("output", outputStr).
@@ -453,25 +800,24 @@ func executeCommandHook(ctx context.Context, pairID string, hook *cfg.Hook, data
 			errorMsg = err.Error()
 		}
 
-		setHookFailure(pairID, data, "command", errorMsg)
-		return
+		return hookFailure(data, "command", errorMsg)
 	}
 
 	// Success
-	log.Info().
+	logging.ForPair(pairID).Info().
 		Str("pair", pairID).
-		Str("file", data.RelPath).
+		Str("file", data.label()).
 		Str("type", "command").
 		Dur("duration", time.Since(startTime)).
 		Msg("command hook success")
 
-	SetLastHookStatus(pairID, HookStatus{
+	return HookStatus{
 		Timestamp: time.Now(),
-		File:      data.RelPath,
+		File:      data.label(),
 		HookType:  "command",
 		Success:   true,
 		Info:      outputStr,
-	})
+	}
 }
 
 // processCommandArguments processes template variables in command arguments
@@ -489,8 +835,8 @@ func processCommandArguments(args []string, data hookTemplateData) ([]string, er
 	return processedArgs, nil
 }
 
-// configureCommand sets up working directory and environment variables for command execution
-func configureCommand(cmd *exec.Cmd, config *cfg.CommandHook) {
+// configureCommand sets up working directory, environment variables and stdin for command execution
+func configureCommand(cmd *exec.Cmd, config *cfg.CommandHook, stdinText string) {
 	// Set working directory if specified
 	if workDir := strings.TrimSpace(config.WorkDir); workDir != "" {
 		cmd.Dir = workDir
@@ -501,17 +847,22 @@ func configureCommand(cmd *exec.Cmd, config *cfg.CommandHook) {
 	for key, value := range config.EnvVars {
 		cmd.Env = append(cmd.Env, key+"="+value)
 	}
+
+	// Pipe the rendered stdin template; without one the command gets no input
+	if config.StdinTemplate != "" {
+		cmd.Stdin = strings.NewReader(stdinText)
+	}
 }
 
 // ===== UTILITY FUNCTIONS =====
 
-// setHookFailure is a convenience function for recording hook failures
-func setHookFailure(pairID string, data hookTemplateData, hookType, errorMsg string) {
-	SetLastHookStatus(pairID, HookStatus{
+// hookFailure returns the status of a failed hook execution
+func hookFailure(data hookTemplateData, hookType, errorMsg string) HookStatus {
+	return HookStatus{
 		Timestamp: time.Now(),
-		File:      data.RelPath,
+		File:      data.label(),
 		HookType:  hookType,
 		Success:   false,
 		Info:      errorMsg,
-	})
+	}
 }
@@ -0,0 +1,399 @@
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== TEST HELPERS =====
+
+// hookServer is an HTTP hook endpoint failing its first failures requests with
+// 500 and recording the body of every request
+type hookServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	bodies   []string
+	failures int
+}
+
+// newHookServer starts a hook endpoint closed when the test ends
+func newHookServer(t *testing.T, failures int) *hookServer {
+	t.Helper()
+	server := &hookServer{failures: failures}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		server.mutex.Lock()
+		server.bodies = append(server.bodies, string(body))
+		fail := len(server.bodies) <= server.failures
+		server.mutex.Unlock()
+		if fail {
+			http.Error(w, "try again", http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// received returns the bodies received so far
+func (s *hookServer) received() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+// ===== HOOK CONCURRENCY LIMIT =====
+
+func TestHookConcurrencyLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		hooks int
+		want  int32 // expected peak of concurrent requests
+	}{
+		{"capped", 3, 12, 3},
+		{"single slot", 1, 5, 1},
+		{"unlimited", 0, 6, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxConcurrentHooks(tt.limit)
+			t.Cleanup(func() { SetMaxConcurrentHooks(DefaultMaxConcurrentHooks) })
+
+			var inFlight, peak atomic.Int32
+			arrived := make(chan struct{}, tt.hooks)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					previous := peak.Load()
+					if current <= previous || peak.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+				arrived <- struct{}{}
+				time.Sleep(100 * time.Millisecond)
+			}))
+			t.Cleanup(server.Close)
+
+			hook := &cfg.Hook{HTTP: &cfg.HTTPHook{URL: server.URL}}
+			var wg sync.WaitGroup
+			for i := 0; i < tt.hooks; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					executeHTTPHook(context.Background(), t.Name(), hook, 0, hookTemplateData{RelPath: "app.jar"})
+				}()
+			}
+			wg.Wait()
+
+			if len(arrived) != tt.hooks {
+				t.Fatalf("%d of %d hooks ran", len(arrived), tt.hooks)
+			}
+			if got := peak.Load(); got != tt.want {
+				t.Errorf("peak concurrent hooks = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// ===== SENTINEL-TRIGGERED BATCH HOOKS =====
+
+func TestBatchHookWaitsForSentinel(t *testing.T) {
+	server := newHookServer(t, 0)
+	pair := newTestPair(t)
+	pair.HookBatch = true
+	pair.Hooks = []cfg.Hook{{
+		HTTP:               &cfg.HTTPHook{URL: server.URL, BodyTemplate: `{{.Count}}:{{range .Files}}{{.}};{{end}}`},
+		TriggerOnlyForGlob: "**/MANIFEST",
+	}}
+
+	steps := []struct {
+		name  string
+		files []string
+		want  []string // bodies received so far
+	}{
+		{"batch without the marker", []string{"a.jar", "b.jar"}, nil},
+		{"marker completes the build", []string{"c.jar", "MANIFEST"}, []string{"2:MANIFEST;c.jar;"}},
+		{"later batch without the marker", []string{"d.jar"}, []string{"2:MANIFEST;c.jar;"}},
+	}
+	for _, step := range steps {
+		for _, name := range step.files {
+			writeTestFile(t, filepath.Join(pair.Source, name), name)
+		}
+		if _, err := syncTestPair(t, pair); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := server.received(); !slices.Equal(got, step.want) {
+			t.Errorf("%s: hook bodies = %q, want %q", step.name, got, step.want)
+		}
+	}
+}
+
+func TestRunBatchHooksSentinel(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentinel  string
+		files     []string
+		wantFired bool
+	}{
+		{"no sentinel fires", "", []string{"a.jar"}, true},
+		{"sentinel present", "**/MANIFEST", []string{"a.jar", "build/MANIFEST"}, true},
+		{"sentinel absent", "**/MANIFEST", []string{"a.jar", "b.jar"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newHookServer(t, 0)
+			pair := newTestPair(t)
+			pair.Hooks = []cfg.Hook{{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: "{{.Count}}"}, TriggerOnlyForGlob: tt.sentinel}}
+
+			RunBatchHooks(context.Background(), pair, tt.files)
+
+			if fired := len(server.received()) == 1; fired != tt.wantFired {
+				t.Errorf("hook fired = %v, want %v", fired, tt.wantFired)
+			}
+		})
+	}
+}
+
+// ===== HOOK TIMEOUTS AND RETRIES =====
+
+func TestHookTimeoutAndRetries(t *testing.T) {
+	tests := []struct {
+		name        string
+		hook        cfg.Hook
+		pairRetries int
+		wantTimeout time.Duration
+		wantRetries int
+	}{
+		{"defaults", cfg.Hook{}, 3, HTTPTimeout, 3},
+		{"hook overrides", cfg.Hook{TimeoutMs: 1500, MaxRetries: 5}, 3, 1500 * time.Millisecond, 5},
+		{"pair retries disabled", cfg.Hook{}, 0, HTTPTimeout, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := &cfg.Pair{HookMaxRetries: tt.pairRetries}
+			if got := hookTimeout(&tt.hook, HTTPTimeout); got != tt.wantTimeout {
+				t.Errorf("hookTimeout() = %s, want %s", got, tt.wantTimeout)
+			}
+			if got := hookRetries(pair, &tt.hook); got != tt.wantRetries {
+				t.Errorf("hookRetries() = %d, want %d", got, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestHTTPHookTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(unblock) })
+
+	hook := &cfg.Hook{HTTP: &cfg.HTTPHook{URL: server.URL}, TimeoutMs: 100}
+	started := time.Now()
+	status := executeHTTPHook(context.Background(), t.Name(), hook, 1, hookTemplateData{RelPath: "app.jar"})
+
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("hook took %s despite the 100ms timeout", elapsed)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("hook made %d attempts, want 2", got)
+	}
+	if status.Success {
+		t.Error("timed out hook reported as successful")
+	}
+}
+
+// ===== COMMAND POLICY =====
+
+func TestIsCommandSafe(t *testing.T) {
+	tests := []struct {
+		name       string
+		blocked    []string
+		patterns   []string
+		allowed    []string
+		executable string
+		args       []string
+		want       bool
+	}{
+		{"plain command", nil, nil, nil, "echo", []string{"hi"}, true},
+		{"built-in blocked executable", nil, nil, nil, "/bin/rm", []string{"file"}, false},
+		{"blocked with extension", nil, nil, nil, "format.com", nil, false},
+		{"built-in pattern", nil, nil, nil, "git", []string{"push", "--force"}, false},
+		{"configured executable", []string{"Curl"}, nil, nil, "curl", []string{"http://x"}, false},
+		{"configured pattern", nil, []string{"drop table"}, nil, "psql", []string{"-c", "DROP TABLE x"}, false},
+		{"allowlisted built-in", nil, nil, []string{"rm"}, "/usr/bin/rm", []string{"-rf", "build"}, true},
+		{"allowlist ignores extension", nil, nil, []string{"cleanup"}, "cleanup.exe", []string{"--recursive"}, true},
+		{"allowlist is per executable", nil, nil, []string{"rm"}, "dd", []string{"if=x"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetCommandPolicy(tt.blocked, tt.patterns, tt.allowed)
+			t.Cleanup(func() { SetCommandPolicy(nil, nil, nil) })
+
+			if got := isCommandSafe(tt.executable, tt.args); got != tt.want {
+				t.Errorf("isCommandSafe(%q, %q) = %v, want %v", tt.executable, tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+// ===== TEMPLATE SIZE AND CHECKSUM =====
+
+func TestHookTemplateSizeAndChecksum(t *testing.T) {
+	tests := []struct {
+		name       string
+		template   string
+		want       string
+		wantHashes int32
+	}{
+		{"size and checksum", `{{.Size}} {{.Checksum}}`, "11 b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", 1},
+		{"size only", `{{.Size}}`, "11", 0},
+		{"unrelated template", `{{.RelPath}}`, "app.jar", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hashes atomic.Int32
+			previous := hookFileHash
+			hookFileHash = func(path string) (string, error) {
+				hashes.Add(1)
+				return previous(path)
+			}
+			t.Cleanup(func() { hookFileHash = previous })
+
+			server := newHookServer(t, 0)
+			pair := newTestPair(t)
+			writeTestFile(t, filepath.Join(pair.Target, "app.jar"), "hello world")
+			// Two hooks using the checksum share a single hash of the file
+			pair.Hooks = []cfg.Hook{
+				{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: tt.template}},
+				{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: tt.template}},
+			}
+
+			RunHooks(context.Background(), pair, "app.jar")
+
+			bodies := server.received()
+			if len(bodies) != 2 || bodies[0] != tt.want || bodies[1] != tt.want {
+				t.Errorf("hook bodies = %q, want %q twice", bodies, tt.want)
+			}
+			if got := hashes.Load(); got != tt.wantHashes {
+				t.Errorf("target hashed %d times, want %d", got, tt.wantHashes)
+			}
+		})
+	}
+}
+
+// ===== HTTP HOOK RETRIES =====
+
+func TestHTTPHookRetriesResendBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    int
+		retries     int
+		wantSuccess bool
+	}{
+		{"first attempt succeeds", 0, 2, true},
+		{"retry succeeds", 1, 2, true},
+		{"retries exhausted", 3, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newHookServer(t, tt.failures)
+			hook := &cfg.Hook{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: `{"file":"{{.RelPath}}"}`}}
+			pairID := t.Name()
+
+			status := executeHTTPHook(context.Background(), pairID, hook, tt.retries, hookTemplateData{RelPath: "app.jar"})
+
+			bodies := server.received()
+			wantAttempts := min(tt.failures+1, tt.retries+1)
+			if len(bodies) != wantAttempts {
+				t.Fatalf("hook made %d attempts, want %d", len(bodies), wantAttempts)
+			}
+			for i, body := range bodies {
+				if body != `{"file":"app.jar"}` {
+					t.Errorf("attempt %d sent body %q", i+1, body)
+				}
+			}
+			if status.Success != tt.wantSuccess {
+				t.Errorf("hook success = %v, want %v (%s)", status.Success, tt.wantSuccess, status.Info)
+			}
+		})
+	}
+}
+
+func TestHTTPHookReleasesSlotWhileBackingOff(t *testing.T) {
+	SetMaxConcurrentHooks(1)
+	t.Cleanup(func() { SetMaxConcurrentHooks(DefaultMaxConcurrentHooks) })
+
+	server := newHookServer(t, 1)
+	hook := &cfg.Hook{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: "{}"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		executeHTTPHook(context.Background(), t.Name(), hook, 1, hookTemplateData{RelPath: "app.jar"})
+	}()
+
+	waitFor(t, 5*time.Second, "the first attempt", func() bool { return len(server.received()) == 1 })
+
+	// The backoff before the retry lasts at least half of RetryInitialInterval
+	ctx, cancel := context.WithTimeout(context.Background(), RetryInitialInterval/3)
+	defer cancel()
+	release, err := acquireHookSlot(ctx)
+	if err != nil {
+		t.Fatalf("hook slot still held while backing off: %v", err)
+	}
+	release()
+
+	<-done
+	if got := len(server.received()); got != 2 {
+		t.Errorf("hook made %d attempts, want 2", got)
+	}
+}
+
+// ===== HOOK TEST RUNS =====
+
+func TestHookTestRunReturnsStatusWithoutHistory(t *testing.T) {
+	server := newHookServer(t, 0)
+	hook := &cfg.Hook{HTTP: &cfg.HTTPHook{URL: server.URL, BodyTemplate: `{"file":"{{.RelPath}}"}`}}
+
+	status, err := TestHook(context.Background(), hook, HookTestValues{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Success || status.HookType != "http" || status.File != DefaultHookTestFile {
+		t.Errorf("status = %+v, want a successful http run for %s", status, DefaultHookTestFile)
+	}
+	if bodies := server.received(); len(bodies) != 1 || bodies[0] != `{"file":"test-file.jar"}` {
+		t.Errorf("hook received %q", bodies)
+	}
+	hookStatusMutex.Lock()
+	defer hookStatusMutex.Unlock()
+	for pairID := range hookHistory {
+		if strings.HasPrefix(pairID, hookTestIDPrefix) {
+			t.Errorf("test run recorded in the hook history of %s", pairID)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+//go:build !windows
+
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== COMMAND HOOK TIMEOUTS =====
+
+func TestCommandHookTimeout(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		timeoutMs   int
+		wantSuccess bool
+	}{
+		{"killed after timeout", []string{"10"}, 200, false},
+		{"finishes in time", []string{"0"}, 5000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &cfg.Hook{Command: &cfg.CommandHook{Executable: "sleep", Args: tt.args}, TimeoutMs: tt.timeoutMs}
+			pairID := strings.ReplaceAll(t.Name(), "/", "_")
+
+			started := time.Now()
+			status := executeCommandHook(context.Background(), pairID, hook, true, hookTemplateData{RelPath: "app.jar"})
+			if elapsed := time.Since(started); elapsed > 5*time.Second {
+				t.Errorf("command ran for %s", elapsed)
+			}
+
+			if status.Success != tt.wantSuccess {
+				t.Fatalf("hook status = %+v, want success %v", status, tt.wantSuccess)
+			}
+			if !tt.wantSuccess && !strings.Contains(status.Info, "timed out") {
+				t.Errorf("failure info %q doesn't mention the timeout", status.Info)
+			}
+		})
+	}
+}
+
+// ===== COMMAND HOOK STDIN =====
+
+func TestCommandHookStdinTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"rendered payload", `{"file":"{{.RelPath}}"}`, `{"file":"app.jar"}`},
+		{"no template gives no input", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := &cfg.Hook{Command: &cfg.CommandHook{Executable: "cat", StdinTemplate: tt.template}}
+			pairID := strings.ReplaceAll(t.Name(), "/", "_")
+
+			status := executeCommandHook(context.Background(), pairID, hook, true, hookTemplateData{RelPath: "app.jar"})
+			if !status.Success || status.Info != tt.want {
+				t.Errorf("hook status = %+v, want success echoing %q", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandHookStdinTemplateError(t *testing.T) {
+	hook := &cfg.Hook{Command: &cfg.CommandHook{Executable: "cat", StdinTemplate: "{{.Missing"}}
+	status := executeCommandHook(context.Background(), t.Name(), hook, true, hookTemplateData{RelPath: "app.jar"})
+	if status.Success || !strings.Contains(status.Info, "stdin template error") {
+		t.Errorf("hook status = %+v, want a stdin template failure", status)
+	}
+}
+
+// ===== UNSAFE COMMANDS =====
+
+func TestUnsafeCommandRunsOnlyForTrustedPairs(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowUnsafe bool
+		wantRemoved bool
+	}{
+		{"rejected by default", false, false},
+		{"trusted pair", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "victim.txt")
+			writeTestFile(t, path, "data")
+			hook := &cfg.Hook{Command: &cfg.CommandHook{Executable: "rm", Args: []string{path}}}
+			pairID := strings.ReplaceAll(t.Name(), "/", "_")
+
+			status := executeCommandHook(context.Background(), pairID, hook, tt.allowUnsafe, hookTemplateData{RelPath: "app.jar"})
+
+			if removed := readTestFile(t, path) == ""; removed != tt.wantRemoved {
+				t.Errorf("file removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if status.Success != tt.wantRemoved {
+				t.Errorf("hook status = %+v, want success %v", status, tt.wantRemoved)
+			}
+			if !tt.allowUnsafe && !strings.Contains(status.Info, "safety checks") {
+				t.Errorf("rejection info = %q", status.Info)
+			}
+		})
+	}
+}
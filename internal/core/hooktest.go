@@ -0,0 +1,99 @@
+// Package core provides hook execution functionality for the FolderSynchronizer application.
+// This file implements test runs of a single, possibly unsaved, hook definition with
+// sample template values, reporting the outcome without touching any pair's history.
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== HOOK TEST CONSTANTS =====
+
+const (
+	// DefaultHookTestFile is the relative path a test run uses when none is given
+	DefaultHookTestFile = "test-file.jar"
+
+	// hookTestIDPrefix names the pseudo pairs test runs are logged under
+	hookTestIDPrefix = "hook-test-"
+)
+
+// ErrUnknownHookType is returned when a tested hook has no HTTP URL, command or gRPC target
+var ErrUnknownHookType = errors.New("hook has no HTTP URL, command executable or gRPC target")
+
+// ===== HOOK TEST STRUCTURES =====
+
+// HookTestValues are the template values of a hook test run. Empty paths are
+// derived from RelPath the way a sync fills them.
+type HookTestValues struct {
+	RelPath    string   `json:"relPath,omitempty"`    // Relative path of the synced file (default DefaultHookTestFile)
+	SourcePath string   `json:"sourcePath,omitempty"` // Full source path (default RelPath under "source")
+	TargetPath string   `json:"targetPath,omitempty"` // Full target path (default RelPath under "target")
+	Files      []string `json:"files,omitempty"`      // Batch mode file list; set to test a batch hook
+	Size       int64    `json:"size,omitempty"`       // Target file size in bytes
+	Checksum   string   `json:"checksum,omitempty"`   // Target file SHA256 in hex
+}
+
+// templateData converts the sample values to the data hook templates see
+func (v HookTestValues) templateData() hookTemplateData {
+	if v.Files != nil {
+		return hookTemplateData{
+			Timestamp: time.Now().Format(time.RFC3339),
+			Files:     v.Files,
+			Count:     len(v.Files),
+		}
+	}
+
+	relPath := v.RelPath
+	if relPath == "" {
+		relPath = DefaultHookTestFile
+	}
+	data := hookTemplateData{
+		RelPath:    relPath,
+		Basename:   filepath.Base(relPath),
+		SourcePath: v.SourcePath,
+		TargetPath: v.TargetPath,
+		Timestamp:  time.Now().Format(time.RFC3339),
+		Count:      1,
+		Size:       v.Size,
+		Checksum:   v.Checksum,
+	}
+	if data.SourcePath == "" {
+		data.SourcePath = filepath.Join("source", relPath)
+	}
+	if data.TargetPath == "" {
+		data.TargetPath = filepath.Join("target", relPath)
+	}
+	return data
+}
+
+// hookTestSeq numbers the pseudo pairs of test runs
+var hookTestSeq atomic.Int64
+
+// ===== HOOK TEST EXECUTION =====
+
+// TestHook executes a single hook once with the given sample values and returns
+// its status. Retries follow the hook's MaxRetries and command hooks always go
+// through the safety checks. The run shares the global hook concurrency limit
+// but isn't recorded in any pair's hook history, events or metrics.
+func TestHook(ctx context.Context, hook *cfg.Hook, values HookTestValues) (HookStatus, error) {
+	id := fmt.Sprintf("%s%d", hookTestIDPrefix, hookTestSeq.Add(1))
+
+	data := values.templateData()
+	switch detectHookType(hook) {
+	case "http":
+		return executeHTTPHook(ctx, id, hook, hook.MaxRetries, data), nil
+	case "command":
+		return executeCommandHook(ctx, id, hook, false, data), nil
+	case "grpc":
+		return executeGRPCHook(ctx, id, hook, hook.MaxRetries, data), nil
+	default:
+		return HookStatus{}, ErrUnknownHookType
+	}
+}
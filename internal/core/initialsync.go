@@ -0,0 +1,55 @@
+// Package core provides throttling of watcher initial syncs for the FolderSynchronizer application.
+// Every watcher pair runs a full comparison before it starts watching; at launch with many
+// large pairs these would all hit the disks at once, so they queue for a limited number of
+// slots. Pairs with SkipInitialSync start watching right away.
+package core
+
+import (
+	"sync/atomic"
+
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== INITIAL SYNC LIMIT =====
+
+// DefaultMaxInitialSyncs bounds watcher initial syncs running at once across all pairs
+const DefaultMaxInitialSyncs = 2
+
+// initialSyncLimiter bounds watcher initial syncs running at once across all pairs
+var initialSyncLimiter atomic.Pointer[Semaphore]
+
+func init() {
+	initialSyncLimiter.Store(NewSemaphore(DefaultMaxInitialSyncs))
+}
+
+// SetMaxInitialSyncs changes the global limit on concurrent watcher initial syncs.
+// A value of zero or less removes the limit. Initial syncs already running keep
+// their slot in the previous limiter until they finish.
+func SetMaxInitialSyncs(limit int) {
+	initialSyncLimiter.Store(NewSemaphore(limit))
+	log.Info().Int("max_initial_syncs", limit).Msg("initial sync limit set")
+}
+
+// InitialSyncsUsage returns the current use of the initial sync limit.
+func InitialSyncsUsage() LimiterUsage {
+	return usageOf(initialSyncLimiter.Load())
+}
+
+// runInitialSync performs the worker's initial comparison once a slot is free.
+// It returns the context error when the worker is stopped while queued.
+func (w *PairWorker) runInitialSync() error {
+	limiter := initialSyncLimiter.Load()
+	if limiter != nil && limiter.InUse() >= limiter.Capacity() {
+		logging.ForPair(w.Pair.ID).Info().Str("pair", w.Pair.ID).Msg("initial sync queued")
+	}
+	if err := limiter.Acquire(w.ctx); err != nil {
+		return err
+	}
+	defer limiter.Release()
+
+	copier := &Copier{syncs: w.syncs}
+	_, _, err := copier.CompareAndSync(w.ctx, w.Pair)
+	return err
+}
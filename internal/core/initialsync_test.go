@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// limitInitialSyncs sets the initial sync limit until the test ends
+func limitInitialSyncs(t *testing.T, limit int) *Semaphore {
+	t.Helper()
+	SetMaxInitialSyncs(limit)
+	t.Cleanup(func() { SetMaxInitialSyncs(DefaultMaxInitialSyncs) })
+	return initialSyncLimiter.Load()
+}
+
+func TestInitialSyncsRespectConcurrencyCap(t *testing.T) {
+	const limit, pairs = 2, 3
+	limiter := limitInitialSyncs(t, limit)
+	pm := newTestPairManager(t)
+
+	// Hold every slot so the initial syncs queue up
+	for range limit {
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var targets []string
+	for i := range pairs {
+		pair := newTestPair(t)
+		pair.ID = fmt.Sprintf("%s-%d", pair.ID, i)
+		pair.Schedule = scheduler.NewWatcherSchedule()
+		writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "data")
+		if err := pm.StartPair(pair); err != nil {
+			t.Fatal(err)
+		}
+		targets = append(targets, filepath.Join(pair.Target, "a.txt"))
+	}
+
+	waitFor(t, 5*time.Second, "the initial syncs to queue", func() bool {
+		return InitialSyncsUsage().Waiting == pairs
+	})
+	for _, target := range targets {
+		if readTestFile(t, target) != "" {
+			t.Fatalf("%s copied without an initial sync slot", target)
+		}
+	}
+
+	// A single free slot lets the queued syncs through one at a time
+	limiter.Release()
+	copied := func() bool {
+		for _, target := range targets {
+			if readTestFile(t, target) != "data" {
+				return false
+			}
+		}
+		return true
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	for !copied() {
+		if usage := InitialSyncsUsage(); usage.InUse > limit {
+			t.Fatalf("%d initial syncs hold slots, limit %d", usage.InUse, limit)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the queued initial syncs")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	limiter.Release()
+	waitFor(t, 5*time.Second, "the initial sync slots to be released", func() bool {
+		usage := InitialSyncsUsage()
+		return usage.InUse == 0 && usage.Waiting == 0
+	})
+}
+
+func TestSkipInitialSync(t *testing.T) {
+	tests := []struct {
+		name         string
+		skip         bool
+		wantExisting bool // the file present at startup is copied
+	}{
+		{"initial sync", false, true},
+		{"skipped", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPairManager(t)
+			pair := newTestPair(t)
+			pair.Schedule = scheduler.NewWatcherSchedule()
+			pair.SkipInitialSync = tt.skip
+			pair.DebounceMs = 10
+			writeTestFile(t, filepath.Join(pair.Source, "existing.txt"), "existing")
+
+			if err := pm.StartPair(pair); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, 5*time.Second, "the watcher to start", func() bool {
+				status, err := pm.GetPairStatus(pair.ID)
+				return err == nil && status.WatchMode == WatchModeEvents
+			})
+
+			// Events are synced either way
+			writeTestFile(t, filepath.Join(pair.Source, "new.txt"), "new")
+			waitFor(t, 5*time.Second, "the event copy", func() bool {
+				return readTestFile(t, filepath.Join(pair.Target, "new.txt")) == "new"
+			})
+			if got := readTestFile(t, filepath.Join(pair.Target, "existing.txt")) != ""; got != tt.wantExisting {
+				t.Errorf("existing file copied = %v, want %v", got, tt.wantExisting)
+			}
+		})
+	}
+}
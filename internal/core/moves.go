@@ -0,0 +1,258 @@
+// Package core provides move/rename detection for the FolderSynchronizer application.
+// It matches new source files to orphaned target files with identical content so a
+// renamed source file can be handled as a target rename instead of delete+copy.
+// Dry runs list the planned renames; full syncs with inline mirror deletes apply them
+// before copying.
+package core
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== MOVE DETECTION STRUCTURES =====
+
+// RenameOp describes a target file rename planned by move detection.
+type RenameOp struct {
+	From string `json:"from"` // Existing target path, relative to target root
+	To   string `json:"to"`   // New target path, relative to target root
+	Size int64  `json:"size"` // File size in bytes
+	Hash string `json:"hash"` // SHA256 shared by the source and the renamed target file
+}
+
+// moveCandidate is a file that may take part in a rename.
+type moveCandidate struct {
+	fullPath     string // Absolute path (source for new files, target for orphans)
+	relativePath string // Path relative to its root
+	size         int64  // File size in bytes
+}
+
+// ===== DRY-RUN PLANNING =====
+
+// planCopy records a file that would be copied during a dry run.
+func (c *Copier) planCopy(pair *cfg.Pair, sourcePath, relativePath string, info os.FileInfo, result *SyncResult) {
+	result.FilesCopied++
+	result.BytesCopied += info.Size()
+
+	// Only files missing from target can be the destination of a move
+	if pair.DetectMoves && !IsFileExists(targetPathFor(pair, relativePath)) {
+		c.newFiles = append(c.newFiles, moveCandidate{
+			fullPath:     sourcePath,
+			relativePath: relativePath,
+			size:         info.Size(),
+		})
+	}
+}
+
+// planDelete records a target file that would be deleted during a dry run.
+func (c *Copier) planDelete(targetPath, relativePath string, dirEntry fs.DirEntry, result *SyncResult) {
+	result.FilesDeleted++
+
+	if !c.pair.DetectMoves {
+		return
+	}
+	info, err := dirEntry.Info()
+	if err != nil {
+		return
+	}
+	c.orphans = append(c.orphans, moveCandidate{
+		fullPath:     targetPath,
+		relativePath: relativePath,
+		size:         info.Size(),
+	})
+}
+
+// applyRenamePlan adds planned renames to a dry-run result, replacing the
+// delete+copy they stand for.
+func (c *Copier) applyRenamePlan(result *SyncResult, renames []RenameOp) {
+	for _, rename := range renames {
+		result.FilesCopied--
+		result.BytesCopied -= rename.Size
+		result.FilesDeleted--
+	}
+	result.Renames = renames
+}
+
+// ===== MATCHING =====
+
+// planRenames matches new source files to orphaned target files. Candidates
+// are grouped by size first and confirmed by SHA256, so only same-size files
+// are ever hashed. Each orphan is used at most once.
+func planRenames(newFiles, orphans []moveCandidate) []RenameOp {
+	if len(newFiles) == 0 || len(orphans) == 0 {
+		return nil
+	}
+
+	orphansBySize := make(map[int64][]moveCandidate)
+	for _, orphan := range orphans {
+		orphansBySize[orphan.size] = append(orphansBySize[orphan.size], orphan)
+	}
+
+	hashes := make(map[string]string)
+	hashOf := func(path string) string {
+		if hash, cached := hashes[path]; cached {
+			return hash
+		}
+		hash, err := cachedFileHash(path)
+		if err != nil {
+			log.Debug().Str("file", path).Err(err).Msg("move detection hash failed")
+		}
+		hashes[path] = hash
+		return hash
+	}
+
+	var renames []RenameOp
+	for _, newFile := range newFiles {
+		candidates := orphansBySize[newFile.size]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		sourceHash := hashOf(newFile.fullPath)
+		if sourceHash == "" {
+			continue
+		}
+
+		for i, orphan := range candidates {
+			if hashOf(orphan.fullPath) != sourceHash {
+				continue
+			}
+
+			renames = append(renames, RenameOp{
+				From: NormalizePath(orphan.relativePath),
+				To:   NormalizePath(newFile.relativePath),
+				Size: newFile.size,
+				Hash: sourceHash,
+			})
+
+			// Consume the orphan so it can't be matched twice
+			orphansBySize[newFile.size] = append(candidates[:i:i], candidates[i+1:]...)
+			break
+		}
+	}
+
+	return renames
+}
+
+// ===== SYNC RENAMES =====
+
+// applyMoves renames orphaned target files to the paths of new source files with
+// the same content, before a full sync copies anything. The renamed files are then
+// up to date and no longer orphaned, so they are neither copied nor deleted. Only
+// pairs with inline mirror deletes rename, since otherwise orphans are kept.
+func (c *Copier) applyMoves(ctx context.Context, pair *cfg.Pair, result *SyncResult) error {
+	if !pair.DetectMoves || !pair.MirrorDeletes || pair.DefersMirrorDeletes() || !IsDirectoryExists(pair.Target) {
+		return nil
+	}
+
+	newFiles, err := newSourceFiles(ctx, c, pair)
+	if err != nil {
+		return err
+	}
+	if len(newFiles) == 0 {
+		return nil
+	}
+	orphans, err := orphanedTargetFiles(ctx, pair)
+	if err != nil {
+		return err
+	}
+
+	for _, rename := range planRenames(newFiles, orphans) {
+		relativePath := filepath.FromSlash(rename.To)
+		fromPath := filepath.Join(pair.Target, filepath.FromSlash(rename.From))
+		toPath := targetPathFor(pair, relativePath)
+		if err := renameTargetFile(fromPath, toPath); err != nil {
+			// The file is copied and the orphan deleted as usual
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("from", rename.From).Str("to", rename.To).Err(err).Msg("detected move failed")
+			continue
+		}
+
+		result.Renames = append(result.Renames, rename)
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("from", rename.From).Str("to", rename.To).Int64("bytes", rename.Size).Msg("moved (detected)")
+		recordEvent(EventFileMoved, pair.ID, rename.To, "from "+rename.From)
+		c.fileSynced(ctx, pair, relativePath)
+	}
+	return nil
+}
+
+// newSourceFiles returns the regular source files passing the pair's filters
+// that don't exist in the target yet.
+func newSourceFiles(ctx context.Context, c *Copier, pair *cfg.Pair) ([]moveCandidate, error) {
+	var newFiles []moveCandidate
+	seen := make(map[string]bool) // Relative paths provided by an earlier source
+	for _, root := range pair.SourceRoots() {
+		err := walkSource(pair, root, func(path, relativePath string, dirEntry fs.DirEntry) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if dirEntry.IsDir() || !dirEntry.Type().IsRegular() || seen[relativePath] {
+				return nil
+			}
+
+			info, err := dirEntry.Info()
+			if err != nil || !c.shouldSyncFile(pair, path, relativePath, info) {
+				return nil
+			}
+			seen[relativePath] = true
+			if _, err := os.Lstat(targetPathFor(pair, relativePath)); !os.IsNotExist(err) {
+				return nil
+			}
+			newFiles = append(newFiles, moveCandidate{fullPath: path, relativePath: relativePath, size: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newFiles, nil
+}
+
+// orphanedTargetFiles returns the regular target files without a source counterpart.
+func orphanedTargetFiles(ctx context.Context, pair *cfg.Pair) ([]moveCandidate, error) {
+	var orphans []moveCandidate
+	err := filepath.WalkDir(pair.Target, func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !dirEntry.Type().IsRegular() {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(pair.Target, path)
+		if err != nil || sourceExistsFor(pair, relativePath) {
+			return err
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil
+		}
+		orphans = append(orphans, moveCandidate{fullPath: path, relativePath: relativePath, size: info.Size()})
+		return nil
+	})
+	return orphans, err
+}
+
+// renameTargetFile moves a target file to a new target path that doesn't exist yet.
+func renameTargetFile(fromPath, toPath string) error {
+	if _, err := os.Lstat(toPath); !os.IsNotExist(err) {
+		return os.ErrExist
+	}
+	if err := os.MkdirAll(filepath.Dir(toPath), DefaultDirPerms); err != nil {
+		return err
+	}
+
+	ownWrites.begin(fromPath, toPath)
+	defer ownWrites.end(fromPath, toPath)
+	hashCache.forget(fromPath)
+	return os.Rename(fromPath, toPath)
+}
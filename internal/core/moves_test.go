@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDryRunPlansRenames(t *testing.T) {
+	tests := []struct {
+		name        string
+		detectMoves bool
+		newContent  string // content of the renamed source file
+		wantRenames []RenameOp
+		wantCopied  int
+		wantDeleted int
+	}{
+		{"rename detected", true, "report body", []RenameOp{{From: "old/report.txt", To: "new/report.txt", Size: 11}}, 0, 0},
+		{"detection disabled", false, "report body", nil, 1, 1},
+		{"different content", true, "other body!", nil, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.MirrorDeletes = true
+			pair.DetectMoves = tt.detectMoves
+			writeTestFile(t, filepath.Join(pair.Target, "old", "report.txt"), "report body")
+			writeTestFile(t, filepath.Join(pair.Source, "new", "report.txt"), tt.newContent)
+
+			result, err := (&Copier{}).DryRun(context.Background(), pair)
+			if err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+
+			for i := range result.Renames {
+				if result.Renames[i].Hash == "" {
+					t.Errorf("rename %d has no hash", i)
+				}
+				result.Renames[i].Hash = ""
+			}
+			if !slices.Equal(result.Renames, tt.wantRenames) {
+				t.Errorf("Renames = %+v, want %+v", result.Renames, tt.wantRenames)
+			}
+			if result.FilesCopied != tt.wantCopied || result.FilesDeleted != tt.wantDeleted {
+				t.Errorf("copied %d and deleted %d, want %d and %d", result.FilesCopied, result.FilesDeleted, tt.wantCopied, tt.wantDeleted)
+			}
+
+			// A dry run leaves the target untouched
+			if readTestFile(t, filepath.Join(pair.Target, "old", "report.txt")) != "report body" {
+				t.Error("dry run modified the target")
+			}
+			if readTestFile(t, filepath.Join(pair.Target, "new", "report.txt")) != "" {
+				t.Error("dry run created a target file")
+			}
+		})
+	}
+}
+
+func TestPlanRenamesUsesEachOrphanOnce(t *testing.T) {
+	source, target := t.TempDir(), t.TempDir()
+	var newFiles, orphans []moveCandidate
+	for _, name := range []string{"a.txt", "b.txt"} {
+		writeTestFile(t, filepath.Join(source, name), "same")
+		newFiles = append(newFiles, moveCandidate{fullPath: filepath.Join(source, name), relativePath: name, size: 4})
+	}
+	writeTestFile(t, filepath.Join(target, "old.txt"), "same")
+	orphans = append(orphans, moveCandidate{fullPath: filepath.Join(target, "old.txt"), relativePath: "old.txt", size: 4})
+
+	renames := planRenames(newFiles, orphans)
+	if len(renames) != 1 || renames[0].From != "old.txt" || renames[0].To != "a.txt" {
+		t.Errorf("planRenames() = %+v, want a single old.txt -> a.txt", renames)
+	}
+}
+
+func TestSyncAppliesDetectedMove(t *testing.T) {
+	pair := newTestPair(t)
+	pair.MirrorDeletes = true
+	pair.DetectMoves = true
+	writeTestFile(t, filepath.Join(pair.Source, "old", "report.txt"), "report body")
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(pair.Source, "new"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(pair.Source, "old", "report.txt"), filepath.Join(pair.Source, "new", "report.txt")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := syncTestPair(t, pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 0 {
+		t.Errorf("sync copied %d files, want the move applied as a rename", files)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "new", "report.txt")) != "report body" || readTestFile(t, filepath.Join(pair.Target, "old", "report.txt")) != "" {
+		t.Error("target file was not moved")
+	}
+}
+
+func TestSyncRenamesBigFileInsteadOfCopying(t *testing.T) {
+	tests := []struct {
+		name        string
+		detectMoves bool
+		wantCopied  int
+		wantSame    bool // the target keeps the moved file rather than a new copy
+	}{
+		{"detected", true, 0, true},
+		{"not detected", false, 1, false},
+	}
+
+	content := strings.Repeat("0123456789abcdef", 1<<19) // 8 MiB
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.MirrorDeletes = true
+			pair.DetectMoves = tt.detectMoves
+			writeTestFile(t, filepath.Join(pair.Source, "media", "video.bin"), content)
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatal(err)
+			}
+			before, err := os.Stat(filepath.Join(pair.Target, "media", "video.bin"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.Rename(filepath.Join(pair.Source, "media", "video.bin"), filepath.Join(pair.Source, "media", "renamed.bin")); err != nil {
+				t.Fatal(err)
+			}
+			files, err := syncTestPair(t, pair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if files != tt.wantCopied {
+				t.Errorf("sync copied %d files, want %d", files, tt.wantCopied)
+			}
+
+			after, err := os.Stat(filepath.Join(pair.Target, "media", "renamed.bin"))
+			if err != nil {
+				t.Fatalf("renamed file missing from the target: %v", err)
+			}
+			if same := os.SameFile(before, after); same != tt.wantSame {
+				t.Errorf("target file moved = %v, want %v", same, tt.wantSame)
+			}
+			if readTestFile(t, filepath.Join(pair.Target, "media", "video.bin")) != "" {
+				t.Error("old name still in the target")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Package core provides the open file limit for the FolderSynchronizer application.
+// Copy and hash operations acquire a slot per file handle so syncs running in
+// parallel stay below the process file descriptor limit (EMFILE).
+package core
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== OPEN FILE LIMIT =====
+
+// DefaultMaxOpenFiles bounds file handles held by copy and hash operations across all pairs
+const DefaultMaxOpenFiles = 256
+
+// openFileLimiter bounds simultaneously open files across all pairs
+var openFileLimiter atomic.Pointer[Semaphore]
+
+// multiSlotMutex serializes callers taking several slots at once, so two of them
+// can never each hold part of what they need and wait on each other forever
+var multiSlotMutex sync.Mutex
+
+func init() {
+	openFileLimiter.Store(NewSemaphore(DefaultMaxOpenFiles))
+}
+
+// LimiterUsage reports the state of a global concurrency limit.
+type LimiterUsage struct {
+	InUse   int `json:"inUse"`   // Slots currently held
+	Waiting int `json:"waiting"` // Callers blocked waiting for a slot
+	Limit   int `json:"limit"`   // Maximum slots (0 = unlimited)
+}
+
+// usageOf returns the current usage of a semaphore
+func usageOf(s *Semaphore) LimiterUsage {
+	return LimiterUsage{InUse: s.InUse(), Waiting: s.Waiting(), Limit: s.Capacity()}
+}
+
+// SetMaxOpenFiles changes the global open file limit.
+// A value of zero or less removes the limit. Files already open keep their
+// slot in the previous limiter until they are closed.
+func SetMaxOpenFiles(limit int) {
+	openFileLimiter.Store(NewSemaphore(limit))
+	log.Info().Int("max_open_files", limit).Msg("open file limit set")
+}
+
+// OpenFilesUsage returns the current use of the open file limit.
+func OpenFilesUsage() LimiterUsage {
+	return usageOf(openFileLimiter.Load())
+}
+
+// HookSlotsUsage returns the current use of the hook concurrency limit.
+func HookSlotsUsage() LimiterUsage {
+	return usageOf(hookLimiter.Load())
+}
+
+// acquireOpenFiles waits until count file handles may be opened and returns the
+// function releasing them. The count is capped at the limit so a limit lower than
+// the handles an operation needs serializes it instead of blocking forever.
+func acquireOpenFiles(ctx context.Context, count int) (func(), error) {
+	limiter := openFileLimiter.Load()
+	if limiter == nil {
+		return func() {}, nil
+	}
+	count = min(count, limiter.Capacity())
+
+	if count > 1 {
+		multiSlotMutex.Lock()
+		defer multiSlotMutex.Unlock()
+	}
+
+	acquired := 0
+	release := func() {
+		for range acquired {
+			limiter.Release()
+		}
+	}
+	for acquired < count {
+		if err := limiter.Acquire(ctx); err != nil {
+			release()
+			return nil, err
+		}
+		acquired++
+	}
+	return release, nil
+}
@@ -5,14 +5,18 @@ package core
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+	"FolderSynchronizer/internal/metrics"
 	"FolderSynchronizer/internal/scheduler"
 
 	"github.com/fsnotify/fsnotify"
@@ -31,13 +35,22 @@ const (
 	SecondRetryDelay = 300 * time.Millisecond
 	ThirdRetryDelay  = 600 * time.Millisecond
 
+	// Upper bound for a single exponential copy retry delay
+	MaxCopyRetryDelay = 30 * time.Second
+
 	// Directory permissions for creating target directories
 	DefaultDirPerms = 0o755
 
 	// Delay for mirror delete operations to handle race conditions
 	MirrorDeleteDelay = 100 * time.Millisecond
+
+	// How often scheduler run statistics are saved when persistence is enabled
+	StatsSaveInterval = time.Minute
 )
 
+// ErrPairPaused is returned when a sync is requested for a paused pair.
+var ErrPairPaused = errors.New("pair is paused")
+
 // ===== PAIR MANAGEMENT STRUCTURES =====
 
 // PairManager manages multiple sync pairs with integrated scheduling and file watching.
@@ -48,6 +61,9 @@ type PairManager struct {
 	mutex     sync.RWMutex           // Thread-safe access to workers map
 	ctx       context.Context        // Manager context for shutdown coordination
 	cancel    context.CancelFunc     // Cancel function for graceful shutdown
+	statsPath string                 // File for persisted run statistics (empty disables persistence)
+	paused    map[string]bool        // Started pairs whose tasks and watcher are paused
+	syncs     *syncLimit             // Bounds concurrent full sync runs of all pairs
 }
 
 // PairStatus contains comprehensive status information about a sync pair,
@@ -61,24 +77,91 @@ type PairStatus struct {
 	NextRun       *time.Time `json:"nextRun"`             // Next scheduled execution
 	RunCount      int        `json:"runCount"`            // Total successful executions
 	FailCount     int        `json:"failCount"`           // Total failed executions
+	SkippedRuns   int        `json:"skippedRuns"`         // Runs skipped because the previous one was in progress or rate limited
 	LastError     string     `json:"lastError,omitempty"` // Last error message
 	WatcherActive bool       `json:"watcherActive"`       // Whether file watcher is running
+
+	// Initial sync outcome of the file watcher
+	InitialSyncError string `json:"initialSyncError,omitempty"` // Error from the watcher's initial sync
+	WatcherHalted    bool   `json:"watcherHalted"`              // Watcher not started because initial sync failed
+
+	// Target drift detection (WatchTarget)
+	TargetWatchActive bool       `json:"targetWatchActive"`         // Whether the target watcher is running
+	DriftRepairs      int        `json:"driftRepairs"`              // Number of target files repaired
+	LastDriftRepair   *time.Time `json:"lastDriftRepair,omitempty"` // Time of the latest drift repair
+
+	// Rate limiting (MinRunInterval)
+	LastSkipReason   string     `json:"lastSkipReason,omitempty"`   // Why the latest skipped run was skipped
+	LastCompletedRun *time.Time `json:"lastCompletedRun,omitempty"` // End of the latest sync run of any origin
+
+	// Copy rate of the latest sync run that copied data, in MB/s
+	LastThroughputMBps float64 `json:"lastThroughputMBps,omitempty"`
+
+	// Deferred mirror deletes
+	PendingDeletes   int        `json:"pendingDeletes,omitempty"`   // Target files waiting to be deleted
+	NextDeletesApply *time.Time `json:"nextDeletesApply,omitempty"` // Next scheduled application of pending deletes
+
+	// Watch setup progress while source directories are being added to the watcher
+	WatchSetup *WatchSetupStatus `json:"watchSetup,omitempty"`
+
+	// Paused pairs stay enabled but run neither scheduled syncs nor a watcher
+	Paused bool `json:"paused"`
+
+	// Watcher copies that failed and are re-attempted periodically until they succeed
+	PendingFiles []PendingFile `json:"pendingFiles,omitempty"`
+
+	// How source changes are detected: "events", or "polling" after hitting the OS watch limit
+	WatchMode string `json:"watchMode,omitempty"`
+
+	// The watched source root disappeared; watching resumes when it reappears
+	SourceMissing      bool       `json:"sourceMissing,omitempty"`
+	SourceMissingSince *time.Time `json:"sourceMissingSince,omitempty"`
+
+	// Source events received by the watcher since it last started
+	WatcherEventCount int64      `json:"watcherEventCount"`          // Events received, including filtered ones
+	LastWatcherEvent  *time.Time `json:"lastWatcherEvent,omitempty"` // Time of the latest event
+
+	// Execution limit (Schedule.MaxRuns); a pair with no runs remaining is no longer synced
+	MaxRuns       int  `json:"maxRuns,omitempty"`       // Executions allowed by the schedule
+	RunsRemaining *int `json:"runsRemaining,omitempty"` // Executions left, absent without a limit
 }
 
 // PairWorker handles file system monitoring for watcher-type sync pairs.
 // It remains separate from the scheduler for real-time file change detection.
 type PairWorker struct {
 	Pair   *cfg.Pair          // Pair configuration
+	syncs  *syncLimit         // Sync concurrency limit of the owning manager
 	ctx    context.Context    // Worker context
 	cancel context.CancelFunc // Worker cancellation
 	wg     sync.WaitGroup     // Wait group for graceful shutdown
+	drift  driftStats         // Target watch activity (WatchTarget)
+	state  workerState        // Initial sync outcome
+
+	watchSetup watchSetupProgress // Progress of adding source directories to the watcher
+	reconcile  reconcileQueue     // Files whose copy failed, awaiting a deferred re-attempt
+	batch      batchState         // Coalesced comparison passes (DebounceMode "batch")
+	renames    renameQueue        // Source paths renamed away, awaiting their new name
+	events     watcherEventStats  // Source events received by the watcher
+
+	watchLimitErr error // Set by the watch loop when adding a watch hit the OS limit
+}
+
+// workerState holds the outcome of a worker's initial synchronization.
+type workerState struct {
+	mutex            sync.Mutex
+	initialSyncError string // Error message of the failed initial sync
+	halted           bool   // Whether watching was aborted due to the failure
+
+	sourceMissingSince *time.Time // When the watched source root disappeared, nil while present
+	watchMode          string     // WatchModeEvents or WatchModePolling once watching began
 }
 
 // ===== PAIR MANAGER LIFECYCLE =====
 
-// NewPairManager creates a new pair manager with integrated scheduler.
+// NewPairManager creates a new pair manager with integrated scheduler, running
+// at most maxConcurrentSyncs full syncs at once (zero or less is unlimited).
 // Uses local timezone for scheduling by default.
-func NewPairManager() (*PairManager, error) {
+func NewPairManager(maxConcurrentSyncs int) (*PairManager, error) {
 	// Initialize scheduler with local timezone
 	sched, err := scheduler.NewScheduler("")
 	if err != nil {
@@ -90,17 +173,92 @@ func NewPairManager() (*PairManager, error) {
 	pm := &PairManager{
 		scheduler: sched,
 		workers:   make(map[string]*PairWorker),
+		paused:    make(map[string]bool),
+		syncs:     newSyncLimit(maxConcurrentSyncs),
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 
+	sched.SetRunLimitHandler(func(task *scheduler.Task) {
+		recordEvent(EventRunLimitReached, task.ID, "", fmt.Sprintf("maximum of %d runs reached", task.Schedule.MaxRuns))
+	})
+
 	sched.Start()
 	return pm, nil
 }
 
+// EnableStatsPersistence restores scheduler run statistics from path into
+// pairs started afterwards, and saves them there periodically and on Close.
+func (pm *PairManager) EnableStatsPersistence(path string) error {
+	stats, err := scheduler.LoadStats(path)
+	if err != nil {
+		return err
+	}
+
+	pm.scheduler.RestoreStats(stats)
+	pm.statsPath = path
+
+	go func() {
+		ticker := time.NewTicker(StatsSaveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				pm.saveStats()
+			case <-pm.ctx.Done():
+				return
+			}
+		}
+	}()
+
+	log.Info().Str("path", path).Int("tasks", len(stats)).Msg("scheduler stats restored")
+	return nil
+}
+
+// saveStats writes current scheduler run statistics if persistence is enabled.
+func (pm *PairManager) saveStats() {
+	if pm.statsPath == "" {
+		return
+	}
+	if err := scheduler.SaveStats(pm.statsPath, pm.scheduler.ExportStats()); err != nil {
+		log.Error().Err(err).Str("path", pm.statsPath).Msg("failed to save scheduler stats")
+	}
+}
+
+// SchedulerRunning reports whether the pair manager's scheduler is running.
+func (pm *PairManager) SchedulerRunning() bool {
+	return pm.scheduler.IsRunning()
+}
+
+// SetHolidays sets the holiday calendar used by custom schedules with SkipHolidays.
+func (pm *PairManager) SetHolidays(provider scheduler.HolidayProvider) {
+	pm.scheduler.SetHolidayProvider(provider)
+}
+
+// Drain lets running syncs finish before shutdown. New syncs are refused from
+// now on, and the call returns once no sync is running or timeout has elapsed.
+// Syncs still running afterwards are cancelled by Close.
+func (pm *PairManager) Drain(timeout time.Duration) {
+	running := pairRuns.running()
+	if running == 0 {
+		pairRuns.drain(0)
+		return
+	}
+
+	log.Info().Int("running", running).Dur("timeout", timeout).Msg("waiting for running syncs to finish")
+	if remaining := pairRuns.drain(timeout); remaining > 0 {
+		log.Warn().Int("running", remaining).Msg("drain timed out, cancelling running syncs")
+		return
+	}
+	log.Info().Msg("running syncs finished")
+}
+
 // Close gracefully shuts down the pair manager, stopping all pairs and the scheduler.
+// A drain ends with it, so a manager created afterwards can sync again.
 func (pm *PairManager) Close() {
 	pm.cancel()
+	pm.saveStats()
 	pm.scheduler.Stop()
 
 	pm.mutex.Lock()
@@ -111,26 +269,28 @@ func (pm *PairManager) Close() {
 		worker.Stop()
 	}
 	pm.workers = make(map[string]*PairWorker)
+	pairRuns.resume()
 }
 
 // ===== PAIR LIFECYCLE OPERATIONS =====
 
 // StartPair starts a sync pair with appropriate scheduling based on its configuration.
 // For watcher-type pairs, creates both a scheduler task and a file watcher.
+// Starting a pair that is already running restarts it with the given configuration.
 func (pm *PairManager) StartPair(pair *cfg.Pair) error {
 	pm.mutex.Lock()
 	defer pm.mutex.Unlock()
 
-	// Stop existing task and worker if they exist
-	if worker, exists := pm.workers[pair.ID]; exists {
-		worker.Stop()
-		pm.scheduler.RemoveTask(pair.ID)
-		delete(pm.workers, pair.ID)
-	}
+	// Replace a running instance; its task may exist without a worker (scheduled
+	// or paused pairs), so the task is removed on its own
+	pm.removePairLocked(pair.ID)
+
+	// Opened before the task is added, so the pair log records its whole lifecycle
+	syncPairLog(pair)
 
 	// Create sync function for the scheduler
 	syncFunc := func(ctx context.Context) error {
-		copier := &Copier{}
+		copier := pm.NewCopier()
 		_, _, err := copier.CompareAndSync(ctx, pair)
 		return err
 	}
@@ -152,41 +312,145 @@ func (pm *PairManager) StartPair(pair *cfg.Pair) error {
 		return err
 	}
 
+	// Scheduled application of deferred mirror deletes
+	if err := pm.syncApplyDeletesTask(pair); err != nil {
+		pm.scheduler.RemoveTask(pair.ID)
+		return err
+	}
+
+	// A paused pair keeps its tasks registered but runs nothing
+	delete(pm.paused, pair.ID)
+	if pair.Paused {
+		pm.pauseLocked(pair.ID)
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("pair started paused")
+		recordEvent(EventPairStarted, pair.ID, "", "paused")
+		return nil
+	}
+
 	// For watcher mode, create additional file system watcher
 	if pair.Schedule.Type == scheduler.ScheduleTypeWatcher {
-		worker := NewPairWorker(pair)
+		worker := pm.newPairWorker(pair)
 		if err := worker.Start(pm.ctx); err != nil {
 			pm.scheduler.RemoveTask(pair.ID)
+			pm.scheduler.RemoveTask(pair.ID + ApplyDeletesTaskSuffix)
 			return err
 		}
 		pm.workers[pair.ID] = worker
 	}
 
-	log.Info().
+	logging.ForPair(pair.ID).Info().
 		Str("pair", pair.ID).
 		Str("schedule", string(pair.Schedule.Type)).
 		Msg("pair started")
+	recordEvent(EventPairStarted, pair.ID, "", string(pair.Schedule.Type))
 
 	return nil
 }
 
 // StopPair stops a sync pair, removing it from both scheduler and file watcher.
+// Stopping a pair that isn't running is a no-op.
 func (pm *PairManager) StopPair(pairID string) error {
 	pm.mutex.Lock()
 	defer pm.mutex.Unlock()
 
-	// Stop worker if exists
+	if !pm.removePairLocked(pairID) {
+		logging.ForPair(pairID).Debug().Str("pair", pairID).Msg("pair already stopped")
+		return nil
+	}
+
+	recordEvent(EventPairStopped, pairID, "", "")
+	return nil
+}
+
+// IsPairRunning reports whether a pair is started (paused pairs count as started).
+func (pm *PairManager) IsPairRunning(pairID string) bool {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	_, err := pm.scheduler.GetTask(pairID)
+	return err == nil
+}
+
+// removePairLocked stops a pair's worker and removes its scheduler tasks, whichever
+// of them exist, and reports whether anything was running.
+// Must be called with the mutex held.
+func (pm *PairManager) removePairLocked(pairID string) bool {
+	removed := false
 	if worker, exists := pm.workers[pairID]; exists {
 		worker.Stop()
 		delete(pm.workers, pairID)
+		removed = true
 	}
 
-	// Remove from scheduler
-	return pm.scheduler.RemoveTask(pairID)
+	delete(pm.paused, pairID)
+	_ = pm.scheduler.RemoveTask(pairID + ApplyDeletesTaskSuffix)
+	if err := pm.scheduler.RemoveTask(pairID); err == nil {
+		removed = true
+	}
+	logging.ClosePairLog(pairID)
+	ResetSyncIgnoreCache(pairID)
+	return removed
+}
+
+// syncPairLog opens or closes the pair's own log file according to Pair.LogFile.
+func syncPairLog(pair *cfg.Pair) {
+	if !pair.LogFile {
+		logging.ClosePairLog(pair.ID)
+		return
+	}
+	if err := logging.OpenPairLog(pair.ID); err != nil {
+		log.Warn().Str("pair", pair.ID).Err(err).Msg("pair log file not opened")
+	}
+}
+
+// pauseLocked disables a pair's scheduler tasks and stops its watcher.
+// Must be called with the mutex held.
+func (pm *PairManager) pauseLocked(pairID string) {
+	pm.paused[pairID] = true
+	_ = pm.scheduler.DisableTask(pairID)
+	_ = pm.scheduler.DisableTask(pairID + ApplyDeletesTaskSuffix)
+
+	if worker, exists := pm.workers[pairID]; exists {
+		worker.Stop()
+		delete(pm.workers, pairID)
+	}
+}
+
+// syncApplyDeletesTask registers, replaces or removes the scheduled task that
+// applies a pair's deferred mirror deletes. Must be called with the mutex held.
+func (pm *PairManager) syncApplyDeletesTask(pair *cfg.Pair) error {
+	taskID := pair.ID + ApplyDeletesTaskSuffix
+	_ = pm.scheduler.RemoveTask(taskID)
+
+	if !pair.DefersMirrorDeletes() || pair.ApplyDeletesSchedule == nil {
+		return nil
+	}
+
+	applyFunc := func(ctx context.Context) error {
+		_, err := ApplyPendingDeletes(ctx, pair)
+		return err
+	}
+	return pm.scheduler.AddTask(taskID, "Apply pending deletes for "+pair.ID, *pair.ApplyDeletesSchedule, applyFunc)
 }
 
 // SyncPairNow triggers immediate synchronization for a pair, bypassing the schedule.
+// A watcher halted by a failed initial sync is restarted, which retries the initial sync.
 func (pm *PairManager) SyncPairNow(pairID string) error {
+	// The restart happens under the mutex, so a worker stopped or replaced by a
+	// concurrent StopPair, StartPair or UpdatePair is never revived
+	pm.mutex.Lock()
+	if pm.paused[pairID] {
+		pm.mutex.Unlock()
+		return ErrPairPaused
+	}
+	if worker, exists := pm.workers[pairID]; exists && worker.isHalted() {
+		defer pm.mutex.Unlock()
+		logging.ForPair(pairID).Info().Str("pair", pairID).Msg("retrying halted watcher")
+		worker.Stop()
+		return worker.Start(pm.ctx)
+	}
+	pm.mutex.Unlock()
+
 	return pm.scheduler.RunTaskNow(pairID)
 }
 
@@ -201,11 +465,37 @@ func (pm *PairManager) UpdatePair(pair *cfg.Pair) error {
 		return err
 	}
 
+	if err := pm.syncApplyDeletesTask(pair); err != nil {
+		return err
+	}
+	syncPairLog(pair)
+
+	// Pausing stops the tasks and watcher; resuming falls through to restart them
+	if pair.Paused {
+		if !pm.paused[pair.ID] {
+			pm.pauseLocked(pair.ID)
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("pair paused")
+			recordEvent(EventPairPaused, pair.ID, "", "")
+		} else {
+			// The apply-deletes task was just re-registered
+			_ = pm.scheduler.DisableTask(pair.ID + ApplyDeletesTaskSuffix)
+		}
+		return nil
+	}
+	if pm.paused[pair.ID] {
+		delete(pm.paused, pair.ID)
+		if err := pm.scheduler.EnableTask(pair.ID); err != nil {
+			return err
+		}
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("pair resumed")
+		recordEvent(EventPairResumed, pair.ID, "", "")
+	}
+
 	// Handle watcher mode transitions
 	if pair.Schedule.Type == scheduler.ScheduleTypeWatcher {
 		// Need a watcher but don't have one - create it
 		if _, exists := pm.workers[pair.ID]; !exists {
-			worker := NewPairWorker(pair)
+			worker := pm.newPairWorker(pair)
 			if err := worker.Start(pm.ctx); err != nil {
 				return err
 			}
@@ -232,55 +522,110 @@ func (pm *PairManager) GetPairStatus(pairID string) (*PairStatus, error) {
 	}
 
 	status := &PairStatus{
-		ID:           task.ID,
-		Name:         task.Name,
-		Enabled:      task.Enabled,
-		ScheduleType: string(task.Schedule.Type),
-		LastRun:      task.LastRun,
-		NextRun:      task.NextRun,
-		RunCount:     task.RunCount,
-		FailCount:    task.FailCount,
-		LastError:    task.LastError,
+		ID:               task.ID,
+		Name:             task.Name,
+		Enabled:          task.Enabled,
+		ScheduleType:     string(task.Schedule.Type),
+		LastRun:          task.LastRun,
+		NextRun:          task.NextRun,
+		RunCount:         task.RunCount,
+		FailCount:        task.FailCount,
+		SkippedRuns:      task.SkippedRuns,
+		LastError:        task.LastError,
+		LastSkipReason:   task.LastSkipReason,
+		LastCompletedRun: pairRuns.lastCompleted(task.ID),
 	}
+	status.LastThroughputMBps = pairRuns.lastThroughput(task.ID)
+	fillRunLimit(status, task)
 
 	// Check watcher status
 	pm.mutex.RLock()
-	_, hasWorker := pm.workers[pairID]
+	worker, hasWorker := pm.workers[pairID]
 	pm.mutex.RUnlock()
 
 	status.WatcherActive = hasWorker
+	if hasWorker {
+		worker.fillStatus(status)
+	}
+	pm.fillPendingDeletes(status)
+	pm.fillPauseState(status)
 
 	return status, nil
 }
 
+// fillRunLimit reports the task's MaxRuns countdown in a pair status.
+func fillRunLimit(status *PairStatus, task *scheduler.Task) {
+	if remaining, limited := task.RunsRemaining(); limited {
+		status.MaxRuns = task.Schedule.MaxRuns
+		status.RunsRemaining = &remaining
+	}
+}
+
 // ListPairStatuses returns status information for all managed pairs.
 func (pm *PairManager) ListPairStatuses() []*PairStatus {
 	tasks := pm.scheduler.ListTasks()
-	statuses := make([]*PairStatus, len(tasks))
-
-	for i, task := range tasks {
-		statuses[i] = &PairStatus{
-			ID:           task.ID,
-			Name:         task.Name,
-			Enabled:      task.Enabled,
-			ScheduleType: string(task.Schedule.Type),
-			LastRun:      task.LastRun,
-			NextRun:      task.NextRun,
-			RunCount:     task.RunCount,
-			FailCount:    task.FailCount,
-			LastError:    task.LastError,
+	statuses := make([]*PairStatus, 0, len(tasks))
+
+	for _, task := range tasks {
+		// Apply-deletes tasks are reported through their pair
+		if strings.HasSuffix(task.ID, ApplyDeletesTaskSuffix) {
+			continue
 		}
 
+		status := &PairStatus{
+			ID:               task.ID,
+			Name:             task.Name,
+			Enabled:          task.Enabled,
+			ScheduleType:     string(task.Schedule.Type),
+			LastRun:          task.LastRun,
+			NextRun:          task.NextRun,
+			RunCount:         task.RunCount,
+			FailCount:        task.FailCount,
+			SkippedRuns:      task.SkippedRuns,
+			LastError:        task.LastError,
+			LastSkipReason:   task.LastSkipReason,
+			LastCompletedRun: pairRuns.lastCompleted(task.ID),
+		}
+		status.LastThroughputMBps = pairRuns.lastThroughput(task.ID)
+		fillRunLimit(status, task)
+
 		// Check watcher status
 		pm.mutex.RLock()
-		_, hasWorker := pm.workers[task.ID]
+		worker, hasWorker := pm.workers[task.ID]
 		pm.mutex.RUnlock()
-		statuses[i].WatcherActive = hasWorker
+		status.WatcherActive = hasWorker
+		if hasWorker {
+			worker.fillStatus(status)
+		}
+		pm.fillPendingDeletes(status)
+		pm.fillPauseState(status)
+
+		statuses = append(statuses, status)
 	}
 
 	return statuses
 }
 
+// fillPauseState marks a paused pair in its status. Its task is disabled while
+// paused, but the pair itself remains enabled.
+func (pm *PairManager) fillPauseState(status *PairStatus) {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+
+	if pm.paused[status.ID] {
+		status.Paused = true
+		status.Enabled = true
+	}
+}
+
+// fillPendingDeletes adds deferred mirror delete information to a pair status.
+func (pm *PairManager) fillPendingDeletes(status *PairStatus) {
+	status.PendingDeletes = len(PendingDeletes(status.ID))
+	if task, err := pm.scheduler.GetTask(status.ID + ApplyDeletesTaskSuffix); err == nil {
+		status.NextDeletesApply = task.NextRun
+	}
+}
+
 // ===== FILE WATCHER IMPLEMENTATION =====
 
 // NewPairWorker creates a new file watcher worker for a sync pair.
@@ -288,6 +633,13 @@ func NewPairWorker(pair *cfg.Pair) *PairWorker {
 	return &PairWorker{Pair: pair}
 }
 
+// newPairWorker creates a worker whose sync runs share the manager's sync limit.
+func (pm *PairManager) newPairWorker(pair *cfg.Pair) *PairWorker {
+	worker := NewPairWorker(pair)
+	worker.syncs = pm.syncs
+	return worker
+}
+
 // Start begins file system monitoring for the pair.
 func (w *PairWorker) Start(parent context.Context) error {
 	if w.cancel != nil {
@@ -297,6 +649,9 @@ func (w *PairWorker) Start(parent context.Context) error {
 	ctx, cancel := context.WithCancel(parent)
 	w.ctx = ctx
 	w.cancel = cancel
+	w.reconcile.reset()
+	w.batch.reset()
+	w.renames.reset()
 	w.wg.Add(1)
 	go w.run()
 	return nil
@@ -309,29 +664,102 @@ func (w *PairWorker) Stop() {
 	}
 
 	w.cancel()
+	w.reconcile.stop()
+	w.batch.stop()
+	w.renames.stop()
 	w.wg.Wait()
 	w.cancel = nil
 }
 
+// fillStatus copies worker runtime statistics into a pair status.
+func (w *PairWorker) fillStatus(status *PairStatus) {
+	status.TargetWatchActive, status.DriftRepairs, status.LastDriftRepair = w.drift.snapshot()
+	status.WatchSetup = w.watchSetup.snapshot()
+	status.PendingFiles = w.reconcile.snapshot()
+	status.WatcherEventCount, status.LastWatcherEvent = w.events.snapshot()
+
+	w.state.mutex.Lock()
+	defer w.state.mutex.Unlock()
+
+	status.InitialSyncError = w.state.initialSyncError
+	status.WatcherHalted = w.state.halted
+	status.WatchMode = w.state.watchMode
+	if w.state.halted {
+		status.WatcherActive = false
+	}
+	if status.LastError == "" && w.state.initialSyncError != "" {
+		status.LastError = "initial sync failed: " + w.state.initialSyncError
+	}
+
+	if w.state.sourceMissingSince != nil {
+		status.SourceMissing = true
+		status.SourceMissingSince = w.state.sourceMissingSince
+		if status.LastError == "" {
+			status.LastError = "source directory missing, watching suspended"
+		}
+	}
+}
+
+// setInitialSyncResult records the outcome of the initial synchronization.
+func (w *PairWorker) setInitialSyncResult(err error, halted bool) {
+	w.state.mutex.Lock()
+	defer w.state.mutex.Unlock()
+
+	w.state.initialSyncError = ""
+	if err != nil {
+		w.state.initialSyncError = err.Error()
+	}
+	w.state.halted = halted
+}
+
+// isHalted reports whether the worker stopped after a failed initial sync.
+func (w *PairWorker) isHalted() bool {
+	w.state.mutex.Lock()
+	defer w.state.mutex.Unlock()
+	return w.state.halted
+}
+
 // run implements the main file watching loop with event processing.
 func (w *PairWorker) run() {
 	defer w.wg.Done()
 
 	pair := w.Pair
-	log.Info().Str("pair", pair.ID).Msg("watcher starting")
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("watcher starting")
 
-	// Perform initial synchronization
-	copier := &Copier{}
-	if _, _, err := copier.CompareAndSync(w.ctx, pair); err != nil {
-		log.Error().Str("pair", pair.ID).Err(err).Msg("initial sync failed")
+	// Perform initial synchronization, unless the pair relies on events alone
+	var err error
+	if pair.SkipInitialSync {
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("initial sync skipped (skipInitialSync)")
+	} else {
+		err = w.runInitialSync()
+	}
+	if w.ctx.Err() != nil {
+		return // Stopped during initial sync
+	}
+	if errors.Is(err, ErrRateLimited) {
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Err(err).Msg("initial sync skipped, watching for changes")
+		err = nil
 	}
+	if err != nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("initial sync failed")
 
-	// Set up file system watcher
-	if err := w.watchFileSystem(); err != nil {
-		log.Error().Str("pair", pair.ID).Err(err).Msg("file system watching failed")
+		if pair.HaltOnInitialSyncError {
+			w.setInitialSyncResult(err, true)
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Msg("watcher halted until manual retry")
+			return
+		}
+	}
+	w.setInitialSyncResult(err, false)
+
+	// Set up file system watcher, polling instead once the OS watch limit is hit
+	w.watchLimitErr = nil
+	if err := w.watchFileSystem(); errors.Is(err, ErrWatchLimit) {
+		w.pollSource(err, w.watchLimitErr != nil)
+	} else if err != nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("file system watching failed")
 	}
 
-	log.Info().Str("pair", pair.ID).Msg("watcher stopping")
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("watcher stopping")
 }
 
 // watchFileSystem sets up and runs the file system watcher with event processing.
@@ -347,45 +775,83 @@ func (w *PairWorker) watchFileSystem() error {
 		return err
 	}
 	defer watcher.Close()
+	w.events.reset()
 
-	// Add all source directories to watcher
-	if err := w.addDirectoriesToWatcher(watcher, pair.Source); err != nil {
+	// Add all source directories to watcher; a missing source is waited for
+	var recovery sourceRecovery
+	defer recovery.stop()
+	defer w.setSourceMissing(false)
+	missing, err := w.watchSourceRoots(watcher)
+	if err != nil {
+		if w.ctx.Err() != nil {
+			return nil // Stopped during watch setup
+		}
 		return err
 	}
+	if missing {
+		w.beginSourceRecovery(&recovery)
+	}
+	w.setWatchMode(WatchModeEvents)
+
+	metrics.WatcherActive.WithLabelValues(pair.ID).Set(1)
+	defer metrics.WatcherActive.WithLabelValues(pair.ID).Set(0)
+
+	// Optionally watch the target for out-of-band changes (nil channels never fire)
+	var targetEvents chan fsnotify.Event
+	var targetErrors chan error
+	var targetWatcher *fsnotify.Watcher
+	if pair.WatchTarget {
+		targetWatcher, err = w.newTargetWatcher()
+		if err != nil {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("target watcher setup failed")
+		} else {
+			defer targetWatcher.Close()
+			targetEvents = targetWatcher.Events
+			targetErrors = targetWatcher.Errors
+			w.drift.setActive(true)
+			defer w.drift.setActive(false)
+		}
+	}
 
 	// Process file system events
 	for {
 		select {
 		case event := <-watcher.Events:
+			w.events.record()
+			if w.isSourceRootLoss(event) {
+				w.beginSourceRecovery(&recovery)
+				continue
+			}
 			w.handleFileSystemEvent(event, watcher, debouncer)
+			if w.watchLimitErr != nil {
+				return w.watchLimitErr
+			}
+
+		case <-recovery.C():
+			w.recoverSource(watcher, &recovery)
+			if w.watchLimitErr != nil {
+				return w.watchLimitErr
+			}
 
 		case err := <-watcher.Errors:
 			if err != nil {
 				log.Error().Err(err).Msg("watcher")
 			}
 
+		case event := <-targetEvents:
+			w.handleTargetEvent(event, targetWatcher, debouncer)
+
+		case err := <-targetErrors:
+			if err != nil {
+				log.Error().Err(err).Msg("target watcher")
+			}
+
 		case <-w.ctx.Done():
 			return nil
 		}
 	}
 }
 
-// addDirectoriesToWatcher recursively adds directories to the file system watcher.
-func (w *PairWorker) addDirectoriesToWatcher(watcher *fsnotify.Watcher, sourcePath string) error {
-	return filepath.WalkDir(sourcePath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if d.IsDir() {
-			if err := watcher.Add(path); err != nil {
-				log.Error().Err(err).Str("dir", path).Msg("watch add failed")
-			}
-		}
-		return nil
-	})
-}
-
 // handleFileSystemEvent processes individual file system events with appropriate actions.
 func (w *PairWorker) handleFileSystemEvent(event fsnotify.Event, watcher *fsnotify.Watcher, debouncer *Debouncer) {
 	pair := w.Pair
@@ -395,7 +861,20 @@ func (w *PairWorker) handleFileSystemEvent(event fsnotify.Event, watcher *fsnoti
 	}
 
 	// Skip excluded files
-	if MatchesExclude(pair.ExcludeGlobs, event.Name) {
+	if MatchesExclude(pair.ExcludeGlobs, event.Name, pair.CaseSensitive) {
+		return
+	}
+
+	// A changed ignore file applies from the next event on
+	if filepath.Base(event.Name) == SyncIgnoreFileName {
+		InvalidateSyncIgnore(pair.ID, event.Name)
+	}
+	sourceRoot, ok := sourceRootOf(pair, event.Name)
+	if !ok {
+		sourceRoot = pair.Source
+	}
+	relativePath := RelPath(sourceRoot, event.Name)
+	if IsSyncIgnored(pair.ID, sourceRoot, relativePath) || pathBeyondMaxDepth(pair, relativePath) || isHiddenPath(pair, event.Name, relativePath) {
 		return
 	}
 
@@ -406,7 +885,16 @@ func (w *PairWorker) handleFileSystemEvent(event fsnotify.Event, watcher *fsnoti
 		}
 	}
 
-	relativePath := RelPath(pair.Source, event.Name)
+	// Batch mode coalesces all events of the pair into one comparison pass
+	if pair.DebounceMode == cfg.DebounceModeBatch {
+		w.queueBatchSync(debouncer)
+		return
+	}
+
+	// A path renamed away may reappear under a new name, which then takes over its target
+	if event.Op&fsnotify.Rename == fsnotify.Rename && w.trackRename(event.Name) {
+		return
+	}
 
 	// Debounce the event processing
 	debouncer.Trigger(event.Name, func() {
@@ -416,18 +904,32 @@ func (w *PairWorker) handleFileSystemEvent(event fsnotify.Event, watcher *fsnoti
 
 // handleDirectoryCreation adds newly created directories to the watcher.
 func (w *PairWorker) handleDirectoryCreation(path string, watcher *fsnotify.Watcher) bool {
+	// Directory links are handled per the pair's symlink mode, like file links
+	if linkInfo, err := os.Lstat(path); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+		return false
+	}
 	if fileInfo, err := os.Stat(path); err == nil && fileInfo.IsDir() {
-		// Add the new directory to watcher
-		_ = watcher.Add(path)
+		// A directory renamed within the source keeps its target contents
+		w.moveRenamedDirectory(sourceRelPath(w.Pair, path))
 
-		// Add all nested subdirectories
+		// Add the new directory and all nested subdirectories within MaxDepth to watcher,
+		// creating their target counterparts with SyncEmptyDirs
 		filepath.WalkDir(path, func(walkPath string, d os.DirEntry, err error) error {
 			if err != nil {
 				log.Error().Err(err).Str("dir", walkPath).Msg("watch add failed")
 				return nil
 			}
-			if d.IsDir() && walkPath != path {
-				_ = watcher.Add(walkPath)
+			if !d.IsDir() {
+				return nil
+			}
+			relativePath := sourceRelPath(w.Pair, walkPath)
+			if dirBeyondMaxDepth(w.Pair, relativePath) || isHiddenEntry(w.Pair, relativePath, d) {
+				return filepath.SkipDir
+			}
+			w.syncDirectoryToTargets(walkPath, relativePath)
+			if err := addWatch(watcher, walkPath); err != nil {
+				w.watchLimitErr = err // The watch loop switches to polling
+				return filepath.SkipAll
 			}
 			return nil
 		})
@@ -443,10 +945,18 @@ func (w *PairWorker) processFileEvent(event fsnotify.Event, relativePath string)
 	// Handle file modifications (Create, Write, Rename, Chmod)
 	if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename|fsnotify.Chmod) != 0 {
 		w.handleFileModification(event.Name, relativePath)
+	} else if len(pair.Sources) > 1 && sourceExistsFor(pair, relativePath) {
+		// Another source still provides the removed file
+		w.handleFileModification(event.Name, relativePath)
 	} else if pair.MirrorDeletes && event.Op&fsnotify.Remove == fsnotify.Remove {
 		// Handle file deletion
-		targetPath := filepath.Join(pair.Target, relativePath)
-		_ = os.Remove(targetPath)
+		if pair.DefersMirrorDeletes() {
+			deferTargetDelete(pair, targetPathFor(pair, relativePath))
+			return
+		}
+		if removeTargetFiles(pair, relativePath) {
+			recordEvent(EventFileDeleted, pair.ID, NormalizePath(relativePath), "watcher")
+		}
 	}
 }
 
@@ -454,60 +964,93 @@ func (w *PairWorker) processFileEvent(event fsnotify.Event, relativePath string)
 func (w *PairWorker) handleFileModification(sourcePath, relativePath string) {
 	pair := w.Pair
 
+	// With several sources the file may be provided by another source
+	sourcePath, ok := w.providingSource(sourcePath, relativePath)
+	if !ok {
+		return
+	}
+
+	// Links follow the pair's symlink mode
+	if w.handleSymlinkEvent(sourcePath, relativePath) {
+		w.reconcile.resolve(relativePath)
+		return
+	}
+
 	// Check if file still exists and is not a directory
 	fileInfo, err := os.Stat(sourcePath)
 	if err != nil || fileInfo.IsDir() {
+		w.reconcile.resolve(relativePath)
+
 		// Handle potential rename/move for mirror deletes
 		if pair.MirrorDeletes && (err != nil || os.IsNotExist(err)) {
 			time.Sleep(MirrorDeleteDelay)
-			if _, checkErr := os.Stat(sourcePath); os.IsNotExist(checkErr) {
-				targetPath := filepath.Join(pair.Target, relativePath)
-				_ = os.Remove(targetPath)
+			if _, checkErr := os.Stat(sourcePath); os.IsNotExist(checkErr) && (len(pair.Sources) <= 1 || !sourceExistsFor(pair, relativePath)) {
+				if removeTargetFiles(pair, relativePath) {
+					recordEvent(EventFileDeleted, pair.ID, NormalizePath(relativePath), "watcher")
+				}
 			}
 		}
 		return
 	}
 
 	// Check file inclusion filters
-	if !MatchesInclude(pair.IncludeExt, sourcePath) {
+	if !MatchesIncludeFilters(pair.IncludeExt, pair.IncludeGlobs, relativePath, pair.CaseSensitive) {
+		return
+	}
+
+	// Check file size limits
+	if !withinSizeLimits(pair, fileInfo.Size()) {
+		logging.ForPair(pair.ID).Debug().
+			Str("pair", pair.ID).
+			Str("file", relativePath).
+			Int64("size", fileInfo.Size()).
+			Msg("skipped (size limits)")
 		return
 	}
 
 	// Prepare target path
-	targetPath := filepath.Join(pair.Target, relativePath)
+	targetPath := targetPathFor(pair, relativePath)
 	if err := os.MkdirAll(filepath.Dir(targetPath), DefaultDirPerms); err != nil {
 		return
 	}
 
-	// Retry copy operation to handle file locks (common on Windows)
-	retryDelays := []time.Duration{FirstRetryDelay, SecondRetryDelay, ThirdRetryDelay}
-	var copyErr error
-
-	for i, delay := range retryDelays {
-		_, copyErr = copyAtomic(sourcePath, targetPath)
-		if copyErr == nil {
-			break
-		}
+	// A file renamed within the source takes over the target of its old name
+	if _, err := os.Lstat(targetPath); os.IsNotExist(err) && w.moveRenamedFile(fileInfo, relativePath, targetPath) {
+		w.reconcile.resolve(relativePath)
+		RunHooks(w.ctx, pair, relativePath)
+		return
+	}
 
-		if i < len(retryDelays)-1 {
-			time.Sleep(delay)
-		}
+	// Retry copy operation to handle file locks (common on Windows)
+	bytesCopied, copyErr := copyWithRetry(w.ctx, pair, sourcePath, targetPath)
+	if len(pair.Targets) > 1 {
+		w.copyToOtherTargets(sourcePath, relativePath)
 	}
 
 	if copyErr == nil {
-		log.Info().
+		metrics.FilesCopied.WithLabelValues(pair.ID).Inc()
+		metrics.BytesCopied.WithLabelValues(pair.ID).Add(float64(bytesCopied))
+
+		logging.ForPair(pair.ID).Info().
 			Str("pair", pair.ID).
 			Str("file", relativePath).
 			Msg("copied (event)")
+		recordEvent(EventFileCopied, pair.ID, NormalizePath(relativePath), "watcher")
+		w.reconcile.resolve(relativePath)
 
 		// Execute hooks for successful copy
 		RunHooks(w.ctx, pair, relativePath)
 	} else {
-		log.Error().
+		logging.ForPair(pair.ID).Error().
 			Str("pair", pair.ID).
 			Str("file", relativePath).
 			Err(copyErr).
 			Msg("copy failed after retries")
+
+		// Re-attempt later so a file locked for longer isn't dropped until the next full scan
+		if w.ctx.Err() == nil {
+			w.scheduleReconcile(sourcePath, relativePath, copyErr)
+		}
 	}
 }
 
@@ -520,14 +1063,73 @@ func ValidatePair(pair *cfg.Pair) error {
 		return errors.New("id is required")
 	}
 
+	if err := cfg.ValidateSources(pair); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateTargets(pair); err != nil {
+		return err
+	}
+
 	if pair.Source == "" || pair.Target == "" {
 		return errors.New("source and target are required")
 	}
 
+	for _, source := range pair.SourceRoots() {
+		for _, target := range pair.TargetRoots() {
+			if err := cfg.ValidatePairPaths(source, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := cfg.ValidateFileSizeLimits(pair.MinFileSize, pair.MaxFileSize); err != nil {
+		return err
+	}
+
+	if pair.CopyRetries < 0 || pair.CopyRetryDelayMs < 0 {
+		return errors.New("copy retries and retry delay cannot be negative")
+	}
+
+	if pair.PeriodicHashCheck < 0 {
+		return errors.New("periodic hash check cannot be negative")
+	}
+
+	if err := cfg.ValidateMinRunInterval(pair.MinRunInterval); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateMirrorDeleteMode(pair); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateDebounceMode(pair.DebounceMode); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateSymlinkMode(pair.SymlinkMode); err != nil {
+		return err
+	}
+
+	if err := cfg.ValidateWatchPollInterval(pair.WatchPollInterval); err != nil {
+		return err
+	}
+
 	// Normalize paths for Windows long path support
 	if runtime.GOOS == "windows" {
 		pair.Source = normalizeWindowsLongPath(pair.Source)
+		for i := range pair.Sources {
+			pair.Sources[i] = normalizeWindowsLongPath(pair.Sources[i])
+		}
 		pair.Target = normalizeWindowsLongPath(pair.Target)
+		for i := range pair.Targets {
+			pair.Targets[i] = normalizeWindowsLongPath(pair.Targets[i])
+		}
+	}
+
+	// Pairs without a schedule adopt the configured default
+	if pair.Schedule.Type == "" {
+		pair.Schedule = defaultPairSchedule()
 	}
 
 	// Validate schedule configuration
@@ -535,6 +1137,10 @@ func ValidatePair(pair *cfg.Pair) error {
 		return err
 	}
 
+	if err := cfg.ValidateArchiveSettings(pair); err != nil {
+		return err
+	}
+
 	// Apply default values
 	applyPairDefaults(pair)
 
@@ -556,18 +1162,51 @@ func applyPairDefaults(pair *cfg.Pair) {
 
 	// Set default schedule if not specified
 	if pair.Schedule.Type == "" {
-		pair.Schedule = scheduler.NewWatcherSchedule()
+		pair.Schedule = defaultPairSchedule()
+	}
+}
+
+// defaultScheduleValue holds the schedule assigned to pairs created without one
+var defaultScheduleValue atomic.Pointer[scheduler.Schedule]
+
+// SetDefaultSchedule changes the schedule given to pairs validated without one.
+// An empty schedule type restores the watcher default.
+func SetDefaultSchedule(schedule scheduler.Schedule) {
+	if schedule.Type == "" {
+		defaultScheduleValue.Store(nil)
+		return
+	}
+	defaultScheduleValue.Store(&schedule)
+}
+
+// defaultPairSchedule returns a copy of the default schedule for a new pair.
+func defaultPairSchedule() scheduler.Schedule {
+	stored := defaultScheduleValue.Load()
+	if stored == nil {
+		return scheduler.NewWatcherSchedule()
+	}
+
+	schedule := *stored
+	if stored.Custom != nil {
+		custom := *stored.Custom
+		custom.WeekDays = append([]scheduler.WeekDay(nil), stored.Custom.WeekDays...)
+		schedule.Custom = &custom
 	}
+	return schedule
 }
 
 // validateScheduleConfiguration validates the schedule configuration based on its type.
 func validateScheduleConfiguration(schedule *scheduler.Schedule) error {
+	if _, err := scheduler.LoadLocation(schedule.Timezone, time.Local); err != nil {
+		return err
+	}
+
 	switch schedule.Type {
 	case scheduler.ScheduleTypeDisabled, scheduler.ScheduleTypeWatcher:
 		// No additional validation required
 		return nil
 
-	case scheduler.ScheduleTypeInterval:
+	case scheduler.ScheduleTypeInterval, scheduler.ScheduleTypePoll:
 		return validateIntervalSchedule(schedule)
 
 	case scheduler.ScheduleTypeCron:
@@ -576,20 +1215,27 @@ func validateScheduleConfiguration(schedule *scheduler.Schedule) error {
 	case scheduler.ScheduleTypeCustom:
 		return validateCustomSchedule(schedule)
 
+	case scheduler.ScheduleTypeOnce:
+		return validateOnceSchedule(schedule)
+
 	default:
 		return errors.New("unsupported schedule type")
 	}
 }
 
-// validateIntervalSchedule validates interval-based schedule configuration.
+// validateIntervalSchedule validates interval and poll schedule configuration.
 func validateIntervalSchedule(schedule *scheduler.Schedule) error {
 	if schedule.Interval == "" {
-		return errors.New("interval is required for interval schedule")
+		return fmt.Errorf("interval is required for %s schedule", schedule.Type)
 	}
 
-	if _, err := time.ParseDuration(schedule.Interval); err != nil {
+	interval, err := time.ParseDuration(schedule.Interval)
+	if err != nil {
 		return errors.New("invalid interval format")
 	}
+	if schedule.Type == scheduler.ScheduleTypePoll && interval <= 0 {
+		return errors.New("poll interval must be positive")
+	}
 
 	return nil
 }
@@ -604,6 +1250,36 @@ func validateCronSchedule(schedule *scheduler.Schedule) error {
 	return nil
 }
 
+// validateOnceSchedule validates one-time schedule configuration.
+func validateOnceSchedule(schedule *scheduler.Schedule) error {
+	if schedule.RunAt == nil {
+		return errors.New("runAt is required for once schedule")
+	}
+
+	return nil
+}
+
+// ValidateRunAtChange requires a once schedule to run in the future when it is
+// new or its runAt differs from the previous schedule. A past runAt that is only
+// carried along stays valid, so pairs whose one-time run has happened can still
+// be edited. previous is nil for pairs being created.
+func ValidateRunAtChange(previous, schedule *scheduler.Schedule) error {
+	if schedule.Type != scheduler.ScheduleTypeOnce || schedule.RunAt == nil {
+		return nil
+	}
+
+	if previous != nil && previous.Type == scheduler.ScheduleTypeOnce &&
+		previous.RunAt != nil && previous.RunAt.Equal(*schedule.RunAt) {
+		return nil
+	}
+
+	if !schedule.RunAt.After(time.Now()) {
+		return errors.New("runAt must be in the future")
+	}
+
+	return nil
+}
+
 // validateCustomSchedule validates custom schedule configuration.
 func validateCustomSchedule(schedule *scheduler.Schedule) error {
 	if schedule.Custom == nil {
@@ -632,7 +1308,8 @@ func validateCustomSchedule(schedule *scheduler.Schedule) error {
 		return errors.New("invalid interval format")
 	}
 
-	return nil
+	// Weekday, work day and holiday flags are checked by the scheduler
+	return scheduler.ValidateSchedule(*schedule)
 }
 
 // ===== UTILITY FUNCTIONS =====
@@ -664,7 +1341,7 @@ func normalizeWindowsLongPath(path string) string {
 
 // normalizeIncludeExtensions normalizes the include extensions list for a pair.
 // Rules:
-//   - Trim spaces, convert to lowercase, ensure each starts with '.'
+//   - Trim spaces, convert to lowercase (unless CaseSensitive), ensure each starts with '.'
 //   - If "*" or ".*" is specified or list is empty, treat as match-all (clear list)
 //   - Remove duplicates
 func normalizeIncludeExtensions(pair *cfg.Pair) {
@@ -677,7 +1354,7 @@ func normalizeIncludeExtensions(pair *cfg.Pair) {
 	seen := make(map[string]bool)
 
 	for _, ext := range pair.IncludeExt {
-		cleanExt := strings.TrimSpace(strings.ToLower(ext))
+		cleanExt := foldCase(strings.TrimSpace(ext), pair.CaseSensitive)
 		if cleanExt == "" {
 			continue
 		}
@@ -0,0 +1,354 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/scheduler"
+)
+
+func TestNormalizeIncludeExtensions(t *testing.T) {
+	tests := []struct {
+		name          string
+		extensions    []string
+		caseSensitive bool
+		want          []string
+	}{
+		{"lowercases by default", []string{".JPG", "png", " .Tar.GZ "}, false, []string{".jpg", ".png", ".tar.gz"}},
+		{"keeps case when case-sensitive", []string{".JPG", "png"}, true, []string{".JPG", ".png"}},
+		{"deduplicates folded duplicates", []string{".jpg", ".JPG"}, false, []string{".jpg"}},
+		{"keeps case variants apart when case-sensitive", []string{".jpg", ".JPG"}, true, []string{".jpg", ".JPG"}},
+		{"wildcard matches all", []string{".jpg", "*"}, false, nil},
+		{"blank entries match all", []string{" ", ""}, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := &cfg.Pair{IncludeExt: tt.extensions, CaseSensitive: tt.caseSensitive}
+			normalizeIncludeExtensions(pair)
+			if !slices.Equal(pair.IncludeExt, tt.want) {
+				t.Errorf("IncludeExt = %q, want %q", pair.IncludeExt, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizedCaseSensitiveExtensionsMatch(t *testing.T) {
+	pair := &cfg.Pair{IncludeExt: []string{".JPG"}, CaseSensitive: true}
+	normalizeIncludeExtensions(pair)
+
+	if !MatchesInclude(pair.IncludeExt, "photos/Photo.JPG", pair.CaseSensitive) {
+		t.Error("Photo.JPG should match .JPG on a case-sensitive pair")
+	}
+	if MatchesInclude(pair.IncludeExt, "photos/photo.jpg", pair.CaseSensitive) {
+		t.Error("photo.jpg should not match .JPG on a case-sensitive pair")
+	}
+}
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newTestPairManager returns a pair manager closed when the test ends
+func newTestPairManager(t *testing.T) *PairManager {
+	t.Helper()
+	pm, err := NewPairManager(DefaultMaxConcurrentSyncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pm.Close)
+	return pm
+}
+
+func TestInitialSyncErrorReported(t *testing.T) {
+	tests := []struct {
+		name string
+		halt bool
+	}{
+		{"proceed", false},
+		{"halt", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPairManager(t)
+			pair := newTestPair(t)
+			pair.Schedule = scheduler.NewWatcherSchedule()
+			pair.HaltOnInitialSyncError = tt.halt
+			pair.CopyRetries, pair.CopyRetryDelayMs = 1, 1
+			writeTestFile(t, filepath.Join(pair.Source, "file.txt"), "data")
+
+			// A file in place of the target directory makes every copy fail
+			pair.Target = filepath.Join(pair.Target, "not-a-directory")
+			writeTestFile(t, pair.Target, "")
+
+			if err := pm.StartPair(pair); err != nil {
+				t.Fatal(err)
+			}
+			var status *PairStatus
+			waitFor(t, 5*time.Second, "the initial sync error", func() bool {
+				var err error
+				status, err = pm.GetPairStatus(pair.ID)
+				return err == nil && status.InitialSyncError != ""
+			})
+
+			if !strings.HasPrefix(status.LastError, "initial sync failed: ") {
+				t.Errorf("LastError = %q, want the initial sync error", status.LastError)
+			}
+			if status.WatcherHalted != tt.halt {
+				t.Errorf("WatcherHalted = %v, want %v", status.WatcherHalted, tt.halt)
+			}
+			if !tt.halt {
+				waitFor(t, 5*time.Second, "the watcher to start", func() bool {
+					status, err := pm.GetPairStatus(pair.ID)
+					return err == nil && status.WatchMode == WatchModeEvents
+				})
+			}
+		})
+	}
+}
+
+func TestValidatePairAdoptsDefaultSchedule(t *testing.T) {
+	t.Cleanup(func() { SetDefaultSchedule(scheduler.Schedule{}) })
+
+	tests := []struct {
+		name     string
+		defaults scheduler.Schedule
+		schedule scheduler.Schedule
+		want     scheduler.Schedule
+	}{
+		{"watcher without a default", scheduler.Schedule{}, scheduler.Schedule{}, scheduler.NewWatcherSchedule()},
+		{"configured default", scheduler.NewIntervalSchedule("15m"), scheduler.Schedule{}, scheduler.NewIntervalSchedule("15m")},
+		{"explicit schedule kept", scheduler.NewIntervalSchedule("15m"), scheduler.NewCronSchedule("0 2 * * *"), scheduler.NewCronSchedule("0 2 * * *")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaultSchedule(tt.defaults)
+			pair := newTestPair(t)
+			pair.Schedule = tt.schedule
+
+			if err := ValidatePair(pair); err != nil {
+				t.Fatalf("ValidatePair: %v", err)
+			}
+			if pair.Schedule.Type != tt.want.Type || pair.Schedule.Interval != tt.want.Interval || pair.Schedule.CronExpr != tt.want.CronExpr {
+				t.Errorf("schedule = %+v, want %+v", pair.Schedule, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRunAtChange(t *testing.T) {
+	past := scheduler.NewOnceSchedule(time.Now().Add(-time.Hour))
+	future := scheduler.NewOnceSchedule(time.Now().Add(time.Hour))
+	earlier := scheduler.NewOnceSchedule(time.Now().Add(-2 * time.Hour))
+	interval := scheduler.NewIntervalSchedule("1h")
+
+	tests := []struct {
+		name     string
+		previous *scheduler.Schedule
+		schedule scheduler.Schedule
+		wantErr  bool
+	}{
+		{"new pair in the future", nil, future, false},
+		{"new pair in the past", nil, past, true},
+		{"unchanged past runAt", &past, past, false},
+		{"changed to another past runAt", &past, earlier, true},
+		{"changed to a future runAt", &past, future, false},
+		{"switched from interval to a past runAt", &interval, past, true},
+		{"not a once schedule", nil, interval, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRunAtChange(tt.previous, &tt.schedule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRunAtChange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// pairState reports whether a pair has a scheduler task and a watcher worker
+func pairState(pm *PairManager, pairID string) (hasTask, hasWorker bool) {
+	pm.mutex.RLock()
+	defer pm.mutex.RUnlock()
+	_, err := pm.scheduler.GetTask(pairID)
+	_, hasWorker = pm.workers[pairID]
+	return err == nil, hasWorker
+}
+
+func TestConcurrentStartStopConverges(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedule   scheduler.Schedule
+		wantWorker bool
+	}{
+		{"watcher", scheduler.NewWatcherSchedule(), true},
+		{"interval", scheduler.NewIntervalSchedule("1h"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm := newTestPairManager(t)
+			pair := newTestPair(t)
+			pair.Schedule = tt.schedule
+			pair.SkipInitialSync = true
+
+			// Interleaved toggles from several callers, like the tray and the API
+			var wg sync.WaitGroup
+			for caller := 0; caller < 8; caller++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 20; i++ {
+						if (caller+i)%2 == 0 {
+							if err := pm.StartPair(pair); err != nil {
+								t.Errorf("StartPair: %v", err)
+							}
+						} else if err := pm.StopPair(pair.ID); err != nil {
+							t.Errorf("StopPair: %v", err)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// The last call decides the state, whatever happened before
+			if err := pm.StartPair(pair); err != nil {
+				t.Fatalf("final StartPair: %v", err)
+			}
+			if hasTask, hasWorker := pairState(pm, pair.ID); !hasTask || hasWorker != tt.wantWorker {
+				t.Errorf("after start: task %v, worker %v, want task and worker %v", hasTask, hasWorker, tt.wantWorker)
+			}
+			if err := pm.StartPair(pair); err != nil {
+				t.Errorf("starting a running pair: %v", err)
+			}
+			if err := pm.StopPair(pair.ID); err != nil {
+				t.Fatal(err)
+			}
+			if hasTask, hasWorker := pairState(pm, pair.ID); hasTask || hasWorker {
+				t.Errorf("after stop: task %v, worker %v, want neither", hasTask, hasWorker)
+			}
+			if err := pm.StopPair(pair.ID); err != nil {
+				t.Errorf("stopping a stopped pair: %v", err)
+			}
+		})
+	}
+}
+
+func TestFillRunLimit(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxRuns       int
+		limitRuns     int
+		wantRemaining int // -1 when no countdown is reported
+	}{
+		{"no limit", 0, 4, -1},
+		{"runs left", 3, 1, 2},
+		{"limit used up", 3, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &scheduler.Task{Schedule: scheduler.Schedule{MaxRuns: tt.maxRuns}, LimitRuns: tt.limitRuns}
+			status := &PairStatus{}
+			fillRunLimit(status, task)
+
+			got := -1
+			if status.RunsRemaining != nil {
+				got = *status.RunsRemaining
+			}
+			if got != tt.wantRemaining {
+				t.Errorf("RunsRemaining = %d, want %d", got, tt.wantRemaining)
+			}
+			if status.MaxRuns != tt.maxRuns {
+				t.Errorf("MaxRuns = %d, want %d", status.MaxRuns, tt.maxRuns)
+			}
+		})
+	}
+}
+
+func TestSyncPairNowRetriesHaltedWatcher(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Source = filepath.Join(t.TempDir(), "missing")
+	pair.Schedule = scheduler.NewWatcherSchedule()
+	pair.HaltOnInitialSyncError = true
+
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the watcher to halt", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.WatcherHalted
+	})
+
+	writeTestFile(t, filepath.Join(pair.Source, "file.txt"), "data")
+	if err := pm.SyncPairNow(pair.ID); err != nil {
+		t.Fatalf("SyncPairNow: %v", err)
+	}
+	waitFor(t, 5*time.Second, "the retried initial sync", func() bool {
+		return readTestFile(t, filepath.Join(pair.Target, "file.txt")) == "data"
+	})
+}
+
+func TestSyncPairNowDoesNotReviveStoppedPair(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Source = filepath.Join(t.TempDir(), "missing")
+	pair.Schedule = scheduler.NewWatcherSchedule()
+	pair.HaltOnInitialSyncError = true
+
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the watcher to halt", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.WatcherHalted
+	})
+	if err := pm.StopPair(pair.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pm.SyncPairNow(pair.ID); err == nil {
+		t.Error("SyncPairNow of a stopped pair succeeded")
+	}
+	if pm.IsPairRunning(pair.ID) {
+		t.Error("stopped pair is running again")
+	}
+	pm.mutex.RLock()
+	_, hasWorker := pm.workers[pair.ID]
+	pm.mutex.RUnlock()
+	if hasWorker {
+		t.Error("stopped pair got its worker back")
+	}
+}
+
+func TestSyncPairNowRejectsPausedPair(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Schedule = scheduler.NewWatcherSchedule()
+	pair.Paused = true
+
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+	if err := pm.SyncPairNow(pair.ID); !errors.Is(err, ErrPairPaused) {
+		t.Errorf("SyncPairNow = %v, want ErrPairPaused", err)
+	}
+}
@@ -0,0 +1,104 @@
+// Package core provides the polling fallback of watcher pairs for the FolderSynchronizer application.
+// When the OS refuses more file watches (the inotify watch limit on Linux), parts of the source tree
+// would silently go unwatched; the worker instead stops watching and runs a comparison pass on a timer.
+package core
+
+import (
+	"FolderSynchronizer/internal/logging"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+// Watch modes reported in PairStatus.WatchMode
+const (
+	WatchModeEvents  = "events"  // File system events from the watcher
+	WatchModePolling = "polling" // Periodic comparison passes after hitting the watch limit
+)
+
+// ErrWatchLimit is returned by watch setup when the OS refuses further watches.
+var ErrWatchLimit = errors.New("file watch limit reached")
+
+// ===== WATCH LIMIT DETECTION =====
+
+// isWatchLimitError reports whether a watcher error means the OS watch limit is exhausted.
+func isWatchLimitError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC) || errors.Is(err, syscall.EMFILE)
+}
+
+// addWatch adds a directory to the watcher. Other failures are logged and
+// skipped; hitting the watch limit returns an error wrapping ErrWatchLimit.
+func addWatch(watcher *fsnotify.Watcher, path string) error {
+	err := watcher.Add(path)
+	if err == nil {
+		return nil
+	}
+	if isWatchLimitError(err) {
+		return fmt.Errorf("%w: %s: %v", ErrWatchLimit, path, err)
+	}
+	log.Error().Err(err).Str("dir", path).Msg("watch add failed")
+	return nil
+}
+
+// ===== POLLING FALLBACK =====
+
+// pollSource replaces the watcher after it hit the watch limit, running a comparison
+// pass every WatchPollInterval until the worker stops. catchUp runs the first pass
+// right away, for changes that may have been missed while watching.
+func (w *PairWorker) pollSource(cause error, catchUp bool) {
+	pair := w.Pair
+	interval := pair.WatchPollPeriod()
+	w.setWatchMode(WatchModePolling)
+
+	logging.ForPair(pair.ID).Warn().
+		Str("pair", pair.ID).
+		Err(cause).
+		Dur("interval", interval).
+		Msg("watch limit reached, falling back to polling (raise fs.inotify.max_user_watches to watch again)")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if catchUp {
+			w.pollOnce()
+		}
+		catchUp = true
+
+		select {
+		case <-ticker.C:
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// pollOnce runs one comparison pass of the polling fallback.
+func (w *PairWorker) pollOnce() {
+	pair := w.Pair
+	copier := &Copier{syncs: w.syncs}
+	copiedFiles, _, err := copier.CompareAndSync(w.ctx, pair)
+
+	switch {
+	case w.ctx.Err() != nil:
+	case errors.Is(err, ErrRateLimited):
+		logging.ForPair(pair.ID).Debug().Str("pair", pair.ID).Err(err).Msg("poll skipped")
+	case err != nil:
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("poll failed")
+	default:
+		logging.ForPair(pair.ID).Debug().Str("pair", pair.ID).Int("files", copiedFiles).Msg("poll completed")
+	}
+}
+
+// setWatchMode records how the worker detects source changes.
+func (w *PairWorker) setWatchMode(mode string) {
+	w.state.mutex.Lock()
+	defer w.state.mutex.Unlock()
+	w.state.watchMode = mode
+}
@@ -0,0 +1,167 @@
+// Package core provides per-pair run rate limiting for the FolderSynchronizer application.
+// A pair's MinRunInterval enforces a minimum gap between any two sync executions,
+// whether scheduled, manual, part of sync all or the watcher's initial sync.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// ErrRateLimited is returned when a sync starts before the pair's MinRunInterval
+// has elapsed since its last completed run. The returned errors also match
+// scheduler.ErrRunSkipped so scheduled runs are recorded as skipped, not failed.
+var ErrRateLimited = errors.New("rate limited")
+
+// ErrShuttingDown is returned for syncs started while the application drains
+// running syncs at shutdown. It wraps scheduler.ErrRunSkipped.
+var ErrShuttingDown = fmt.Errorf("%w: shutting down", scheduler.ErrRunSkipped)
+
+// rateLimitError describes a refused run; it matches ErrRateLimited and scheduler.ErrRunSkipped.
+type rateLimitError struct {
+	reason string
+}
+
+func (e *rateLimitError) Error() string {
+	return ErrRateLimited.Error() + ": " + e.reason
+}
+
+func (e *rateLimitError) Is(target error) bool {
+	return target == ErrRateLimited || target == scheduler.ErrRunSkipped
+}
+
+// ===== RUN TRACKING =====
+
+// runTracker records in-flight and completed sync runs per pair.
+type runTracker struct {
+	mutex      sync.Mutex
+	active     map[string]int       // Runs in progress per pair
+	completed  map[string]time.Time // End of the latest run per pair
+	started    map[string]int       // Runs started per pair since launch (PeriodicHashCheck)
+	throughput map[string]float64   // MB/s of the latest run per pair that copied data
+	draining   bool                 // Shutdown in progress; new runs are refused
+}
+
+// drainPollInterval is how often a shutdown drain checks for finished runs
+const drainPollInterval = 100 * time.Millisecond
+
+// pairRuns is the process-wide tracker shared by all sync entry points
+var pairRuns = &runTracker{
+	active:     make(map[string]int),
+	completed:  make(map[string]time.Time),
+	started:    make(map[string]int),
+	throughput: make(map[string]float64),
+}
+
+// begin registers the start of a sync run. Pairs with a MinRunInterval are
+// refused while another run is in progress or until the interval has elapsed
+// since the last completed run. It reports whether the run is due for a
+// periodic hash check.
+func (t *runTracker) begin(pair *cfg.Pair) (bool, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.draining {
+		return false, ErrShuttingDown
+	}
+	if gap := pair.MinRunGap(); gap > 0 {
+		if t.active[pair.ID] > 0 {
+			return false, &rateLimitError{reason: "another run is in progress"}
+		}
+		if last, ok := t.completed[pair.ID]; ok {
+			if wait := gap - time.Since(last); wait > 0 {
+				return false, &rateLimitError{reason: fmt.Sprintf("next run allowed in %s", wait.Round(time.Second))}
+			}
+		}
+	}
+
+	t.active[pair.ID]++
+	t.started[pair.ID]++
+
+	// Only the mtime strategy can miss content changes
+	every := pair.PeriodicHashCheck
+	if every <= 0 || (pair.SyncStrategy != "" && pair.SyncStrategy != SyncStrategyMTime) {
+		return false, nil
+	}
+	return t.started[pair.ID]%every == 0, nil
+}
+
+// end records the completion of a sync run started with begin.
+func (t *runTracker) end(pairID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.active[pairID] <= 1 {
+		delete(t.active, pairID)
+	} else {
+		t.active[pairID]--
+	}
+	t.completed[pairID] = time.Now()
+}
+
+// running returns the number of sync runs in progress across all pairs.
+func (t *runTracker) running() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	total := 0
+	for _, count := range t.active {
+		total += count
+	}
+	return total
+}
+
+// drain refuses new runs and waits up to timeout for the runs in progress to
+// finish. It returns the number of runs still in progress.
+func (t *runTracker) drain(timeout time.Duration) int {
+	t.mutex.Lock()
+	t.draining = true
+	t.mutex.Unlock()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		remaining := t.running()
+		if remaining == 0 || !time.Now().Before(deadline) {
+			return remaining
+		}
+		time.Sleep(drainPollInterval)
+	}
+}
+
+// resume accepts new runs again after a drain.
+func (t *runTracker) resume() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.draining = false
+}
+
+// lastCompleted returns the end of the pair's latest run, or nil if none completed.
+func (t *runTracker) lastCompleted(pairID string) *time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	last, ok := t.completed[pairID]
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+// recordThroughput stores the copy rate of a pair's latest run that copied data.
+func (t *runTracker) recordThroughput(pairID string, mbps float64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.throughput[pairID] = mbps
+}
+
+// lastThroughput returns the copy rate of the pair's latest run that copied data, or 0.
+func (t *runTracker) lastThroughput(pairID string) float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.throughput[pairID]
+}
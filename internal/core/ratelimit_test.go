@@ -0,0 +1,138 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"FolderSynchronizer/internal/scheduler"
+)
+
+// forgetPairRuns clears the process-wide run history of a pair now and when the test ends
+func forgetPairRuns(t *testing.T, pairID string) {
+	t.Helper()
+	forget := func() {
+		pairRuns.mutex.Lock()
+		delete(pairRuns.active, pairID)
+		delete(pairRuns.completed, pairID)
+		delete(pairRuns.started, pairID)
+		pairRuns.mutex.Unlock()
+	}
+	forget()
+	t.Cleanup(forget)
+}
+
+func TestMinRunIntervalLimitsBackToBackRuns(t *testing.T) {
+	tests := []struct {
+		name        string
+		interval    string
+		wait        time.Duration // pause between the two runs
+		wantLimited bool
+	}{
+		{"no limit", "", 0, false},
+		{"second run too soon", "1h", 0, true},
+		{"interval elapsed", "50ms", 100 * time.Millisecond, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.MinRunInterval = tt.interval
+			forgetPairRuns(t, pair.ID)
+			writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "a")
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatalf("first run: %v", err)
+			}
+			if pairRuns.lastCompleted(pair.ID) == nil {
+				t.Error("first run not recorded as completed")
+			}
+			time.Sleep(tt.wait)
+
+			_, err := syncTestPair(t, pair)
+			if limited := errors.Is(err, ErrRateLimited); limited != tt.wantLimited {
+				t.Fatalf("second run error = %v, want rate limited %v", err, tt.wantLimited)
+			}
+			if tt.wantLimited && !errors.Is(err, scheduler.ErrRunSkipped) {
+				t.Errorf("rate limited error %v doesn't match scheduler.ErrRunSkipped", err)
+			}
+			if !tt.wantLimited && err != nil {
+				t.Errorf("second run: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunTrackerRefusesOverlappingRuns(t *testing.T) {
+	pair := newTestPair(t)
+	pair.MinRunInterval = "1ms"
+	forgetPairRuns(t, pair.ID)
+
+	if _, err := pairRuns.begin(pair); err != nil {
+		t.Fatalf("first run refused: %v", err)
+	}
+	if _, err := pairRuns.begin(pair); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("overlapping run error = %v, want rate limited", err)
+	}
+	pairRuns.end(pair.ID)
+}
+
+func TestRateLimitedManualRunIsSkipped(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Schedule = scheduler.NewCronSchedule("0 0 0 * * *")
+	pair.MinRunInterval = "1h"
+	forgetPairRuns(t, pair.ID)
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pm.SyncPairNow(pair.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the first run", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.LastCompletedRun != nil
+	})
+
+	if err := pm.SyncPairNow(pair.ID); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the rate limited run", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.SkippedRuns == 1
+	})
+
+	status, err := pm.GetPairStatus(pair.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.FailCount != 0 || status.LastError != "" {
+		t.Errorf("rate limited run counted as a failure: %d failures, error %q", status.FailCount, status.LastError)
+	}
+	if !strings.Contains(status.LastSkipReason, ErrRateLimited.Error()) {
+		t.Errorf("LastSkipReason = %q, want a rate limit reason", status.LastSkipReason)
+	}
+}
+
+func TestClosedManagerEndsDrain(t *testing.T) {
+	pm, err := NewPairManager(DefaultMaxConcurrentSyncs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pair := newTestPair(t)
+	forgetPairRuns(t, pair.ID)
+
+	pm.Drain(0)
+	if _, err := syncTestPair(t, pair); !errors.Is(err, ErrShuttingDown) {
+		pm.Close()
+		t.Fatalf("sync while draining = %v, want ErrShuttingDown", err)
+	}
+
+	pm.Close()
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Errorf("sync after the drained manager closed = %v, want it to run", err)
+	}
+}
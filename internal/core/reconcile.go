@@ -0,0 +1,202 @@
+// Package core provides deferred reconciliation of failed watcher copies for the FolderSynchronizer application.
+// A file whose copy still fails after the short lock retries is re-attempted after a longer delay,
+// and listed as pending in the pair status until it is copied, so no change is silently dropped.
+// With ReconcileMaxAttempts set, a file is given up after that many re-attempts and stays
+// listed as failed until a later change of the file is copied.
+package core
+
+import (
+	"FolderSynchronizer/internal/logging"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// ReconcileDelay is how long after a failed watcher copy the file is re-attempted
+	ReconcileDelay = 30 * time.Second
+
+	// MaxReconcileDelay caps the delay between re-attempts of a file that keeps failing
+	MaxReconcileDelay = 10 * time.Minute
+)
+
+// ===== PENDING FILES =====
+
+// PendingFile describes a file whose watcher copy failed and is waiting to be re-attempted.
+type PendingFile struct {
+	Path      string    `json:"path"`             // Relative file path
+	Error     string    `json:"error"`            // Latest copy error
+	Attempts  int       `json:"attempts"`         // Failed reconcile attempts so far
+	Since     time.Time `json:"since"`            // When the first copy failed
+	NextRetry time.Time `json:"nextRetry"`        // When the file is re-attempted next
+	GaveUp    bool      `json:"gaveUp,omitempty"` // Re-attempts exhausted; waiting for the next change of the file
+}
+
+// reconcileEntry tracks one file scheduled for a deferred reconcile.
+type reconcileEntry struct {
+	sourcePath string        // Absolute source path
+	timer      *time.Timer   // Pending re-attempt
+	attempts   int           // Reconcile runs that failed
+	running    bool          // Whether the re-attempt is in progress
+	gaveUp     bool          // ReconcileMaxAttempts reached; no timer is pending
+	delay      time.Duration // Delay used for the current timer
+	since      time.Time     // First failure
+	nextRetry  time.Time     // Timer deadline
+	lastError  string        // Latest copy error
+}
+
+// reconcileQueue holds a worker's files awaiting a deferred reconcile.
+type reconcileQueue struct {
+	mutex   sync.Mutex
+	entries map[string]*reconcileEntry // By relative path
+	stopped bool                       // Set once the worker is stopping; no new runs start
+}
+
+// reset prepares the queue for a (re)started worker. The initial sync of the new
+// run covers any file left over from before.
+func (q *reconcileQueue) reset() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.entries = make(map[string]*reconcileEntry)
+	q.stopped = false
+}
+
+// stop cancels pending re-attempts and prevents new ones from starting.
+func (q *reconcileQueue) stop() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.stopped = true
+	for _, entry := range q.entries {
+		entry.timer.Stop()
+	}
+}
+
+// resolve forgets a file once it has been copied or no longer needs copying.
+func (q *reconcileQueue) resolve(relativePath string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if entry, exists := q.entries[relativePath]; exists {
+		entry.timer.Stop()
+		delete(q.entries, relativePath)
+	}
+}
+
+// snapshot returns the files whose deferred reconcile has failed at least once.
+func (q *reconcileQueue) snapshot() []PendingFile {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var pending []PendingFile
+	for path, entry := range q.entries {
+		if entry.attempts == 0 {
+			continue
+		}
+		pending = append(pending, PendingFile{
+			Path:      NormalizePath(path),
+			Error:     entry.lastError,
+			Attempts:  entry.attempts,
+			Since:     entry.since,
+			NextRetry: entry.nextRetry,
+			GaveUp:    entry.gaveUp,
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Path < pending[j].Path })
+	return pending
+}
+
+// ===== DEFERRED RECONCILE =====
+
+// scheduleReconcile re-attempts a file whose watcher copy failed after ReconcileDelay.
+// Further failures of the same file push the re-attempt back (debounced); failures
+// of the re-attempt itself double the delay up to MaxReconcileDelay, until the
+// pair's ReconcileMaxAttempts is reached. A failed event for a given-up file
+// starts the re-attempts over.
+func (w *PairWorker) scheduleReconcile(sourcePath, relativePath string, copyErr error) {
+	q := &w.reconcile
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.stopped {
+		return
+	}
+
+	entry, exists := q.entries[relativePath]
+	if !exists {
+		entry = &reconcileEntry{sourcePath: sourcePath, since: time.Now(), delay: ReconcileDelay}
+		entry.timer = time.AfterFunc(entry.delay, func() { w.runReconcile(relativePath) })
+		q.entries[relativePath] = entry
+	} else if entry.running {
+		// The re-attempt failed: back off, or give up once out of attempts
+		entry.running = false
+		entry.attempts++
+		if maxAttempts := w.Pair.ReconcileMaxAttempts; maxAttempts > 0 && entry.attempts >= maxAttempts {
+			entry.gaveUp = true
+			entry.lastError = copyErr.Error()
+			entry.nextRetry = time.Time{}
+			logging.ForPair(w.Pair.ID).Error().
+				Str("pair", w.Pair.ID).
+				Str("file", relativePath).
+				Int("attempts", entry.attempts).
+				Err(copyErr).
+				Msg("reconcile gave up")
+			return
+		}
+		entry.delay = min(entry.delay*2, MaxReconcileDelay)
+		entry.timer.Reset(entry.delay)
+	} else if entry.gaveUp {
+		// The file changed again: start the re-attempts over
+		entry.gaveUp = false
+		entry.attempts = 0
+		entry.delay = ReconcileDelay
+		entry.timer.Reset(entry.delay)
+	} else {
+		// Another event failed while waiting: restart the wait
+		entry.timer.Reset(entry.delay)
+	}
+	entry.lastError = copyErr.Error()
+	entry.nextRetry = time.Now().Add(entry.delay)
+
+	logging.ForPair(w.Pair.ID).Info().
+		Str("pair", w.Pair.ID).
+		Str("file", relativePath).
+		Int("attempts", entry.attempts).
+		Dur("retry_in", entry.delay).
+		Msg("reconcile scheduled")
+}
+
+// runReconcile re-attempts the copy of a pending file. It runs as part of the
+// worker so Stop waits for an attempt in progress.
+func (w *PairWorker) runReconcile(relativePath string) {
+	q := &w.reconcile
+	q.mutex.Lock()
+	entry, exists := q.entries[relativePath]
+	if q.stopped || !exists {
+		q.mutex.Unlock()
+		return
+	}
+	entry.running = true
+	sourcePath := entry.sourcePath
+	w.wg.Add(1)
+	q.mutex.Unlock()
+	defer w.wg.Done()
+
+	// A file deleted in the meantime has nothing left to copy
+	if _, err := os.Stat(sourcePath); err != nil {
+		q.resolve(relativePath)
+		logging.ForPair(w.Pair.ID).Debug().Str("pair", w.Pair.ID).Str("file", relativePath).Msg("pending file gone, reconcile dropped")
+		return
+	}
+
+	logging.ForPair(w.Pair.ID).Debug().Str("pair", w.Pair.ID).Str("file", relativePath).Msg("reconciling file")
+	w.handleFileModification(sourcePath, relativePath)
+
+	// Neither copied nor rescheduled: the file is now skipped by filters or size limits
+	q.mutex.Lock()
+	if q.entries[relativePath] == entry && entry.running {
+		delete(q.entries, relativePath)
+	}
+	q.mutex.Unlock()
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newReconcileWorker returns a worker ready to handle events without watching,
+// with its pending re-attempts cancelled when the test ends
+func newReconcileWorker(t *testing.T) *PairWorker {
+	t.Helper()
+	pair := newTestPair(t)
+	pair.CopyRetries = 1
+	pair.CopyRetryDelayMs = 10
+	worker, _ := newSetupWorker(t, pair)
+	worker.reconcile.reset()
+	t.Cleanup(worker.reconcile.stop)
+	return worker
+}
+
+// pendingFiles returns the pending files reported in the worker's status
+func pendingFiles(w *PairWorker) []PendingFile {
+	status := &PairStatus{}
+	w.fillStatus(status)
+	return status.PendingFiles
+}
+
+// isQueued reports whether a file waits for a deferred reconcile
+func isQueued(w *PairWorker, relativePath string) bool {
+	w.reconcile.mutex.Lock()
+	defer w.reconcile.mutex.Unlock()
+	_, queued := w.reconcile.entries[relativePath]
+	return queued
+}
+
+func TestLockedFileIsReconciledAfterUnlock(t *testing.T) {
+	w := newReconcileWorker(t)
+	sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+	targetPath := filepath.Join(w.Pair.Target, "a.txt")
+	writeTestFile(t, sourcePath, "content")
+	unblock := blockTarget(t, targetPath)
+
+	// The watcher copy exhausts its lock retries and queues a deferred reconcile
+	w.handleFileModification(sourcePath, "a.txt")
+	if !isQueued(w, "a.txt") {
+		t.Fatal("failed copy wasn't queued for a reconcile")
+	}
+	if pending := pendingFiles(w); len(pending) != 0 {
+		t.Errorf("file listed as pending before any reconcile failed: %+v", pending)
+	}
+
+	// A failing re-attempt lists the file as pending in the status
+	w.runReconcile("a.txt")
+	pending := pendingFiles(w)
+	if len(pending) != 1 || pending[0].Path != "a.txt" || pending[0].Attempts != 1 || pending[0].Error == "" || pending[0].GaveUp {
+		t.Fatalf("pending files = %+v, want a.txt after one failed attempt", pending)
+	}
+	if !pending[0].NextRetry.After(pending[0].Since) {
+		t.Errorf("next retry %v isn't scheduled after the first failure %v", pending[0].NextRetry, pending[0].Since)
+	}
+
+	// Once unlocked, the next re-attempt copies the file and clears it
+	unblock()
+	w.runReconcile("a.txt")
+	if got := readTestFile(t, targetPath); got != "content" {
+		t.Errorf("target = %q, want the reconciled content", got)
+	}
+	if isQueued(w, "a.txt") || len(pendingFiles(w)) != 0 {
+		t.Errorf("reconciled file still pending: %+v", pendingFiles(w))
+	}
+}
+
+func TestReconcileGivesUpAfterMaxAttempts(t *testing.T) {
+	w := newReconcileWorker(t)
+	w.Pair.ReconcileMaxAttempts = 2
+	sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+	writeTestFile(t, sourcePath, "content")
+	blockTarget(t, filepath.Join(w.Pair.Target, "a.txt"))
+
+	w.handleFileModification(sourcePath, "a.txt")
+	w.runReconcile("a.txt")
+	w.runReconcile("a.txt")
+
+	pending := pendingFiles(w)
+	if len(pending) != 1 || !pending[0].GaveUp || pending[0].Attempts != 2 || !pending[0].NextRetry.IsZero() {
+		t.Fatalf("pending files = %+v, want a.txt given up after 2 attempts", pending)
+	}
+
+	// A new failed event of the file starts the re-attempts over
+	w.handleFileModification(sourcePath, "a.txt")
+	if pending := pendingFiles(w); len(pending) != 0 {
+		t.Errorf("re-attempts didn't start over: %+v", pending)
+	}
+}
+
+func TestReconcileDropsDeletedFile(t *testing.T) {
+	w := newReconcileWorker(t)
+	sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+	writeTestFile(t, sourcePath, "content")
+	blockTarget(t, filepath.Join(w.Pair.Target, "a.txt"))
+
+	w.handleFileModification(sourcePath, "a.txt")
+	if err := os.Remove(sourcePath); err != nil {
+		t.Fatal(err)
+	}
+	w.runReconcile("a.txt")
+
+	if isQueued(w, "a.txt") {
+		t.Error("reconcile of a deleted source file still queued")
+	}
+}
+
+func TestReconcileMaxAttemptsAndBackoff(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		failures    int // failed re-attempts
+		wantGaveUp  bool
+		wantDelay   time.Duration // delay before the next re-attempt while still trying
+	}{
+		{"unlimited keeps trying", 0, 4, false, 16 * ReconcileDelay},
+		{"backs off under the limit", 3, 2, false, 4 * ReconcileDelay},
+		{"gives up at the limit", 3, 3, true, 0},
+		{"delay is capped", 0, 10, false, MaxReconcileDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newReconcileWorker(t)
+			w.Pair.ReconcileMaxAttempts = tt.maxAttempts
+			sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+			writeTestFile(t, sourcePath, "content")
+			unblock := blockTarget(t, filepath.Join(w.Pair.Target, "a.txt"))
+
+			w.handleFileModification(sourcePath, "a.txt")
+			for range tt.failures {
+				w.runReconcile("a.txt")
+			}
+
+			pending := pendingFiles(w)
+			if len(pending) != 1 || pending[0].Attempts != tt.failures || pending[0].GaveUp != tt.wantGaveUp {
+				t.Fatalf("pending files = %+v, want a.txt after %d attempts, gave up %v", pending, tt.failures, tt.wantGaveUp)
+			}
+			w.reconcile.mutex.Lock()
+			delay := w.reconcile.entries["a.txt"].delay
+			w.reconcile.mutex.Unlock()
+			if !tt.wantGaveUp && delay != tt.wantDelay {
+				t.Errorf("next re-attempt delay = %s, want %s", delay, tt.wantDelay)
+			}
+
+			// Released, the file is copied by the next re-attempt or change
+			unblock()
+			if tt.wantGaveUp {
+				w.handleFileModification(sourcePath, "a.txt")
+			} else {
+				w.runReconcile("a.txt")
+			}
+			if readTestFile(t, filepath.Join(w.Pair.Target, "a.txt")) != "content" || isQueued(w, "a.txt") {
+				t.Errorf("released file not reconciled: pending %+v", pendingFiles(w))
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Package core provides source root recovery for watcher pairs of the FolderSynchronizer application.
+// When the watched source root is deleted or moved away the watcher loses all its watches; the worker
+// then reports the pair as degraded and checks with backoff until the directory is back, re-adds the
+// watches and runs a comparison pass to pick up what changed in the meantime.
+package core
+
+import (
+	"FolderSynchronizer/internal/logging"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// SourceRecoveryDelay is how long after losing the source root it is first checked again
+	SourceRecoveryDelay = time.Second
+
+	// MaxSourceRecoveryDelay caps the delay between checks for a missing source root
+	MaxSourceRecoveryDelay = time.Minute
+)
+
+// ===== SOURCE RECOVERY =====
+
+// sourceRecovery schedules checks for a missing source root from the watch loop.
+type sourceRecovery struct {
+	delay time.Duration // Delay before the pending check
+	timer *time.Timer   // Pending check; nil while the source is watched
+}
+
+// C returns the channel of the pending check, or nil (never ready) while the source is watched.
+func (r *sourceRecovery) C() <-chan time.Time {
+	if r.timer == nil {
+		return nil
+	}
+	return r.timer.C
+}
+
+// active reports whether the source root is missing.
+func (r *sourceRecovery) active() bool {
+	return r.timer != nil
+}
+
+// schedule arms the next check, doubling the delay after each unsuccessful one.
+func (r *sourceRecovery) schedule() {
+	if r.timer == nil {
+		r.delay = SourceRecoveryDelay
+		r.timer = time.NewTimer(r.delay)
+		return
+	}
+	r.delay = min(r.delay*2, MaxSourceRecoveryDelay)
+	r.timer.Reset(r.delay)
+}
+
+// stop ends recovery.
+func (r *sourceRecovery) stop() {
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+}
+
+// isSourceRootLoss reports whether an event removed or moved away a source root itself.
+func (w *PairWorker) isSourceRootLoss(event fsnotify.Event) bool {
+	if event.Op&(fsnotify.Remove|fsnotify.Rename) == 0 {
+		return false
+	}
+	for _, root := range w.Pair.SourceRoots() {
+		if filepath.Clean(event.Name) == filepath.Clean(root) {
+			return !sourceRootPresent(root)
+		}
+	}
+	return false
+}
+
+// sourceRootPresent reports whether the source root exists as a directory.
+func sourceRootPresent(root string) bool {
+	info, err := os.Stat(root)
+	return err == nil && info.IsDir()
+}
+
+// sourceRootsPresent reports whether all source roots exist as directories.
+func sourceRootsPresent(roots []string) bool {
+	for _, root := range roots {
+		if !sourceRootPresent(root) {
+			return false
+		}
+	}
+	return true
+}
+
+// watchSourceRoots adds the directories of every source root to the watcher.
+// A missing root is skipped and reported, so the other sources are still watched.
+func (w *PairWorker) watchSourceRoots(watcher *fsnotify.Watcher) (missing bool, err error) {
+	for _, root := range w.Pair.SourceRoots() {
+		err := w.addDirectoriesToWatcher(watcher, root, &w.watchSetup)
+		if err == nil {
+			continue
+		}
+		if w.ctx.Err() != nil || errors.Is(err, ErrWatchLimit) || sourceRootPresent(root) {
+			return missing, err
+		}
+		missing = true
+	}
+	return missing, nil
+}
+
+// beginSourceRecovery marks the pair degraded and schedules the first check.
+func (w *PairWorker) beginSourceRecovery(recovery *sourceRecovery) {
+	if recovery.active() {
+		return
+	}
+	w.setSourceMissing(true)
+	recovery.schedule()
+
+	logging.ForPair(w.Pair.ID).Warn().
+		Str("pair", w.Pair.ID).
+		Strs("sources", w.Pair.SourceRoots()).
+		Msg("source directory missing, watching suspended until it reappears")
+}
+
+// recoverSource checks for the source roots and, once all are back, re-adds the
+// watches and syncs the changes made while one was missing. Otherwise it
+// schedules the next check.
+func (w *PairWorker) recoverSource(watcher *fsnotify.Watcher, recovery *sourceRecovery) {
+	pair := w.Pair
+	if !sourceRootsPresent(pair.SourceRoots()) {
+		recovery.schedule()
+		logging.ForPair(pair.ID).Debug().Str("pair", pair.ID).Dur("retry_in", recovery.delay).Msg("source directory still missing")
+		return
+	}
+
+	missing, err := w.watchSourceRoots(watcher)
+	if err != nil {
+		if w.ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, ErrWatchLimit) {
+			// The watch loop switches to polling
+			recovery.stop()
+			w.setSourceMissing(false)
+			w.watchLimitErr = err
+			return
+		}
+		logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Err(err).Msg("re-adding watches failed")
+		recovery.schedule()
+		return
+	}
+	if missing {
+		recovery.schedule() // Gone again while the watches were added
+		return
+	}
+
+	recovery.stop()
+	w.setSourceMissing(false)
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Strs("sources", pair.SourceRoots()).Msg("source directory back, watching resumed")
+
+	copier := &Copier{syncs: w.syncs}
+	if _, _, err := copier.CompareAndSync(w.ctx, pair); err != nil && w.ctx.Err() == nil {
+		if errors.Is(err, ErrRateLimited) {
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Err(err).Msg("catch-up sync skipped")
+		} else {
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("catch-up sync failed")
+		}
+	}
+}
+
+// setSourceMissing records whether the source root is missing.
+func (w *PairWorker) setSourceMissing(missing bool) {
+	w.state.mutex.Lock()
+	defer w.state.mutex.Unlock()
+
+	w.state.sourceMissingSince = nil
+	if missing {
+		now := time.Now()
+		w.state.sourceMissingSince = &now
+	}
+}
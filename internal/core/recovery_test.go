@@ -0,0 +1,124 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"FolderSynchronizer/internal/scheduler"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestSourceRecoveryBackoff(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules int
+		want      time.Duration
+	}{
+		{"first check", 1, SourceRecoveryDelay},
+		{"doubles", 3, 4 * SourceRecoveryDelay},
+		{"capped", 20, MaxSourceRecoveryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var recovery sourceRecovery
+			defer recovery.stop()
+			for range tt.schedules {
+				recovery.schedule()
+			}
+			if recovery.delay != tt.want {
+				t.Errorf("delay = %s, want %s", recovery.delay, tt.want)
+			}
+			if !recovery.active() || recovery.C() == nil {
+				t.Error("scheduled recovery isn't active")
+			}
+
+			recovery.stop()
+			if recovery.active() || recovery.C() != nil {
+				t.Error("stopped recovery is still active")
+			}
+		})
+	}
+}
+
+func TestIsSourceRootLoss(t *testing.T) {
+	tests := []struct {
+		name       string
+		op         fsnotify.Op
+		child      string // event path below the source root, "" for the root
+		removeRoot bool
+		want       bool
+	}{
+		{"root removed", fsnotify.Remove, "", true, true},
+		{"root renamed away", fsnotify.Rename, "", true, true},
+		{"root still there", fsnotify.Remove, "", false, false},
+		{"file below the root removed", fsnotify.Remove, "a.txt", true, false},
+		{"root written", fsnotify.Write, "", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.Source = filepath.Join(pair.Source, "src")
+			if !tt.removeRoot {
+				if err := os.Mkdir(pair.Source, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			w := &PairWorker{Pair: pair}
+
+			event := fsnotify.Event{Name: filepath.Join(pair.Source, tt.child), Op: tt.op}
+			if got := w.isSourceRootLoss(event); got != tt.want {
+				t.Errorf("isSourceRootLoss(%v) = %v, want %v", event, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncResumesAfterSourceRecreated(t *testing.T) {
+	pm := newTestPairManager(t)
+	pair := newTestPair(t)
+	pair.Source = filepath.Join(pair.Source, "src")
+	pair.Schedule = scheduler.NewWatcherSchedule()
+	pair.DebounceMs = 10
+	writeTestFile(t, filepath.Join(pair.Source, "before.txt"), "before")
+
+	if err := pm.StartPair(pair); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the watcher to start", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.WatchMode == WatchModeEvents && readTestFile(t, filepath.Join(pair.Target, "before.txt")) != ""
+	})
+
+	// Losing the source root degrades the pair
+	if err := os.RemoveAll(pair.Source); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, "the missing source to be reported", func() bool {
+		status, err := pm.GetPairStatus(pair.ID)
+		return err == nil && status.SourceMissing && status.SourceMissingSince != nil
+	})
+
+	// Files created while it was gone are caught up once it returns
+	writeTestFile(t, filepath.Join(pair.Source, "during.txt"), "during")
+	waitFor(t, 10*time.Second, "the catch-up sync", func() bool {
+		return readTestFile(t, filepath.Join(pair.Target, "during.txt")) == "during"
+	})
+	status, err := pm.GetPairStatus(pair.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.SourceMissing {
+		t.Error("pair still reported degraded after the source returned")
+	}
+
+	// Watches are back: later changes sync from events again
+	writeTestFile(t, filepath.Join(pair.Source, "after.txt"), "after")
+	waitFor(t, 5*time.Second, "a watched change after recovery", func() bool {
+		return readTestFile(t, filepath.Join(pair.Target, "after.txt")) == "after"
+	})
+}
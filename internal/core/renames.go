@@ -0,0 +1,232 @@
+// Package core provides rename correlation for watcher pairs of the FolderSynchronizer application.
+// A source path renamed away is held briefly; when a matching file or directory appears elsewhere in
+// the source, its target is moved instead of deleted and copied again. Unclaimed renames were moves
+// out of the watched tree and their targets are deleted (inline mirror deletes only).
+package core
+
+import (
+	"FolderSynchronizer/internal/logging"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+// RenameMatchWindow is how long, beyond the debounce window, a renamed-away path waits for its new name
+const RenameMatchWindow = time.Second
+
+// ===== RENAME QUEUE =====
+
+// renameEntry describes a source path renamed away whose target is still in place.
+type renameEntry struct {
+	relativePath string      // Old path relative to the source root
+	targetPath   string      // Target of the old path
+	isDir        bool        // Whether the target is a directory
+	size         int64       // Target file size
+	modTime      time.Time   // Target modification time (preserved from the source)
+	timer        *time.Timer // Deletes the target when the window passes unclaimed
+}
+
+// renameQueue holds a worker's renamed-away paths awaiting their new name.
+type renameQueue struct {
+	mutex   sync.Mutex
+	entries map[string]*renameEntry // By old relative path
+	stopped bool                    // Set once the worker is stopping; no deletes start
+}
+
+// reset prepares the queue for a (re)started worker.
+func (q *renameQueue) reset() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.entries = make(map[string]*renameEntry)
+	q.stopped = false
+}
+
+// stop cancels pending deletes. Targets left behind are handled by the next full sync.
+func (q *renameQueue) stop() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.stopped = true
+	for _, entry := range q.entries {
+		entry.timer.Stop()
+	}
+}
+
+// claim removes and returns the first entry accepted by match, preferring one
+// with the given base name.
+func (q *renameQueue) claim(baseName string, match func(*renameEntry) bool) *renameEntry {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var found *renameEntry
+	for _, entry := range q.entries {
+		if !match(entry) {
+			continue
+		}
+		if found == nil || filepath.Base(entry.relativePath) == baseName {
+			found = entry
+		}
+	}
+	if found != nil {
+		found.timer.Stop()
+		delete(q.entries, found.relativePath)
+	}
+	return found
+}
+
+// ===== RENAME TRACKING =====
+
+// trackRename holds a renamed-away source path so a following create can move
+// its target. It reports whether the event was taken over; otherwise it is
+// processed like any other event.
+func (w *PairWorker) trackRename(sourcePath string) bool {
+	pair := w.Pair
+	if !pair.MirrorDeletes || pair.DefersMirrorDeletes() || len(pair.Targets) > 1 {
+		return false
+	}
+	if _, err := os.Lstat(sourcePath); !os.IsNotExist(err) {
+		return false // Still there (or unreadable): not a rename away
+	}
+
+	// Another source still provides the path
+	relativePath := sourceRelPath(pair, sourcePath)
+	if len(pair.Sources) > 1 && sourceExistsFor(pair, relativePath) {
+		return false
+	}
+	targetPath := targetPathFor(pair, relativePath)
+	targetInfo, err := os.Lstat(targetPath)
+	if err != nil {
+		return false
+	}
+
+	q := &w.renames
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.stopped {
+		return false
+	}
+
+	if previous, exists := q.entries[relativePath]; exists {
+		previous.timer.Stop()
+	}
+	entry := &renameEntry{
+		relativePath: relativePath,
+		targetPath:   targetPath,
+		isDir:        targetInfo.IsDir(),
+		size:         targetInfo.Size(),
+		modTime:      targetInfo.ModTime(),
+	}
+	window := time.Duration(pair.DebounceMs)*time.Millisecond + RenameMatchWindow
+	entry.timer = time.AfterFunc(window, func() { w.expireRename(entry) })
+	q.entries[relativePath] = entry
+	return true
+}
+
+// expireRename deletes the target of a path that was renamed out of the source tree.
+func (w *PairWorker) expireRename(entry *renameEntry) {
+	q := &w.renames
+	q.mutex.Lock()
+	if q.stopped || q.entries[entry.relativePath] != entry {
+		q.mutex.Unlock()
+		return
+	}
+	delete(q.entries, entry.relativePath)
+	w.wg.Add(1)
+	q.mutex.Unlock()
+	defer w.wg.Done()
+
+	pair := w.Pair
+	if sourceExistsFor(pair, entry.relativePath) {
+		return // Renamed back in the meantime
+	}
+
+	var err error
+	if entry.isDir {
+		ownWrites.begin(entry.targetPath)
+		err = os.RemoveAll(entry.targetPath)
+		ownWrites.end(entry.targetPath)
+	} else {
+		err = removeOwnedFile(entry.targetPath)
+	}
+	if err != nil {
+		logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("file", entry.relativePath).Err(err).Msg("delete of renamed-away path failed")
+		return
+	}
+
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", entry.relativePath).Msg("deleted (renamed away)")
+	recordEvent(EventFileDeleted, pair.ID, NormalizePath(entry.relativePath), "watcher rename")
+}
+
+// moveRenamedFile moves the target of a renamed-away file with the same size and
+// modification time to the target of a new source file. It reports whether the
+// moved target is up to date, so the copy can be skipped.
+func (w *PairWorker) moveRenamedFile(sourceInfo os.FileInfo, relativePath, targetPath string) bool {
+	entry := w.renames.claim(filepath.Base(relativePath), func(entry *renameEntry) bool {
+		return !entry.isDir && entry.size == sourceInfo.Size() &&
+			sameModTime(entry.modTime, sourceInfo.ModTime())
+	})
+	if entry == nil {
+		return false
+	}
+
+	if err := w.moveTarget(entry, relativePath, targetPath); err != nil {
+		return false
+	}
+
+	// The content may have changed along with the name
+	movedInfo, err := os.Stat(targetPath)
+	return err == nil && movedInfo.Size() == sourceInfo.Size() && sameModTime(movedInfo.ModTime(), sourceInfo.ModTime())
+}
+
+// moveRenamedDirectory moves the target of a renamed-away directory to the target
+// of a directory that just appeared in the source, so its files aren't copied again.
+func (w *PairWorker) moveRenamedDirectory(relativePath string) {
+	targetPath := targetPathFor(w.Pair, relativePath)
+	if _, err := os.Lstat(targetPath); err == nil {
+		return // Target already there
+	}
+
+	entry := w.renames.claim(filepath.Base(relativePath), func(entry *renameEntry) bool {
+		return entry.isDir
+	})
+	if entry != nil {
+		_ = w.moveTarget(entry, relativePath, targetPath)
+	}
+}
+
+// moveTarget renames a claimed target to its new location. On failure the old
+// target is deleted, as it would have been without the new name.
+func (w *PairWorker) moveTarget(entry *renameEntry, relativePath, targetPath string) error {
+	pair := w.Pair
+
+	err := os.MkdirAll(filepath.Dir(targetPath), DefaultDirPerms)
+	if err == nil {
+		ownWrites.begin(entry.targetPath, targetPath)
+		err = os.Rename(entry.targetPath, targetPath)
+		ownWrites.end(entry.targetPath, targetPath)
+	}
+	if err != nil {
+		logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("from", entry.relativePath).Str("to", relativePath).Err(err).Msg("target move failed")
+		if entry.isDir {
+			_ = os.RemoveAll(entry.targetPath)
+		} else {
+			_ = removeOwnedFile(entry.targetPath)
+		}
+		return err
+	}
+
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("from", entry.relativePath).Str("to", relativePath).Msg("moved (event)")
+	recordEvent(EventFileMoved, pair.ID, NormalizePath(relativePath), "from "+NormalizePath(entry.relativePath))
+	return nil
+}
+
+// sameModTime compares modification times with the cross-filesystem tolerance.
+func sameModTime(a, b time.Time) bool {
+	diff := a.Sub(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= ModTimeToleranceSeconds*time.Second
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// newRenameWorker returns a synced mirror-deletes worker ready to handle events
+// without watching, with its pending renames cancelled when the test ends
+func newRenameWorker(t *testing.T) (*PairWorker, *fsnotify.Watcher) {
+	t.Helper()
+	pair := newTestPair(t)
+	pair.MirrorDeletes = true
+	pair.DebounceMs = 10
+	worker, watcher := newSetupWorker(t, pair)
+	worker.renames.reset()
+	t.Cleanup(worker.renames.stop)
+	return worker, watcher
+}
+
+// renameSource renames a source path and reports it to the worker
+func renameSource(t *testing.T, w *PairWorker, watcher *fsnotify.Watcher, from, to string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		t.Fatal(err)
+	}
+	debouncer := NewDebouncer(w.Pair.DebounceMs)
+	t.Cleanup(debouncer.Close)
+	w.handleFileSystemEvent(fsnotify.Event{Name: from, Op: fsnotify.Rename}, watcher, debouncer)
+}
+
+func TestTrackRename(t *testing.T) {
+	tests := []struct {
+		name        string
+		mirror      bool
+		deleteMode  string
+		removeSrc   bool // the source is renamed away
+		syncTarget  bool // the target holds a copy of the source
+		wantTracked bool
+	}{
+		{"renamed away", true, "", true, true, true},
+		{"mirror deletes off", false, "", true, true, false},
+		{"deferred mirror deletes", true, cfg.MirrorDeleteDeferred, true, true, false},
+		{"source still there", true, "", false, true, false},
+		{"nothing in the target", true, "", true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _ := newRenameWorker(t)
+			w.Pair.MirrorDeletes = tt.mirror
+			w.Pair.MirrorDeleteMode = tt.deleteMode
+			sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+			if !tt.removeSrc {
+				writeTestFile(t, sourcePath, "a")
+			}
+			if tt.syncTarget {
+				writeTestFile(t, filepath.Join(w.Pair.Target, "a.txt"), "a")
+			}
+
+			if got := w.trackRename(sourcePath); got != tt.wantTracked {
+				t.Errorf("trackRename() = %v, want %v", got, tt.wantTracked)
+			}
+		})
+	}
+}
+
+func TestRenameWithinTreeMovesTarget(t *testing.T) {
+	w, watcher := newRenameWorker(t)
+	oldSource := filepath.Join(w.Pair.Source, "old", "report.txt")
+	writeTestFile(t, oldSource, "report body")
+	if _, err := syncTestPair(t, w.Pair); err != nil {
+		t.Fatal(err)
+	}
+	oldTarget := filepath.Join(w.Pair.Target, "old", "report.txt")
+	before, err := os.Stat(oldTarget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newSource := filepath.Join(w.Pair.Source, "new", "report.txt")
+	renameSource(t, w, watcher, oldSource, newSource)
+	w.handleFileModification(newSource, filepath.Join("new", "report.txt"))
+
+	newTarget := filepath.Join(w.Pair.Target, "new", "report.txt")
+	after, err := os.Stat(newTarget)
+	if err != nil {
+		t.Fatalf("target not moved: %v", err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("target was copied again instead of moved")
+	}
+	if readTestFile(t, oldTarget) != "" {
+		t.Error("old target still present after the move")
+	}
+}
+
+func TestRenamedDirectoryMovesTarget(t *testing.T) {
+	w, watcher := newRenameWorker(t)
+	writeTestFile(t, filepath.Join(w.Pair.Source, "photos", "a.jpg"), "a")
+	writeTestFile(t, filepath.Join(w.Pair.Source, "photos", "b.jpg"), "b")
+	if _, err := syncTestPair(t, w.Pair); err != nil {
+		t.Fatal(err)
+	}
+
+	newDir := filepath.Join(w.Pair.Source, "archive", "photos-2024")
+	renameSource(t, w, watcher, filepath.Join(w.Pair.Source, "photos"), newDir)
+	w.handleDirectoryCreation(newDir, watcher)
+
+	for _, name := range []string{"a.jpg", "b.jpg"} {
+		if readTestFile(t, filepath.Join(w.Pair.Target, "archive", "photos-2024", name)) == "" {
+			t.Errorf("%s not moved with its directory", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(w.Pair.Target, "photos")); !os.IsNotExist(err) {
+		t.Errorf("old target directory still present: %v", err)
+	}
+}
+
+func TestRenameOutOfTreeDeletesTarget(t *testing.T) {
+	w, watcher := newRenameWorker(t)
+	sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+	writeTestFile(t, sourcePath, "a")
+	if _, err := syncTestPair(t, w.Pair); err != nil {
+		t.Fatal(err)
+	}
+
+	renameSource(t, w, watcher, sourcePath, filepath.Join(t.TempDir(), "a.txt"))
+	targetPath := filepath.Join(w.Pair.Target, "a.txt")
+	if readTestFile(t, targetPath) == "" {
+		t.Fatal("target deleted before the rename window passed")
+	}
+
+	waitFor(t, 5*time.Second, "renamed-away target deleted", func() bool {
+		_, err := os.Stat(targetPath)
+		return os.IsNotExist(err)
+	})
+}
+
+func TestStoppedRenameQueueKeepsTarget(t *testing.T) {
+	w, watcher := newRenameWorker(t)
+	sourcePath := filepath.Join(w.Pair.Source, "a.txt")
+	writeTestFile(t, sourcePath, "a")
+	if _, err := syncTestPair(t, w.Pair); err != nil {
+		t.Fatal(err)
+	}
+
+	renameSource(t, w, watcher, sourcePath, filepath.Join(t.TempDir(), "a.txt"))
+	w.renames.stop()
+	time.Sleep(RenameMatchWindow + 200*time.Millisecond)
+
+	// The next full sync handles what a stopping worker left behind
+	if readTestFile(t, filepath.Join(w.Pair.Target, "a.txt")) != "a" {
+		t.Error("stopped worker deleted the renamed-away target")
+	}
+}
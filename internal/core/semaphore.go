@@ -0,0 +1,82 @@
+// Package core provides a counting semaphore used to bound concurrent operations
+// (hook executions, file handles, syncs) across all pairs.
+package core
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// ===== SEMAPHORE IMPLEMENTATION =====
+
+// Semaphore limits the number of concurrent holders.
+// A nil *Semaphore imposes no limit.
+type Semaphore struct {
+	slots   chan struct{} // Buffered channel holding one token per active holder
+	waiting atomic.Int64  // Number of callers blocked in Acquire
+}
+
+// NewSemaphore creates a semaphore with the given capacity.
+// A capacity of zero or less returns nil, meaning unlimited.
+func NewSemaphore(capacity int) *Semaphore {
+	if capacity <= 0 {
+		return nil
+	}
+	return &Semaphore{slots: make(chan struct{}, capacity)}
+}
+
+// Acquire blocks until a slot is available or the context is cancelled.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	// Fast path without touching the waiting counter
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	s.waiting.Add(1)
+	defer s.waiting.Add(-1)
+
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot previously obtained with Acquire.
+func (s *Semaphore) Release() {
+	if s == nil {
+		return
+	}
+	<-s.slots
+}
+
+// InUse returns the number of currently held slots.
+func (s *Semaphore) InUse() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.slots)
+}
+
+// Waiting returns the number of callers currently blocked in Acquire.
+func (s *Semaphore) Waiting() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.waiting.Load())
+}
+
+// Capacity returns the maximum number of concurrent holders (0 = unlimited).
+func (s *Semaphore) Capacity() int {
+	if s == nil {
+		return 0
+	}
+	return cap(s.slots)
+}
@@ -0,0 +1,92 @@
+// Package core provides multi-source support for the FolderSynchronizer application.
+// A pair may merge several source directories into one target. Sources are searched in
+// list order: the earliest source holding a relative path provides the file, and a target
+// file is only orphaned once no source holds it any more.
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== SOURCE ROOTS =====
+
+// sourceRootOf returns the source root containing path.
+func sourceRootOf(pair *cfg.Pair, path string) (string, bool) {
+	cleaned := filepath.Clean(path)
+	for _, root := range pair.SourceRoots() {
+		root = filepath.Clean(root)
+		if cleaned == root || strings.HasPrefix(cleaned, root+string(filepath.Separator)) {
+			return root, true
+		}
+	}
+	return "", false
+}
+
+// sourceRelPath returns a source path relative to the source root containing it.
+func sourceRelPath(pair *cfg.Pair, path string) string {
+	if root, ok := sourceRootOf(pair, path); ok {
+		return RelPath(root, path)
+	}
+	return RelPath(pair.Source, path)
+}
+
+// sourceCandidates returns the paths of every source holding relativePath, in priority order.
+func sourceCandidates(pair *cfg.Pair, relativePath string) []string {
+	var candidates []string
+	for _, root := range pair.SourceRoots() {
+		path := filepath.Join(root, relativePath)
+		if _, err := os.Lstat(path); err == nil {
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates
+}
+
+// sourcePathFor returns the source path providing relativePath: the first source
+// holding it, or the path in the first source when none does.
+func sourcePathFor(pair *cfg.Pair, relativePath string) string {
+	if len(pair.Sources) > 1 {
+		if candidates := sourceCandidates(pair, relativePath); len(candidates) > 0 {
+			return candidates[0]
+		}
+	}
+	return filepath.Join(pair.Source, relativePath)
+}
+
+// sourceConflictError describes a relative path found in more than one source.
+func sourceConflictError(relativePath string, paths ...string) error {
+	return fmt.Errorf("source conflict: %s exists in %s", NormalizePath(relativePath), strings.Join(paths, " and "))
+}
+
+// ===== WATCHER EVENTS =====
+
+// providingSource maps a watcher event path to the source path that provides the file.
+// An event in a later source is redirected to an earlier source holding the same path;
+// with SourceConflict "error" such a collision is logged and nothing is synced.
+func (w *PairWorker) providingSource(sourcePath, relativePath string) (string, bool) {
+	pair := w.Pair
+	if len(pair.Sources) <= 1 {
+		return sourcePath, true
+	}
+
+	candidates := sourceCandidates(pair, relativePath)
+	switch {
+	case len(candidates) == 0:
+		return sourcePath, true // Gone from all sources
+	case len(candidates) > 1 && pair.SourceConflict == cfg.SourceConflictError:
+		logging.ForPair(pair.ID).Error().
+			Str("pair", pair.ID).
+			Str("file", relativePath).
+			Err(sourceConflictError(relativePath, candidates...)).
+			Msg("skipped (source conflict)")
+		return "", false
+	default:
+		return candidates[0], true
+	}
+}
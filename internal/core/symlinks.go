@@ -0,0 +1,267 @@
+// Package core provides symlink handling for the FolderSynchronizer application.
+// A pair's SymlinkMode decides whether source links are followed, recreated as links or skipped.
+// Absolute links into the source tree break once the tree is mirrored to another root;
+// rewriting them as relative or target-root paths keeps recreated links pointing inside the copy.
+package core
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== SOURCE WALK =====
+
+// walkSource walks a source root like filepath.WalkDir, applying the pair's symlink
+// mode. Skipped links aren't reported; with "copy-link" links are reported as they are.
+// Followed file links are reported with the pointed-to file's info and followed
+// directory links are descended into under the link's path, unless that would loop.
+// Directories beyond the pair's MaxDepth are reported but not descended into.
+func walkSource(pair *cfg.Pair, root string, fn func(path, relativePath string, dirEntry fs.DirEntry) error) error {
+	return walkSourceDir(pair, root, root, "", nil, fn)
+}
+
+// walkSourceDir walks dir, the resolved directory of the source path base, reporting
+// paths under base and relative to the source root (relDir joined with the path below
+// dir). followed holds the resolved directories of the links being followed.
+func walkSourceDir(pair *cfg.Pair, dir, base, relDir string, followed []string, fn func(path, relativePath string, dirEntry fs.DirEntry) error) error {
+	return filepath.WalkDir(dir, func(walkPath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		below, err := filepath.Rel(dir, walkPath)
+		if err != nil {
+			return err
+		}
+		path, relativePath := walkPath, below
+		if relDir != "" {
+			if below == "." {
+				return nil // The followed link itself
+			}
+			path = filepath.Join(base, below)
+			relativePath = filepath.Join(relDir, below)
+		}
+
+		if dirEntry.IsDir() && dirBeyondMaxDepth(pair, relativePath) {
+			return reportBeyondMaxDepth(path, relativePath, dirEntry, fn)
+		}
+		if dirEntry.Type()&fs.ModeSymlink == 0 {
+			return fn(path, relativePath, dirEntry)
+		}
+
+		switch pair.SymlinkMode {
+		case cfg.SymlinkModeSkip:
+			return nil
+		case cfg.SymlinkModeCopyLink:
+			return fn(path, relativePath, dirEntry)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("link", relativePath).Err(err).Msg("dangling symlink skipped")
+			return nil
+		}
+		if !info.IsDir() {
+			return fn(path, relativePath, fs.FileInfoToDirEntry(info))
+		}
+
+		if dirBeyondMaxDepth(pair, relativePath) {
+			if err := reportBeyondMaxDepth(path, relativePath, fs.FileInfoToDirEntry(info), fn); err != filepath.SkipDir {
+				return err
+			}
+			return nil // SkipDir would skip the rest of the link's parent
+		}
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil
+		}
+		if symlinkLoops(path, resolved, followed) {
+			logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("link", relativePath).Str("link_target", resolved).Msg("symlink loop, not followed")
+			return nil
+		}
+		return walkSourceDir(pair, resolved, path, relativePath, append(followed[:len(followed):len(followed)], resolved), fn)
+	})
+}
+
+// reportBeyondMaxDepth reports a directory beyond the pair's MaxDepth to fn and
+// returns filepath.SkipDir so the walk doesn't descend into it.
+func reportBeyondMaxDepth(path, relativePath string, dirEntry fs.DirEntry, fn func(path, relativePath string, dirEntry fs.DirEntry) error) error {
+	if err := fn(path, relativePath, dirEntry); err != nil && err != filepath.SkipDir {
+		return err
+	}
+	return filepath.SkipDir
+}
+
+// symlinkLoops reports whether following the directory link at path, resolving to
+// resolved, would descend into a directory already being walked.
+func symlinkLoops(path, resolved string, followed []string) bool {
+	within := func(dir, ancestor string) bool {
+		rel, err := filepath.Rel(ancestor, dir)
+		return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+	}
+
+	if parent, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil && within(parent, resolved) {
+		return true
+	}
+	for _, dir := range followed {
+		if within(dir, resolved) {
+			return true
+		}
+	}
+	return false
+}
+
+// ===== LINK COPY =====
+
+// copySymlink recreates the source link at relativePath in the target, rewriting
+// its target per SymlinkRewrite. It reports whether the target changed; a link
+// already pointing to the same place is left alone.
+func copySymlink(pair *cfg.Pair, sourcePath, relativePath string, dryRun bool) (bool, error) {
+	linkTarget, err := os.Readlink(sourcePath)
+	if err != nil {
+		return false, err
+	}
+	linkTarget = rewriteSymlinkTarget(pair, relativePath, linkTarget)
+
+	targetPath := targetPathFor(pair, relativePath)
+	if current, err := os.Readlink(targetPath); err == nil && current == linkTarget {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), DefaultDirPerms); err != nil {
+		return false, err
+	}
+
+	// Mark the path as written by the app so target drift detection ignores it
+	ownWrites.begin(targetPath)
+	defer ownWrites.end(targetPath)
+
+	// Whatever is in the way (a copied file or followed directory) is replaced
+	if err := os.RemoveAll(targetPath); err != nil {
+		return false, err
+	}
+	return true, os.Symlink(linkTarget, targetPath)
+}
+
+// syncSymlinkEntry recreates a source link during a full sync (SymlinkMode "copy-link").
+func (c *Copier) syncSymlinkEntry(ctx context.Context, pair *cfg.Pair, path, relativePath string, result *SyncResult) error {
+	if !isPathIncluded(pair, path, relativePath) {
+		result.FilesSkipped++
+		return nil
+	}
+
+	changed, err := copySymlink(pair, path, relativePath, c.dryRun)
+	if err != nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("file", relativePath).Err(err).Msg("symlink copy failed")
+		result.Errors = append(result.Errors, err)
+		return nil
+	}
+	if !changed {
+		result.FilesSkipped++
+		return nil
+	}
+
+	result.FilesCopied++
+	if c.dryRun {
+		return nil
+	}
+
+	logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", relativePath).Msg("copied (symlink)")
+	recordEvent(EventFileCopied, pair.ID, NormalizePath(relativePath), "symlink")
+	c.fileSynced(ctx, pair, relativePath)
+	return nil
+}
+
+// ===== SYMLINK TARGET REWRITE =====
+
+// rewriteSymlinkTarget returns the link target to use when recreating the symlink at
+// relativePath in the target tree. Relative targets and targets outside the source root
+// are returned unchanged; the latter are logged since they may not resolve on the target side.
+func rewriteSymlinkTarget(pair *cfg.Pair, relativePath, linkTarget string) string {
+	if pair.SymlinkRewrite == "" || !filepath.IsAbs(linkTarget) {
+		return linkTarget
+	}
+
+	// Any of the pair's sources counts; they are merged into the same target tree
+	sourceRoot, inside := sourceRootOf(pair, linkTarget)
+	insideRel, err := filepath.Rel(sourceRoot, filepath.Clean(linkTarget))
+	if !inside || err != nil {
+		logging.ForPair(pair.ID).Warn().
+			Str("pair", pair.ID).
+			Str("link", relativePath).
+			Str("link_target", linkTarget).
+			Msg("symlink points outside the source root, keeping absolute target")
+		return linkTarget
+	}
+
+	switch pair.SymlinkRewrite {
+	case cfg.SymlinkRewriteRelative:
+		// Resolve relative to the link's directory; the same layout exists in the target
+		linkDir := filepath.Dir(filepath.Join(sourceRoot, filepath.FromSlash(relativePath)))
+		if rewritten, err := filepath.Rel(linkDir, filepath.Join(sourceRoot, insideRel)); err == nil {
+			return rewritten
+		}
+		return linkTarget
+	case cfg.SymlinkRewriteTarget:
+		return targetPathFor(pair, insideRel)
+	default:
+		return linkTarget
+	}
+}
+
+// ===== WATCHER EVENTS =====
+
+// handleSymlinkEvent applies the pair's symlink mode to a changed source link.
+// It reports whether the event was handled; followed file links are left to the
+// regular copy. Changes inside followed directory links aren't watched and are
+// picked up by the next full sync.
+func (w *PairWorker) handleSymlinkEvent(sourcePath, relativePath string) bool {
+	pair := w.Pair
+	info, err := os.Lstat(sourcePath)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return false
+	}
+
+	switch pair.SymlinkMode {
+	case cfg.SymlinkModeSkip:
+		return true
+	case cfg.SymlinkModeCopyLink:
+		if !isPathIncluded(pair, sourcePath, relativePath) {
+			return true
+		}
+		copied := false
+		for _, target := range pair.TargetRoots() {
+			changed, err := copySymlink(pair.ForTarget(target), sourcePath, relativePath, false)
+			if err != nil {
+				logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("target", target).Str("file", relativePath).Err(err).Msg("symlink copy failed")
+				continue
+			}
+			copied = copied || changed
+		}
+		if copied {
+			logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("file", relativePath).Msg("copied (symlink event)")
+			recordEvent(EventFileCopied, pair.ID, NormalizePath(relativePath), "watcher symlink")
+			RunHooks(w.ctx, pair, relativePath)
+		}
+		return true
+	}
+
+	// A followed directory link brings a whole tree along
+	if targetInfo, err := os.Stat(sourcePath); err != nil || !targetInfo.IsDir() {
+		return false
+	}
+	copier := &Copier{syncs: w.syncs}
+	if _, _, err := copier.CompareAndSync(w.ctx, pair); err != nil && w.ctx.Err() == nil {
+		logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("link", relativePath).Err(err).Msg("sync of linked directory failed")
+	}
+	return true
+}
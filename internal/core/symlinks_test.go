@@ -0,0 +1,209 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// symlinkOrSkip creates a symlink, skipping the test where links can't be created
+func symlinkOrSkip(t *testing.T, linkTarget, path string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(linkTarget, path); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+}
+
+// ===== SYMLINK TARGET REWRITE =====
+
+func TestRewriteSymlinkTarget(t *testing.T) {
+	pair := newTestPair(t)
+	external := filepath.Join(t.TempDir(), "shared", "lib.so")
+	internal := filepath.Join(pair.Source, "lib", "v2", "lib.so")
+
+	tests := []struct {
+		name       string
+		rewrite    string
+		linkTarget string
+		want       string
+	}{
+		{"off keeps internal absolute", "", internal, internal},
+		{"relative rewrites internal", cfg.SymlinkRewriteRelative, internal, filepath.Join("..", "lib", "v2", "lib.so")},
+		{"target rewrites internal", cfg.SymlinkRewriteTarget, internal, filepath.Join(pair.Target, "lib", "v2", "lib.so")},
+		{"relative keeps external", cfg.SymlinkRewriteRelative, external, external},
+		{"target keeps external", cfg.SymlinkRewriteTarget, external, external},
+		{"relative link untouched", cfg.SymlinkRewriteTarget, "../lib/v2/lib.so", "../lib/v2/lib.so"},
+		{"source root prefix isn't inside", cfg.SymlinkRewriteRelative, pair.Source + "-other/lib.so", pair.Source + "-other/lib.so"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rewritten := *pair
+			rewritten.SymlinkRewrite = tt.rewrite
+			if got := rewriteSymlinkTarget(&rewritten, "bin/lib.so", tt.linkTarget); got != tt.want {
+				t.Errorf("rewriteSymlinkTarget(%q) = %q, want %q", tt.linkTarget, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncRecreatesRewrittenSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewrite string
+		// wantInternal returns the expected target of the internal link
+		wantInternal func(pair *cfg.Pair) string
+	}{
+		{"relative", cfg.SymlinkRewriteRelative, func(*cfg.Pair) string { return filepath.Join("..", "data", "config.json") }},
+		{"target root", cfg.SymlinkRewriteTarget, func(pair *cfg.Pair) string { return filepath.Join(pair.Target, "data", "config.json") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SymlinkMode = cfg.SymlinkModeCopyLink
+			pair.SymlinkRewrite = tt.rewrite
+			external := filepath.Join(t.TempDir(), "external.txt")
+			writeTestFile(t, external, "outside")
+			writeTestFile(t, filepath.Join(pair.Source, "data", "config.json"), "{}")
+			symlinkOrSkip(t, filepath.Join(pair.Source, "data", "config.json"), filepath.Join(pair.Source, "links", "internal"))
+			symlinkOrSkip(t, external, filepath.Join(pair.Source, "links", "external"))
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatal(err)
+			}
+
+			internalLink := filepath.Join(pair.Target, "links", "internal")
+			if got, err := os.Readlink(internalLink); err != nil || got != tt.wantInternal(pair) {
+				t.Errorf("internal link -> %q (%v), want %q", got, err, tt.wantInternal(pair))
+			}
+			if readTestFile(t, internalLink) != "{}" {
+				t.Error("rewritten internal link doesn't resolve inside the target")
+			}
+			if got, err := os.Readlink(filepath.Join(pair.Target, "links", "external")); err != nil || got != external {
+				t.Errorf("external link -> %q (%v), want %q unchanged", got, err, external)
+			}
+
+			// The rewritten link is stable across syncs
+			if files, err := syncTestPair(t, pair); err != nil || files != 0 {
+				t.Errorf("second sync copied %d files, err %v, want 0", files, err)
+			}
+		})
+	}
+}
+
+// ===== SYMLINK MODES =====
+
+// linkKind describes what a target path holds after a sync
+func linkKind(t *testing.T, path string) string {
+	t.Helper()
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+		return "missing"
+	case err != nil:
+		t.Fatal(err)
+	case info.Mode()&os.ModeSymlink != 0:
+		return "link"
+	case info.IsDir():
+		return "dir"
+	}
+	return "file"
+}
+
+func TestSyncSymlinkModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		wantFile string // kind of links/file in the target
+		wantDir  string // kind of links/dir in the target
+	}{
+		{"follow", cfg.SymlinkModeFollow, "file", "dir"},
+		{"copy-link", cfg.SymlinkModeCopyLink, "link", "link"},
+		{"skip", cfg.SymlinkModeSkip, "missing", "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SymlinkMode = tt.mode
+			writeTestFile(t, filepath.Join(pair.Source, "data", "real.txt"), "real")
+			symlinkOrSkip(t, filepath.Join(pair.Source, "data", "real.txt"), filepath.Join(pair.Source, "links", "file"))
+			symlinkOrSkip(t, filepath.Join(pair.Source, "data"), filepath.Join(pair.Source, "links", "dir"))
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := linkKind(t, filepath.Join(pair.Target, "links", "file")); got != tt.wantFile {
+				t.Errorf("links/file is %s, want %s", got, tt.wantFile)
+			}
+			if got := linkKind(t, filepath.Join(pair.Target, "links", "dir")); got != tt.wantDir {
+				t.Errorf("links/dir is %s, want %s", got, tt.wantDir)
+			}
+			if tt.wantFile != "missing" && readTestFile(t, filepath.Join(pair.Target, "links", "file")) != "real" {
+				t.Error("links/file doesn't resolve to the linked content")
+			}
+			if readTestFile(t, filepath.Join(pair.Target, "data", "real.txt")) != "real" {
+				t.Error("regular file not synced")
+			}
+		})
+	}
+}
+
+func TestFollowedSymlinkLoopIsNotDescended(t *testing.T) {
+	pair := newTestPair(t)
+	pair.SymlinkMode = cfg.SymlinkModeFollow
+	writeTestFile(t, filepath.Join(pair.Source, "a", "file.txt"), "data")
+	symlinkOrSkip(t, pair.Source, filepath.Join(pair.Source, "a", "root"))
+	symlinkOrSkip(t, filepath.Join(pair.Source, "a"), filepath.Join(pair.Source, "a", "self"))
+
+	files, err := syncTestPair(t, pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files != 1 {
+		t.Errorf("sync copied %d files, want only a/file.txt", files)
+	}
+	for _, loop := range []string{"root", "self"} {
+		if got := linkKind(t, filepath.Join(pair.Target, "a", loop)); got != "missing" {
+			t.Errorf("looping link a/%s synced as %s", loop, got)
+		}
+	}
+}
+
+func TestWatcherSymlinkEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		wantHandled bool
+		wantTarget  string // kind of the link's target path afterwards
+	}{
+		{"follow leaves it to the copy", cfg.SymlinkModeFollow, false, "missing"},
+		{"copy-link recreates the link", cfg.SymlinkModeCopyLink, true, "link"},
+		{"skip ignores it", cfg.SymlinkModeSkip, true, "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SymlinkMode = tt.mode
+			w, _ := newSetupWorker(t, pair)
+			writeTestFile(t, filepath.Join(pair.Source, "real.txt"), "real")
+			linkPath := filepath.Join(pair.Source, "link.txt")
+			symlinkOrSkip(t, filepath.Join(pair.Source, "real.txt"), linkPath)
+
+			if got := w.handleSymlinkEvent(linkPath, "link.txt"); got != tt.wantHandled {
+				t.Errorf("handleSymlinkEvent() = %v, want %v", got, tt.wantHandled)
+			}
+			if got := linkKind(t, filepath.Join(pair.Target, "link.txt")); got != tt.wantTarget {
+				t.Errorf("target link.txt is %s, want %s", got, tt.wantTarget)
+			}
+		})
+	}
+}
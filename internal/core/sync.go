@@ -6,8 +6,11 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -15,6 +18,8 @@ import (
 	"time"
 
 	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+	"FolderSynchronizer/internal/metrics"
 
 	"github.com/rs/zerolog/log"
 )
@@ -29,27 +34,57 @@ const (
 	// Time comparison tolerance for cross-filesystem compatibility
 	ModTimeToleranceSeconds = 2
 
+	// Syncs shorter than this report no throughput; the figure would be meaningless
+	MinThroughputDuration = 10 * time.Millisecond
+
 	// Sync strategies
 	SyncStrategyMTime = "mtime" // Modification time + size comparison
 	SyncStrategyHash  = "hash"  // SHA256 hash comparison
+	SyncStrategyDelta = "delta" // Hash comparison; changed files rewritten from matching target blocks
 )
 
+// ErrVerificationFailed is returned when a copied file's hash doesn't match its source.
+var ErrVerificationFailed = errors.New("copy verification failed")
+
 // ===== SYNCHRONIZATION STRUCTURES =====
 
 // Copier handles file synchronization operations between source and target directories.
 // It supports different comparison strategies and provides comprehensive sync statistics.
 type Copier struct {
-	pair *cfg.Pair // Current sync pair configuration
+	pair   *cfg.Pair  // Current sync pair configuration
+	dryRun bool       // Plan operations without touching the target
+	syncs  *syncLimit // Concurrency limit a full run waits on (nil: unlimited)
+
+	// Move detection candidates collected during a dry run
+	newFiles []moveCandidate // Source files missing from target
+	orphans  []moveCandidate // Target files missing from source
+
+	// Copied files whose hooks fire together after the run (Pair.HookBatch)
+	batchedFiles []string
+
+	// Compare by hash in this run although the pair uses mtime (PeriodicHashCheck)
+	hashCheck bool
+
+	// Files whose hooks already fired in this run, shared by the targets of a fan-out pair
+	hookedFiles map[string]bool
 }
 
 // SyncResult contains detailed statistics about a synchronization operation.
+// In a dry run the counters describe the planned operations instead.
 type SyncResult struct {
-	FilesCopied  int           // Number of files successfully copied
-	BytesCopied  int64         // Total bytes copied
-	FilesDeleted int           // Number of files deleted (mirror mode)
-	FilesSkipped int           // Number of files skipped (unchanged)
-	Duration     time.Duration // Total sync operation duration
-	Errors       []error       // Any non-fatal errors encountered
+	FilesCopied     int            `json:"filesCopied"`       // Number of files successfully copied
+	BytesCopied     int64          `json:"bytesCopied"`       // Total bytes copied
+	FilesDeleted    int            `json:"filesDeleted"`      // Number of files deleted (mirror mode)
+	DirsDeleted     int            `json:"dirsDeleted"`       // Number of directories deleted (mirror mode)
+	DirsCreated     int            `json:"dirsCreated"`       // Number of directories created (SyncEmptyDirs)
+	FilesSkipped    int            `json:"filesSkipped"`      // Number of files skipped (unchanged)
+	DeletesDeferred int            `json:"deletesDeferred"`   // Orphaned target files recorded as pending deletes (deferred mode)
+	DirsBeyondDepth int            `json:"dirsBeyondDepth"`   // Source directories not descended into (MaxDepth)
+	HiddenSkipped   int            `json:"hiddenSkipped"`     // Hidden files and directories left out (SkipHidden)
+	Renames         []RenameOp     `json:"renames,omitempty"` // Target renames replacing delete+copy (move detection)
+	Targets         []TargetResult `json:"targets,omitempty"` // Per-target results of a fan-out pair
+	Duration        time.Duration  `json:"duration"`          // Total sync operation duration
+	Errors          []error        `json:"-"`                 // Any non-fatal errors encountered
 }
 
 // ===== MAIN SYNCHRONIZATION LOGIC =====
@@ -58,31 +93,161 @@ type SyncResult struct {
 // It compares files using the specified strategy, copies changed files, and optionally
 // mirrors deletions. Returns statistics about the operation.
 func (c *Copier) CompareAndSync(ctx context.Context, pair *cfg.Pair) (int, int64, error) {
+	// Wait for a slot of the manager's sync limit
+	release, err := c.syncs.acquire(ctx, pair)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer release()
+
 	startTime := time.Now()
 	c.pair = pair
 
+	// Enforce the pair's minimum gap between runs
+	hashCheck, err := pairRuns.begin(pair)
+	if err != nil {
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Err(err).Msg("sync skipped")
+		return 0, 0, err
+	}
+	defer pairRuns.end(pair.ID)
+	c.hashCheck = hashCheck
+
+	// The walk re-reads the tree's ignore files; drop what earlier runs cached
+	ResetSyncIgnoreCache(pair.ID)
+	if hashCheck {
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Msg("periodic hash check: comparing files by hash")
+		recordEvent(EventSyncStarted, pair.ID, "", "hash check")
+	} else {
+		recordEvent(EventSyncStarted, pair.ID, "", "")
+	}
+
+	// Abort early when the target can't hold the planned copies
+	if pair.PrecheckDiskSpace {
+		if err := precheckTargetCapacity(ctx, pair); err != nil {
+			metrics.SyncFailures.WithLabelValues(pair.ID).Inc()
+			logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Err(err).Msg("sync aborted by disk capacity precheck")
+			recordOutcome(EventSyncFinished, pair.ID, "", false, err.Error())
+			return 0, 0, err
+		}
+	}
+
 	result, err := c.performSync(ctx, pair)
+	hashCache.save()
+	if !c.dryRun {
+		sweepStaleTempFiles(pair)
+	}
+
+	// Batch hooks fire once for everything copied, even when the run failed later
+	if pair.HookBatch {
+		RunBatchHooks(ctx, pair, c.batchedFiles)
+		c.batchedFiles = nil
+	}
+
+	metrics.FilesCopied.WithLabelValues(pair.ID).Add(float64(result.FilesCopied))
+	metrics.BytesCopied.WithLabelValues(pair.ID).Add(float64(result.BytesCopied))
+	if err != nil || len(result.Errors) > 0 {
+		metrics.SyncFailures.WithLabelValues(pair.ID).Inc()
+	}
+
 	if err != nil {
+		recordOutcome(EventSyncFinished, pair.ID, "", false, err.Error())
 		return result.FilesCopied, result.BytesCopied, err
 	}
 
-	log.Info().
+	result.Duration = time.Since(startTime)
+	throughput := throughputMBps(result.BytesCopied, result.Duration)
+	if throughput > 0 {
+		pairRuns.recordThroughput(pair.ID, throughput)
+	}
+
+	if result.DirsBeyondDepth > 0 {
+		logging.ForPair(pair.ID).Warn().
+			Str("pair", pair.ID).
+			Int("directories", result.DirsBeyondDepth).
+			Int("max_depth", pair.MaxDepth).
+			Msg("directories beyond max depth not synced")
+	}
+
+	logging.ForPair(pair.ID).Info().
 		Str("pair", pair.ID).
 		Int("files", result.FilesCopied).
 		Int64("bytes", result.BytesCopied).
-		Dur("duration", time.Since(startTime)).
+		Float64("mb_per_sec", throughput).
+		Int("deleted", result.FilesDeleted).
+		Int("dirs_deleted", result.DirsDeleted).
+		Int("dirs_created", result.DirsCreated).
+		Int("renames", len(result.Renames)).
+		Int("hidden_skipped", result.HiddenSkipped).
+		Int("errors", len(result.Errors)).
+		Dur("duration", result.Duration).
 		Msg("sync completed")
 
+	summary := fmt.Sprintf("%d copied, %d deleted, %d errors", result.FilesCopied, result.FilesDeleted, len(result.Errors))
+	recordOutcome(EventSyncFinished, pair.ID, "", len(result.Errors) == 0, summary)
+
+	// Report non-fatal per-file errors so the run is recorded as failed
+	if len(result.Errors) > 0 {
+		return result.FilesCopied, result.BytesCopied, errors.Join(result.Errors...)
+	}
+
 	return result.FilesCopied, result.BytesCopied, nil
 }
 
+// DryRun plans a synchronization without modifying the target. The returned
+// result counts the files that would be copied and deleted and, when the pair
+// has DetectMoves enabled, lists target renames that would replace a
+// delete+copy of the same content.
+func (c *Copier) DryRun(ctx context.Context, pair *cfg.Pair) (*SyncResult, error) {
+	startTime := time.Now()
+	c.pair = pair
+	c.dryRun = true
+
+	result, err := c.performSync(ctx, pair)
+	if err != nil {
+		return result, err
+	}
+
+	if pair.DetectMoves {
+		c.applyRenamePlan(result, planRenames(c.newFiles, c.orphans))
+	}
+
+	result.Duration = time.Since(startTime)
+	logging.ForPair(pair.ID).Info().
+		Str("pair", pair.ID).
+		Int("files", result.FilesCopied).
+		Int("deleted", result.FilesDeleted).
+		Int("renames", len(result.Renames)).
+		Msg("dry run completed")
+
+	return result, nil
+}
+
 // performSync executes the main synchronization logic with proper error handling.
 func (c *Copier) performSync(ctx context.Context, pair *cfg.Pair) (*SyncResult, error) {
+	// Fan-out pairs run once per target
+	if len(pair.Targets) > 1 {
+		return c.fanOutSync(ctx, pair)
+	}
+
+	// Archive mode replaces mirroring (and mirror deletes) entirely
+	if pair.ArchiveMode != "" {
+		return c.archiveSync(ctx, pair)
+	}
+
 	result := &SyncResult{}
 
-	// Ensure target directory exists
-	if err := os.MkdirAll(pair.Target, 0o755); err != nil {
-		return result, err
+	// Ensure target directory exists (a dry run never creates it)
+	if !c.dryRun {
+		if err := os.MkdirAll(pair.Target, 0o755); err != nil {
+			return result, err
+		}
+	}
+
+	// Rename target files whose source was moved instead of copying them again
+	if !c.dryRun {
+		if err := c.applyMoves(ctx, pair, result); err != nil {
+			return result, err
+		}
 	}
 
 	// Sync files from source to target
@@ -91,7 +256,7 @@ func (c *Copier) performSync(ctx context.Context, pair *cfg.Pair) (*SyncResult,
 	}
 
 	// Handle mirror deletions if enabled
-	if pair.MirrorDeletes {
+	if pair.MirrorDeletes && (!c.dryRun || IsDirectoryExists(pair.Target)) {
 		if err := c.mirrorDeletions(pair, result); err != nil {
 			return result, err
 		}
@@ -100,28 +265,84 @@ func (c *Copier) performSync(ctx context.Context, pair *cfg.Pair) (*SyncResult,
 	return result, nil
 }
 
-// syncSourceToTarget walks the source directory and synchronizes files to target.
+// syncSourceToTarget walks the source directories in priority order and
+// synchronizes files to target. A relative path already provided by an earlier
+// source is skipped; with SourceConflict "error" the collision is also recorded.
 func (c *Copier) syncSourceToTarget(ctx context.Context, pair *cfg.Pair, result *SyncResult) error {
-	return filepath.WalkDir(pair.Source, func(path string, dirEntry fs.DirEntry, err error) error {
-		if err != nil {
+	var provided map[string]string // Source path by relative path, for multi-source pairs
+	if len(pair.Sources) > 1 {
+		provided = make(map[string]string)
+	}
+
+	for _, root := range pair.SourceRoots() {
+		if err := c.syncSourceRoot(ctx, pair, root, provided, result); err != nil {
 			return err
 		}
+	}
+	return nil
+}
 
+// syncSourceRoot synchronizes the files of one source directory to target.
+func (c *Copier) syncSourceRoot(ctx context.Context, pair *cfg.Pair, root string, provided map[string]string, result *SyncResult) error {
+	return walkSource(pair, root, func(path, relativePath string, dirEntry fs.DirEntry) error {
 		// Skip directories
 		if dirEntry.IsDir() {
+			switch {
+			case dirBeyondMaxDepth(pair, relativePath):
+				result.DirsBeyondDepth++
+			case relativePath == ".":
+			case isHiddenEntry(pair, relativePath, dirEntry):
+				result.HiddenSkipped++
+				return filepath.SkipDir
+			case pair.SyncEmptyDirs:
+				c.syncDirectory(pair, path, relativePath, result)
+			}
 			return nil
 		}
 
-		// Get relative path for filtering and target calculation
-		relativePath, err := filepath.Rel(pair.Source, path)
-		if err != nil {
-			return err
+		// Hidden files are counted apart from other filtered files
+		if isHiddenEntry(pair, relativePath, dirEntry) {
+			result.HiddenSkipped++
+			return nil
 		}
 
-		// Apply file filters
-		if !c.shouldSyncFile(pair, path, relativePath) {
-			result.FilesSkipped++
-			return nil
+		// Apply file filters. Links reported by the walk are recreated as links
+		// (SymlinkMode "copy-link") and only checked against the path filters.
+		isLink := dirEntry.Type()&fs.ModeSymlink != 0
+		var fileInfo os.FileInfo
+		if isLink {
+			if !isPathIncluded(pair, path, relativePath) {
+				result.FilesSkipped++
+				return nil
+			}
+		} else {
+			var err error
+			if fileInfo, err = dirEntry.Info(); err != nil {
+				return err
+			}
+			if !c.shouldSyncFile(pair, path, relativePath, fileInfo) {
+				result.FilesSkipped++
+				return nil
+			}
+		}
+
+		// An earlier source already provides this path; filtered files don't
+		// count, so they never hide the path in a later source
+		if provided != nil {
+			if earlier, exists := provided[relativePath]; exists {
+				if pair.SourceConflict == cfg.SourceConflictError {
+					conflictErr := sourceConflictError(relativePath, earlier, path)
+					logging.ForPair(pair.ID).Error().Str("pair", pair.ID).Str("file", relativePath).Err(conflictErr).Msg("source conflict")
+					result.Errors = append(result.Errors, conflictErr)
+				}
+				result.FilesSkipped++
+				return nil
+			}
+			provided[relativePath] = path
+		}
+
+		if isLink {
+			return c.syncSymlinkEntry(ctx, pair, path, relativePath, result)
 		}
 
 		// Check if file needs to be copied
@@ -132,8 +353,24 @@ func (c *Copier) syncSourceToTarget(ctx context.Context, pair *cfg.Pair, result
 			return nil
 		}
 
+		// Only record the planned copy in a dry run
+		if c.dryRun {
+			c.planCopy(pair, path, relativePath, fileInfo, result)
+			return nil
+		}
+
 		// Copy the file
 		bytesCopied, err := c.copyFile(ctx, path, pair, relativePath)
+		if errors.Is(err, ErrVerificationFailed) {
+			// Corrupted copy was removed; record it and continue with other files
+			logging.ForPair(pair.ID).Error().
+				Str("pair", pair.ID).
+				Str("file", relativePath).
+				Err(err).
+				Msg("copy verification failed")
+			result.Errors = append(result.Errors, err)
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -141,34 +378,78 @@ func (c *Copier) syncSourceToTarget(ctx context.Context, pair *cfg.Pair, result
 		result.FilesCopied++
 		result.BytesCopied += bytesCopied
 
-		log.Info().
+		logging.ForPair(pair.ID).Info().
 			Str("pair", pair.ID).
 			Str("file", relativePath).
 			Int64("bytes", bytesCopied).
 			Msg("copied")
-
-		// Execute hooks for the synchronized file
-		RunHooks(ctx, pair, NormalizePath(relativePath))
+		recordEvent(EventFileCopied, pair.ID, NormalizePath(relativePath), "")
+		c.fileSynced(ctx, pair, relativePath)
 
 		return nil
 	})
 }
 
+// fileSynced runs the hooks of a file synchronized during the run, or defers them
+// to the end of the run.
+func (c *Copier) fileSynced(ctx context.Context, pair *cfg.Pair, relativePath string) {
+	// Hooks fire once per file, however many targets it was copied to
+	if c.hookedFiles != nil {
+		if c.hookedFiles[relativePath] {
+			return
+		}
+		c.hookedFiles[relativePath] = true
+	}
+
+	if pair.HookBatch {
+		c.batchedFiles = append(c.batchedFiles, NormalizePath(relativePath))
+	} else {
+		RunHooks(ctx, pair, NormalizePath(relativePath))
+	}
+}
+
 // shouldSyncFile determines if a file should be synchronized based on filters.
-func (c *Copier) shouldSyncFile(pair *cfg.Pair, fullPath, relativePath string) bool {
-	// Check include extensions filter
-	if !MatchesInclude(pair.IncludeExt, fullPath) {
+func (c *Copier) shouldSyncFile(pair *cfg.Pair, fullPath, relativePath string, fileInfo os.FileInfo) bool {
+	if !isPathIncluded(pair, fullPath, relativePath) || isHidden(pair, relativePath, fileInfo) {
 		return false
 	}
 
-	// Check exclude globs filter
-	if MatchesExclude(pair.ExcludeGlobs, fullPath) {
+	// Check file size limits
+	if !withinSizeLimits(pair, fileInfo.Size()) {
 		return false
 	}
 
 	return true
 }
 
+// isPathIncluded reports whether a source path passes the pair's include and
+// exclude filters and .syncignore files.
+func isPathIncluded(pair *cfg.Pair, fullPath, relativePath string) bool {
+	// Check include extensions and include globs filters
+	if !MatchesIncludeFilters(pair.IncludeExt, pair.IncludeGlobs, relativePath, pair.CaseSensitive) {
+		return false
+	}
+
+	// Check exclude globs filter and .syncignore files
+	sourceRoot, ok := sourceRootOf(pair, fullPath)
+	if !ok {
+		sourceRoot = pair.Source
+	}
+	return !MatchesExclude(pair.ExcludeGlobs, fullPath, pair.CaseSensitive) && !IsSyncIgnored(pair.ID, sourceRoot, relativePath)
+}
+
+// withinSizeLimits reports whether a file size is inside the pair's configured
+// [MinFileSize, MaxFileSize] range. A MaxFileSize of 0 means no upper limit.
+func withinSizeLimits(pair *cfg.Pair, size int64) bool {
+	if pair.MinFileSize > 0 && size < pair.MinFileSize {
+		return false
+	}
+	if pair.MaxFileSize > 0 && size > pair.MaxFileSize {
+		return false
+	}
+	return true
+}
+
 // isFileChanged determines if a file has changed and needs to be copied.
 func (c *Copier) isFileChanged(sourcePath string, pair *cfg.Pair, relativePath string) (bool, error) {
 	sourceInfo, err := os.Stat(sourcePath)
@@ -176,7 +457,7 @@ func (c *Copier) isFileChanged(sourcePath string, pair *cfg.Pair, relativePath s
 		return false, err
 	}
 
-	targetPath := filepath.Join(pair.Target, relativePath)
+	targetPath := targetPathFor(pair, relativePath)
 	targetInfo, err := os.Stat(targetPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -185,29 +466,58 @@ func (c *Copier) isFileChanged(sourcePath string, pair *cfg.Pair, relativePath s
 		return false, err
 	}
 
-	return c.filesAreDifferent(sourcePath, targetPath, sourceInfo, targetInfo, pair.SyncStrategy)
+	strategy := pair.SyncStrategy
+	if c.hashCheck {
+		strategy = SyncStrategyHash
+	}
+	return c.filesAreDifferent(sourcePath, targetPath, sourceInfo, targetInfo, strategy)
 }
 
 // filesAreDifferent compares two files using the specified strategy.
 func (c *Copier) filesAreDifferent(sourcePath, targetPath string, sourceInfo, targetInfo os.FileInfo, strategy string) (bool, error) {
+	reason, err := c.changeReason(sourcePath, targetPath, sourceInfo, targetInfo, strategy)
+	return reason != "", err
+}
+
+// changeReason compares two files using the specified strategy and returns why
+// they differ (ChangeReasonSize, ChangeReasonMTime or ChangeReasonHash), or ""
+// when they are considered identical.
+func (c *Copier) changeReason(sourcePath, targetPath string, sourceInfo, targetInfo os.FileInfo, strategy string) (string, error) {
 	switch strategy {
-	case SyncStrategyHash:
-		return c.compareByHash(sourcePath, targetPath)
+	case SyncStrategyHash, SyncStrategyDelta:
+		different, err := c.compareByHash(sourcePath, targetPath)
+		if err != nil || !different {
+			return "", err
+		}
+		return ChangeReasonHash, nil
 	case SyncStrategyMTime:
 		fallthrough
 	default:
-		return c.compareByModTimeAndSize(sourceInfo, targetInfo), nil
+		if sourceInfo.Size() != targetInfo.Size() {
+			return ChangeReasonSize, nil
+		}
+		if c.compareByModTimeAndSize(sourceInfo, targetInfo) {
+			return ChangeReasonMTime, nil
+		}
+		return "", nil
 	}
 }
 
-// compareByHash compares files using SHA256 hash calculation.
+// compareByHash compares files using SHA256 hash calculation. Hashes of files
+// unchanged since they were last hashed come from the checksum cache, except in
+// a periodic hash check, which is meant to re-read everything.
 func (c *Copier) compareByHash(sourcePath, targetPath string) (bool, error) {
-	sourceHash, err := calculateFileHash(sourcePath)
+	fileHash := cachedFileHash
+	if c.hashCheck {
+		fileHash = calculateFileHash
+	}
+
+	sourceHash, err := fileHash(sourcePath)
 	if err != nil {
 		return false, err
 	}
 
-	targetHash, err := calculateFileHash(targetPath)
+	targetHash, err := fileHash(targetPath)
 	if err != nil {
 		return false, err
 	}
@@ -233,26 +543,135 @@ func (c *Copier) compareByModTimeAndSize(sourceInfo, targetInfo os.FileInfo) boo
 
 // copyFile copies a single file from source to target with atomic operations.
 func (c *Copier) copyFile(ctx context.Context, sourcePath string, pair *cfg.Pair, relativePath string) (int64, error) {
-	targetPath := filepath.Join(pair.Target, relativePath)
+	targetPath := targetPathFor(pair, relativePath)
 
 	// Ensure target directory exists
 	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
 		return 0, err
 	}
 
-	// Copy file atomically
-	return copyAtomic(sourcePath, targetPath)
+	// Copy file atomically, retrying transient failures
+	return copyWithRetry(ctx, pair, sourcePath, targetPath)
+}
+
+// copyRetryDelays returns the delays between copy attempts for a pair.
+// Without configuration the legacy schedule (3 attempts, 100ms/300ms apart)
+// is used; otherwise CopyRetries delays grow exponentially from CopyRetryDelayMs.
+func copyRetryDelays(pair *cfg.Pair) []time.Duration {
+	if pair.CopyRetries == 0 && pair.CopyRetryDelayMs == 0 {
+		return []time.Duration{FirstRetryDelay, SecondRetryDelay}
+	}
+
+	retries := pair.CopyRetries
+	if retries == 0 {
+		retries = cfg.DefaultCopyRetries
+	}
+
+	delay := FirstRetryDelay
+	if pair.CopyRetryDelayMs > 0 {
+		delay = time.Duration(pair.CopyRetryDelayMs) * time.Millisecond
+	}
+
+	delays := make([]time.Duration, retries)
+	for i := range delays {
+		delays[i] = delay
+		delay *= 2
+		if delay > MaxCopyRetryDelay {
+			delay = MaxCopyRetryDelay
+		}
+	}
+	return delays
+}
+
+// copyWithRetry copies a file, retrying on failure according to the pair's
+// retry schedule. Retries stop early when the context is cancelled.
+func copyWithRetry(ctx context.Context, pair *cfg.Pair, sourcePath, targetPath string) (int64, error) {
+	delays := copyRetryDelays(pair)
+
+	for attempt := 0; ; attempt++ {
+		bytesCopied, err := copyPairFile(pair, sourcePath, targetPath)
+		if err == nil || attempt >= len(delays) {
+			return bytesCopied, err
+		}
+
+		logging.ForPair(pair.ID).Debug().
+			Str("pair", pair.ID).
+			Str("file", sourcePath).
+			Int("attempt", attempt+1).
+			Dur("retry_in", delays[attempt]).
+			Err(err).
+			Msg("copy failed, retrying")
+
+		select {
+		case <-time.After(delays[attempt]):
+		case <-ctx.Done():
+			return bytesCopied, err
+		}
+	}
+}
+
+// copyPairFile copies a file atomically and, when the pair has VerifyAfterCopy
+// enabled, verifies the written target against the source. Pairs using the delta
+// strategy reuse unchanged blocks of an existing target.
+func copyPairFile(pair *cfg.Pair, sourcePath, targetPath string) (int64, error) {
+	// The rewritten target may keep the size and modification time of the old one
+	defer hashCache.forget(targetPath)
+
+	var bytesCopied int64
+	var err error
+	if pair.SyncStrategy == SyncStrategyDelta {
+		bytesCopied, err = copyDelta(sourcePath, targetPath)
+	} else {
+		bytesCopied, err = copyAtomic(sourcePath, targetPath)
+	}
+	if err != nil || !pair.VerifyAfterCopy {
+		return bytesCopied, err
+	}
+
+	if err := verifyCopy(sourcePath, targetPath); err != nil {
+		return bytesCopied, err
+	}
+
+	return bytesCopied, nil
+}
+
+// verifyCopy recomputes SHA256 of source and target and deletes the target
+// if they differ. Returns an error wrapping ErrVerificationFailed on mismatch.
+func verifyCopy(sourcePath, targetPath string) error {
+	sourceHash, err := calculateFileHash(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	targetHash, err := calculateFileHash(targetPath)
+	if err != nil {
+		return err
+	}
+
+	if sourceHash != targetHash {
+		_ = removeOwnedFile(targetPath) // Never leave a corrupted copy in place
+		return fmt.Errorf("%w: %s (source %s, target %s)", ErrVerificationFailed, targetPath, sourceHash, targetHash)
+	}
+
+	return nil
 }
 
-// mirrorDeletions removes files from target that no longer exist in source.
+// mirrorDeletions removes files and directories from target that no longer exist in source.
+// In deferred mode the orphaned files replace the pair's pending deletes instead.
 func (c *Copier) mirrorDeletions(pair *cfg.Pair, result *SyncResult) error {
-	return filepath.WalkDir(pair.Target, func(path string, dirEntry fs.DirEntry, err error) error {
+	var directories []string
+	var deferred []string
+
+	err := filepath.WalkDir(pair.Target, func(path string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		// Skip directories
+		// Collect directories for bottom-up removal after files are processed
 		if dirEntry.IsDir() {
+			if path != pair.Target {
+				directories = append(directories, path)
+			}
 			return nil
 		}
 
@@ -263,11 +682,23 @@ func (c *Copier) mirrorDeletions(pair *cfg.Pair, result *SyncResult) error {
 		}
 
 		// Check if corresponding source file exists
-		sourcePath := filepath.Join(pair.Source, relativePath)
-		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		if !sourceExistsFor(pair, relativePath) {
+			// Deferred mode only records the file for a later apply
+			if pair.DefersMirrorDeletes() {
+				deferred = append(deferred, NormalizePath(relativePath))
+				result.DeletesDeferred++
+				return nil
+			}
+
+			// Only record the planned deletion in a dry run
+			if c.dryRun {
+				c.planDelete(path, relativePath, dirEntry, result)
+				return nil
+			}
+
 			// Source file doesn't exist, remove target file
-			if err := os.Remove(path); err != nil {
-				log.Error().
+			if err := removeOwnedFile(path); err != nil {
+				logging.ForPair(pair.ID).Error().
 					Str("pair", pair.ID).
 					Str("file", relativePath).
 					Err(err).
@@ -276,20 +707,103 @@ func (c *Copier) mirrorDeletions(pair *cfg.Pair, result *SyncResult) error {
 			}
 
 			result.FilesDeleted++
-			log.Info().
+			logging.ForPair(pair.ID).Info().
 				Str("pair", pair.ID).
 				Str("file", relativePath).
 				Msg("deleted (mirror)")
+			recordEvent(EventFileDeleted, pair.ID, NormalizePath(relativePath), "mirror")
 		}
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	if pair.DefersMirrorDeletes() {
+		if !c.dryRun {
+			pendingDeletes.replace(pair.ID, deferred)
+		}
+		return nil
+	}
+
+	if !c.dryRun {
+		c.mirrorDirectoryDeletions(pair, directories, result)
+	}
+	return nil
+}
+
+// mirrorDirectoryDeletions removes target directories whose source counterpart
+// no longer exists. WalkDir visits parents before children, so iterating in
+// reverse removes nested directories first. The target root is never removed
+// and non-empty directories are skipped without a warning.
+func (c *Copier) mirrorDirectoryDeletions(pair *cfg.Pair, directories []string, result *SyncResult) {
+	for i := len(directories) - 1; i >= 0; i-- {
+		path := directories[i]
+
+		relativePath, err := filepath.Rel(pair.Target, path)
+		if err != nil || relativePath == "." {
+			continue
+		}
+
+		if sourceExistsFor(pair, relativePath) {
+			continue
+		}
+
+		// Directories still holding entries (excluded, filtered or kept files)
+		// stay in place; that is expected, not a failure
+		if empty, err := isEmptyDirectory(path); err == nil && !empty {
+			logging.ForPair(pair.ID).Debug().
+				Str("pair", pair.ID).
+				Str("dir", relativePath).
+				Msg("kept non-empty target directory")
+			continue
+		}
+
+		if err := removeOwnedFile(path); err != nil {
+			logging.ForPair(pair.ID).Warn().
+				Str("pair", pair.ID).
+				Str("dir", relativePath).
+				Err(err).
+				Msg("failed to delete target directory")
+			continue
+		}
+
+		result.DirsDeleted++
+		logging.ForPair(pair.ID).Info().
+			Str("pair", pair.ID).
+			Str("dir", relativePath).
+			Msg("deleted directory (mirror)")
+	}
+}
+
+// isEmptyDirectory reports whether the directory at path has no entries
+func isEmptyDirectory(path string) (bool, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err != nil {
+		if err == io.EOF {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
 }
 
 // ===== FILE OPERATIONS =====
 
 // calculateFileHash computes SHA256 hash of a file using optimized buffering.
 func calculateFileHash(filePath string) (string, error) {
+	release, err := acquireOpenFiles(context.Background(), 1)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return "", err
@@ -319,7 +833,18 @@ func calculateFileHash(filePath string) (string, error) {
 // copyAtomic performs atomic file copying using temporary file and rename.
 // This ensures that the target file is never in a partially written state.
 func copyAtomic(sourcePath, targetPath string) (int64, error) {
-	tempPath := targetPath + ".tmp"
+	tempPath := tempPathFor(targetPath)
+
+	// Mark paths as written by the app so target drift detection ignores them
+	ownWrites.begin(tempPath, targetPath)
+	defer ownWrites.end(tempPath, targetPath)
+
+	// Reserve handles for the source and temporary files
+	release, err := acquireOpenFiles(context.Background(), 2)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
 
 	// Open source file
 	sourceFile, err := os.Open(sourcePath)
@@ -329,7 +854,7 @@ func copyAtomic(sourcePath, targetPath string) (int64, error) {
 	defer sourceFile.Close()
 
 	// Create temporary target file
-	tempFile, err := os.Create(tempPath)
+	tempFile, err := createTempFile(tempPath)
 	if err != nil {
 		return 0, err
 	}
@@ -354,7 +879,7 @@ func copyAtomic(sourcePath, targetPath string) (int64, error) {
 	}
 
 	// Atomic rename to final destination
-	if err := os.Rename(tempPath, targetPath); err != nil {
+	if err := replaceTarget(tempPath, targetPath); err != nil {
 		os.Remove(tempPath) // Clean up on failure
 		return bytesCopied, err
 	}
@@ -362,6 +887,54 @@ func copyAtomic(sourcePath, targetPath string) (int64, error) {
 	return bytesCopied, nil
 }
 
+// renameTarget moves a finished temporary file into place; replaceable for testing.
+var renameTarget = os.Rename
+
+// replaceTarget renames a finished temporary file over the target. When the rename
+// crosses filesystems, which happens when the target file itself is a bind mount or
+// mount point, the content is written over the target in place instead and the
+// temporary file removed. The target is then briefly partially written.
+func replaceTarget(tempPath, targetPath string) error {
+	err := renameTarget(tempPath, targetPath)
+	if err == nil || !isCrossDeviceError(err) {
+		return err
+	}
+
+	log.Warn().Str("file", targetPath).Err(err).Msg("cross-device rename, overwriting target in place")
+	if err := overwriteFile(tempPath, targetPath); err != nil {
+		return err
+	}
+	return os.Remove(tempPath)
+}
+
+// overwriteFile copies the content and modification time of sourcePath over targetPath.
+func overwriteFile(sourcePath, targetPath string) error {
+	sourceFile, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer sourceFile.Close()
+
+	targetFile, err := os.OpenFile(targetPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	buffer := make([]byte, CopyBufferSize)
+	_, copyErr := io.CopyBuffer(targetFile, sourceFile, buffer)
+	if closeErr := targetFile.Close(); copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+
+	if sourceInfo, err := sourceFile.Stat(); err == nil {
+		os.Chtimes(targetPath, time.Now(), sourceInfo.ModTime())
+	}
+	return nil
+}
+
 // ===== EVENT DEBOUNCING =====
 
 // Debouncer coalesces rapid file system events per key to prevent excessive processing.
@@ -431,6 +1004,15 @@ func NormalizePath(path string) string {
 	return strings.ReplaceAll(path, string(os.PathSeparator), "/")
 }
 
+// throughputMBps returns the copy rate in MB/s rounded to two decimals, or 0 when
+// nothing was copied or the duration is too short to measure.
+func throughputMBps(bytes int64, duration time.Duration) float64 {
+	if bytes <= 0 || duration < MinThroughputDuration {
+		return 0
+	}
+	return math.Round(float64(bytes)/(1024*1024)/duration.Seconds()*100) / 100
+}
+
 // RelPath returns the relative path from base to target, normalized with forward slashes.
 // This is a convenience wrapper around filepath.Rel with path normalization.
 func RelPath(base, target string) string {
@@ -0,0 +1,359 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// ===== TEST HELPERS =====
+
+// newTestPair returns a pair syncing a fresh source directory to a fresh target
+func newTestPair(t *testing.T) *cfg.Pair {
+	t.Helper()
+	return &cfg.Pair{
+		ID:           strings.ReplaceAll(t.Name(), "/", "_"),
+		Source:       t.TempDir(),
+		Target:       t.TempDir(),
+		SyncStrategy: "mtime",
+	}
+}
+
+// writeTestFile creates a file and its parent directories
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readTestFile returns a file's content, or "" when it doesn't exist
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// syncTestPair runs a full sync of pair
+func syncTestPair(t *testing.T, pair *cfg.Pair) (int, error) {
+	t.Helper()
+	files, _, err := (&Copier{}).CompareAndSync(context.Background(), pair)
+	return files, err
+}
+
+// ===== COPY RETRIES =====
+
+func TestCopyRetryDelays(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		delayMs int
+		want    []time.Duration
+	}{
+		{"legacy schedule", 0, 0, []time.Duration{FirstRetryDelay, SecondRetryDelay}},
+		{"retries with default delay", 3, 0, []time.Duration{FirstRetryDelay, 2 * FirstRetryDelay, 4 * FirstRetryDelay}},
+		{"delay with default retries", 0, 50, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}},
+		{"exponential growth", 4, 250, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second}},
+		{"capped growth", 3, 20000, []time.Duration{20 * time.Second, MaxCopyRetryDelay, MaxCopyRetryDelay}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := &cfg.Pair{CopyRetries: tt.retries, CopyRetryDelayMs: tt.delayMs}
+			if got := copyRetryDelays(pair); !slices.Equal(got, tt.want) {
+				t.Errorf("copyRetryDelays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// blockTarget puts a non-empty directory where a file is to be copied, failing
+// the copy's final rename until unblock is called
+func blockTarget(t *testing.T, targetPath string) (unblock func()) {
+	t.Helper()
+	writeTestFile(t, filepath.Join(targetPath, "blocker"), "")
+	return func() {
+		if err := os.RemoveAll(targetPath); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestCopyRetriesTransientFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		retries   int
+		unblockIn time.Duration // 0 keeps the target blocked
+		wantErr   bool
+	}{
+		{"recovers on retry", 3, 50 * time.Millisecond, false},
+		{"gives up after retries", 1, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.CopyRetries = tt.retries
+			pair.CopyRetryDelayMs = 200
+			writeTestFile(t, filepath.Join(pair.Source, "app.jar"), "data")
+			targetPath := filepath.Join(pair.Target, "app.jar")
+			unblock := blockTarget(t, targetPath)
+			if tt.unblockIn > 0 {
+				time.AfterFunc(tt.unblockIn, unblock)
+			}
+
+			// The full sync copy path retries like the watcher's
+			_, err := syncTestPair(t, pair)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sync error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && readTestFile(t, targetPath) != "data" {
+				t.Error("file not copied after the transient failure")
+			}
+		})
+	}
+}
+
+func TestCopyRetriesStopOnCancel(t *testing.T) {
+	pair := newTestPair(t)
+	pair.CopyRetries = 5
+	pair.CopyRetryDelayMs = 10000
+	sourcePath := filepath.Join(pair.Source, "app.jar")
+	writeTestFile(t, sourcePath, "data")
+	targetPath := filepath.Join(pair.Target, "app.jar")
+	blockTarget(t, targetPath)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := copyWithRetry(ctx, pair, sourcePath, targetPath); err == nil {
+		t.Fatal("copy into a blocked target succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled copy kept retrying for %s", elapsed)
+	}
+}
+
+// ===== PERIODIC HASH CHECK =====
+
+func TestRunTrackerSchedulesHashChecks(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		every    int
+		want     []bool // hash check decision of consecutive runs
+	}{
+		{"disabled", SyncStrategyMTime, 0, []bool{false, false, false, false}},
+		{"every run", SyncStrategyMTime, 1, []bool{true, true, true, true}},
+		{"every third run", SyncStrategyMTime, 3, []bool{false, false, true, false, false, true}},
+		{"default strategy", "", 2, []bool{false, true, false, true}},
+		{"hash strategy already hashes", SyncStrategyHash, 2, []bool{false, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.SyncStrategy = tt.strategy
+			pair.PeriodicHashCheck = tt.every
+			forgetPairRuns(t, pair.ID)
+
+			for run, want := range tt.want {
+				got, err := pairRuns.begin(pair)
+				if err != nil {
+					t.Fatalf("run %d: %v", run+1, err)
+				}
+				pairRuns.end(pair.ID)
+				if got != want {
+					t.Errorf("run %d hash check = %v, want %v", run+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPeriodicHashCheckCatchesSilentChange(t *testing.T) {
+	tests := []struct {
+		name      string
+		every     int
+		wantRun   int // run that recopies the changed file (0 = none)
+		totalRuns int
+	}{
+		{"caught on the third run", 3, 3, 4},
+		{"never caught without the check", 0, 0, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.PeriodicHashCheck = tt.every
+			forgetPairRuns(t, pair.ID)
+			sourcePath := filepath.Join(pair.Source, "config.ini")
+			targetPath := filepath.Join(pair.Target, "config.ini")
+			writeTestFile(t, sourcePath, "version=1")
+
+			// Run 1 copies the file; then it changes keeping its size and mtime
+			if files, err := syncTestPair(t, pair); err != nil || files != 1 {
+				t.Fatalf("run 1 copied %d files, err %v, want 1", files, err)
+			}
+			writeTestFile(t, sourcePath, "version=2")
+			stamp := time.Now().Add(-time.Hour).Truncate(time.Second)
+			for _, path := range []string{sourcePath, targetPath} {
+				if err := os.Chtimes(path, stamp, stamp); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for run := 2; run <= tt.totalRuns; run++ {
+				files, err := syncTestPair(t, pair)
+				if err != nil {
+					t.Fatalf("run %d: %v", run, err)
+				}
+				wantFiles := 0
+				if run == tt.wantRun {
+					wantFiles = 1
+				}
+				if files != wantFiles {
+					t.Errorf("run %d copied %d files, want %d", run, files, wantFiles)
+				}
+			}
+
+			want := "version=1"
+			if tt.wantRun != 0 {
+				want = "version=2"
+			}
+			if got := readTestFile(t, targetPath); got != want {
+				t.Errorf("target holds %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+// ===== MULTIPLE SOURCES =====
+
+func TestMultiSourceFilteredFileDoesNotHideLaterSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		conflict string
+		filter   func(pair *cfg.Pair)
+	}{
+		{"excluded in first source", cfg.SourceConflictError, func(pair *cfg.Pair) { pair.ExcludeGlobs = []string{"**/first/**"} }},
+		{"too large in first source", cfg.SourceConflictError, func(pair *cfg.Pair) { pair.MaxFileSize = 10 }},
+		{"first wins mode", "", func(pair *cfg.Pair) { pair.MaxFileSize = 10 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			base := t.TempDir()
+			first, second := filepath.Join(base, "first"), filepath.Join(base, "second")
+			pair.Sources = []string{first, second}
+			pair.SourceConflict = tt.conflict
+			tt.filter(pair)
+
+			writeTestFile(t, filepath.Join(first, "app.cfg"), "filtered out of the first source")
+			writeTestFile(t, filepath.Join(second, "app.cfg"), "second")
+
+			if _, err := syncTestPair(t, pair); err != nil {
+				t.Fatalf("sync: %v", err)
+			}
+			if got := readTestFile(t, filepath.Join(pair.Target, "app.cfg")); got != "second" {
+				t.Errorf("target app.cfg = %q, want the second source's copy", got)
+			}
+		})
+	}
+}
+
+func TestMultiSourceConflictStillReported(t *testing.T) {
+	pair := newTestPair(t)
+	base := t.TempDir()
+	first, second := filepath.Join(base, "first"), filepath.Join(base, "second")
+	pair.Sources = []string{first, second}
+	pair.SourceConflict = cfg.SourceConflictError
+
+	writeTestFile(t, filepath.Join(first, "app.cfg"), "first")
+	writeTestFile(t, filepath.Join(second, "app.cfg"), "second")
+
+	if _, err := syncTestPair(t, pair); err == nil {
+		t.Fatal("sync succeeded, want a source conflict error")
+	}
+	if got := readTestFile(t, filepath.Join(pair.Target, "app.cfg")); got != "first" {
+		t.Errorf("target app.cfg = %q, want the first source's copy", got)
+	}
+}
+
+// ===== MIRROR DIRECTORY DELETIONS =====
+
+func TestMirrorDeletesRemovesOrphanedDirectories(t *testing.T) {
+	pair := newTestPair(t)
+	pair.MirrorDeletes = true
+	writeTestFile(t, filepath.Join(pair.Source, "kept", "file.txt"), "data")
+	for _, dir := range []string{"kept", "gone/nested/deeper", "gone/sibling"} {
+		if err := os.MkdirAll(filepath.Join(pair.Target, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, filepath.Join(pair.Target, "gone", "nested", "orphan.txt"), "orphan")
+
+	if _, err := syncTestPair(t, pair); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pair.Target, "gone")); !os.IsNotExist(err) {
+		t.Errorf("orphaned directory tree survived: %v", err)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, "kept", "file.txt")) != "data" {
+		t.Error("directory with a source counterpart lost its file")
+	}
+	if _, err := os.Stat(pair.Target); err != nil {
+		t.Errorf("target root removed: %v", err)
+	}
+}
+
+func TestMirrorDirectoryDeletionsSkipsNonEmptyDirectories(t *testing.T) {
+	pair := newTestPair(t)
+	var directories []string
+	for _, dir := range []string{"gone", "gone/empty", "gone/full"} {
+		path := filepath.Join(pair.Target, dir)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		directories = append(directories, path)
+	}
+	writeTestFile(t, filepath.Join(pair.Target, "gone", "full", "excluded.tmp"), "kept")
+
+	result := &SyncResult{}
+	(&Copier{pair: pair}).mirrorDirectoryDeletions(pair, append([]string{pair.Target}, directories...), result)
+
+	tests := []struct {
+		dir        string
+		wantExists bool
+	}{
+		{".", true},
+		{"gone", true},
+		{"gone/empty", false},
+		{"gone/full", true},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(pair.Target, tt.dir))
+		if exists := err == nil; exists != tt.wantExists {
+			t.Errorf("%s exists = %v, want %v", tt.dir, exists, tt.wantExists)
+		}
+	}
+	if result.DirsDeleted != 1 || len(result.Errors) != 0 {
+		t.Errorf("DirsDeleted = %d, errors %v, want 1 and none", result.DirsDeleted, result.Errors)
+	}
+}
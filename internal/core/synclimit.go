@@ -0,0 +1,83 @@
+// Package core provides the concurrent sync limit for the FolderSynchronizer application.
+// Full sync runs of a pair manager's pairs (scheduled, manual, sync all and watcher
+// comparison passes) take a slot before they start, so pairs firing at the same time
+// queue instead of all hitting the disks at once.
+package core
+
+import (
+	"context"
+	"sync/atomic"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ===== SYNC CONCURRENCY LIMIT =====
+
+// DefaultMaxConcurrentSyncs bounds full sync runs executing at once across all pairs
+const DefaultMaxConcurrentSyncs = 4
+
+// syncLimit bounds concurrent full sync runs across the pairs of a manager.
+// A nil syncLimit doesn't limit anything.
+type syncLimit struct {
+	limiter atomic.Pointer[Semaphore]
+}
+
+// newSyncLimit returns a limit of limit concurrent syncs; zero or less is unlimited
+func newSyncLimit(limit int) *syncLimit {
+	l := &syncLimit{}
+	l.limiter.Store(NewSemaphore(limit))
+	return l
+}
+
+// set changes the limit. Syncs already running keep their slot in the
+// previous limiter until they finish.
+func (l *syncLimit) set(limit int) {
+	l.limiter.Store(NewSemaphore(limit))
+}
+
+// usage returns the current use of the limit
+func (l *syncLimit) usage() LimiterUsage {
+	if l == nil {
+		return LimiterUsage{}
+	}
+	return usageOf(l.limiter.Load())
+}
+
+// acquire waits for a free sync slot and returns its release function.
+func (l *syncLimit) acquire(ctx context.Context, pair *cfg.Pair) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	limiter := l.limiter.Load()
+	if limiter != nil && limiter.InUse() >= limiter.Capacity() {
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Int("limit", limiter.Capacity()).Msg("sync queued")
+	}
+	if err := limiter.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return limiter.Release, nil
+}
+
+// SetMaxConcurrentSyncs changes the manager's sync concurrency limit.
+// A value of zero or less removes the limit. Syncs already running keep
+// their slot in the previous limiter until they finish.
+func (pm *PairManager) SetMaxConcurrentSyncs(limit int) {
+	pm.syncs.set(limit)
+	log.Info().Int("max_concurrent_syncs", limit).Msg("sync concurrency limit set")
+}
+
+// SyncSlotsUsage returns the current use of the sync concurrency limit;
+// Waiting is the number of queued sync runs.
+func (pm *PairManager) SyncSlotsUsage() LimiterUsage {
+	return pm.syncs.usage()
+}
+
+// NewCopier returns a copier whose full sync runs wait for a slot of the
+// manager's sync concurrency limit.
+func (pm *PairManager) NewCopier() *Copier {
+	return &Copier{syncs: pm.syncs}
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// limitSyncs returns a pair manager running at most limit syncs at once,
+// and the limiter holding its slots
+func limitSyncs(t *testing.T, limit int) (*PairManager, *Semaphore) {
+	t.Helper()
+	pm := newTestPairManager(t)
+	pm.SetMaxConcurrentSyncs(limit)
+	return pm, pm.syncs.limiter.Load()
+}
+
+func TestConcurrentSyncsStayWithinLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		syncs int
+	}{
+		{"one at a time", 1, 4},
+		{"two at a time", 2, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm, _ := limitSyncs(t, tt.limit)
+			var pairs []*cfg.Pair
+			for i := range tt.syncs {
+				pair := newTestPair(t)
+				pair.ID = fmt.Sprintf("%s-%d", pair.ID, i)
+				forgetPairRuns(t, pair.ID)
+				for j := range 50 {
+					writeTestFile(t, filepath.Join(pair.Source, fmt.Sprintf("f%02d.txt", j)), "data")
+				}
+				pairs = append(pairs, pair)
+			}
+
+			// Sample the running syncs while all pairs sync at once
+			var peak atomic.Int32
+			done := make(chan struct{})
+			sampled := make(chan struct{})
+			go func() {
+				defer close(sampled)
+				for {
+					if running := int32(pairRuns.running()); running > peak.Load() {
+						peak.Store(running)
+					}
+					select {
+					case <-done:
+						return
+					default:
+					}
+				}
+			}()
+
+			var wg sync.WaitGroup
+			for _, pair := range pairs {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, _, err := pm.NewCopier().CompareAndSync(context.Background(), pair); err != nil {
+						t.Errorf("sync of %s: %v", pair.ID, err)
+					}
+				}()
+			}
+			wg.Wait()
+			close(done)
+			<-sampled
+
+			if got := int(peak.Load()); got > tt.limit {
+				t.Errorf("%d syncs ran at once, limit %d", got, tt.limit)
+			}
+			for _, pair := range pairs {
+				if readTestFile(t, filepath.Join(pair.Target, "f49.txt")) != "data" {
+					t.Errorf("pair %s not synced", pair.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestQueuedSyncIsReported(t *testing.T) {
+	pm, limiter := limitSyncs(t, 1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	pair := newTestPair(t)
+	forgetPairRuns(t, pair.ID)
+	writeTestFile(t, filepath.Join(pair.Source, "a.txt"), "a")
+
+	finished := make(chan error, 1)
+	go func() {
+		_, _, err := pm.NewCopier().CompareAndSync(context.Background(), pair)
+		finished <- err
+	}()
+	waitFor(t, 5*time.Second, "the sync to queue", func() bool {
+		usage := pm.SyncSlotsUsage()
+		return usage.InUse == 1 && usage.Waiting == 1 && usage.Limit == 1
+	})
+	if readTestFile(t, filepath.Join(pair.Target, "a.txt")) != "" {
+		t.Fatal("queued sync copied a file")
+	}
+
+	limiter.Release()
+	if err := <-finished; err != nil {
+		t.Fatal(err)
+	}
+	if usage := pm.SyncSlotsUsage(); usage.InUse != 0 || usage.Waiting != 0 {
+		t.Errorf("usage after the sync = %+v, want idle", usage)
+	}
+}
+
+func TestQueuedSyncStopsOnCancel(t *testing.T) {
+	pm, limiter := limitSyncs(t, 1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(limiter.Release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, err := pm.NewCopier().CompareAndSync(ctx, newTestPair(t)); err == nil {
+		t.Error("queued sync ran although its context ended")
+	}
+}
@@ -0,0 +1,116 @@
+// Package core provides cleanup of leftover temporary copy files for the FolderSynchronizer application.
+// Copies are written to a temporary file next to the target and renamed into place; a crash or kill
+// mid-copy leaves that file behind. Sweeps at startup and after each sync remove the ones old enough
+// that no copy can still be writing them.
+package core
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+// TempFileSuffix ends the name of every temporary copy file
+const TempFileSuffix = ".tmp"
+
+// tempNameRandomBytes is the number of random bytes in a temporary file name
+const tempNameRandomBytes = 6
+
+// staleTempAge is the age after which a temporary copy file is considered abandoned
+var staleTempAge atomic.Int64
+
+func init() {
+	staleTempAge.Store(int64(cfg.DefaultStaleTempAge))
+}
+
+// SetStaleTempAge sets the age after which leftover temporary copy files are removed.
+func SetStaleTempAge(age time.Duration) {
+	if age <= 0 {
+		age = cfg.DefaultStaleTempAge
+	}
+	staleTempAge.Store(int64(age))
+}
+
+// ===== TEMP FILE NAMING =====
+
+// tempPathFor returns a unique temporary path next to targetPath, such as
+// "report.pdf.3f9a0c21b7e4.tmp". Concurrent copies of the same file, e.g. a watcher
+// event racing a scheduled run, each write their own temporary file.
+func tempPathFor(targetPath string) string {
+	random := make([]byte, tempNameRandomBytes)
+	_, _ = rand.Read(random)
+	return targetPath + "." + hex.EncodeToString(random) + TempFileSuffix
+}
+
+// createTempFile creates the temporary file at tempPath, failing if it already
+// exists rather than truncating another copy's file.
+func createTempFile(tempPath string) (*os.File, error) {
+	return os.OpenFile(tempPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o666)
+}
+
+// ===== STALE TEMP FILE SWEEP =====
+
+// SweepStaleTempFiles removes leftover temporary copy files from the targets of all pairs.
+func SweepStaleTempFiles(pairs []*cfg.Pair) {
+	for _, pair := range pairs {
+		sweepStaleTempFiles(pair)
+	}
+}
+
+// sweepStaleTempFiles removes temporary copy files older than the stale age from
+// the pair's targets and returns how many were removed. A file is only taken for a
+// temporary file when its name ends in TempFileSuffix, which also matches the
+// fixed "<file>.tmp" names of older versions, and no source holds a file of that
+// name, so synced files that happen to end in .tmp are kept.
+func sweepStaleTempFiles(pair *cfg.Pair) int {
+	cutoff := time.Now().Add(-time.Duration(staleTempAge.Load()))
+	removed := 0
+
+	for _, target := range pair.TargetRoots() {
+		if !IsDirectoryExists(target) {
+			continue
+		}
+
+		filepath.WalkDir(target, func(path string, dirEntry fs.DirEntry, err error) error {
+			if err != nil || dirEntry.IsDir() || !dirEntry.Type().IsRegular() {
+				return nil
+			}
+			if !strings.HasSuffix(dirEntry.Name(), TempFileSuffix) || ownWrites.isOwn(path) {
+				return nil
+			}
+
+			relativePath, err := filepath.Rel(target, path)
+			if err != nil || sourceExistsFor(pair, relativePath) {
+				return nil
+			}
+
+			info, err := dirEntry.Info()
+			if err != nil || info.ModTime().After(cutoff) {
+				return nil
+			}
+
+			if err := os.Remove(path); err != nil {
+				logging.ForPair(pair.ID).Warn().Str("pair", pair.ID).Str("file", path).Err(err).Msg("stale temp file removal failed")
+				return nil
+			}
+			removed++
+			logging.ForPair(pair.ID).Info().
+				Str("pair", pair.ID).
+				Str("file", path).
+				Time("modified", info.ModTime()).
+				Msg("removed stale temp file")
+			return nil
+		})
+	}
+	return removed
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTempPathForIsUnique(t *testing.T) {
+	targetPath := filepath.Join(t.TempDir(), "report.pdf")
+	seen := make(map[string]bool)
+	for range 1000 {
+		tempPath := tempPathFor(targetPath)
+		if seen[tempPath] {
+			t.Fatalf("temporary path %q handed out twice", tempPath)
+		}
+		seen[tempPath] = true
+
+		if filepath.Dir(tempPath) != filepath.Dir(targetPath) {
+			t.Fatalf("temporary path %q isn't next to the target", tempPath)
+		}
+		if name := filepath.Base(tempPath); !strings.HasPrefix(name, "report.pdf.") || !strings.HasSuffix(name, TempFileSuffix) {
+			t.Fatalf("temporary name %q, want report.pdf.<random>%s", name, TempFileSuffix)
+		}
+	}
+}
+
+func TestCreateTempFileRefusesExisting(t *testing.T) {
+	tempPath := tempPathFor(filepath.Join(t.TempDir(), "a.txt"))
+	file, err := createTempFile(tempPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	if file, err := createTempFile(tempPath); err == nil {
+		file.Close()
+		t.Error("createTempFile truncated an existing temporary file")
+	}
+}
+
+func TestConcurrentCopiesOfSameFile(t *testing.T) {
+	dir := t.TempDir()
+	sourcePath, targetPath := filepath.Join(dir, "source.bin"), filepath.Join(dir, "target", "source.bin")
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1<<16) // 1 MiB
+	if err := os.WriteFile(sourcePath, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A watcher copy and a scheduled run copying the same file at once
+	const copiers, rounds = 2, 20
+	var wg sync.WaitGroup
+	errs := make(chan error, copiers*rounds)
+	for range copiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				if _, err := copyAtomic(sourcePath, targetPath); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent copy failed: %v", err)
+	}
+
+	got, err := os.ReadFile(targetPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("target corrupted: %d bytes, want %d", len(got), len(content))
+	}
+	entries, err := os.ReadDir(filepath.Dir(targetPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("target directory holds %d entries, want no temporary files left", len(entries))
+	}
+}
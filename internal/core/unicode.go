@@ -0,0 +1,66 @@
+// Package core provides Unicode path normalization for the FolderSynchronizer application.
+// It keeps cross-platform syncs stable when source and target use different normalization forms
+// (macOS file systems typically report NFD names while Windows and Linux use NFC).
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	cfg "FolderSynchronizer/internal/config"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ===== UNICODE NORMALIZATION FORMS =====
+
+const (
+	UnicodeNormalizationNone = ""    // Keep names as reported by the file system
+	UnicodeNormalizationNFC  = "nfc" // Canonical composition (Windows/Linux convention)
+	UnicodeNormalizationNFD  = "nfd" // Canonical decomposition (macOS convention)
+)
+
+// NormalizeUnicodePath converts a path to the requested Unicode normalization form.
+// Unknown or empty forms return the path unchanged.
+func NormalizeUnicodePath(form, path string) string {
+	switch strings.ToLower(form) {
+	case UnicodeNormalizationNFC:
+		return norm.NFC.String(path)
+	case UnicodeNormalizationNFD:
+		return norm.NFD.String(path)
+	default:
+		return path
+	}
+}
+
+// UnicodePathsEqual reports whether two paths are identical after canonical
+// normalization, e.g. "café.txt" in NFC and NFD forms.
+func UnicodePathsEqual(a, b string) bool {
+	return a == b || norm.NFC.String(a) == norm.NFC.String(b)
+}
+
+// targetPathFor builds the target path for a source-relative path, applying
+// the pair's Unicode normalization to the relative part.
+func targetPathFor(pair *cfg.Pair, relativePath string) string {
+	return filepath.Join(pair.Target, NormalizeUnicodePath(pair.UnicodeNormalization, relativePath))
+}
+
+// sourceExistsFor reports whether a source counterpart exists for a
+// target-relative path in any of the pair's sources. When normalization is enabled, both NFC and NFD
+// spellings of the name are tried so a renormalized copy isn't treated as orphaned.
+func sourceExistsFor(pair *cfg.Pair, relativePath string) bool {
+	candidates := []string{relativePath}
+	if pair.UnicodeNormalization != UnicodeNormalizationNone {
+		candidates = append(candidates, norm.NFC.String(relativePath), norm.NFD.String(relativePath))
+	}
+
+	for _, root := range pair.SourceRoots() {
+		for _, candidate := range candidates {
+			if _, err := os.Lstat(filepath.Join(root, candidate)); err == nil || !os.IsNotExist(err) {
+				return true
+			}
+		}
+	}
+	return false
+}
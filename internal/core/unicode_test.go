@@ -0,0 +1,76 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC and NFD spellings of the same name
+var (
+	cafeNFC = norm.NFC.String("café.txt")
+	cafeNFD = norm.NFD.String("café.txt")
+)
+
+func TestNormalizeUnicodePath(t *testing.T) {
+	tests := []struct {
+		name string
+		form string
+		path string
+		want string
+	}{
+		{"nfd to nfc", UnicodeNormalizationNFC, cafeNFD, cafeNFC},
+		{"nfc stays nfc", UnicodeNormalizationNFC, cafeNFC, cafeNFC},
+		{"nfc to nfd", UnicodeNormalizationNFD, cafeNFC, cafeNFD},
+		{"form is case-insensitive", "NFC", cafeNFD, cafeNFC},
+		{"none keeps the name", UnicodeNormalizationNone, cafeNFD, cafeNFD},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeUnicodePath(tt.form, tt.path); got != tt.want {
+				t.Errorf("NormalizeUnicodePath(%q, %q) = %q, want %q", tt.form, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnicodePathsEqual(t *testing.T) {
+	if cafeNFC == cafeNFD {
+		t.Fatal("test names should differ in their bytes")
+	}
+	if !UnicodePathsEqual(cafeNFC, cafeNFD) {
+		t.Error("NFC and NFD spellings of café.txt should be equal")
+	}
+	if UnicodePathsEqual(cafeNFC, "cafe.txt") {
+		t.Error("café.txt and cafe.txt should differ")
+	}
+}
+
+func TestUnicodeNormalizedSyncIsStable(t *testing.T) {
+	pair := newTestPair(t)
+	pair.UnicodeNormalization = UnicodeNormalizationNFC
+	pair.MirrorDeletes = true
+	writeTestFile(t, filepath.Join(pair.Source, cafeNFD), "data")
+
+	if files, err := syncTestPair(t, pair); err != nil || files != 1 {
+		t.Fatalf("first sync copied %d files, err %v, want 1", files, err)
+	}
+	if readTestFile(t, filepath.Join(pair.Target, cafeNFC)) != "data" {
+		t.Fatal("target name was not normalized to NFC")
+	}
+
+	// The NFC copy is the NFD source file: no recopy and no mirror delete
+	if files, err := syncTestPair(t, pair); err != nil || files != 0 {
+		t.Errorf("second sync copied %d files, err %v, want 0", files, err)
+	}
+	entries, err := os.ReadDir(pair.Target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != cafeNFC {
+		t.Errorf("target holds %v, want only the NFC name", entries)
+	}
+}
@@ -0,0 +1,41 @@
+// Package core provides watcher event statistics for the FolderSynchronizer application.
+// Counting the source events a watcher receives shows in the pair status whether a
+// watcher is actually seeing changes, which helps explain a change that didn't sync.
+package core
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ===== WATCHER EVENT STATISTICS =====
+
+// watcherEventStats counts the source events received by a worker's watcher.
+// The counters are atomic since status requests read them while events arrive.
+type watcherEventStats struct {
+	count atomic.Int64 // Events received since the watcher started
+	last  atomic.Int64 // Time of the latest event in Unix nanoseconds, 0 before the first
+}
+
+// record counts one received event.
+func (s *watcherEventStats) record() {
+	s.count.Add(1)
+	s.last.Store(time.Now().UnixNano())
+}
+
+// reset clears the counters when the watcher (re)starts.
+func (s *watcherEventStats) reset() {
+	s.count.Store(0)
+	s.last.Store(0)
+}
+
+// snapshot returns the event count and the time of the latest event (nil before the first).
+func (s *watcherEventStats) snapshot() (int64, *time.Time) {
+	count := s.count.Load()
+	last := s.last.Load()
+	if last == 0 {
+		return count, nil
+	}
+	lastEvent := time.Unix(0, last)
+	return count, &lastEvent
+}
@@ -0,0 +1,219 @@
+// Package core provides throttled watch setup for the FolderSynchronizer application.
+// Directories are added to the file watcher in batches, yielding between batches and
+// stopping promptly when the worker is cancelled, with progress exposed in pair status.
+package core
+
+import (
+	cfg "FolderSynchronizer/internal/config"
+	"FolderSynchronizer/internal/logging"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// DefaultWatchSetupBatchSize is how many directories are added before yielding
+	DefaultWatchSetupBatchSize = 1000
+
+	// WatchSetupProgressInterval is how often watch setup progress is logged
+	WatchSetupProgressInterval = 5 * time.Second
+)
+
+// ===== WATCH SETUP PROGRESS =====
+
+// WatchSetupStatus reports the progress of adding directories to the watcher.
+type WatchSetupStatus struct {
+	Added int `json:"added"` // Directories added so far
+	Total int `json:"total"` // Estimated number of directories to add
+}
+
+// watchSetupProgress tracks watch setup of a worker for status reporting.
+type watchSetupProgress struct {
+	mutex  sync.Mutex
+	active bool // Whether watch setup is in progress
+	added  int  // Directories added so far
+	total  int  // Estimated number of directories
+}
+
+// begin records the start of watch setup with an estimated directory count.
+func (p *watchSetupProgress) begin(total int) {
+	p.mutex.Lock()
+	p.active, p.added, p.total = true, 0, total
+	p.mutex.Unlock()
+}
+
+// update records the number of directories added so far.
+func (p *watchSetupProgress) update(added int) {
+	p.mutex.Lock()
+	p.added = added
+	if added > p.total {
+		p.total = added // Directories created since the estimate
+	}
+	p.mutex.Unlock()
+}
+
+// finish records the end of watch setup.
+func (p *watchSetupProgress) finish() {
+	p.mutex.Lock()
+	p.active = false
+	p.mutex.Unlock()
+}
+
+// snapshot returns the setup progress, or nil when no setup is in progress.
+func (p *watchSetupProgress) snapshot() *WatchSetupStatus {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if !p.active {
+		return nil
+	}
+	return &WatchSetupStatus{Added: p.added, Total: p.total}
+}
+
+// ===== THROTTLED WATCH SETUP =====
+
+// addDirectoriesToWatcher recursively adds directories to the file system watcher.
+// Directories are added in batches of the pair's WatchSetupBatchSize, yielding (or
+// pausing WatchSetupPauseMs) between batches. Setup stops with the context error when
+// the worker is cancelled. Progress is recorded when progress is non-nil. Directories
+// beyond the pair's MaxDepth below root aren't watched.
+func (w *PairWorker) addDirectoriesToWatcher(watcher *fsnotify.Watcher, root string, progress *watchSetupProgress) error {
+	pair := w.Pair
+	ctx := w.ctx
+
+	batchSize := pair.WatchSetupBatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultWatchSetupBatchSize
+	}
+	pause := time.Duration(pair.WatchSetupPauseMs) * time.Millisecond
+
+	if progress != nil {
+		total, err := countDirectories(ctx, pair, root, batchSize)
+		if err != nil {
+			return err
+		}
+		progress.begin(total)
+		defer progress.finish()
+
+		logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Str("dir", root).Int("directories", total).Msg("setting up watches")
+	}
+
+	started := time.Now()
+	lastLog := started
+	added := 0
+	beyondDepth := 0
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		relativePath := RelPath(root, path)
+		if dirBeyondMaxDepth(pair, relativePath) {
+			beyondDepth++
+			return filepath.SkipDir
+		}
+		if relativePath != "." && isHiddenEntry(pair, relativePath, d) {
+			return filepath.SkipDir
+		}
+
+		if err := addWatch(watcher, path); err != nil {
+			return err
+		}
+		added++
+
+		if added%batchSize != 0 {
+			return nil
+		}
+
+		if progress != nil {
+			progress.update(added)
+			if time.Since(lastLog) >= WatchSetupProgressInterval {
+				lastLog = time.Now()
+				status := progress.snapshot()
+				logging.ForPair(pair.ID).Info().Str("pair", pair.ID).Int("added", status.Added).Int("total", status.Total).Msg("setting up watches")
+			}
+		}
+
+		return yieldWatchSetup(ctx, pause)
+	})
+	if err != nil {
+		return err
+	}
+
+	if beyondDepth > 0 {
+		logging.ForPair(pair.ID).Warn().
+			Str("pair", pair.ID).
+			Str("dir", root).
+			Int("directories", beyondDepth).
+			Int("max_depth", pair.MaxDepth).
+			Msg("directories beyond max depth not watched")
+	}
+
+	if progress != nil {
+		progress.update(added)
+		logging.ForPair(pair.ID).Info().
+			Str("pair", pair.ID).
+			Int("directories", added).
+			Dur("elapsed", time.Since(started)).
+			Msg("watch setup complete")
+	}
+	return nil
+}
+
+// countDirectories estimates the number of directories under root for progress
+// reporting, yielding every batchSize directories and honoring cancellation.
+// Directories beyond the pair's MaxDepth aren't counted.
+func countDirectories(ctx context.Context, pair *cfg.Pair, root string, batchSize int) (int, error) {
+	count := 0
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			return nil // Errors surface when the directories are added
+		}
+		if d.IsDir() {
+			if relativePath := RelPath(root, path); dirBeyondMaxDepth(pair, relativePath) || (relativePath != "." && isHiddenEntry(pair, relativePath, d)) {
+				return filepath.SkipDir
+			}
+			count++
+			if count%batchSize == 0 {
+				runtime.Gosched()
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+// yieldWatchSetup gives other goroutines a chance to run between batches,
+// sleeping for pause when it is set. Returns the context error if cancelled.
+func yieldWatchSetup(ctx context.Context, pause time.Duration) error {
+	if pause <= 0 {
+		runtime.Gosched()
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(pause)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
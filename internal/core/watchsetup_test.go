@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	cfg "FolderSynchronizer/internal/config"
+)
+
+// makeDirectoryTree creates count directories of 10 subdirectories each below
+// root and returns the total number of directories including root
+func makeDirectoryTree(t *testing.T, root string, count int) int {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		path := filepath.Join(root, fmt.Sprintf("d%03d", i/10), fmt.Sprintf("d%03d", i))
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return 1 + count + (count+9)/10
+}
+
+// newSetupWorker returns a worker over pair with its own cancellable context
+func newSetupWorker(t *testing.T, pair *cfg.Pair) (*PairWorker, *fsnotify.Watcher) {
+	t.Helper()
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { watcher.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return &PairWorker{Pair: pair, ctx: ctx, cancel: cancel}, watcher
+}
+
+func TestAddDirectoriesToWatcher(t *testing.T) {
+	tests := []struct {
+		name      string
+		batchSize int
+		maxDepth  int
+		want      func(total int) int
+	}{
+		{"default batch", 0, 0, func(total int) int { return total }},
+		{"small batches", 7, 0, func(total int) int { return total }},
+		{"max depth", 7, 1, func(total int) int { return 1 + 5 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := newTestPair(t)
+			pair.WatchSetupBatchSize = tt.batchSize
+			pair.MaxDepth = tt.maxDepth
+			total := makeDirectoryTree(t, pair.Source, 50)
+			worker, watcher := newSetupWorker(t, pair)
+
+			if err := worker.addDirectoriesToWatcher(watcher, pair.Source, &worker.watchSetup); err != nil {
+				t.Fatalf("watch setup: %v", err)
+			}
+			if got, want := len(watcher.WatchList()), tt.want(total); got != want {
+				t.Errorf("watching %d directories, want %d", got, want)
+			}
+			if status := worker.watchSetup.snapshot(); status != nil {
+				t.Errorf("setup still reported in progress: %+v", status)
+			}
+		})
+	}
+}
+
+func TestWatchSetupReportsProgressAndStopsOnCancel(t *testing.T) {
+	pair := newTestPair(t)
+	pair.WatchSetupBatchSize = 10
+	pair.WatchSetupPauseMs = 20
+	total := makeDirectoryTree(t, pair.Source, 500)
+	worker, watcher := newSetupWorker(t, pair)
+
+	done := make(chan error, 1)
+	go func() { done <- worker.addDirectoriesToWatcher(watcher, pair.Source, &worker.watchSetup) }()
+
+	// Progress carries the upfront estimate while directories are added
+	var status *WatchSetupStatus
+	waitFor(t, 5*time.Second, "watch setup progress", func() bool {
+		status = worker.watchSetup.snapshot()
+		return status != nil && status.Added >= 20
+	})
+	if status.Total != total {
+		t.Errorf("estimated %d directories, want %d", status.Total, total)
+	}
+
+	worker.cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled setup returned %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch setup ignored cancellation")
+	}
+
+	if added := len(watcher.WatchList()); added >= total {
+		t.Errorf("setup added all %d directories despite the cancellation", added)
+	}
+	if status := worker.watchSetup.snapshot(); status != nil {
+		t.Errorf("cancelled setup still reported in progress: %+v", status)
+	}
+}
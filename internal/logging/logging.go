@@ -3,9 +3,13 @@
 package logging
 
 import (
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -25,6 +29,9 @@ const (
 
 	// Directory permissions for log directory creation
 	LogDirPermissions = 0o755
+
+	// Per-pair log files are named <prefix><pair id>.log in the logs directory
+	PairLogFilePrefix = "pair-"
 )
 
 // ===== LOGGING CONFIGURATION STRUCTURES =====
@@ -42,6 +49,139 @@ type Config struct {
 	PrettyLog  bool          // Whether to use pretty console formatting
 }
 
+// ===== ACTIVE LOG FILE =====
+
+// activeLogFile is the path of the rotating log file written by the current logger
+var (
+	activeLogMutex sync.RWMutex
+	activeLogFile  string
+)
+
+// LogFilePath returns the path of the current log file, or "" if file logging
+// has not been set up.
+func LogFilePath() string {
+	activeLogMutex.RLock()
+	defer activeLogMutex.RUnlock()
+	return activeLogFile
+}
+
+// RotatedLogFiles returns the rotated backups of the current log file (plain or
+// gzip-compressed), newest first.
+func RotatedLogFiles() ([]string, error) {
+	current := LogFilePath()
+	if current == "" {
+		return nil, nil
+	}
+
+	// Lumberjack names backups <name>-<timestamp><ext>[.gz] next to the log file
+	ext := filepath.Ext(current)
+	prefix := strings.TrimSuffix(filepath.Base(current), ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Dir(current))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if strings.HasSuffix(name, ext) || strings.HasSuffix(name, ext+".gz") {
+			backups = append(backups, filepath.Join(filepath.Dir(current), name))
+		}
+	}
+
+	// Timestamped names sort oldest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// ===== PER-PAIR LOG FILES =====
+
+// pairLog tees a pair's log events to its own rotating file
+type pairLog struct {
+	file   *lumberjack.Logger // Rotating pair-<id>.log writer
+	logger zerolog.Logger     // Global logger writing to both the main output and file
+}
+
+// Per-pair log state. mainWriter and activeConfig are set by SetupWithConfig;
+// pair logs opened before that are not created.
+var (
+	pairLogMutex sync.RWMutex
+	pairLogs     = make(map[string]*pairLog)
+	mainWriter   io.Writer
+	activeConfig *Config
+)
+
+// OpenPairLog starts teeing the events of a pair to pair-<id>.log in the logs
+// directory, rotated with the main log's settings. Opening an already open pair
+// log is a no-op.
+func OpenPairLog(pairID string) error {
+	pairLogMutex.Lock()
+	defer pairLogMutex.Unlock()
+
+	if _, exists := pairLogs[pairID]; exists {
+		return nil
+	}
+	if activeConfig == nil {
+		return errors.New("logging is not configured")
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   PairLogFilePath(pairID),
+		MaxSize:    activeConfig.MaxSizeMB,
+		MaxBackups: activeConfig.MaxBackups,
+		MaxAge:     activeConfig.MaxAgeDays,
+		Compress:   activeConfig.Compress,
+	}
+	pairLogs[pairID] = &pairLog{
+		file:   file,
+		logger: log.Logger.Output(io.MultiWriter(mainWriter, file)),
+	}
+	return nil
+}
+
+// ClosePairLog stops teeing a pair's events to its log file and closes it.
+func ClosePairLog(pairID string) {
+	pairLogMutex.Lock()
+	defer pairLogMutex.Unlock()
+
+	if pl, exists := pairLogs[pairID]; exists {
+		_ = pl.file.Close()
+		delete(pairLogs, pairID)
+	}
+}
+
+// ForPair returns the logger for a pair's events: the global logger, writing
+// to the pair's log file as well when OpenPairLog was called for it.
+func ForPair(pairID string) *zerolog.Logger {
+	pairLogMutex.RLock()
+	defer pairLogMutex.RUnlock()
+
+	if pl, exists := pairLogs[pairID]; exists {
+		return &pl.logger
+	}
+	return &log.Logger
+}
+
+// PairLogFilePath returns the path of a pair's log file, or "" if file logging
+// has not been set up. Characters not safe in file names are replaced by "_".
+func PairLogFilePath(pairID string) string {
+	current := LogFilePath()
+	if current == "" {
+		return ""
+	}
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, pairID)
+	return filepath.Join(filepath.Dir(current), PairLogFilePrefix+name+".log")
+}
+
 // ===== DEFAULT CONFIGURATION =====
 
 // DefaultConfig returns a sensible default logging configuration.
@@ -106,6 +246,7 @@ func SetupWithConfig(config *Config) (zerolog.Logger, error) {
 
 	// Set as global logger
 	log.Logger = logger
+	resetPairLogs(config, multiWriter)
 
 	// Log the setup completion
 	logger.Info().
@@ -125,6 +266,10 @@ func SetupWithConfig(config *Config) (zerolog.Logger, error) {
 func createFileWriter(config *Config) (io.Writer, error) {
 	logFilePath := filepath.Join(config.LogsDir, config.FileName)
 
+	activeLogMutex.Lock()
+	activeLogFile = logFilePath
+	activeLogMutex.Unlock()
+
 	fileRotator := &lumberjack.Logger{
 		Filename:   logFilePath,
 		MaxSize:    config.MaxSizeMB,
@@ -136,6 +281,18 @@ func createFileWriter(config *Config) (io.Writer, error) {
 	return fileRotator, nil
 }
 
+// resetPairLogs points open pair logs at a newly configured global logger.
+func resetPairLogs(config *Config, writer io.Writer) {
+	pairLogMutex.Lock()
+	defer pairLogMutex.Unlock()
+
+	activeConfig = config
+	mainWriter = writer
+	for _, pl := range pairLogs {
+		pl.logger = log.Logger.Output(io.MultiWriter(mainWriter, pl.file))
+	}
+}
+
 // createConsoleWriter creates a console writer with optional pretty formatting.
 func createConsoleWriter(prettyLog bool) io.Writer {
 	if prettyLog {
@@ -166,9 +323,19 @@ func setupZerologGlobals(config *Config) {
 
 // ===== UTILITY FUNCTIONS =====
 
-// SetLogLevel changes the global log level at runtime.
+// SetLogLevel changes the global log level at runtime. The global logger (and
+// open pair loggers) get the new level too, since their own level would
+// otherwise keep filtering out more verbose events.
 func SetLogLevel(level zerolog.Level) {
 	zerolog.SetGlobalLevel(level)
+	log.Logger = log.Logger.Level(level)
+
+	pairLogMutex.Lock()
+	for _, pl := range pairLogs {
+		pl.logger = pl.logger.Level(level)
+	}
+	pairLogMutex.Unlock()
+
 	log.Info().
 		Str("new_level", level.String()).
 		Msg("log level changed")
@@ -0,0 +1,121 @@
+// Package metrics provides Prometheus instrumentation for the FolderSynchronizer application.
+// It defines the sync, hook and scheduler collectors and exposes them via an HTTP handler.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// ===== CONSTANTS =====
+
+const (
+	// Namespace prefixes all metric names
+	Namespace = "foldersync"
+
+	// Label values for execution results
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultSkipped = "skipped"
+)
+
+// ===== COLLECTORS =====
+
+var (
+	// FilesCopied counts files copied per pair (full syncs and watcher events)
+	FilesCopied = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "files_copied_total",
+		Help:      "Total number of files copied.",
+	}, []string{"pair"})
+
+	// BytesCopied counts bytes copied per pair
+	BytesCopied = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "bytes_copied_total",
+		Help:      "Total number of bytes copied.",
+	}, []string{"pair"})
+
+	// SyncFailures counts failed CompareAndSync runs per pair
+	SyncFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "sync_failures_total",
+		Help:      "Total number of failed synchronization runs.",
+	}, []string{"pair"})
+
+	// HookExecutions counts hook executions per pair, hook type and result
+	HookExecutions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "hook_executions_total",
+		Help:      "Total number of hook executions by type and result.",
+	}, []string{"pair", "type", "result"})
+
+	// TaskRuns counts scheduler task executions per pair and result
+	TaskRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "task_runs_total",
+		Help:      "Total number of scheduled task executions by result.",
+	}, []string{"pair", "result"})
+
+	// WatcherActive reports 1 while a pair's file watcher is running
+	WatcherActive = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "watcher_active",
+		Help:      "Whether the file watcher of a pair is active (1) or not (0).",
+	}, []string{"pair"})
+)
+
+// Registry holds the application collectors plus Go runtime and process metrics
+var Registry = prometheus.NewRegistry()
+
+var registerOnce sync.Once
+
+// ===== REGISTRATION AND EXPOSITION =====
+
+// Register registers all collectors with the application registry.
+// It is safe to call multiple times; collectors are registered only once.
+func Register() error {
+	var registerErr error
+
+	registerOnce.Do(func() {
+		appCollectors := []prometheus.Collector{
+			FilesCopied,
+			BytesCopied,
+			SyncFailures,
+			HookExecutions,
+			TaskRuns,
+			WatcherActive,
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		}
+
+		for _, collector := range appCollectors {
+			if err := Registry.Register(collector); err != nil {
+				var alreadyRegistered prometheus.AlreadyRegisteredError
+				if !errors.As(err, &alreadyRegistered) {
+					registerErr = err
+				}
+			}
+		}
+	})
+
+	return registerErr
+}
+
+// Handler returns the HTTP handler serving metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ResultLabel converts a success flag to a result label value.
+func ResultLabel(success bool) string {
+	if success {
+		return ResultSuccess
+	}
+	return ResultFailure
+}
@@ -0,0 +1,64 @@
+// Package scheduler provides holiday calendars for the FolderSynchronizer application.
+// Custom schedules with SkipHolidays consult a HolidayProvider before running.
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HolidayDateLayout is the date format of configured holidays
+const HolidayDateLayout = "2006-01-02"
+
+// ===== HOLIDAY PROVIDERS =====
+
+// HolidayProvider reports whether a date is a holiday. The date is given in
+// the timezone of the schedule being evaluated.
+type HolidayProvider interface {
+	IsHoliday(date time.Time) bool
+}
+
+// HolidayList is a fixed set of holiday dates keyed by "YYYY-MM-DD".
+type HolidayList map[string]struct{}
+
+// NewHolidayList parses holiday dates in HolidayDateLayout format.
+func NewHolidayList(dates []string) (HolidayList, error) {
+	holidays := make(HolidayList, len(dates))
+	for _, date := range dates {
+		date = strings.TrimSpace(date)
+		if _, err := time.Parse(HolidayDateLayout, date); err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q (expected YYYY-MM-DD): %w", date, err)
+		}
+		holidays[date] = struct{}{}
+	}
+	return holidays, nil
+}
+
+// IsHoliday reports whether the calendar date of t is in the list.
+func (h HolidayList) IsHoliday(date time.Time) bool {
+	_, exists := h[date.Format(HolidayDateLayout)]
+	return exists
+}
+
+// ===== WORK DAYS =====
+
+// isWorkDay reports whether a weekday is Monday through Friday.
+func isWorkDay(day time.Weekday) bool {
+	return day != time.Saturday && day != time.Sunday
+}
+
+// customDayAllowed applies the weekday, OnlyWorkDays and SkipHolidays rules of
+// a custom schedule to a date.
+func customDayAllowed(custom *CustomSchedule, holidays HolidayProvider, date time.Time) bool {
+	if !(&Scheduler{}).isValidWeekDay(custom.WeekDays, date.Weekday()) {
+		return false
+	}
+	if custom.OnlyWorkDays && !isWorkDay(date.Weekday()) {
+		return false
+	}
+	if custom.SkipHolidays && holidays != nil && holidays.IsHoliday(date) {
+		return false
+	}
+	return true
+}
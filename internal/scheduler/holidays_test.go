@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewHolidayList(t *testing.T) {
+	holidays, err := NewHolidayList([]string{"2024-12-25", " 2025-01-01 "})
+	if err != nil {
+		t.Fatalf("NewHolidayList: %v", err)
+	}
+	if !holidays.IsHoliday(time.Date(2024, 12, 25, 15, 0, 0, 0, time.UTC)) {
+		t.Error("2024-12-25 should be a holiday at any time of day")
+	}
+	if !holidays.IsHoliday(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("trimmed 2025-01-01 should be a holiday")
+	}
+	if holidays.IsHoliday(time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC)) {
+		t.Error("2024-12-26 should not be a holiday")
+	}
+
+	for _, invalid := range []string{"25.12.2024", "2024-13-01", "christmas"} {
+		if _, err := NewHolidayList([]string{invalid}); err == nil {
+			t.Errorf("NewHolidayList accepted %q", invalid)
+		}
+	}
+}
+
+func TestCustomDayAllowed(t *testing.T) {
+	holidays, err := NewHolidayList([]string{"2024-12-25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	christmas := time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC) // Wednesday
+	weekday := time.Date(2024, 12, 18, 10, 0, 0, 0, time.UTC)   // Wednesday
+	saturday := time.Date(2024, 12, 21, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		custom   CustomSchedule
+		provider HolidayProvider
+		date     time.Time
+		want     bool
+	}{
+		{"holiday on a weekday skipped", CustomSchedule{SkipHolidays: true}, holidays, christmas, false},
+		{"holiday runs without SkipHolidays", CustomSchedule{}, holidays, christmas, true},
+		{"ordinary weekday runs", CustomSchedule{SkipHolidays: true}, holidays, weekday, true},
+		{"no calendar means no holidays", CustomSchedule{SkipHolidays: true}, nil, christmas, true},
+		{"only work days skips saturday", CustomSchedule{OnlyWorkDays: true}, nil, saturday, false},
+		{"only work days allows wednesday", CustomSchedule{OnlyWorkDays: true}, nil, weekday, true},
+		{"only work days overrides listed weekend", CustomSchedule{OnlyWorkDays: true, WeekDays: []WeekDay{Saturday, Wednesday}}, nil, saturday, false},
+		{"listed weekdays without work day rule", CustomSchedule{WeekDays: []WeekDay{Saturday}}, nil, saturday, true},
+		{"unlisted weekday", CustomSchedule{WeekDays: []WeekDay{Monday}}, nil, weekday, false},
+		{"work day holiday with both rules", CustomSchedule{OnlyWorkDays: true, SkipHolidays: true}, holidays, christmas, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := customDayAllowed(&tt.custom, tt.provider, tt.date); got != tt.want {
+				t.Errorf("customDayAllowed(%s) = %v, want %v", tt.date.Format("Mon 2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldExecuteCustomTaskSkipsHoliday(t *testing.T) {
+	s := newTestScheduler(t)
+	holidays, err := NewHolidayList([]string{"2024-12-25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetHolidayProvider(holidays)
+
+	schedule := NewCustomSchedule([]WeekDay{Monday, Tuesday, Wednesday, Thursday, Friday}, "08:00", "20:00", "1h")
+	schedule.Custom.SkipHolidays = true
+	task := &Task{ID: "docs", Schedule: schedule, Enabled: true}
+	start, _ := time.Parse("15:04", "08:00")
+	end, _ := time.Parse("15:04", "20:00")
+	var lastExecution time.Time
+
+	if s.shouldExecuteCustomTask(task, time.Date(2024, 12, 25, 10, 0, 0, 0, time.UTC), start, end, time.Hour, &lastExecution) {
+		t.Error("task ran on a holiday")
+	}
+	if !s.shouldExecuteCustomTask(task, time.Date(2024, 12, 24, 10, 0, 0, 0, time.UTC), start, end, time.Hour, &lastExecution) {
+		t.Error("task didn't run on the working day before the holiday")
+	}
+}
+
+func TestValidateOnlyWorkDays(t *testing.T) {
+	tests := []struct {
+		name     string
+		weekDays []WeekDay
+		wantErr  bool
+	}{
+		{"no weekdays listed", nil, false},
+		{"work days listed", []WeekDay{Monday, Friday}, false},
+		{"mixed days listed", []WeekDay{Saturday, Monday}, false},
+		{"weekend only", []WeekDay{Saturday, Sunday}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule := NewCustomSchedule(tt.weekDays, "08:00", "20:00", "1h")
+			schedule.Custom.OnlyWorkDays = true
+			if err := ValidateSchedule(schedule); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSchedule() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
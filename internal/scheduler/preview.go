@@ -0,0 +1,155 @@
+// Package scheduler provides schedule preview functionality for the FolderSynchronizer application.
+// It computes upcoming run times for a schedule without registering a task.
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ===== PREVIEW CONSTANTS =====
+
+const (
+	DefaultPreviewCount = 5   // Number of run times returned when none is requested
+	MaxPreviewCount     = 100 // Upper bound on requested run times
+	MaxPreviewDays      = 366 // How far ahead custom schedules are searched
+)
+
+// cronParser matches the parser used by the scheduler (seconds field required)
+var cronParser = cron.NewParser(
+	cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// ===== SCHEDULE PREVIEW =====
+
+// PreviewRuns returns up to count upcoming run times of a schedule after from,
+// expressed in the schedule's timezone (local time when none is set).
+// Date range and MaxRuns limits are respected, so fewer times may be returned.
+// Watcher and disabled schedules have no run times. Holidays are consulted
+// for custom schedules with SkipHolidays and may be nil.
+func PreviewRuns(schedule Schedule, from time.Time, count int, holidays HolidayProvider) ([]time.Time, error) {
+	if count <= 0 {
+		count = DefaultPreviewCount
+	}
+	if count > MaxPreviewCount {
+		count = MaxPreviewCount
+	}
+	if schedule.MaxRuns > 0 && schedule.MaxRuns < count {
+		count = schedule.MaxRuns
+	}
+
+	location, err := LoadLocation(schedule.Timezone, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	from = from.In(location)
+	if schedule.StartDate != nil && from.Before(*schedule.StartDate) {
+		from = schedule.StartDate.In(location)
+	}
+
+	var next func(time.Time) time.Time
+	switch schedule.Type {
+	case ScheduleTypeDisabled, ScheduleTypeWatcher:
+		return []time.Time{}, nil
+
+	case ScheduleTypeInterval, ScheduleTypePoll:
+		interval, err := time.ParseDuration(schedule.Interval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval %s", schedule.Interval)
+		}
+		next = func(t time.Time) time.Time { return t.Add(interval) }
+
+	case ScheduleTypeCron:
+		cronSchedule, err := cronParser.Parse(cronSpecInLocation(schedule.CronExpr, location))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %s: %w", schedule.CronExpr, err)
+		}
+		next = cronSchedule.Next
+
+	case ScheduleTypeCustom:
+		return previewCustomRuns(schedule, from, count, holidays)
+
+	case ScheduleTypeOnce:
+		if schedule.RunAt == nil {
+			return nil, fmt.Errorf("runAt is required for once schedule")
+		}
+		runAt := schedule.RunAt.In(location)
+		if !runAt.After(from) || (schedule.EndDate != nil && runAt.After(*schedule.EndDate)) {
+			return []time.Time{}, nil
+		}
+		return []time.Time{runAt}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported schedule type: %s", schedule.Type)
+	}
+
+	runs := make([]time.Time, 0, count)
+	for t := next(from); len(runs) < count && !t.IsZero(); t = next(t) {
+		if schedule.EndDate != nil && t.After(*schedule.EndDate) {
+			break
+		}
+		runs = append(runs, t)
+	}
+	return runs, nil
+}
+
+// previewCustomRuns computes run times for a custom schedule: runs start at
+// the beginning of each allowed window and repeat every interval while inside
+// the window, keeping at least one interval between consecutive runs.
+func previewCustomRuns(schedule Schedule, from time.Time, count int, holidays HolidayProvider) ([]time.Time, error) {
+	custom := schedule.Custom
+	if custom == nil {
+		return nil, fmt.Errorf("custom schedule configuration is missing")
+	}
+
+	s := &Scheduler{}
+	if err := s.validateCustomSchedule(custom); err != nil {
+		return nil, err
+	}
+
+	interval, _ := time.ParseDuration(custom.Interval)
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid custom interval %s", custom.Interval)
+	}
+	startTime, _ := time.Parse("15:04", custom.StartTime)
+	endTime, _ := time.Parse("15:04", custom.EndTime)
+
+	location := from.Location()
+	runs := make([]time.Time, 0, count)
+	var lastRun time.Time
+
+	// Start a day early so a midnight-crossing window opened yesterday is included
+	day := time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, location)
+	for i := 0; i <= MaxPreviewDays && len(runs) < count; i, day = i+1, day.AddDate(0, 0, 1) {
+		if !customDayAllowed(custom, holidays, day) {
+			continue
+		}
+
+		windowStart := time.Date(day.Year(), day.Month(), day.Day(), startTime.Hour(), startTime.Minute(), 0, 0, location)
+		windowEnd := time.Date(day.Year(), day.Month(), day.Day(), endTime.Hour(), endTime.Minute(), 0, 0, location)
+		if windowEnd.Before(windowStart) {
+			windowEnd = windowEnd.AddDate(0, 0, 1) // Window spans midnight
+		}
+
+		t := windowStart
+		if !lastRun.IsZero() && lastRun.Add(interval).After(t) {
+			t = lastRun.Add(interval)
+		}
+		for !t.After(from) {
+			t = t.Add(interval)
+		}
+
+		for ; !t.After(windowEnd) && len(runs) < count; t = t.Add(interval) {
+			if schedule.EndDate != nil && t.After(*schedule.EndDate) {
+				return runs, nil
+			}
+			runs = append(runs, t)
+			lastRun = t
+		}
+	}
+
+	return runs, nil
+}
@@ -4,10 +4,17 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"FolderSynchronizer/internal/logging"
+	"FolderSynchronizer/internal/metrics"
+
 	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog/log"
 )
@@ -23,6 +30,8 @@ const (
 	ScheduleTypeInterval ScheduleType = "interval" // Fixed interval execution
 	ScheduleTypeCron     ScheduleType = "cron"     // Cron expression scheduling
 	ScheduleTypeCustom   ScheduleType = "custom"   // Custom schedule configuration
+	ScheduleTypeOnce     ScheduleType = "once"     // Single execution at RunAt, then disabled
+	ScheduleTypePoll     ScheduleType = "poll"     // Watch by comparing every Interval, for shares where file events are unreliable
 )
 
 // WeekDay represents days of the week for scheduling
@@ -42,38 +51,46 @@ const (
 
 // Schedule contains complete schedule configuration for a task
 type Schedule struct {
-	Type ScheduleType `json:"type"` // Type of schedule
+	Type ScheduleType `json:"type" yaml:"type"` // Type of schedule
 
-	// For interval type scheduling
-	Interval string `json:"interval,omitempty"` // "5m", "1h30m", "2h"
+	// For interval and poll type scheduling
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"` // "5m", "1h30m", "2h"
 
 	// For cron type scheduling
-	CronExpr string `json:"cronExpr,omitempty"` // "0 8-20/90 * * 1-5"
+	CronExpr string `json:"cronExpr,omitempty" yaml:"cronExpr,omitempty"` // "0 8-20/90 * * 1-5"
 
 	// For custom type scheduling - detailed configuration
-	Custom *CustomSchedule `json:"custom,omitempty"`
+	Custom *CustomSchedule `json:"custom,omitempty" yaml:"custom,omitempty"`
+
+	// For once type scheduling
+	RunAt *time.Time `json:"runAt,omitempty" yaml:"runAt,omitempty"` // "2025-03-01T02:00:00+03:00"
 
 	// Common schedule settings
-	Timezone  string     `json:"timezone,omitempty"`  // "Europe/Moscow", "UTC"
-	StartDate *time.Time `json:"startDate,omitempty"` // Schedule activation date
-	EndDate   *time.Time `json:"endDate,omitempty"`   // Schedule expiration date
-	MaxRuns   int        `json:"maxRuns,omitempty"`   // Maximum number of executions
+	Timezone  string     `json:"timezone,omitempty" yaml:"timezone,omitempty"`   // "Europe/Moscow", "UTC"
+	StartDate *time.Time `json:"startDate,omitempty" yaml:"startDate,omitempty"` // Schedule activation date
+	EndDate   *time.Time `json:"endDate,omitempty" yaml:"endDate,omitempty"`     // Schedule expiration date
+	MaxRuns   int        `json:"maxRuns,omitempty" yaml:"maxRuns,omitempty"`     // Maximum number of executions
 }
 
 // CustomSchedule provides detailed schedule configuration with time windows and weekdays
 type CustomSchedule struct {
-	WeekDays  []WeekDay `json:"weekDays"`  // [Monday, Tuesday, Wednesday, Thursday, Friday]
-	StartTime string    `json:"startTime"` // "08:00"
-	EndTime   string    `json:"endTime"`   // "20:00"
-	Interval  string    `json:"interval"`  // "1h30m"
+	WeekDays  []WeekDay `json:"weekDays" yaml:"weekDays"`   // [Monday, Tuesday, Wednesday, Thursday, Friday]
+	StartTime string    `json:"startTime" yaml:"startTime"` // "08:00"
+	EndTime   string    `json:"endTime" yaml:"endTime"`     // "20:00"
+	Interval  string    `json:"interval" yaml:"interval"`   // "1h30m"
 
 	// Additional scheduling options
-	SkipHolidays bool `json:"skipHolidays,omitempty"` // Skip holidays (future feature)
-	OnlyWorkDays bool `json:"onlyWorkDays,omitempty"` // Only work days (future feature)
+	SkipHolidays bool `json:"skipHolidays,omitempty" yaml:"skipHolidays,omitempty"` // Skip dates in the scheduler's holiday calendar
+	OnlyWorkDays bool `json:"onlyWorkDays,omitempty" yaml:"onlyWorkDays,omitempty"` // Only Monday-Friday, in addition to WeekDays
 }
 
 // ===== TASK DEFINITIONS =====
 
+// ErrRunSkipped can be wrapped by a task function's error to report that the
+// run was skipped rather than failed. Skipped runs are counted in SkippedRuns
+// and don't change LastRun, RunCount, FailCount or LastError.
+var ErrRunSkipped = errors.New("run skipped")
+
 // TaskFunc represents a function that can be executed by the scheduler
 type TaskFunc func(ctx context.Context) error
 
@@ -91,11 +108,21 @@ type Task struct {
 	FailCount int        `json:"failCount"`           // Total failed executions
 	LastError string     `json:"lastError,omitempty"` // Last error message
 
+	// Executions counted against Schedule.MaxRuns; reset when the schedule changes
+	LimitRuns int `json:"limitRuns"`
+
+	// Triggers dropped because the previous run was still in progress or the
+	// task function reported ErrRunSkipped
+	SkippedRuns    int    `json:"skippedRuns"`
+	LastSkipReason string `json:"lastSkipReason,omitempty"` // Why the latest skipped run was skipped
+
 	// Internal fields (not serialized)
-	fn        TaskFunc      // Task execution function
-	cronEntry cron.EntryID  // Cron scheduler entry ID
-	ticker    *time.Ticker  // Interval ticker
-	stopChan  chan struct{} // Stop signal channel
+	fn        TaskFunc       // Task execution function
+	cronEntry cron.EntryID   // Cron scheduler entry ID
+	ticker    *time.Ticker   // Interval ticker
+	timer     *time.Timer    // One-shot timer (once schedules)
+	stopChan  chan struct{}  // Stop signal channel
+	location  *time.Location // Timezone of the task's schedule
 }
 
 // ===== SCHEDULER IMPLEMENTATION =====
@@ -108,22 +135,25 @@ type Scheduler struct {
 	ctx      context.Context    // Scheduler context for shutdown
 	cancel   context.CancelFunc // Cancel function for graceful shutdown
 	timezone *time.Location     // Default timezone for scheduling
+
+	restored map[string]TaskStats // Saved statistics awaiting their task (see RestoreStats)
+	holidays HolidayProvider      // Holiday calendar for SkipHolidays schedules
+	running  atomic.Bool          // Set between Start and Stop
+
+	// Set while a task's execution is in progress, by task ID. Kept out of Task
+	// so that the copies handed out by GetTask and ListTasks don't race with it.
+	inFlight map[string]*atomic.Bool
+
+	onRunLimit func(task *Task) // Called when a task uses up its MaxRuns (see SetRunLimitHandler)
 }
 
 // ===== SCHEDULER LIFECYCLE =====
 
 // NewScheduler creates a new scheduler instance with the specified timezone
 func NewScheduler(timezone string) (*Scheduler, error) {
-	var location *time.Location
-	var err error
-
-	if timezone == "" {
-		location = time.Local
-	} else {
-		location, err = time.LoadLocation(timezone)
-		if err != nil {
-			return nil, fmt.Errorf("invalid timezone %s: %w", timezone, err)
-		}
+	location, err := LoadLocation(timezone, time.Local)
+	if err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -137,6 +167,7 @@ func NewScheduler(timezone string) (*Scheduler, error) {
 
 	return &Scheduler{
 		tasks:    make(map[string]*Task),
+		inFlight: make(map[string]*atomic.Bool),
 		cron:     cronScheduler,
 		ctx:      ctx,
 		cancel:   cancel,
@@ -147,11 +178,18 @@ func NewScheduler(timezone string) (*Scheduler, error) {
 // Start begins scheduler operation
 func (s *Scheduler) Start() {
 	s.cron.Start()
+	s.running.Store(true)
 	log.Info().Msg("scheduler started")
 }
 
+// IsRunning reports whether the scheduler has been started and not stopped
+func (s *Scheduler) IsRunning() bool {
+	return s.running.Load()
+}
+
 // Stop gracefully shuts down the scheduler and all running tasks
 func (s *Scheduler) Stop() {
+	s.running.Store(false)
 	s.cancel()
 	s.cron.Stop()
 
@@ -172,6 +210,11 @@ func (s *Scheduler) cleanupTask(task *Task) {
 		task.ticker = nil
 	}
 
+	if task.timer != nil {
+		task.timer.Stop()
+		task.timer = nil
+	}
+
 	if task.stopChan != nil {
 		select {
 		case <-task.stopChan:
@@ -182,6 +225,29 @@ func (s *Scheduler) cleanupTask(task *Task) {
 	}
 }
 
+// SetHolidayProvider sets the holiday calendar consulted by custom schedules
+// with SkipHolidays. A nil provider means no holidays.
+func (s *Scheduler) SetHolidayProvider(provider HolidayProvider) {
+	s.mutex.Lock()
+	s.holidays = provider
+	s.mutex.Unlock()
+}
+
+// SetRunLimitHandler sets a function called, with a copy of the task, after the
+// execution that uses up a task's MaxRuns. A nil handler disables the callback.
+func (s *Scheduler) SetRunLimitHandler(handler func(task *Task)) {
+	s.mutex.Lock()
+	s.onRunLimit = handler
+	s.mutex.Unlock()
+}
+
+// holidayProvider returns the current holiday calendar
+func (s *Scheduler) holidayProvider() HolidayProvider {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.holidays
+}
+
 // ===== TASK MANAGEMENT =====
 
 // AddTask adds a new task to the scheduler with the specified configuration
@@ -206,8 +272,10 @@ func (s *Scheduler) AddTask(id, name string, schedule Schedule, fn TaskFunc) err
 		return fmt.Errorf("failed to schedule task %s: %w", id, err)
 	}
 
+	s.applyRestoredStats(task)
 	s.tasks[id] = task
-	log.Info().
+	s.inFlight[id] = new(atomic.Bool)
+	logging.ForPair(id).Info().
 		Str("task", id).
 		Str("type", string(schedule.Type)).
 		Msg("task added")
@@ -227,8 +295,9 @@ func (s *Scheduler) RemoveTask(id string) error {
 
 	s.unscheduleTask(task)
 	delete(s.tasks, id)
+	delete(s.inFlight, id)
 
-	log.Info().Str("task", id).Msg("task removed")
+	logging.ForPair(id).Info().Str("task", id).Msg("task removed")
 	return nil
 }
 
@@ -245,6 +314,11 @@ func (s *Scheduler) UpdateTask(id string, schedule Schedule) error {
 	// Stop current schedule
 	s.unscheduleTask(task)
 
+	// A changed schedule gets a fresh MaxRuns budget
+	if !reflect.DeepEqual(task.Schedule, schedule) {
+		task.LimitRuns = 0
+	}
+
 	// Update schedule configuration
 	task.Schedule = schedule
 
@@ -255,7 +329,7 @@ func (s *Scheduler) UpdateTask(id string, schedule Schedule) error {
 		}
 	}
 
-	log.Info().
+	logging.ForPair(id).Info().
 		Str("task", id).
 		Str("type", string(schedule.Type)).
 		Msg("task updated")
@@ -330,6 +404,15 @@ func (s *Scheduler) GetTask(id string) (*Task, error) {
 	return s.copyTaskForAPI(task), nil
 }
 
+// RunsRemaining returns how many executions the task's MaxRuns still allows,
+// and false when the schedule has no limit.
+func (t *Task) RunsRemaining() (int, bool) {
+	if t.Schedule.MaxRuns <= 0 {
+		return 0, false
+	}
+	return max(t.Schedule.MaxRuns-t.LimitRuns, 0), true
+}
+
 // ListTasks returns information about all tasks
 func (s *Scheduler) ListTasks() []*Task {
 	s.mutex.RLock()
@@ -355,6 +438,12 @@ func (s *Scheduler) copyTaskForAPI(task *Task) *Task {
 
 // scheduleTask configures task execution based on its schedule type
 func (s *Scheduler) scheduleTask(task *Task) error {
+	location, err := LoadLocation(task.Schedule.Timezone, s.timezone)
+	if err != nil {
+		return err
+	}
+	task.location = location
+
 	switch task.Schedule.Type {
 	case ScheduleTypeDisabled:
 		// No scheduling needed for disabled tasks
@@ -367,12 +456,18 @@ func (s *Scheduler) scheduleTask(task *Task) error {
 	case ScheduleTypeInterval:
 		return s.scheduleIntervalTask(task)
 
+	case ScheduleTypePoll:
+		return s.schedulePollTask(task)
+
 	case ScheduleTypeCron:
 		return s.scheduleCronTask(task)
 
 	case ScheduleTypeCustom:
 		return s.scheduleCustomTask(task)
 
+	case ScheduleTypeOnce:
+		return s.scheduleOnceTask(task)
+
 	default:
 		return fmt.Errorf("unsupported schedule type: %s", task.Schedule.Type)
 	}
@@ -386,22 +481,50 @@ func (s *Scheduler) scheduleIntervalTask(task *Task) error {
 	}
 
 	task.ticker = time.NewTicker(interval)
-	task.NextRun = timePtr(time.Now().Add(interval))
+	task.NextRun = timePtr(time.Now().In(task.location).Add(interval))
 
-	go s.runIntervalTask(task, interval)
+	go s.runIntervalTask(task, task.ticker, task.stopChan, interval)
+	return nil
+}
+
+// schedulePollTask sets up a polling watch: a first pass right away, like a
+// watcher's initial scan, then one pass per interval.
+func (s *Scheduler) schedulePollTask(task *Task) error {
+	interval, err := time.ParseDuration(task.Schedule.Interval)
+	if err != nil {
+		return fmt.Errorf("invalid poll interval %s: %w", task.Schedule.Interval, err)
+	}
+
+	task.ticker = time.NewTicker(interval)
+	task.NextRun = timePtr(time.Now().In(task.location))
+
+	go func(ticker *time.Ticker, stopChan chan struct{}) {
+		select {
+		case <-stopChan:
+			return
+		case <-s.ctx.Done():
+			return
+		default:
+		}
+		if s.shouldExecuteTask(task) {
+			s.executeTask(task)
+			s.setNextRun(task, time.Now().In(task.location).Add(interval))
+		}
+		s.runIntervalTask(task, ticker, stopChan, interval)
+	}(task.ticker, task.stopChan)
 	return nil
 }
 
 // runIntervalTask handles the interval execution loop
-func (s *Scheduler) runIntervalTask(task *Task, interval time.Duration) {
+func (s *Scheduler) runIntervalTask(task *Task, ticker *time.Ticker, stopChan chan struct{}, interval time.Duration) {
 	for {
 		select {
-		case <-task.ticker.C:
+		case <-ticker.C:
 			if s.shouldExecuteTask(task) {
 				s.executeTask(task)
-				task.NextRun = timePtr(time.Now().Add(interval))
+				s.setNextRun(task, time.Now().In(task.location).Add(interval))
 			}
-		case <-task.stopChan:
+		case <-stopChan:
 			return
 		case <-s.ctx.Done():
 			return
@@ -409,9 +532,9 @@ func (s *Scheduler) runIntervalTask(task *Task, interval time.Duration) {
 	}
 }
 
-// scheduleCronTask sets up cron-based task execution
+// scheduleCronTask sets up cron-based task execution in the task's timezone
 func (s *Scheduler) scheduleCronTask(task *Task) error {
-	entryID, err := s.cron.AddFunc(task.Schedule.CronExpr, func() {
+	entryID, err := s.cron.AddFunc(cronSpecInLocation(task.Schedule.CronExpr, task.location), func() {
 		if s.shouldExecuteTask(task) {
 			s.executeTask(task)
 		}
@@ -430,6 +553,51 @@ func (s *Scheduler) scheduleCronTask(task *Task) error {
 	return nil
 }
 
+// scheduleOnceTask sets up a single execution at RunAt. A RunAt already in
+// the past (e.g. after a restart) leaves the task disabled.
+func (s *Scheduler) scheduleOnceTask(task *Task) error {
+	runAt := task.Schedule.RunAt
+	if runAt == nil {
+		return fmt.Errorf("runAt is required for once schedule")
+	}
+
+	delay := time.Until(*runAt)
+	if delay < 0 {
+		logging.ForPair(task.ID).Info().
+			Str("task", task.ID).
+			Time("run_at", *runAt).
+			Msg("one-time run is in the past, not scheduled")
+		task.Enabled = false
+		return nil
+	}
+
+	task.timer = time.NewTimer(delay)
+	task.NextRun = timePtr(runAt.In(task.location))
+
+	go s.runOnceTask(task, task.timer, task.stopChan)
+	return nil
+}
+
+// runOnceTask waits for the one-shot timer, runs the task and disables it
+func (s *Scheduler) runOnceTask(task *Task, timer *time.Timer, stopChan chan struct{}) {
+	select {
+	case <-timer.C:
+		if s.shouldExecuteTask(task) {
+			s.executeTask(task)
+		}
+
+		s.mutex.Lock()
+		task.Enabled = false
+		task.NextRun = nil
+		task.timer = nil
+		s.mutex.Unlock()
+
+		logging.ForPair(task.ID).Info().Str("task", task.ID).Msg("one-time task completed and disabled")
+	case <-stopChan:
+	case <-s.ctx.Done():
+	}
+}
+
 // scheduleCustomTask sets up custom schedule-based task execution
 func (s *Scheduler) scheduleCustomTask(task *Task) error {
 	custom := task.Schedule.Custom
@@ -450,7 +618,7 @@ func (s *Scheduler) scheduleCustomTask(task *Task) error {
 	task.ticker = time.NewTicker(checkInterval)
 	task.NextRun = timePtr(s.calculateNextCustomExecution(task))
 
-	go s.runCustomTask(task, startTime, endTime, interval)
+	go s.runCustomTask(task, task.ticker, task.stopChan, startTime, endTime, interval)
 	return nil
 }
 
@@ -468,6 +636,16 @@ func (s *Scheduler) validateCustomSchedule(custom *CustomSchedule) error {
 		return fmt.Errorf("invalid end time %s: %w", custom.EndTime, err)
 	}
 
+	// OnlyWorkDays must leave at least one of the listed weekdays
+	if custom.OnlyWorkDays && len(custom.WeekDays) > 0 {
+		for _, day := range custom.WeekDays {
+			if isWorkDay(time.Weekday(day)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("onlyWorkDays excludes every configured weekday")
+	}
+
 	return nil
 }
 
@@ -481,18 +659,20 @@ func (s *Scheduler) calculateCheckInterval(interval time.Duration) time.Duration
 }
 
 // runCustomTask handles the custom schedule execution loop
-func (s *Scheduler) runCustomTask(task *Task, startTime, endTime time.Time, interval time.Duration) {
+func (s *Scheduler) runCustomTask(task *Task, ticker *time.Ticker, stopChan chan struct{}, startTime, endTime time.Time, interval time.Duration) {
 	var lastExecution time.Time
 
 	for {
 		select {
-		case now := <-task.ticker.C:
+		case now := <-ticker.C:
+			// Weekday and time window are evaluated in the task's timezone
+			now = now.In(task.location)
 			if s.shouldExecuteCustomTask(task, now, startTime, endTime, interval, &lastExecution) {
 				s.executeTask(task)
 				lastExecution = now
-				task.NextRun = timePtr(s.calculateNextCustomExecution(task))
+				s.setNextRun(task, s.calculateNextCustomExecution(task))
 			}
-		case <-task.stopChan:
+		case <-stopChan:
 			return
 		case <-s.ctx.Done():
 			return
@@ -507,8 +687,8 @@ func (s *Scheduler) shouldExecuteCustomTask(task *Task, now time.Time, startTime
 		return false
 	}
 
-	// Check weekday constraints
-	if !s.isValidWeekDay(task.Schedule.Custom.WeekDays, now.Weekday()) {
+	// Check weekday, work day and holiday constraints
+	if !customDayAllowed(task.Schedule.Custom, s.holidayProvider(), now) {
 		return false
 	}
 
@@ -539,19 +719,23 @@ func (s *Scheduler) isValidWeekDay(allowedDays []WeekDay, currentDay time.Weekda
 	return false
 }
 
-// isWithinTimeWindow checks if current time is within the allowed execution window
+// isWithinTimeWindow checks if current time is within the allowed execution window.
+// A window whose end is before its start (e.g. 22:00-06:00) spans midnight.
 func (s *Scheduler) isWithinTimeWindow(now time.Time, startTime, endTime time.Time) bool {
 	currentTime := time.Date(0, 1, 1, now.Hour(), now.Minute(), now.Second(), 0, time.UTC)
 	start := time.Date(0, 1, 1, startTime.Hour(), startTime.Minute(), 0, 0, time.UTC)
 	end := time.Date(0, 1, 1, endTime.Hour(), endTime.Minute(), 0, 0, time.UTC)
 
+	if end.Before(start) {
+		return !currentTime.Before(start) || !currentTime.After(end)
+	}
 	return !currentTime.Before(start) && !currentTime.After(end)
 }
 
 // calculateNextCustomExecution calculates the next execution time for custom schedules
 func (s *Scheduler) calculateNextCustomExecution(task *Task) time.Time {
 	custom := task.Schedule.Custom
-	now := time.Now()
+	now := time.Now().In(task.location)
 
 	// Simple logic - next interval (could be enhanced with more sophisticated calculation)
 	interval, _ := time.ParseDuration(custom.Interval)
@@ -572,6 +756,12 @@ func (s *Scheduler) unscheduleTask(task *Task) {
 		task.ticker = nil
 	}
 
+	// Stop one-shot timer
+	if task.timer != nil {
+		task.timer.Stop()
+		task.timer = nil
+	}
+
 	// Close stop channel and recreate for potential reuse
 	if task.stopChan != nil {
 		select {
@@ -588,8 +778,18 @@ func (s *Scheduler) unscheduleTask(task *Task) {
 
 // ===== TASK EXECUTION =====
 
+// setNextRun records when a running schedule triggers the task next
+func (s *Scheduler) setNextRun(task *Task, next time.Time) {
+	s.mutex.Lock()
+	task.NextRun = &next
+	s.mutex.Unlock()
+}
+
 // shouldExecuteTask checks general conditions for task execution
 func (s *Scheduler) shouldExecuteTask(task *Task) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
 	if !task.Enabled {
 		return false
 	}
@@ -606,50 +806,182 @@ func (s *Scheduler) shouldExecuteTask(task *Task) bool {
 	}
 
 	// Check maximum execution limit
-	if task.Schedule.MaxRuns > 0 && task.RunCount >= task.Schedule.MaxRuns {
+	if remaining, limited := task.RunsRemaining(); limited && remaining == 0 {
 		return false
 	}
 
 	return true
 }
 
-// executeTask runs a task with error handling and statistics tracking
+// executeTask runs a task with error handling and statistics tracking.
+// A trigger arriving while the task is still running is skipped and counted,
+// so slow syncs never overlap with themselves.
 func (s *Scheduler) executeTask(task *Task) {
+	s.mutex.RLock()
+	inFlight := s.inFlight[task.ID]
+	s.mutex.RUnlock()
+	if inFlight == nil {
+		return // Removed before the trigger fired
+	}
+
+	if !inFlight.CompareAndSwap(false, true) {
+		s.mutex.Lock()
+		task.SkippedRuns++
+		task.LastSkipReason = "previous run still in progress"
+		skippedRuns := task.SkippedRuns
+		s.mutex.Unlock()
+
+		metrics.TaskRuns.WithLabelValues(task.ID, metrics.ResultSkipped).Inc()
+		logging.ForPair(task.ID).Warn().
+			Str("task", task.ID).
+			Int("skipped_runs", skippedRuns).
+			Msg("task run skipped: previous run still in progress")
+		return
+	}
+	defer inFlight.Store(false)
+
 	defer func() {
 		if r := recover(); r != nil {
-			log.Error().
+			logging.ForPair(task.ID).Error().
 				Str("task", task.ID).
 				Interface("panic", r).
 				Msg("task panicked")
 
+			s.mutex.Lock()
 			task.FailCount++
 			task.LastError = fmt.Sprintf("panic: %v", r)
+			s.mutex.Unlock()
+			metrics.TaskRuns.WithLabelValues(task.ID, metrics.ResultFailure).Inc()
 		}
 	}()
 
-	log.Info().Str("task", task.ID).Msg("executing task")
+	logging.ForPair(task.ID).Info().Str("task", task.ID).Msg("executing task")
 
 	startTime := time.Now()
+	s.mutex.Lock()
+	previousRun := task.LastRun
 	task.LastRun = &startTime
+	s.mutex.Unlock()
 
-	if err := task.fn(s.ctx); err != nil {
-		log.Error().
+	err := task.fn(s.ctx)
+	if errors.Is(err, ErrRunSkipped) {
+		logging.ForPair(task.ID).Warn().
 			Str("task", task.ID).
 			Err(err).
-			Msg("task failed")
+			Msg("task run skipped")
+
+		s.mutex.Lock()
+		task.LastRun = previousRun
+		task.SkippedRuns++
+		task.LastSkipReason = err.Error()
+		s.mutex.Unlock()
+		metrics.TaskRuns.WithLabelValues(task.ID, metrics.ResultSkipped).Inc()
+		return
+	}
 
-		task.FailCount++
-		task.LastError = err.Error()
+	if err != nil {
+		logging.ForPair(task.ID).Error().
+			Str("task", task.ID).
+			Err(err).
+			Msg("task failed")
+		metrics.TaskRuns.WithLabelValues(task.ID, metrics.ResultFailure).Inc()
 	} else {
-		log.Info().
+		logging.ForPair(task.ID).Info().
 			Str("task", task.ID).
 			Dur("duration", time.Since(startTime)).
 			Msg("task completed")
+		metrics.TaskRuns.WithLabelValues(task.ID, metrics.ResultSuccess).Inc()
+	}
 
+	s.mutex.Lock()
+	if err != nil {
+		task.FailCount++
+		task.LastError = err.Error()
+	} else {
 		task.LastError = ""
 	}
-
 	task.RunCount++
+	limitReached := false
+	if task.Schedule.MaxRuns > 0 {
+		task.LimitRuns++
+		limitReached = task.LimitRuns == task.Schedule.MaxRuns
+	}
+	s.mutex.Unlock()
+
+	if limitReached {
+		s.runLimitReached(task)
+	}
+}
+
+// runLimitReached reports a task that used up its MaxRuns; it stays registered
+// but is no longer executed until its schedule changes.
+func (s *Scheduler) runLimitReached(task *Task) {
+	s.mutex.RLock()
+	handler := s.onRunLimit
+	taskCopy := s.copyTaskForAPI(task)
+	s.mutex.RUnlock()
+
+	logging.ForPair(task.ID).Info().
+		Str("task", task.ID).
+		Int("max_runs", taskCopy.Schedule.MaxRuns).
+		Msg("task reached its maximum number of runs")
+
+	if handler != nil {
+		handler(taskCopy)
+	}
+}
+
+// ===== SCHEDULE VALIDATION =====
+
+// ValidateSchedule checks that a schedule can be registered: known type,
+// loadable timezone, parseable interval or cron expression, and a complete
+// custom configuration.
+func ValidateSchedule(schedule Schedule) error {
+	if _, err := LoadLocation(schedule.Timezone, time.Local); err != nil {
+		return err
+	}
+
+	switch schedule.Type {
+	case ScheduleTypeDisabled, ScheduleTypeWatcher:
+		return nil
+
+	case ScheduleTypeInterval:
+		if _, err := time.ParseDuration(schedule.Interval); err != nil {
+			return fmt.Errorf("invalid interval %s: %w", schedule.Interval, err)
+		}
+		return nil
+
+	case ScheduleTypePoll:
+		interval, err := time.ParseDuration(schedule.Interval)
+		if err != nil {
+			return fmt.Errorf("invalid poll interval %s: %w", schedule.Interval, err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("poll interval must be positive")
+		}
+		return nil
+
+	case ScheduleTypeCron:
+		if _, err := cronParser.Parse(schedule.CronExpr); err != nil {
+			return fmt.Errorf("invalid cron expression %s: %w", schedule.CronExpr, err)
+		}
+		return nil
+
+	case ScheduleTypeCustom:
+		if schedule.Custom == nil {
+			return fmt.Errorf("custom schedule configuration is missing")
+		}
+		return (&Scheduler{}).validateCustomSchedule(schedule.Custom)
+
+	case ScheduleTypeOnce:
+		if schedule.RunAt == nil {
+			return fmt.Errorf("runAt is required for once schedule")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported schedule type: %s", schedule.Type)
+	}
 }
 
 // ===== UTILITY FUNCTIONS =====
@@ -659,6 +991,29 @@ func timePtr(t time.Time) *time.Time {
 	return &t
 }
 
+// LoadLocation resolves a schedule timezone name, returning fallback when empty
+func LoadLocation(timezone string, fallback *time.Location) (*time.Location, error) {
+	if timezone == "" {
+		return fallback, nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s: %w", timezone, err)
+	}
+	return location, nil
+}
+
+// cronSpecInLocation prefixes a cron expression with the location so the cron
+// library evaluates it in that timezone. Explicit TZ prefixes are kept.
+func cronSpecInLocation(expr string, location *time.Location) string {
+	trimmed := strings.TrimSpace(expr)
+	if location == nil || strings.HasPrefix(trimmed, "TZ=") || strings.HasPrefix(trimmed, "CRON_TZ=") {
+		return expr
+	}
+	return "CRON_TZ=" + location.String() + " " + trimmed
+}
+
 // ===== CRON LOGGER IMPLEMENTATION =====
 
 // cronLogger implements the cron library's logging interface
@@ -687,6 +1042,14 @@ func NewIntervalSchedule(interval string) Schedule {
 	}
 }
 
+// NewPollSchedule creates a polling watch schedule comparing every interval
+func NewPollSchedule(interval string) Schedule {
+	return Schedule{
+		Type:     ScheduleTypePoll,
+		Interval: interval,
+	}
+}
+
 // NewCronSchedule creates a cron expression-based schedule
 func NewCronSchedule(cronExpr string) Schedule {
 	return Schedule{
@@ -695,6 +1058,14 @@ func NewCronSchedule(cronExpr string) Schedule {
 	}
 }
 
+// NewOnceSchedule creates a schedule that runs a single time at runAt
+func NewOnceSchedule(runAt time.Time) Schedule {
+	return Schedule{
+		Type:  ScheduleTypeOnce,
+		RunAt: &runAt,
+	}
+}
+
 // NewWorkdaysSchedule creates a workday schedule (Monday-Friday)
 // Example: Mon-Fri from 8:00 to 20:00 every 1.5 hours
 func NewWorkdaysSchedule(startTime, endTime, interval string) Schedule {
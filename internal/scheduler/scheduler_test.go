@@ -0,0 +1,163 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clock returns a time of day on an arbitrary date
+func clock(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("15:04:05", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Date(2024, 3, 6, parsed.Hour(), parsed.Minute(), parsed.Second(), 0, time.UTC)
+}
+
+func TestIsWithinTimeWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end string
+		now        string
+		want       bool
+	}{
+		// Windows within one day
+		{"inside day window", "08:00", "20:00", "12:30:00", true},
+		{"before day window", "08:00", "20:00", "07:59:59", false},
+		{"after day window", "08:00", "20:00", "20:00:01", false},
+		{"day window start minute", "08:00", "20:00", "08:00:00", true},
+		{"day window end minute", "08:00", "20:00", "20:00:00", true},
+
+		// Windows crossing midnight
+		{"late evening in overnight window", "22:00", "06:00", "23:15:00", true},
+		{"early morning in overnight window", "22:00", "06:00", "03:00:00", true},
+		{"midnight in overnight window", "22:00", "06:00", "00:00:00", true},
+		{"midday outside overnight window", "22:00", "06:00", "12:00:00", false},
+		{"overnight window start minute", "22:00", "06:00", "22:00:00", true},
+		{"just before overnight window", "22:00", "06:00", "21:59:59", false},
+		{"overnight window end minute", "22:00", "06:00", "06:00:00", true},
+		{"just after overnight window", "22:00", "06:00", "06:00:01", false},
+
+		// Degenerate windows
+		{"single minute window", "12:00", "12:00", "12:00:00", true},
+		{"outside single minute window", "12:00", "12:00", "12:01:00", false},
+	}
+
+	s := &Scheduler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := clock(t, tt.start+":00"), clock(t, tt.end+":00")
+			if got := s.isWithinTimeWindow(clock(t, tt.now), start, end); got != tt.want {
+				t.Errorf("isWithinTimeWindow(%s, %s-%s) = %v, want %v", tt.now, tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+// ===== MAX RUNS =====
+
+func TestMaxRunsIntervalTaskRunsExactlyThreeTimes(t *testing.T) {
+	s := newTestScheduler(t)
+	var limitReached atomic.Int32
+	exhausted := make(chan *Task, 1)
+	s.SetRunLimitHandler(func(task *Task) {
+		if task.ID == "docs" && limitReached.Add(1) == 1 {
+			exhausted <- task
+		}
+	})
+
+	var runs atomic.Int32
+	schedule := NewIntervalSchedule("10ms")
+	schedule.MaxRuns = 3
+	if err := s.AddTask("docs", "Docs", schedule, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runs.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Give the ticker time to fire well past the limit
+	time.Sleep(100 * time.Millisecond)
+
+	if got := runs.Load(); got != 3 {
+		t.Fatalf("task ran %d times, want 3", got)
+	}
+	if got := limitReached.Load(); got != 1 {
+		t.Errorf("run limit handler called %d times, want 1", got)
+	}
+	// The handler receives a copy taken by the runner, safe to read here
+	task := <-exhausted
+	if remaining, limited := task.RunsRemaining(); !limited || remaining != 0 {
+		t.Errorf("RunsRemaining() = %d, %v, want 0, true", remaining, limited)
+	}
+}
+
+func TestRunsRemainingAfterScheduleUpdate(t *testing.T) {
+	tests := []struct {
+		name          string
+		update        func(*Schedule)
+		wantRemaining int
+		wantLimited   bool
+	}{
+		{"unchanged schedule keeps the countdown", func(*Schedule) {}, 1, true},
+		{"new interval resets the countdown", func(s *Schedule) { s.Interval = "2h" }, 3, true},
+		{"raised limit resets the countdown", func(s *Schedule) { s.MaxRuns = 5 }, 5, true},
+		{"removed limit", func(s *Schedule) { s.MaxRuns = 0 }, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t)
+			schedule := NewIntervalSchedule("1h")
+			schedule.MaxRuns = 3
+			if err := s.AddTask("docs", "Docs", schedule, func(ctx context.Context) error { return nil }); err != nil {
+				t.Fatal(err)
+			}
+			for range 2 {
+				s.executeTask(s.tasks["docs"])
+			}
+
+			tt.update(&schedule)
+			if err := s.UpdateTask("docs", schedule); err != nil {
+				t.Fatal(err)
+			}
+			task, err := s.GetTask("docs")
+			if err != nil {
+				t.Fatal(err)
+			}
+			remaining, limited := task.RunsRemaining()
+			if remaining != tt.wantRemaining || limited != tt.wantLimited {
+				t.Errorf("RunsRemaining() = %d, %v, want %d, %v", remaining, limited, tt.wantRemaining, tt.wantLimited)
+			}
+			if task.RunCount != 2 {
+				t.Errorf("RunCount = %d, want the lifetime count 2", task.RunCount)
+			}
+		})
+	}
+}
+
+func TestPastOnceScheduleDisablesTask(t *testing.T) {
+	s := newTestScheduler(t)
+	schedule := NewOnceSchedule(time.Now().Add(-time.Hour))
+	if err := s.AddTask("docs", "Docs", schedule, func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := s.GetTask("docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Enabled {
+		t.Error("task with a past runAt is still enabled")
+	}
+	if task.NextRun != nil {
+		t.Errorf("NextRun = %v, want none", task.NextRun)
+	}
+}
@@ -0,0 +1,133 @@
+// Package scheduler provides run statistics persistence for the FolderSynchronizer application.
+// Task statistics are saved to a JSON file and restored into tasks re-added after a restart.
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ===== STATISTICS STRUCTURES =====
+
+// TaskStats contains the persisted execution statistics of a task.
+// Runtime fields (tickers, channels, cron entries) are never persisted.
+type TaskStats struct {
+	LastRun     *time.Time `json:"lastRun,omitempty"`     // Last execution timestamp
+	NextRun     *time.Time `json:"nextRun,omitempty"`     // Next scheduled execution at save time
+	RunCount    int        `json:"runCount"`              // Total successful executions
+	FailCount   int        `json:"failCount"`             // Total failed executions
+	LastError   string     `json:"lastError,omitempty"`   // Last error message
+	SkippedRuns int        `json:"skippedRuns,omitempty"` // Triggers skipped due to an in-flight run
+	LimitRuns   int        `json:"limitRuns,omitempty"`   // Executions counted against MaxRuns
+}
+
+// statsFile is the on-disk format of persisted statistics.
+type statsFile struct {
+	SavedAt time.Time            `json:"savedAt"` // When the statistics were written
+	Tasks   map[string]TaskStats `json:"tasks"`   // Statistics by task ID
+}
+
+// ===== STATISTICS EXPORT AND RESTORE =====
+
+// ExportStats returns the current statistics of all tasks by task ID.
+func (s *Scheduler) ExportStats() map[string]TaskStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	stats := make(map[string]TaskStats, len(s.tasks))
+	for id, task := range s.tasks {
+		stats[id] = TaskStats{
+			LastRun:     task.LastRun,
+			NextRun:     task.NextRun,
+			RunCount:    task.RunCount,
+			FailCount:   task.FailCount,
+			LastError:   task.LastError,
+			SkippedRuns: task.SkippedRuns,
+			LimitRuns:   task.LimitRuns,
+		}
+	}
+	return stats
+}
+
+// RestoreStats registers previously saved statistics. Each entry is applied
+// once, to the task with the same ID when it is added.
+func (s *Scheduler) RestoreStats(stats map[string]TaskStats) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.restored == nil {
+		s.restored = make(map[string]TaskStats, len(stats))
+	}
+	for id, taskStats := range stats {
+		s.restored[id] = taskStats
+	}
+}
+
+// applyRestoredStats copies saved statistics into a newly added task.
+// A saved NextRun is only used when scheduling computed none and it is still ahead.
+// Must be called with the scheduler mutex held.
+func (s *Scheduler) applyRestoredStats(task *Task) {
+	stats, exists := s.restored[task.ID]
+	if !exists {
+		return
+	}
+	delete(s.restored, task.ID)
+
+	task.LastRun = stats.LastRun
+	task.RunCount = stats.RunCount
+	task.FailCount = stats.FailCount
+	task.LastError = stats.LastError
+	task.SkippedRuns = stats.SkippedRuns
+	task.LimitRuns = stats.LimitRuns
+
+	if task.NextRun == nil && stats.NextRun != nil && stats.NextRun.After(time.Now()) {
+		task.NextRun = stats.NextRun
+	}
+}
+
+// ===== STATISTICS FILE OPERATIONS =====
+
+// SaveStats writes task statistics to a file atomically.
+func SaveStats(path string, stats map[string]TaskStats) error {
+	tempPath := path + ".tmp"
+
+	data, err := json.MarshalIndent(statsFile{SavedAt: time.Now(), Tasks: stats}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheduler stats: %w", err)
+	}
+
+	if err := os.WriteFile(tempPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write temp stats file: %w", err)
+	}
+
+	if err := os.Rename(tempPath, path); err != nil {
+		// Clean up temp file on failure
+		_ = os.Remove(tempPath)
+		return fmt.Errorf("failed to rename stats file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadStats reads task statistics from a file. A missing file yields no statistics.
+func LoadStats(path string) (map[string]TaskStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]TaskStats{}, nil
+		}
+		return nil, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	var file statsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse stats file: %w", err)
+	}
+	if file.Tasks == nil {
+		file.Tasks = map[string]TaskStats{}
+	}
+
+	return file.Tasks, nil
+}
@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestScheduler returns a running scheduler stopped when the test ends
+func newTestScheduler(t *testing.T) *Scheduler {
+	t.Helper()
+	s, err := NewScheduler("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func TestStatsFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	lastRun := time.Now().Add(-time.Hour).Truncate(time.Second)
+	stats := map[string]TaskStats{
+		"docs":   {LastRun: &lastRun, RunCount: 7, FailCount: 2, LastError: "disk full", SkippedRuns: 1, LimitRuns: 3},
+		"photos": {},
+	}
+
+	if err := SaveStats(path, stats); err != nil {
+		t.Fatalf("SaveStats: %v", err)
+	}
+	loaded, err := LoadStats(path)
+	if err != nil {
+		t.Fatalf("LoadStats: %v", err)
+	}
+	if len(loaded) != len(stats) {
+		t.Fatalf("loaded %d tasks, want %d", len(loaded), len(stats))
+	}
+	got := loaded["docs"]
+	if got.LastRun == nil || !got.LastRun.Equal(lastRun) || got.RunCount != 7 || got.FailCount != 2 ||
+		got.LastError != "disk full" || got.SkippedRuns != 1 || got.LimitRuns != 3 {
+		t.Errorf("loaded %+v, want %+v", got, stats["docs"])
+	}
+
+	missing, err := LoadStats(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("LoadStats of a missing file = %v, %v, want no stats", missing, err)
+	}
+}
+
+func TestStatsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	fail := errors.New("target unwritable")
+	results := []error{nil, fail, nil}
+
+	// First run: execute the task a few times and save on shutdown
+	before := newTestScheduler(t)
+	if err := before.AddTask("docs", "Docs", NewCronSchedule("0 0 0 * * *"), func(ctx context.Context) error {
+		result := results[0]
+		results = results[1:]
+		return result
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		before.executeTask(before.tasks["docs"])
+	}
+	if err := SaveStats(path, before.ExportStats()); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := before.GetTask("docs")
+
+	// Restart: statistics are restored into the re-added task only
+	after := newTestScheduler(t)
+	stats, err := LoadStats(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after.RestoreStats(stats)
+	noop := func(ctx context.Context) error { return nil }
+	if err := after.AddTask("docs", "Docs", NewCronSchedule("0 0 0 * * *"), noop); err != nil {
+		t.Fatal(err)
+	}
+	if err := after.AddTask("other", "Other", NewCronSchedule("0 0 0 * * *"), noop); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, _ := after.GetTask("docs")
+	if restored.RunCount != 3 || restored.FailCount != 1 || restored.LastError != "" {
+		t.Errorf("restored runs %d, failures %d, error %q, want 3, 1 and none", restored.RunCount, restored.FailCount, restored.LastError)
+	}
+	if restored.LastRun == nil || !restored.LastRun.Equal(*saved.LastRun) {
+		t.Errorf("restored LastRun = %v, want %v", restored.LastRun, saved.LastRun)
+	}
+	if restored.NextRun == nil || !restored.NextRun.After(time.Now()) {
+		t.Errorf("restored NextRun = %v, want a fresh future run", restored.NextRun)
+	}
+	if other, _ := after.GetTask("other"); other.RunCount != 0 || other.LastRun != nil {
+		t.Errorf("unrelated task got statistics: %+v", other)
+	}
+
+	// Statistics are applied once; a re-added task starts fresh
+	if err := after.RemoveTask("docs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := after.AddTask("docs", "Docs", NewCronSchedule("0 0 0 * * *"), noop); err != nil {
+		t.Fatal(err)
+	}
+	if readded, _ := after.GetTask("docs"); readded.RunCount != 0 {
+		t.Errorf("statistics applied twice: RunCount = %d", readded.RunCount)
+	}
+}
+
+func TestRestoredNextRunOnlyWhenAhead(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	tests := []struct {
+		name    string
+		nextRun *time.Time
+		want    *time.Time
+	}{
+		{"future next run kept", &future, &future},
+		{"past next run dropped", &past, nil},
+		{"no next run", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScheduler(t)
+			s.RestoreStats(map[string]TaskStats{"task": {NextRun: tt.nextRun}})
+			task := &Task{ID: "task"}
+			s.mutex.Lock()
+			s.applyRestoredStats(task)
+			s.mutex.Unlock()
+
+			if (task.NextRun == nil) != (tt.want == nil) || (tt.want != nil && !task.NextRun.Equal(*tt.want)) {
+				t.Errorf("NextRun = %v, want %v", task.NextRun, tt.want)
+			}
+		})
+	}
+}
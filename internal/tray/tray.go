@@ -0,0 +1,47 @@
+// Package tray provides system tray integration for the FolderSynchronizer application.
+// This file contains the platform-independent types shared by all tray implementations.
+package tray
+
+import "time"
+
+// ===== TYPE DEFINITIONS =====
+
+// Callbacks defines the interface between the tray and the main application.
+// These callback functions allow the tray to interact with core application functionality.
+type Callbacks struct {
+	// OnOpenUI is called when the user clicks "Open UI" in the tray menu
+	OnOpenUI func()
+
+	// OnSyncAll is called when the user clicks "Sync all now" in the tray menu
+	OnSyncAll func()
+
+	// OnQuit is called when the user clicks "Quit" in the tray menu
+	OnQuit func()
+
+	// ListPairs returns current sync pairs for displaying in the tray menu
+	ListPairs func() []PairSummary
+
+	// TogglePair enables or disables a specific sync pair
+	TogglePair func(id string, enabled bool)
+}
+
+// PairSummary contains minimal information about a sync pair for tray display.
+type PairSummary struct {
+	ID      string // Unique identifier for the sync pair
+	Enabled bool   // Whether the pair is currently active
+	Paused  bool   // Whether the enabled pair is temporarily paused
+
+	// Run status of a started pair (zero values when the pair is not running)
+	LastRun   *time.Time // Last execution timestamp
+	RunCount  int        // Total successful executions
+	FailCount int        // Total failed executions
+	LastError string     // Last error message, empty when the last run succeeded
+}
+
+// ===== PLATFORM SUPPORT =====
+
+// GetSupportedPlatforms returns the list of platforms where tray functionality is available.
+// Linux requires a build with the appindicator tag.
+func GetSupportedPlatforms() []string {
+	return []string{"windows", "darwin", "linux"}
+}
@@ -0,0 +1,57 @@
+//go:build darwin && cgo
+
+// Package tray provides system tray integration for the FolderSynchronizer application on macOS.
+// It places a status item in the menu bar with the same menu as the Windows tray.
+package tray
+
+import (
+	"github.com/getlantern/systray"
+	"github.com/rs/zerolog/log"
+)
+
+// ===== BUILD CONFIGURATION =====
+
+// WindowsBuild indicates whether the Windows tray implementation is compiled in.
+// This is always false for macOS builds.
+var WindowsBuild = false
+
+// ===== MAIN ENTRY POINT =====
+
+// Run initializes and starts the macOS menu bar tray functionality.
+// This function blocks until the tray is closed and must be called from the main goroutine.
+//
+// Parameters:
+//   - icon: Icon data in PNG or ICO format for the menu bar icon
+//   - callbacks: Application callback functions for tray interactions
+func Run(icon []byte, callbacks Callbacks) {
+	log.Info().Msg("starting macOS menu bar tray")
+
+	runSystray(icon, callbacks)
+}
+
+// ===== TRAY SETUP FUNCTIONS =====
+
+// setupTrayProperties configures the basic tray icon properties.
+// The title is left empty so only the icon is shown in the menu bar.
+func setupTrayProperties(icon []byte) {
+	systray.SetTooltip(AppTooltip)
+
+	// The menu bar renders both PNG and ICO images
+	if isValidPNGIcon(icon) || isValidICOIcon(icon) {
+		systray.SetIcon(icon)
+		log.Debug().Msg("tray icon set successfully")
+	} else if len(icon) > 0 {
+		log.Warn().Msg("provided icon is not in PNG or ICO format, showing title instead")
+		systray.SetTitle(AppTitle)
+	} else {
+		log.Debug().Msg("no icon provided, showing title instead")
+		systray.SetTitle(AppTitle)
+	}
+}
+
+// ===== UTILITY FUNCTIONS =====
+
+// IsSupported returns whether system tray functionality is supported on macOS
+func IsSupported() bool {
+	return true // Always supported on macOS cgo builds
+}
@@ -0,0 +1,112 @@
+//go:build linux && cgo && appindicator
+
+// Package tray provides system tray integration for the FolderSynchronizer application on Linux.
+// It uses AppIndicator (StatusNotifierItem) through systray, so building requires the
+// appindicator build tag and the GTK 3 and Ayatana AppIndicator development packages.
+package tray
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/getlantern/systray"
+	"github.com/rs/zerolog/log"
+)
+
+// ===== BUILD CONFIGURATION =====
+
+// WindowsBuild indicates whether the Windows tray implementation is compiled in.
+// This is always false for Linux builds.
+var WindowsBuild = false
+
+// ===== HOST DETECTION CONSTANTS =====
+
+const (
+	// StatusNotifierWatcher is the D-Bus name owned by tray hosts implementing StatusNotifierItem
+	StatusNotifierWatcher = "org.kde.StatusNotifierWatcher"
+
+	// HostProbeTimeout bounds the D-Bus query for a tray host
+	HostProbeTimeout = 2 * time.Second
+)
+
+// ===== MAIN ENTRY POINT =====
+
+// Run initializes and starts the Linux system tray functionality.
+// This function blocks until the tray is closed. When no graphical session
+// or tray host is available it logs the reason and returns immediately.
+//
+// Parameters:
+//   - icon: Icon data in PNG or ICO format for the tray icon
+//   - callbacks: Application callback functions for tray interactions
+func Run(icon []byte, callbacks Callbacks) {
+	if reason := trayHostUnavailable(); reason != "" {
+		log.Warn().Str("reason", reason).Msg("system tray not available, continuing without tray")
+		return
+	}
+
+	log.Info().Msg("starting Linux system tray")
+
+	runSystray(icon, callbacks)
+}
+
+// ===== TRAY SETUP FUNCTIONS =====
+
+// setupTrayProperties configures the basic tray icon properties
+func setupTrayProperties(icon []byte) {
+	systray.SetTitle(AppTitle)
+	systray.SetTooltip(AppTooltip)
+
+	// AppIndicator loads the icon from a file and accepts both PNG and ICO
+	if isValidPNGIcon(icon) || isValidICOIcon(icon) {
+		systray.SetIcon(icon)
+		log.Debug().Msg("tray icon set successfully")
+	} else if len(icon) > 0 {
+		log.Warn().Msg("provided icon is not in PNG or ICO format, using default icon")
+	} else {
+		log.Debug().Msg("no icon provided, using default system icon")
+	}
+}
+
+// ===== HOST DETECTION =====
+
+// trayHostUnavailable returns why a tray cannot be shown, or "" if it can.
+// GTK aborts the process without a display, so this is checked before systray starts.
+func trayHostUnavailable() string {
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return "no graphical display"
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return "no D-Bus session bus"
+	}
+
+	// Ask the session bus for a StatusNotifier host; skipped when dbus-send is missing
+	dbusSend, err := exec.LookPath("dbus-send")
+	if err != nil {
+		return ""
+	}
+
+	cmd := exec.Command(dbusSend, "--session", "--print-reply",
+		fmt.Sprintf("--reply-timeout=%d", HostProbeTimeout.Milliseconds()),
+		"--dest=org.freedesktop.DBus", "/org/freedesktop/DBus",
+		"org.freedesktop.DBus.NameHasOwner", "string:"+StatusNotifierWatcher)
+	output, err := cmd.Output()
+	if err != nil {
+		return "D-Bus session bus not reachable"
+	}
+	if strings.Contains(string(output), "boolean false") {
+		return "no StatusNotifier tray host running"
+	}
+
+	return ""
+}
+
+// ===== UTILITY FUNCTIONS =====
+
+// IsSupported returns whether system tray functionality is available in the
+// current Linux session (graphical display, D-Bus and a tray host)
+func IsSupported() bool {
+	return trayHostUnavailable() == ""
+}
@@ -0,0 +1,366 @@
+//go:build windows || (darwin && cgo) || (linux && cgo && appindicator)
+
+// Package tray provides the systray-based tray implementation shared by Windows, macOS and Linux.
+// It builds the menu (Open UI, Sync All, Pairs submenu, Quit) and keeps the pairs in sync.
+package tray
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/getlantern/systray"
+	"github.com/rs/zerolog/log"
+)
+
+// ===== CONSTANTS AND CONFIGURATION =====
+
+const (
+	// Application branding
+	AppTitle   = "FolderSynchronizer"
+	AppTooltip = "FolderSynchronizer - File Synchronization Tool"
+
+	// Menu item labels
+	MenuOpenUI  = "Open UI"
+	MenuSyncAll = "Sync all now"
+	MenuPairs   = "Pairs"
+	MenuQuit    = "Quit"
+
+	// Menu item descriptions
+	DescOpenUI  = "Open the web UI in your browser"
+	DescSyncAll = "Run synchronization for all enabled pairs"
+	DescPairs   = "Enable/disable sync pairs"
+	DescQuit    = "Quit the application"
+
+	// ICO file header signature for icon validation
+	ICOHeaderByte0 = 0x00
+	ICOHeaderByte1 = 0x00
+	ICOHeaderByte2 = 0x01
+	ICOHeaderByte3 = 0x00
+
+	// Pair item status display
+	FailingPairMarker     = "⚠ " // Label prefix for pairs whose last run failed
+	PausedPairMarker      = "⏸ " // Label prefix for paused pairs
+	MaxTooltipErrorLength = 120  // Longer error messages are truncated in tooltips
+	PairTimeLayout        = "2006-01-02 15:04:05"
+
+	// PNG file signature for icon validation
+	PNGSignature = "\x89PNG\r\n\x1a\n"
+
+	// Update intervals
+	PairRefreshInterval = 8 * time.Second
+)
+
+// ===== TYPE DEFINITIONS =====
+
+// ItemHandle provides a type alias for systray menu items for better API clarity
+type ItemHandle = *systray.MenuItem
+
+// ===== TRAY MANAGEMENT STRUCTURE =====
+
+// trayManager manages the system tray state and menu items
+type trayManager struct {
+	callbacks Callbacks
+	pairItems map[string]*systray.MenuItem
+
+	// Main menu items
+	openUIItem  *systray.MenuItem
+	syncAllItem *systray.MenuItem
+	pairsRoot   *systray.MenuItem
+	quitItem    *systray.MenuItem
+}
+
+// ===== SYSTRAY LIFECYCLE HANDLERS =====
+
+// runSystray starts the systray event loop with the shared menu; blocks until quit
+func runSystray(icon []byte, callbacks Callbacks) {
+	systray.Run(
+		func() { onReady(icon, callbacks) },
+		func() { onExit() },
+	)
+}
+
+// onReady is called when the system tray is ready to be configured
+func onReady(icon []byte, callbacks Callbacks) {
+	log.Debug().Msg("system tray ready, initializing")
+
+	// Initialize tray manager
+	manager := &trayManager{
+		callbacks: callbacks,
+		pairItems: make(map[string]*systray.MenuItem),
+	}
+
+	// Set up basic tray properties
+	setupTrayProperties(icon)
+
+	// Create menu structure
+	manager.createMainMenu()
+
+	// Start event handlers
+	manager.startEventHandlers()
+
+	// Start periodic updates
+	manager.startPeriodicUpdates()
+
+	log.Info().Msg("system tray initialized successfully")
+}
+
+// onExit is called when the system tray is being shut down
+func onExit() {
+	log.Info().Msg("system tray shutting down")
+}
+
+// ===== ICON VALIDATION =====
+
+// isValidICOIcon checks if the provided icon data has a valid ICO header
+func isValidICOIcon(icon []byte) bool {
+	if len(icon) < 4 {
+		return false
+	}
+
+	return icon[0] == ICOHeaderByte0 &&
+		icon[1] == ICOHeaderByte1 &&
+		icon[2] == ICOHeaderByte2 &&
+		icon[3] == ICOHeaderByte3
+}
+
+// isValidPNGIcon checks if the provided icon data has a PNG signature
+func isValidPNGIcon(icon []byte) bool {
+	return bytes.HasPrefix(icon, []byte(PNGSignature))
+}
+
+// ===== MENU CREATION =====
+
+// createMainMenu creates the main tray menu structure
+func (tm *trayManager) createMainMenu() {
+	tm.openUIItem = systray.AddMenuItem(MenuOpenUI, DescOpenUI)
+	tm.syncAllItem = systray.AddMenuItem(MenuSyncAll, DescSyncAll)
+	tm.pairsRoot = systray.AddMenuItem(MenuPairs, DescPairs)
+
+	systray.AddSeparator()
+	tm.quitItem = systray.AddMenuItem(MenuQuit, DescQuit)
+
+	// Initialize pairs submenu
+	tm.rebuildPairsMenu()
+
+	log.Debug().Msg("main menu structure created")
+}
+
+// ===== EVENT HANDLING =====
+
+// startEventHandlers starts goroutines to handle menu item clicks
+func (tm *trayManager) startEventHandlers() {
+	// Handle "Open UI" clicks
+	go tm.handleOpenUIClicks()
+
+	// Handle "Sync All" clicks
+	go tm.handleSyncAllClicks()
+
+	// Handle "Quit" clicks
+	go tm.handleQuitClicks()
+
+	log.Debug().Msg("event handlers started")
+}
+
+// handleOpenUIClicks processes "Open UI" menu item clicks
+func (tm *trayManager) handleOpenUIClicks() {
+	for range tm.openUIItem.ClickedCh {
+		log.Info().Msg("tray: Open UI clicked")
+
+		if tm.callbacks.OnOpenUI != nil {
+			tm.callbacks.OnOpenUI()
+		} else {
+			log.Warn().Msg("OnOpenUI callback not provided")
+		}
+	}
+}
+
+// handleSyncAllClicks processes "Sync All" menu item clicks
+func (tm *trayManager) handleSyncAllClicks() {
+	for range tm.syncAllItem.ClickedCh {
+		log.Info().Msg("tray: Sync all clicked")
+
+		if tm.callbacks.OnSyncAll != nil {
+			tm.callbacks.OnSyncAll()
+		} else {
+			log.Warn().Msg("OnSyncAll callback not provided")
+		}
+	}
+}
+
+// handleQuitClicks processes "Quit" menu item clicks
+func (tm *trayManager) handleQuitClicks() {
+	for range tm.quitItem.ClickedCh {
+		log.Info().Msg("tray: Quit clicked")
+
+		if tm.callbacks.OnQuit != nil {
+			tm.callbacks.OnQuit()
+		}
+
+		systray.Quit()
+		return
+	}
+}
+
+// ===== PAIRS MENU MANAGEMENT =====
+
+// startPeriodicUpdates starts the periodic refresh of the pairs menu
+func (tm *trayManager) startPeriodicUpdates() {
+	if tm.callbacks.ListPairs == nil {
+		log.Debug().Msg("ListPairs callback not provided, skipping periodic updates")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(PairRefreshInterval)
+		defer ticker.Stop()
+
+		log.Debug().
+			Dur("interval", PairRefreshInterval).
+			Msg("starting periodic pairs menu refresh")
+
+		for range ticker.C {
+			tm.rebuildPairsMenu()
+		}
+	}()
+}
+
+// rebuildPairsMenu rebuilds the pairs submenu based on current sync pairs
+func (tm *trayManager) rebuildPairsMenu() {
+	if tm.callbacks.ListPairs == nil {
+		return
+	}
+
+	currentPairs := tm.callbacks.ListPairs()
+
+	// Remove pairs that no longer exist
+	tm.removeObsoletePairs(currentPairs)
+
+	// Add or update existing pairs
+	tm.updatePairsMenu(currentPairs)
+}
+
+// removeObsoletePairs removes menu items for pairs that no longer exist
+func (tm *trayManager) removeObsoletePairs(currentPairs []PairSummary) {
+	for pairID, menuItem := range tm.pairItems {
+		stillExists := false
+		for _, pair := range currentPairs {
+			if pair.ID == pairID {
+				stillExists = true
+				break
+			}
+		}
+
+		if !stillExists {
+			log.Debug().Str("pair_id", pairID).Msg("removing obsolete pair from tray menu")
+			menuItem.Hide()
+			delete(tm.pairItems, pairID)
+		}
+	}
+}
+
+// updatePairsMenu adds new pairs and updates existing ones in the menu
+func (tm *trayManager) updatePairsMenu(currentPairs []PairSummary) {
+	for _, pair := range currentPairs {
+		menuItem := tm.pairItems[pair.ID]
+
+		if menuItem == nil {
+			// Create new menu item for this pair
+			tm.createPairMenuItem(pair)
+		} else {
+			// Update existing menu item state
+			tm.updatePairMenuItem(menuItem, pair)
+		}
+	}
+}
+
+// createPairMenuItem creates a new menu item for a sync pair
+func (tm *trayManager) createPairMenuItem(pair PairSummary) {
+	menuItem := tm.pairsRoot.AddSubMenuItemCheckbox(
+		pairLabel(pair),
+		pairTooltip(pair),
+		pair.Enabled,
+	)
+
+	tm.pairItems[pair.ID] = menuItem
+
+	// Start click handler for this pair
+	go tm.handlePairToggle(menuItem, pair.ID)
+
+	log.Debug().
+		Str("pair_id", pair.ID).
+		Bool("enabled", pair.Enabled).
+		Msg("created new pair menu item")
+}
+
+// updatePairMenuItem updates the state and run status of an existing pair menu item
+func (tm *trayManager) updatePairMenuItem(menuItem *systray.MenuItem, pair PairSummary) {
+	if pair.Enabled {
+		menuItem.Check()
+	} else {
+		menuItem.Uncheck()
+	}
+
+	menuItem.SetTitle(pairLabel(pair))
+	menuItem.SetTooltip(pairTooltip(pair))
+}
+
+// pairLabel returns the menu label of a pair, marking pairs whose last run failed
+func pairLabel(pair PairSummary) string {
+	if pair.LastError != "" {
+		return FailingPairMarker + pair.ID
+	}
+	if pair.Paused {
+		return PausedPairMarker + pair.ID
+	}
+	return pair.ID
+}
+
+// pairTooltip describes a pair's last run, run counts and last error
+func pairTooltip(pair PairSummary) string {
+	lastRun := "never"
+	if pair.LastRun != nil {
+		lastRun = pair.LastRun.Local().Format(PairTimeLayout)
+	}
+
+	tooltip := fmt.Sprintf("Toggle sync pair: %s\nLast run: %s\nRuns: %d, failures: %d",
+		pair.ID, lastRun, pair.RunCount, pair.FailCount)
+
+	if pair.Paused {
+		tooltip += "\nPaused"
+	}
+
+	if pair.LastError != "" {
+		lastError := pair.LastError
+		if runes := []rune(lastError); len(runes) > MaxTooltipErrorLength {
+			lastError = string(runes[:MaxTooltipErrorLength]) + "…"
+		}
+		tooltip += "\nLast error: " + lastError
+	}
+	return tooltip
+}
+
+// handlePairToggle handles clicks on individual pair menu items
+func (tm *trayManager) handlePairToggle(menuItem *systray.MenuItem, pairID string) {
+	for range menuItem.ClickedCh {
+		newState := !menuItem.Checked()
+
+		log.Info().
+			Str("pair_id", pairID).
+			Bool("new_state", newState).
+			Msg("tray: pair toggle clicked")
+
+		if tm.callbacks.TogglePair != nil {
+			tm.callbacks.TogglePair(pairID, newState)
+		} else {
+			log.Warn().Msg("TogglePair callback not provided")
+		}
+
+		// Update menu item state
+		if newState {
+			menuItem.Check()
+		} else {
+			menuItem.Uncheck()
+		}
+	}
+}